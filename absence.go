@@ -0,0 +1,260 @@
+package yapper
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Absence is a period a person is away, e.g. imported from an out of office calendar, during which they are not paired.
+// People are not paired in any round overlapping one of their absences.
+type Absence struct {
+	Person ID   `json:"person"`
+	Start  Date `json:"start"`
+	// End is the last day of the absence, inclusive.
+	End Date `json:"end"`
+	// Source is where the absence was imported from, so importing from it again replaces its absences.
+	Source string `json:"source,omitempty"`
+}
+
+func (a Absence) validate(conf Config) error {
+	if _, err := conf.GetPerson(a.Person); err != nil {
+		return fmt.Errorf("absence refers to an unknown person: %w", err)
+	}
+
+	if a.End.Before(a.Start.Time) {
+		return fmt.Errorf("absence of %s ends before it starts", a.Person)
+	}
+
+	return nil
+}
+
+// overlaps reports whether the absence overlaps the round starting on date.
+func (a Absence) overlaps(conf Config, date time.Time) bool {
+	roundStart := NewDate(date)
+	roundEnd := NewDate(date.AddDate(0, 0, conf.RoundInterval()-1))
+	return !a.Start.After(roundEnd.Time) && !a.End.Before(roundStart.Time)
+}
+
+// absent reports whether the person is absent during any of the round starting on date.
+func (c Config) absent(id ID, date time.Time) bool {
+	return slices.ContainsFunc(c.Absences, func(a Absence) bool { return a.Person == id && a.overlaps(c, date) })
+}
+
+// ReplaceAbsences replaces the absences previously imported from the source with the given absences.
+func (c *Config) ReplaceAbsences(source string, absences []Absence) {
+	c.Absences = slices.DeleteFunc(c.Absences, func(a Absence) bool { return a.Source == source })
+	for _, absence := range absences {
+		absence.Source = source
+		c.Absences = append(c.Absences, absence)
+	}
+}
+
+// personByEmail returns the ID of the person with the email, ignoring case.
+func (c Config) personByEmail(email string) (ID, bool) {
+	index := slices.IndexFunc(c.People, func(p Person) bool { return p.Email != "" && strings.EqualFold(p.Email, email) })
+	if index == -1 {
+		return "", false
+	}
+	return c.People[index].ID, true
+}
+
+// ParseAbsencesCSV reads absences from CSV data with the columns person, start and end, as exported from an HR system.
+// The person is either their ID or email, dates are YYYY-MM-DD with the end inclusive, and a header row starting with
+// "person" is skipped.
+func ParseAbsencesCSV(reader io.Reader, config Config) ([]Absence, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 3
+	csvReader.TrimLeadingSpace = true
+
+	var absences []Absence
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return absences, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading absences: %w", err)
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "person") {
+			continue
+		}
+
+		person := ID(strings.TrimSpace(record[0]))
+		if id, found := config.personByEmail(string(person)); found {
+			person = id
+		} else if _, err := config.GetPerson(person); err != nil {
+			return nil, fmt.Errorf("unknown person on line %d: %s", line, person)
+		}
+
+		start, err := time.Parse(time.DateOnly, strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid start on line %d: %w", line, err)
+		}
+		end, err := time.Parse(time.DateOnly, strings.TrimSpace(record[2]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid end on line %d: %w", line, err)
+		}
+
+		absence := Absence{Person: person, Start: Date{start}, End: Date{end}}
+		if err := absence.validate(config); err != nil {
+			return nil, fmt.Errorf("invalid absence on line %d: %w", line, err)
+		}
+		absences = append(absences, absence)
+	}
+}
+
+// ParseAbsencesICS reads absences from the events of an iCalendar feed, such as an out of office calendar.
+// Every event is an absence of the person if one is given, as for a feed of one person's calendar. Otherwise events
+// are matched to people by the email of their organizer or attendees, and events not matching anyone are ignored.
+// Cancelled events and events marked as free are ignored.
+func ParseAbsencesICS(reader io.Reader, config Config, person ID) ([]Absence, error) {
+	if person != "" {
+		if _, err := config.GetPerson(person); err != nil {
+			return nil, err
+		}
+	}
+
+	lines, err := unfoldICS(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading calendar: %w", err)
+	}
+
+	var absences []Absence
+	var event map[string][]icsProperty
+	for _, line := range lines {
+		property := parseICSProperty(line)
+		switch {
+		case property.name == "BEGIN" && property.value == "VEVENT":
+			event = map[string][]icsProperty{}
+		case property.name == "END" && property.value == "VEVENT" && event != nil:
+			eventAbsences, err := eventAbsences(config, person, event)
+			if err != nil {
+				return nil, err
+			}
+			absences = append(absences, eventAbsences...)
+			event = nil
+		case event != nil:
+			event[property.name] = append(event[property.name], property)
+		}
+	}
+
+	return absences, nil
+}
+
+// icsProperty is a content line of an iCalendar file, such as DTSTART;VALUE=DATE:20250106.
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldICS returns the content lines of the calendar, joining lines folded onto continuation lines.
+func unfoldICS(reader io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseICSProperty(line string) icsProperty {
+	nameAndParams, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(nameAndParams, ";")
+
+	property := icsProperty{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: value}
+	for _, param := range parts[1:] {
+		key, paramValue, _ := strings.Cut(param, "=")
+		property.params[strings.ToUpper(key)] = strings.Trim(paramValue, `"`)
+	}
+	return property
+}
+
+// eventAbsences returns the absences of the people the event belongs to.
+func eventAbsences(config Config, person ID, event map[string][]icsProperty) ([]Absence, error) {
+	if first(event, "STATUS") == "CANCELLED" || first(event, "TRANSP") == "TRANSPARENT" {
+		return nil, nil
+	}
+
+	starts := event["DTSTART"]
+	if len(starts) == 0 {
+		return nil, errors.New("calendar event is missing a start")
+	}
+	start, allDay, err := parseICSDate(starts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid start of calendar event: %w", err)
+	}
+
+	end := start
+	if ends := event["DTEND"]; len(ends) > 0 {
+		end, _, err = parseICSDate(ends[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of calendar event: %w", err)
+		}
+
+		// The end of an event is exclusive, so an all day event or one ending at midnight ends the day before.
+		if end.After(start) && (allDay || (end.Hour() == 0 && end.Minute() == 0 && end.Second() == 0)) {
+			end = end.AddDate(0, 0, -1)
+		}
+	}
+
+	var people []ID
+	if person != "" {
+		people = append(people, person)
+	} else {
+		for _, property := range append(event["ORGANIZER"], event["ATTENDEE"]...) {
+			email, found := strings.CutPrefix(strings.ToLower(property.value), "mailto:")
+			if id, exists := config.personByEmail(email); found && exists && !slices.Contains(people, id) {
+				people = append(people, id)
+			}
+		}
+	}
+
+	var absences []Absence
+	for _, id := range people {
+		absences = append(absences, Absence{Person: id, Start: NewDate(start), End: NewDate(end)})
+	}
+	return absences, nil
+}
+
+// parseICSDate parses a DATE or DATE-TIME value, reporting whether it is a date without a time.
+// Times are converted to the timezone given by the TZID parameter, or UTC if they are not floating.
+func parseICSDate(property icsProperty) (time.Time, bool, error) {
+	if property.params["VALUE"] == "DATE" || len(property.value) == len("20060102") {
+		date, err := time.Parse("20060102", property.value)
+		return date, true, err
+	}
+
+	location := time.UTC
+	if tzid := property.params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+
+	if value, found := strings.CutSuffix(property.value, "Z"); found {
+		parsed, err := time.Parse("20060102T150405", value)
+		return parsed, false, err
+	}
+
+	parsed, err := time.ParseInLocation("20060102T150405", property.value, location)
+	return parsed, false, err
+}
+
+func first(event map[string][]icsProperty, name string) string {
+	if properties := event[name]; len(properties) > 0 {
+		return strings.ToUpper(properties[0].value)
+	}
+	return ""
+}
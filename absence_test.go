@@ -0,0 +1,148 @@
+package yapper
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func date(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+var absenceConfig = Config{People: []Person{
+	{ID: "Mario", Email: "mario@mushroom.kingdom"},
+	{ID: "Luigi", Email: "Luigi@mushroom.kingdom"},
+	{ID: "Peach"},
+}}
+
+func TestPairPeopleSkipsAbsentPeople(t *testing.T) {
+	config := absenceConfig
+	config.Absences = []Absence{{Person: "Luigi", Start: date(2025, time.August, 8), End: date(2025, time.August, 15)}}
+
+	for _, test := range []struct {
+		date        time.Time
+		luigiPaired bool
+	}{
+		{time.Date(2025, time.July, 28, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, time.August, 18, 0, 0, 0, 0, time.UTC), true},
+	} {
+		ineligible := getIneligiblePeople(config, determineValidPairings(config), test.date)
+		if absent := slices.Contains(ineligible, "Luigi"); absent == test.luigiPaired {
+			t.Errorf("Expected Luigi to be eligible %v in the round starting %s, got ineligible %v", test.luigiPaired, test.date.Format(time.DateOnly), ineligible)
+		}
+	}
+
+	pairings := pairPeople(config, determineValidPairings(config), history.History{}, time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+	if len(pairings.data) != 1 || slices.Contains(pairings.data[0][:], "Luigi") {
+		t.Errorf("Expected only Mario and Peach to be paired, got %v", pairings.data)
+	}
+}
+
+func TestParseAbsencesCSV(t *testing.T) {
+	data := "person,start,end\nmario@mushroom.kingdom,2025-08-04,2025-08-08\nPeach, 2025-09-01, 2025-09-01\n"
+
+	absences, err := ParseAbsencesCSV(strings.NewReader(data), absenceConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing absences: %v", err)
+	}
+
+	expected := []Absence{
+		{Person: "Mario", Start: date(2025, time.August, 4), End: date(2025, time.August, 8)},
+		{Person: "Peach", Start: date(2025, time.September, 1), End: date(2025, time.September, 1)},
+	}
+	if !reflect.DeepEqual(expected, absences) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, absences)
+	}
+}
+
+func TestParseAbsencesCSVRejectsInvalidRows(t *testing.T) {
+	for _, data := range []string{
+		"Bowser,2025-08-04,2025-08-08\n",
+		"Mario,2025-08-08,2025-08-04\n",
+		"Mario,04/08/2025,2025-08-08\n",
+	} {
+		if _, err := ParseAbsencesCSV(strings.NewReader(data), absenceConfig); err == nil {
+			t.Errorf("Expected an error parsing %q", data)
+		}
+	}
+}
+
+const absenceCalendar = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Out of office\r\n" +
+	"DTSTART;VALUE=DATE:20250804\r\n" +
+	"DTEND;VALUE=DATE:20250809\r\n" +
+	"ORGANIZER;CN=Mario:mailto:mario@mushroom.kingdom\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250901T090000Z\r\n" +
+	"DTEND:20250903T000000Z\r\n" +
+	"ATTENDEE;CN=Luigi:MAILTO:luigi@mushroom.kingdom\r\n" +
+	"ATTENDEE;CN=Bowser:mailto:bowser@koopa.kingdom\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20251001\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"ORGANIZER:mailto:mario@mushroom.kingdom\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20251101\r\n" +
+	"ORGANIZER:mailto:toad@mushroom.kingdom\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseAbsencesICSMatchesPeopleByEmail(t *testing.T) {
+	absences, err := ParseAbsencesICS(strings.NewReader(absenceCalendar), absenceConfig, "")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing absences: %v", err)
+	}
+
+	expected := []Absence{
+		{Person: "Mario", Start: date(2025, time.August, 4), End: date(2025, time.August, 8)},
+		{Person: "Luigi", Start: date(2025, time.September, 1), End: date(2025, time.September, 2)},
+	}
+	if !reflect.DeepEqual(expected, absences) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, absences)
+	}
+}
+
+func TestParseAbsencesICSForOnePerson(t *testing.T) {
+	absences, err := ParseAbsencesICS(strings.NewReader(absenceCalendar), absenceConfig, "Peach")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing absences: %v", err)
+	}
+
+	expected := []Absence{
+		{Person: "Peach", Start: date(2025, time.August, 4), End: date(2025, time.August, 8)},
+		{Person: "Peach", Start: date(2025, time.September, 1), End: date(2025, time.September, 2)},
+		{Person: "Peach", Start: date(2025, time.November, 1), End: date(2025, time.November, 1)},
+	}
+	if !reflect.DeepEqual(expected, absences) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, absences)
+	}
+}
+
+func TestReplaceAbsencesKeepsOtherSources(t *testing.T) {
+	config := absenceConfig
+	config.Absences = []Absence{
+		{Person: "Mario", Start: date(2025, time.August, 4), End: date(2025, time.August, 8), Source: "hr.csv"},
+		{Person: "Luigi", Start: date(2025, time.August, 4), End: date(2025, time.August, 8)},
+	}
+
+	config.ReplaceAbsences("hr.csv", []Absence{{Person: "Peach", Start: date(2025, time.May, 1), End: date(2025, time.May, 2)}})
+
+	expected := []Absence{
+		{Person: "Luigi", Start: date(2025, time.August, 4), End: date(2025, time.August, 8)},
+		{Person: "Peach", Start: date(2025, time.May, 1), End: date(2025, time.May, 2), Source: "hr.csv"},
+	}
+	if !reflect.DeepEqual(expected, config.Absences) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, config.Absences)
+	}
+}
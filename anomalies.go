@@ -0,0 +1,166 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// AnomalyKind is a kind of unusual pattern in the history, which is usually a sign the constraints need tuning.
+type AnomalyKind string
+
+const (
+	// AnomalyCoverageDrop is when the share of people paired has dropped in several rounds in a row.
+	AnomalyCoverageDrop AnomalyKind = "coverage-drop"
+	// AnomalyClusteredPartners is when someone keeps meeting the same one or two people, despite others they could meet.
+	AnomalyClusteredPartners AnomalyKind = "clustered-partners"
+	// AnomalySquadsNeverMix is when no one of two squads has met anyone of the other, despite pairs of them being valid.
+	AnomalySquadsNeverMix AnomalyKind = "squads-never-mix"
+)
+
+// Anomaly is an unusual pattern found in the history.
+type Anomaly struct {
+	Kind    AnomalyKind `json:"kind"`
+	People  []ID        `json:"people,omitempty"`
+	Squads  []string    `json:"squads,omitempty"`
+	Message string      `json:"message"`
+}
+
+// AnomalyOptions are the thresholds of the anomalies.
+type AnomalyOptions struct {
+	// DroppingRounds is how many rounds in a row the share of people paired must drop in to be reported.
+	DroppingRounds int
+	// ClusterMeetings is how many meetings someone must have had with at most two people to be reported.
+	ClusterMeetings int
+}
+
+// DetectAnomalies returns the unusual patterns in the rounds recorded in the history of the people in the config:
+// the share of people paired dropping, people whose meetings cluster with only one or two partners, and squads
+// which never mix.
+func DetectAnomalies(config Config, hist history.History, options AnomalyOptions) []Anomaly {
+	anomalies := []Anomaly{}
+	idToValidPairings := determineValidPairings(config)
+
+	recorded := RecordedRounds(config, hist)
+	if len(recorded) == 0 {
+		return anomalies
+	}
+
+	if anomaly, found := coverageDrop(config, idToValidPairings, recorded, options.DroppingRounds); found {
+		anomalies = append(anomalies, anomaly)
+	}
+	anomalies = append(anomalies, clusteredPartners(config, hist, idToValidPairings, options.ClusterMeetings)...)
+	anomalies = append(anomalies, squadsNeverMixing(config, hist, idToValidPairings)...)
+	return anomalies
+}
+
+// coverageDrop reports whether the share of the people who could meet who were paired dropped in each of the last
+// rounds recorded.
+func coverageDrop(config Config, idToValidPairings map[ID][]ID, recorded []Pairings, rounds int) (Anomaly, bool) {
+	if rounds <= 0 || len(recorded) <= rounds {
+		return Anomaly{}, false
+	}
+
+	shares := make([]float64, 0, rounds+1)
+	for _, round := range recorded[len(recorded)-rounds-1:] {
+		eligible := len(idToValidPairings) - len(getIneligiblePeople(config, idToValidPairings, round.date))
+		if eligible <= 0 {
+			return Anomaly{}, false
+		}
+		shares = append(shares, float64(2*round.Len())/float64(eligible))
+	}
+
+	for i := 1; i < len(shares); i++ {
+		if shares[i] >= shares[i-1] {
+			return Anomaly{}, false
+		}
+	}
+
+	message := fmt.Sprintf("the share of people paired dropped in each of the last %d rounds, from %.1f%% to %.1f%%", rounds, 100*shares[0], 100*shares[len(shares)-1])
+	return Anomaly{Kind: AnomalyCoverageDrop, Message: message}, true
+}
+
+// clusteredPartners returns the people who met at most two people at least the given number of times, while they
+// could be paired with others.
+func clusteredPartners(config Config, hist history.History, idToValidPairings map[ID][]ID, meetings int) []Anomaly {
+	anomalies := []Anomaly{}
+	if meetings <= 0 {
+		return anomalies
+	}
+
+	index := config.Index()
+	for _, person := range config.People {
+		var partners []ID
+		total := 0
+		for other := range hist.PartnersOf(history.ID(person.ID)) {
+			if _, found := index[ID(other)]; !found {
+				continue
+			}
+			partners = append(partners, ID(other))
+			total += hist.TimesMet(history.ID(person.ID), other)
+		}
+
+		if len(partners) == 0 || len(partners) > 2 || total < meetings || len(idToValidPairings[person.ID]) <= len(partners) {
+			continue
+		}
+
+		slices.Sort(partners)
+		names := make([]string, 0, len(partners))
+		for _, partner := range partners {
+			names = append(names, string(partner))
+		}
+		message := fmt.Sprintf("%s has only met %s in %d meetings, out of %d people they could be paired with", person.ID, strings.Join(names, " and "), total, len(idToValidPairings[person.ID]))
+		anomalies = append(anomalies, Anomaly{Kind: AnomalyClusteredPartners, People: append([]ID{person.ID}, partners...), Message: message})
+	}
+	return anomalies
+}
+
+// squadsNeverMixing returns the pairs of squads with a valid pair of people between them, none of whom have met.
+func squadsNeverMixing(config Config, hist history.History, idToValidPairings map[ID][]ID) []Anomaly {
+	squads := []string{}
+	squadOf := map[ID]string{}
+	for _, person := range config.People {
+		if person.Squad == "" {
+			continue
+		}
+		squadOf[person.ID] = person.Squad
+		if !slices.Contains(squads, person.Squad) {
+			squads = append(squads, person.Squad)
+		}
+	}
+	slices.Sort(squads)
+
+	valid, met := map[[2]string]bool{}, map[[2]string]bool{}
+	key := func(squad1, squad2 string) [2]string {
+		if squad2 < squad1 {
+			return [2]string{squad2, squad1}
+		}
+		return [2]string{squad1, squad2}
+	}
+	for id, validPairings := range idToValidPairings {
+		for _, other := range validPairings {
+			if squadOf[id] != "" && squadOf[other] != "" && squadOf[id] != squadOf[other] {
+				valid[key(squadOf[id], squadOf[other])] = true
+			}
+		}
+	}
+	for pair := range hist.All() {
+		squad1, squad2 := squadOf[ID(pair[0])], squadOf[ID(pair[1])]
+		if squad1 != "" && squad2 != "" && squad1 != squad2 {
+			met[key(squad1, squad2)] = true
+		}
+	}
+
+	anomalies := []Anomaly{}
+	for i, squad1 := range squads {
+		for _, squad2 := range squads[i+1:] {
+			if valid[key(squad1, squad2)] && !met[key(squad1, squad2)] {
+				message := fmt.Sprintf("no one in %s has met anyone in %s", squad1, squad2)
+				anomalies = append(anomalies, Anomaly{Kind: AnomalySquadsNeverMix, Squads: []string{squad1, squad2}, Message: message})
+			}
+		}
+	}
+	return anomalies
+}
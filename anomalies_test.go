@@ -0,0 +1,77 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+var anomalyOptions = AnomalyOptions{DroppingRounds: 2, ClusterMeetings: 4}
+
+func TestDetectAnomaliesFindsCoverageDrop(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Daisy"}, {ID: "Toad"}, {ID: "Yoshi"}}}
+	first := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", first)
+	hist.AddMeeting("Peach", "Daisy", first)
+	hist.AddMeeting("Toad", "Yoshi", first)
+	hist.AddMeeting("Mario", "Peach", first.AddDate(0, 0, 7))
+	hist.AddMeeting("Luigi", "Toad", first.AddDate(0, 0, 7))
+	hist.AddMeeting("Daisy", "Yoshi", first.AddDate(0, 0, 14))
+
+	anomalies := DetectAnomalies(config, hist, anomalyOptions)
+
+	expected := []Anomaly{{Kind: AnomalyCoverageDrop, Message: "the share of people paired dropped in each of the last 2 rounds, from 100.0% to 33.3%"}}
+	if !reflect.DeepEqual(expected, anomalies) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, anomalies)
+	}
+}
+
+func TestDetectAnomaliesFindsClusteredPartners(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}}}
+	first := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	for week := range 4 {
+		hist.AddMeeting("Mario", "Luigi", first.AddDate(0, 0, 7*week))
+	}
+
+	anomalies := DetectAnomalies(config, hist, anomalyOptions)
+
+	expected := []Anomaly{
+		{Kind: AnomalyClusteredPartners, People: []ID{"Mario", "Luigi"}, Message: "Mario has only met Luigi in 4 meetings, out of 2 people they could be paired with"},
+		{Kind: AnomalyClusteredPartners, People: []ID{"Luigi", "Mario"}, Message: "Luigi has only met Mario in 4 meetings, out of 2 people they could be paired with"},
+	}
+	if !reflect.DeepEqual(expected, anomalies) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, anomalies)
+	}
+}
+
+func TestDetectAnomaliesFindsSquadsWhichNeverMix(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario", Squad: "plumbers"},
+		{ID: "Peach", Squad: "royals"},
+		{ID: "Toad", Squad: "retainers"},
+	}}
+	hist := history.History{}
+	hist.AddMeeting("Peach", "Toad", time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+
+	anomalies := DetectAnomalies(config, hist, anomalyOptions)
+
+	expected := []Anomaly{
+		{Kind: AnomalySquadsNeverMix, Squads: []string{"plumbers", "retainers"}, Message: "no one in plumbers has met anyone in retainers"},
+		{Kind: AnomalySquadsNeverMix, Squads: []string{"plumbers", "royals"}, Message: "no one in plumbers has met anyone in royals"},
+	}
+	if !reflect.DeepEqual(expected, anomalies) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, anomalies)
+	}
+}
+
+func TestDetectAnomaliesIsEmptyWithoutHistory(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario", Squad: "plumbers"}, {ID: "Peach", Squad: "royals"}}}
+
+	if anomalies := DetectAnomalies(config, history.History{}, anomalyOptions); len(anomalies) != 0 {
+		t.Errorf("Expected no anomalies, got %v", anomalies)
+	}
+}
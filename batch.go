@@ -0,0 +1,100 @@
+package yapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// ProgramSpec is one of the programs to generate pairings for with GenerateAll.
+type ProgramSpec struct {
+	// Name identifies the program in errors.
+	Name   string
+	Config Config
+	// History is updated with the generated pairings in the namespace of the config. Several programs can share a
+	// history, as long as it is not used elsewhere while the pairings are generated.
+	History *history.History
+	// Rounds is the number of rounds of pairings to generate.
+	Rounds int
+	// Now is the time the first round contains, defaulting to the current time in the timezone of the config.
+	Now time.Time
+}
+
+// ProgramResult is the pairings generated for a program by GenerateAll, or the error which prevented it.
+type ProgramResult struct {
+	Name     string
+	Pairings []Pairings
+	Err      error
+}
+
+// GenerateAll generates the pairings of every program concurrently, on as many workers as there are processors shared
+// between all of the programs, as GeneratePairings would for each. The results are in the same order as the programs.
+//
+// The pairings of each program are only recorded in its history once every program has finished, in the order of the
+// programs, so programs sharing a history do not change it while others are reading it. Programs which fail are not
+// recorded, and their errors are joined in the returned error. Once the context is done, programs which have not
+// finished fail with its error.
+func GenerateAll(ctx context.Context, programs []ProgramSpec) ([]ProgramResult, error) {
+	results := make([]ProgramResult, len(programs))
+	hists := make([]*history.History, len(programs))
+	dates := make([][]time.Time, len(programs))
+
+	for i, program := range programs {
+		results[i].Name = program.Name
+		if program.History == nil {
+			results[i].Err = errors.New("no history given")
+			continue
+		}
+		hists[i] = program.History.Namespace(program.Config.Namespace)
+
+		now := program.Now
+		if now.IsZero() {
+			location, err := program.Config.Location()
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			now = time.Now().In(location)
+		}
+		dates[i] = roundDates(program.Config, now, program.Rounds)
+	}
+
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, program := range programs {
+		if results[i].Err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case workers <- struct{}{}:
+				defer func() { <-workers }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+
+			results[i].Pairings, results[i].Err = chooseWithStrategies(ctx, program.Config, determineValidPairings(program.Config), *hists[i], dates[i])
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, program := range programs {
+		if results[i].Err != nil {
+			errs = append(errs, fmt.Errorf("error generating pairings for program %s: %w", program.Name, results[i].Err))
+			continue
+		}
+		recordRounds(program.Config, hists[i], results[i].Pairings)
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package yapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestGenerateAllRecordsEachProgramInItsNamespace(t *testing.T) {
+	now := time.Date(2025, time.August, 6, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	programs := []ProgramSpec{
+		{Name: "coffee", Config: Config{Namespace: "coffee", People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}, History: &hist, Rounds: 2, Now: now},
+		{Name: "mentoring", Config: Config{Namespace: "mentoring", People: []Person{{ID: "Peach"}, {ID: "Toad"}}}, History: &hist, Rounds: 1, Now: now},
+	}
+
+	results, err := GenerateAll(context.Background(), programs)
+	if err != nil {
+		t.Fatalf("Unexpected error generating pairings: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "coffee" || len(results[0].Pairings) != 2 || len(results[1].Pairings) != 1 {
+		t.Fatalf("Expected 2 rounds of coffee and 1 of mentoring, got %+v", results)
+	}
+	if expected := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC); !results[1].Pairings[0].Date().Equal(expected) {
+		t.Errorf("Expected the first round to start on %v, got %v", expected, results[1].Pairings[0].Date())
+	}
+
+	if _, met := hist.Namespace("coffee").GetPersonToLastMeetingMap("Mario")["Luigi"]; !met {
+		t.Error("Expected Mario and Luigi to have met in the coffee namespace")
+	}
+	if _, met := hist.Namespace("mentoring").GetPersonToLastMeetingMap("Peach")["Toad"]; !met {
+		t.Error("Expected Peach and Toad to have met in the mentoring namespace")
+	}
+	if _, met := hist.Namespace("coffee").GetPersonToLastMeetingMap("Peach")["Toad"]; met {
+		t.Error("Expected the mentoring meetings to be kept out of the coffee namespace")
+	}
+}
+
+func TestGenerateAllDoesNotRecordFailedPrograms(t *testing.T) {
+	hist := history.History{}
+	programs := []ProgramSpec{
+		{Name: "coffee", Config: Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}, History: &hist, Rounds: 1},
+		{Name: "lost", Config: Config{Timezone: "Mushroom/Kingdom", People: []Person{{ID: "Peach"}, {ID: "Toad"}}}, History: &hist, Rounds: 1},
+		{Name: "missing", Config: Config{People: []Person{{ID: "Yoshi"}, {ID: "Bowser"}}}, Rounds: 1},
+	}
+
+	results, err := GenerateAll(context.Background(), programs)
+	if err == nil {
+		t.Fatal("Expected an error for the programs which could not be generated")
+	}
+
+	if results[0].Err != nil || len(results[0].Pairings) != 1 {
+		t.Errorf("Expected the coffee program to be generated, got %+v", results[0])
+	}
+	if results[1].Err == nil || results[2].Err == nil {
+		t.Errorf("Expected errors for the lost and missing programs, got %+v", results[1:])
+	}
+	if _, met := hist.GetPersonToLastMeetingMap("Peach")["Toad"]; met {
+		t.Error("Expected the failed program not to be recorded")
+	}
+}
+
+func TestGenerateAllStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	hist := history.History{}
+	programs := []ProgramSpec{{Name: "coffee", Config: Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}, History: &hist, Rounds: 1}}
+
+	if _, err := GenerateAll(ctx, programs); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the generation to be cancelled, got %v", err)
+	}
+}
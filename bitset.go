@@ -0,0 +1,45 @@
+package yapper
+
+// ordinals number the people of a config in config order, so sets of them can be kept as bitsets.
+type ordinals map[ID]int
+
+func newOrdinals(conf Config) ordinals {
+	o := make(ordinals, len(conf.People))
+	for _, person := range conf.People {
+		if _, found := o[person.ID]; !found {
+			o[person.ID] = len(o)
+		}
+	}
+	return o
+}
+
+// personSet is a set of the people of a config, with a bit for each of their ordinals. Adding and checking for a
+// person is constant time, unlike searching a slice of IDs. People who are not in the config are never in the set.
+type personSet struct {
+	ordinals ordinals
+	bits     []uint64
+}
+
+func newPersonSet(o ordinals, ids ...ID) personSet {
+	s := personSet{ordinals: o, bits: make([]uint64, (len(o)+63)/64)}
+	for _, id := range ids {
+		s.add(id)
+	}
+	return s
+}
+
+func (s personSet) add(id ID) {
+	if ordinal, found := s.ordinals[id]; found {
+		s.bits[ordinal/64] |= 1 << (ordinal % 64)
+	}
+}
+
+func (s personSet) contains(id ID) bool {
+	ordinal, found := s.ordinals[id]
+	return found && s.bits[ordinal/64]&(1<<(ordinal%64)) != 0
+}
+
+// clear removes everyone from the set, so it can be reused.
+func (s personSet) clear() {
+	clear(s.bits)
+}
@@ -0,0 +1,58 @@
+package yapper
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestPersonSet(t *testing.T) {
+	config := Config{}
+	for i := range 100 {
+		config.People = append(config.People, Person{ID: ID(fmt.Sprintf("person-%d", i))})
+	}
+
+	set := newPersonSet(newOrdinals(config), "person-3", "person-64", "Bowser")
+	set.add("person-99")
+
+	for _, id := range []ID{"person-3", "person-64", "person-99"} {
+		if !set.contains(id) {
+			t.Errorf("Expected %s to be in the set", id)
+		}
+	}
+	for _, id := range []ID{"person-0", "person-63", "person-65", "Bowser"} {
+		if set.contains(id) {
+			t.Errorf("Expected %s not to be in the set", id)
+		}
+	}
+
+	set.clear()
+	if set.contains("person-3") {
+		t.Error("Expected the set to be empty after clearing it")
+	}
+}
+
+// BenchmarkPairPeople pairs 1000 people who have already met over a few rounds.
+func BenchmarkPairPeople(b *testing.B) {
+	config := Config{}
+	for i := range 1000 {
+		config.People = append(config.People, Person{ID: ID(fmt.Sprintf("person-%d", i))})
+	}
+	idToValidPairings := computeValidPairings(config)
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 5)
+
+	hist := history.History{}
+	for _, date := range dates[:4] {
+		pairings := pairPeople(config, idToValidPairings, hist, date)
+		for id1, id2 := range pairings.All() {
+			hist.AddMeeting(history.ID(id1), history.ID(id2), date)
+		}
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		pairPeople(config, idToValidPairings, hist, dates[4])
+	}
+}
@@ -0,0 +1,132 @@
+package yapper
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Campaign is a period during which people from two cohorts are preferentially paired with each other.
+// The standard constraints still apply, a campaign only changes which of the valid pairings are preferred.
+type Campaign struct {
+	Name    string    `json:"name"`
+	Start   Date      `json:"start"`
+	End     Date      `json:"end"`
+	Cohorts [2]Cohort `json:"cohorts"`
+}
+
+// Cohort defines a group of people taking part in a campaign, by squad or by a tag pattern.
+type Cohort struct {
+	Squad string `json:"squad"`
+	Tag   string `json:"tag"`
+}
+
+func (c Cohort) includes(person Person) bool {
+	return (c.Squad != "" && person.Squad == c.Squad) || (c.Tag != "" && person.HasTag(c.Tag))
+}
+
+func (c Campaign) validate() error {
+	if c.Name == "" {
+		return errors.New("campaign is missing a name")
+	}
+
+	if c.End.Before(c.Start.Time) {
+		return fmt.Errorf("campaign %s ends before it starts", c.Name)
+	}
+
+	for _, cohort := range c.Cohorts {
+		if cohort.Squad == "" && cohort.Tag == "" {
+			return fmt.Errorf("campaign %s has a cohort without a squad or tag", c.Name)
+		}
+	}
+
+	return nil
+}
+
+// activeOn reports whether the round starting on date is part of the campaign.
+func (c Campaign) activeOn(date time.Time) bool {
+	return NewDate(date).Within(c.Start, c.End)
+}
+
+// includes reports whether the person belongs to either cohort of the campaign.
+func (c Campaign) includes(person Person) bool {
+	return c.Cohorts[0].includes(person) || c.Cohorts[1].includes(person)
+}
+
+// targets reports whether the campaign wants the two people to be paired, which is the case when they are in opposite cohorts.
+func (c Campaign) targets(person1, person2 Person) bool {
+	return (c.Cohorts[0].includes(person1) && c.Cohorts[1].includes(person2)) ||
+		(c.Cohorts[1].includes(person1) && c.Cohorts[0].includes(person2))
+}
+
+// activeCampaigns returns the campaigns running in the round starting on date.
+func activeCampaigns(conf Config, date time.Time) []Campaign {
+	var active []Campaign
+	for _, campaign := range conf.Campaigns {
+		if campaign.activeOn(date) {
+			active = append(active, campaign)
+		}
+	}
+	return active
+}
+
+// prioritiseCampaignMembers orders the IDs so people taking part in an active campaign are paired first.
+// This gives them the best chance of being paired with the opposite cohort before those people are taken.
+func prioritiseCampaignMembers(index map[ID]Person, campaigns []Campaign, ids []ID) []ID {
+	if len(campaigns) == 0 {
+		return ids
+	}
+
+	slices.SortStableFunc(ids, func(a, b ID) int {
+		return boolToOrder(inAnyCampaign(index, campaigns, b)) - boolToOrder(inAnyCampaign(index, campaigns, a))
+	})
+	return ids
+}
+
+// prioritiseCampaignPairings moves the possible pairings targeted by an active campaign to the front,
+// otherwise keeping the existing order.
+func prioritiseCampaignPairings(index map[ID]Person, campaigns []Campaign, id ID, possiblePairings []ID) []ID {
+	if len(campaigns) == 0 {
+		return possiblePairings
+	}
+
+	person, found := index[id]
+	if !found {
+		return possiblePairings
+	}
+
+	targeted := func(other ID) bool {
+		otherPerson, found := index[other]
+		if !found {
+			return false
+		}
+
+		return slices.ContainsFunc(campaigns, func(c Campaign) bool {
+			return c.targets(person, otherPerson)
+		})
+	}
+
+	slices.SortStableFunc(possiblePairings, func(a, b ID) int {
+		return boolToOrder(targeted(b)) - boolToOrder(targeted(a))
+	})
+	return possiblePairings
+}
+
+func inAnyCampaign(index map[ID]Person, campaigns []Campaign, id ID) bool {
+	person, found := index[id]
+	if !found {
+		return false
+	}
+
+	return slices.ContainsFunc(campaigns, func(c Campaign) bool {
+		return c.includes(person)
+	})
+}
+
+func boolToOrder(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package yapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestCampaignValidateReturnsErrorWhenEndIsBeforeStart(t *testing.T) {
+	campaign := Campaign{
+		Name:    "backwards",
+		Start:   NewDate(time.Date(2025, time.August, 31, 0, 0, 0, 0, time.UTC)),
+		End:     NewDate(time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)),
+		Cohorts: [2]Cohort{{Squad: "bros"}, {Squad: "koopas"}},
+	}
+
+	if err := campaign.validate(); err == nil {
+		t.Errorf("Expected error due to campaign ending before it starts")
+	}
+}
+
+func TestCampaignValidateReturnsErrorForEmptyCohort(t *testing.T) {
+	campaign := Campaign{
+		Name:    "lonely",
+		Start:   NewDate(time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)),
+		End:     NewDate(time.Date(2025, time.August, 31, 0, 0, 0, 0, time.UTC)),
+		Cohorts: [2]Cohort{{Squad: "bros"}},
+	}
+
+	if err := campaign.validate(); err == nil {
+		t.Errorf("Expected error due to cohort without a squad or tag")
+	}
+}
+
+func TestPairPeoplePrefersCampaignCohortsWhileActive(t *testing.T) {
+	date := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	config := getConfigFromFile(t, validConfigName)
+	config.Campaigns = []Campaign{{
+		Name:    "bros-meet-koopas",
+		Start:   NewDate(date),
+		End:     NewDate(date.AddDate(0, 0, 30)),
+		Cohorts: [2]Cohort{{Squad: "bros"}, {Squad: "koopas"}},
+	}}
+
+	pairings := pairPeople(config, determineValidPairings(config), history.History{}, date)
+
+	partners := map[ID]ID{}
+	for id1, id2 := range pairings.All() {
+		partners[id1] = id2
+		partners[id2] = id1
+	}
+
+	for _, id := range []ID{"Mario", "Luigi"} {
+		partner, err := config.GetPerson(partners[id])
+		if err != nil {
+			t.Fatalf("%s was not paired: %v", id, err)
+		}
+
+		if partner.Squad != "koopas" {
+			t.Errorf("%s should be paired with a koopa during the campaign, got: %s", id, partner.ID)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+const formatCSV = "csv"
+
+// executeAbsences runs the absences subcommand named by the first argument.
+func executeAbsences(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Expected an absences subcommand: import")
+		return exitCodeInvalidArguments
+	}
+
+	switch args[0] {
+	case "import":
+		return executeAbsencesImport(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unexpected absences subcommand: %s\n", args[0])
+		return exitCodeInvalidArguments
+	}
+}
+
+// executeAbsencesImport replaces the absences in the config with those from an iCalendar feed or CSV file.
+func executeAbsencesImport(args []string) int {
+	cmd := newFlagSet("yapper absences import")
+	pathToConfig := cmd.String("config", "", "Path to a yapper config file. The updated config will be written to this file as well.")
+	format := cmd.String("format", "", "Format of the absences, ics or csv. Defaults to the extension of the source.")
+	person := cmd.String("person", "", "ID of the person every event of an iCalendar feed belongs to. Otherwise events are matched to people by email.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "Usage: yapper absences import [flags] file-or-url")
+		fmt.Fprintln(cmd.Output(), "The CSV format has the columns person, start and end, with the person as their ID or email and dates as YYYY-MM-DD.")
+		cmd.PrintDefaults()
+		printExamples(cmd)
+	}
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if cmd.NArg() != 1 {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+	source := cmd.Arg(0)
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(source)), ".")
+	}
+	if *format != formatICS && *format != formatCSV {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFile(*pathToConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+	if config.Interpolated() {
+		fmt.Fprintln(os.Stderr, "The config refers to environment variables, so absences cannot be imported into it")
+		return exitCodeError
+	}
+
+	reader, err := openSource(source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening absences: %v\n", err)
+		return exitCodeError
+	}
+	defer reader.Close()
+
+	var absences []yapper.Absence
+	if *format == formatCSV {
+		absences, err = yapper.ParseAbsencesCSV(reader, config)
+	} else {
+		absences, err = yapper.ParseAbsencesICS(reader, config, yapper.ID(*person))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error importing absences: %v\n", err)
+		return exitCodeError
+	}
+
+	config.ReplaceAbsences(source, absences)
+
+	if err := writeConfigToFile(config, *pathToConfig); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Imported %d absences from %s", len(absences), source)
+	return exitCodeSuccess
+}
+
+// openSource opens the file at the path, or fetches the URL if it is one, e.g. a calendar feed.
+func openSource(source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.Open(source)
+	}
+
+	resp, err := http.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status fetching %s: %s", source, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// executeAnomalies reports the unusual patterns in the recorded history, to help organizers tune the constraints.
+func executeAnomalies(args []string) int {
+	cmd := newFlagSet("yapper anomalies")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to the yapper history file to look for anomalies in.")
+	droppingRounds := cmd.Int("dropping-rounds", 3, "Number of rounds in a row the share of people paired must drop in to be reported.")
+	clusterMeetings := cmd.Int("cluster-meetings", 4, "Number of meetings with only one or two partners for someone to be reported.")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	options := yapper.AnomalyOptions{DroppingRounds: *droppingRounds, ClusterMeetings: *clusterMeetings}
+	anomalies := yapper.DetectAnomalies(config, *hist.Namespace(config.Namespace), options)
+
+	if *format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(anomalies); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing anomalies: %v\n", err)
+			return exitCodeError
+		}
+		return exitCodeSuccess
+	}
+
+	if len(anomalies) == 0 {
+		fmt.Println("No anomalies found")
+	}
+	for _, anomaly := range anomalies {
+		fmt.Printf("%s: %s\n", anomaly.Kind, anomaly.Message)
+	}
+	return exitCodeSuccess
+}
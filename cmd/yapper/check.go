@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// roundViolations are the constraints broken by the pairings of a round, which has no date for a bare pairings file.
+type roundViolations struct {
+	Date       string             `json:"date,omitempty"`
+	Violations []yapper.Violation `json:"violations"`
+}
+
+// executeCheck validates pairings, e.g. after swapping people by hand, against the constraints of the config.
+// The exit code is an error if any constraint is violated.
+func executeCheck(args []string) int {
+	cmd := newFlagSet("yapper check")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToPlan := cmd.String("plan", "", "Path to pairings written by generate with -format json, checking every round.")
+	pathToPairings := cmd.String("pairings", "", "Path to a JSON array of pairs of IDs, instead of a plan.")
+	date := cmd.String("date", "", "Date of the round of the -pairings, in the format 2006-01-02, to also check cadences, absences, holidays and pins.")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors and violations.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	if (*pathToPlan == "") == (*pathToPairings == "") {
+		fmt.Fprintln(os.Stderr, "Either a plan or a pairings file is required")
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	var rounds []yapper.Pairings
+	if *pathToPlan != "" {
+		plan, err := readPlan(*pathToPlan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading plan: %v\n", err)
+			return artifactExitCode(err)
+		}
+
+		for _, round := range plan.Rounds {
+			roundDate, err := time.Parse(time.DateOnly, round.Date)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing round date: %v\n", err)
+				return exitCodeError
+			}
+
+			pairs := make([][2]yapper.ID, 0, len(round.Pairings))
+			for _, pairing := range round.Pairings {
+				pairs = append(pairs, pairing.People)
+			}
+			rounds = append(rounds, yapper.NewPairings(roundDate, pairs))
+		}
+	} else {
+		if err := verifyArtifact(*pathToPairings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading pairings: %v\n", err)
+			return artifactExitCode(err)
+		}
+		pairings, err := yapper.NewPairingsFromFile(*pathToPairings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading pairings: %v\n", err)
+			return exitCodeError
+		}
+
+		var roundDate time.Time
+		if *date != "" {
+			if roundDate, err = time.Parse(time.DateOnly, *date); err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing date: %v\n", err)
+				return exitCodeInvalidArguments
+			}
+		}
+
+		pairs := [][2]yapper.ID{}
+		for id1, id2 := range pairings.All() {
+			pairs = append(pairs, [2]yapper.ID{id1, id2})
+		}
+		rounds = append(rounds, yapper.NewPairings(roundDate, pairs))
+	}
+
+	output := []roundViolations{}
+	violated := false
+	for _, pairings := range rounds {
+		round := roundViolations{Violations: yapper.ValidatePairings(config, pairings)}
+		if !pairings.Date().IsZero() {
+			round.Date = pairings.Date().Format(time.DateOnly)
+		}
+		violated = violated || len(round.Violations) > 0
+		output = append(output, round)
+	}
+
+	if *format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing violations: %v\n", err)
+			return exitCodeError
+		}
+	} else {
+		for _, round := range output {
+			for _, violation := range round.Violations {
+				if round.Date == "" {
+					fmt.Printf("%s: %s\n", violation.Kind, violation.Message)
+				} else {
+					fmt.Printf("%s %s: %s\n", round.Date, violation.Kind, violation.Message)
+				}
+			}
+		}
+	}
+
+	if violated {
+		return exitCodeError
+	}
+
+	infof(*quiet, "No constraints are violated")
+	return exitCodeSuccess
+}
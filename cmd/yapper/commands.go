@@ -0,0 +1,299 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// command describes a subcommand for the help and shell completion.
+type command struct {
+	// name follows yapper on the command line, e.g. "history backfill".
+	name     string
+	summary  string
+	examples []string
+	run      func(args []string) int
+}
+
+// commands are every subcommand, in the order they are listed by help. They are set by init, as help and completion
+// refer to them.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"generate", "Generate pairings, the default when no subcommand is given.", []string{
+			"yapper generate -config config.json -history history.json",
+			"yapper generate -config config.json -weeks 8 -strategy planned -format json > plan.json",
+		}, executeGenerate},
+		{"check", "Check pairings edited by hand against the constraints of the config.", []string{
+			"yapper check -config config.json -plan plan.json",
+			"yapper check -config config.json -pairings pairings.json -date 2025-08-04",
+		}, executeCheck},
+		{"decline", "Record that a pair declined to meet and re-match them.", []string{
+			"yapper decline -config config.json -plan plan.json -people Mario,Luigi",
+		}, executeDecline},
+		{"event", "Generate several short rounds, or tables, for a one-off event.", []string{
+			"yapper event -config config.json -date 2025-08-07 -rounds 4",
+			"yapper event -config config.json -date 2025-08-07 -rounds 5 -table-size 4",
+		}, executeEvent},
+		{"evaluate", "Compare the strategies against the recorded history.", []string{
+			"yapper evaluate -config config.json -history history.json",
+		}, executeEvaluate},
+		{"feed", "Write the meeting graph as nodes and links JSON for dashboards.", []string{
+			"yapper feed -config config.json -history history.json -half-life 720h > meetings.json",
+		}, executeFeed},
+		{"anomalies", "Report unusual patterns in the history, which are usually a sign the constraints need tuning.", []string{
+			"yapper anomalies -config config.json -history history.json",
+		}, executeAnomalies},
+		{"frequency", "Compare the cadence of each person with how often they met in the history.", []string{
+			"yapper frequency -config config.json -history history.json",
+			"yapper frequency -config config.json -history history.json -since 2025-01-06 -under-served",
+		}, executeFrequency},
+		{"suggest", "Suggest changes to the config, such as redundant deny list entries or squads too small to be matched.", []string{
+			"yapper suggest -config config.json -history history.json",
+			"yapper suggest -config config.json -history history.json -small-squad 3 -format json",
+		}, executeSuggest},
+		{"graph", "Write the pairs the constraints permit as text, JSON or Graphviz DOT.", []string{
+			"yapper graph -config config.json -valid-only -format dot | dot -Tsvg > pairs.svg",
+		}, executeGraph},
+		{"deny-lists", "Report the exclusions of the deny lists, or make them symmetric.", []string{
+			"yapper deny-lists -config config.json -write",
+		}, executeDenyLists},
+		{"absences import", "Replace the absences in the config with those from a calendar or CSV file.", []string{
+			"yapper absences import -config config.json hr-export.csv",
+			"yapper absences import -config config.json -person Mario https://calendar.example.com/mario.ics",
+		}, executeAbsencesImport},
+		{"history backfill", "Record past meetings from a CSV file in the history.", []string{
+			"yapper history backfill -history history.json past-meetings.csv",
+		}, executeHistoryBackfill},
+		{"history snapshot", "Save a copy of the history.", []string{
+			"yapper history snapshot -history history.json",
+		}, executeHistorySnapshot},
+		{"history snapshots", "List the snapshots of the history.", []string{
+			"yapper history snapshots -history history.json",
+		}, executeHistorySnapshots},
+		{"history restore", "Restore the history from a snapshot.", []string{
+			"yapper history restore -history history.json latest",
+		}, executeHistoryRestore},
+		{"history journal", "Start appending the changes to the history to a journal.", []string{
+			"yapper history journal -history history.json",
+		}, executeHistoryJournal},
+		{"history compact", "Write the journal into the history file.", []string{
+			"yapper history compact -history history.json -indent 2",
+		}, executeHistoryCompact},
+		{"history state", "Show the last run, last round, pending round and unpaired counts kept in the history.", []string{
+			"yapper history state -history history.json",
+		}, executeHistoryState},
+		{"notify", "Message each pair of the current round through Slack or email.", []string{
+			"yapper notify -config config.json -plan plan.json",
+			"yapper notify -config config.json -plan plan.json -dry-run",
+		}, executeNotify},
+		{"schedule", "Show the upcoming matches of a person, or write them as a calendar.", []string{
+			"yapper schedule -plan plan.json -person Mario -format ics > mario.ics",
+		}, executeSchedule},
+		{"serve", "Serve the dashboard, participant portal and REST API.", []string{
+			"yapper serve -config config.json -history history.json -addr localhost:8080",
+			"yapper serve -tenants tenants.json",
+			"yapper serve -config config.json -history /shared/history.json -leader-election",
+		}, executeServe},
+		{"token create", "Create an API token for an integration.", []string{
+			"yapper token create -tokens tokens.json -name chat-bot -scopes pairings:read,meetings:write",
+		}, executeTokenCreate},
+		{"token list", "List the API tokens.", []string{
+			"yapper token list -tokens tokens.json",
+		}, executeTokenList},
+		{"token revoke", "Revoke an API token.", []string{
+			"yapper token revoke -tokens tokens.json chat-bot",
+		}, executeTokenRevoke},
+		{"key create", "Create a key pair for signing and verifying the history, plans and pairings files.", []string{
+			"yapper key create -private signing.pem -public verify.pem",
+		}, executeKeyCreate},
+		{"sign", "Sign files such as plans written by generate.", []string{
+			"yapper sign -key signing.pem plan.json",
+		}, executeSign},
+		{"verify", "Verify the signatures of files, failing if any was changed since it was signed.", []string{
+			"yapper verify -key verify.pem history.json plan.json",
+		}, executeVerify},
+		{"schema", "Write the JSON Schema of a file format, or validate a file against it.", []string{
+			"yapper schema config > config.schema.json",
+			"yapper schema config config.json",
+		}, executeSchema},
+		{"init", "Create a starter config and empty history for a new program.", []string{
+			"yapper init",
+			"yapper init -people Mario=plumbers,Luigi=plumbers,Peach=royals -cadence two-weeks",
+		}, executeInit},
+		{"doctor", "Check the config, history, notifier and sign in before the first run.", []string{
+			"yapper doctor -config config.json -history history.json -provider slack",
+		}, executeDoctor},
+		{"version", "Show the version, commit and supported file versions.", []string{
+			"yapper version -format json",
+		}, executeVersion},
+		{"completion", "Write the shell completion script for bash, zsh or fish.", []string{
+			"source <(yapper completion bash)",
+			"yapper completion fish > ~/.config/fish/completions/yapper.fish",
+		}, executeCompletion},
+		{"help", "Show the commands, or the flags and examples of one.", []string{
+			"yapper help history backfill",
+		}, executeHelp},
+	}
+}
+
+// findCommand returns the command named by the leading arguments and the arguments following its name.
+func findCommand(args []string) (command, []string, bool) {
+	for _, c := range commands {
+		words := strings.Fields(c.name)
+		if len(args) >= len(words) && slices.Equal(args[:len(words)], words) {
+			return c, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+// flagSetCreated is called with each flag set made by newFlagSet if it is set, so completion can find the flags of a
+// command by running it with -h.
+var flagSetCreated func(cmd *flag.FlagSet)
+
+// newFlagSet returns the flag set of the command with the name, whose usage shows the summary and examples of the
+// command after its flags.
+func newFlagSet(name string) *flag.FlagSet {
+	cmd := flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd.Usage = func() {
+		fmt.Fprintf(cmd.Output(), "Usage: %s [flags]\n", name)
+		cmd.PrintDefaults()
+		printExamples(cmd)
+	}
+	if flagSetCreated != nil {
+		flagSetCreated(cmd)
+	}
+	return cmd
+}
+
+// printExamples writes the summary and examples of the command of the flag set, if there are any, to its output.
+func printExamples(cmd *flag.FlagSet) {
+	c, _, found := findCommand(strings.Fields(strings.TrimPrefix(cmd.Name(), "yapper ")))
+	if !found {
+		return
+	}
+
+	fmt.Fprintf(cmd.Output(), "\n%s\n", c.summary)
+	if len(c.examples) > 0 {
+		fmt.Fprintln(cmd.Output(), "\nExamples:")
+		for _, example := range c.examples {
+			fmt.Fprintf(cmd.Output(), "  %s\n", example)
+		}
+	}
+}
+
+// parseFlags parses the arguments of the command, returning false with the exit code if the command should stop. The
+// help requested with -h exits successfully, while other errors are reported as invalid arguments.
+func parseFlags(cmd *flag.FlagSet, args []string) (int, bool) {
+	err := cmd.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitCodeSuccess, false
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing arguments: %v\n", err)
+		return exitCodeInvalidArguments, false
+	}
+	return exitCodeSuccess, true
+}
+
+// executeHelp lists the commands, or shows the usage of the command named by the arguments.
+func executeHelp(args []string) int {
+	cmd := newFlagSet("yapper help")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if cmd.NArg() > 0 {
+		c, _, found := findCommand(cmd.Args())
+		if !found {
+			fmt.Fprintf(os.Stderr, "Unexpected command: %s\n", strings.Join(cmd.Args(), " "))
+			return exitCodeInvalidArguments
+		}
+		c.run([]string{"-h"})
+		return exitCodeSuccess
+	}
+
+	fmt.Println("Usage: yapper <command> [flags]")
+	fmt.Println("\nCommands:")
+	for _, c := range commands {
+		fmt.Printf("  %-18s %s\n", c.name, c.summary)
+	}
+	fmt.Println("\nRun yapper help <command> for the flags and examples of a command.")
+	return exitCodeSuccess
+}
+
+// commandFlags returns the flags of the command, found by running it with -h while discarding its usage.
+func commandFlags(c command) []string {
+	var cmd *flag.FlagSet
+	flagSetCreated = func(created *flag.FlagSet) {
+		created.SetOutput(io.Discard)
+		cmd = created
+	}
+	c.run([]string{"-h"})
+	flagSetCreated = nil
+
+	var flags []string
+	if cmd != nil {
+		cmd.VisitAll(func(f *flag.Flag) {
+			flags = append(flags, "-"+f.Name)
+		})
+	}
+	return flags
+}
+
+// completions returns the candidates for the last of the words following yapper on the command line, which is the
+// word being completed. Nothing is returned where a file name or other value is expected.
+func completions(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current, preceding := words[len(words)-1], words[:len(words)-1]
+
+	helping := len(preceding) > 0 && preceding[0] == "help"
+	if helping {
+		preceding = preceding[1:]
+	}
+
+	c, _, found := findCommand(preceding)
+	if strings.HasPrefix(current, "-") {
+		if helping {
+			return nil
+		}
+		if !found {
+			c = commands[0]
+		}
+		return withPrefix(commandFlags(c), current)
+	}
+	if found {
+		return nil
+	}
+
+	// Complete the next word of the names of the commands starting with the preceding words.
+	prefix := strings.Join(preceding, " ")
+	var names []string
+	for _, c := range commands {
+		name, matches := strings.CutPrefix(c.name, prefix)
+		if prefix != "" && (!matches || !strings.HasPrefix(name, " ")) {
+			continue
+		}
+		if next := strings.Fields(name); len(next) > 0 && !slices.Contains(names, next[0]) {
+			names = append(names, next[0])
+		}
+	}
+	return withPrefix(names, current)
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	var matching []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matching = append(matching, candidate)
+		}
+	}
+	return matching
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// completionScripts are the completion scripts of each shell, which ask yapper __complete for the candidates.
+var completionScripts = map[string]string{
+	"bash": `_yapper() {
+	local IFS=$'\n'
+	COMPREPLY=($(yapper __complete "${COMP_WORDS[@]:1:COMP_CWORD}"))
+}
+complete -o default -F _yapper yapper
+`,
+	"zsh": `#compdef yapper
+_yapper() {
+	local -a candidates
+	candidates=("${(@f)$(yapper __complete "${(@)words[2,CURRENT]}")}")
+	if [[ -n "${candidates[1]}" ]]; then
+		compadd -a candidates
+	else
+		_files
+	fi
+}
+compdef _yapper yapper
+`,
+	"fish": `function __yapper_complete
+	set -l tokens (commandline -opc)
+	set -e tokens[1]
+	set -l current (commandline -ct)
+	yapper __complete $tokens "$current"
+end
+complete -c yapper -a '(__yapper_complete)'
+`,
+}
+
+// executeCompletion writes the completion script of the shell named by the argument.
+func executeCompletion(args []string) int {
+	cmd := newFlagSet("yapper completion")
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "Usage: yapper completion <bash|zsh|fish>")
+		printExamples(cmd)
+	}
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if cmd.NArg() != 1 {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+
+	script, exists := completionScripts[cmd.Arg(0)]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Unexpected shell: %s\n", cmd.Arg(0))
+		return exitCodeInvalidArguments
+	}
+	fmt.Print(script)
+	return exitCodeSuccess
+}
+
+// executeComplete writes the candidates for the last of the arguments, one per line, for the completion scripts.
+func executeComplete(args []string) int {
+	for _, candidate := range completions(args) {
+		fmt.Println(candidate)
+	}
+	return exitCodeSuccess
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// executeDecline removes a pair which declined to meet from a plan and re-matches them with anyone else without a
+// partner in the round, recording the new pairs in the plan and history and optionally notifying them.
+func executeDecline(args []string) int {
+	cmd := newFlagSet("yapper decline")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToPlan := cmd.String("plan", "plan.json", "Path to pairings written by generate with -format json. The updated plan will be written to this file as well.")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file. The new pairs will be written to this file as well.")
+	people := cmd.String("people", "", "Comma separated IDs of the two people who declined to meet.")
+	round := cmd.String("round", "", "Date of the round the pair declined, in the format 2006-01-02. Defaults to the current round of the plan.")
+	providerOptions := addProviderFlags(cmd, "", true)
+	templateOptions := addTemplateFlags(cmd)
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	strict := addStrictFlag(cmd)
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	ids := splitList(*people)
+	if len(ids) != 2 || ids[0] == ids[1] {
+		fmt.Fprintln(os.Stderr, "Two different people are required")
+		return exitCodeInvalidArguments
+	}
+	declined := [2]yapper.ID{yapper.ID(ids[0]), yapper.ID(ids[1])}
+
+	providers, err := providerOptions.providers(true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error choosing provider: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	tmpl, err := templateOptions.template()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing template: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	plan, err := readPlan(*pathToPlan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading plan: %v\n", err)
+		return artifactExitCode(err)
+	}
+
+	index, err := findRound(plan, *round)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding round: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+	planRound := &plan.Rounds[index]
+
+	pairs := make([][2]yapper.ID, 0, len(planRound.Pairings))
+	for _, pairing := range planRound.Pairings {
+		pairs = append(pairs, pairing.People)
+	}
+	if !containsPeople(pairs, declined) {
+		fmt.Fprintf(os.Stderr, "%s and %s are not paired in the round starting %s\n", declined[0], declined[1], planRound.Date)
+		return exitCodeInvalidArguments
+	}
+
+	date, err := time.Parse(time.DateOnly, planRound.Date)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing round date: %v\n", err)
+		return exitCodeError
+	}
+
+	unlock, err := lockHistory(*pathToHistory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locking history: %v\n", err)
+		return historyExitCode(err)
+	}
+	defer unlock()
+
+	hist, err := getHistoryFromFile(*pathToHistory, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	if err := crossValidate(config, hist, *strict, *quiet); err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating history against config: %v\n", err)
+		return exitCodeError
+	}
+
+	program := hist.Namespace(config.Namespace)
+	planRound.Declined = append(planRound.Declined, declined)
+	pairings := yapper.NewPairings(date, pairs)
+	rematched := pairings.Rematch(config, *program, planRound.Declined)
+
+	kept := []pairingOutput{}
+	for _, pairing := range planRound.Pairings {
+		if !containsPeople(planRound.Declined, pairing.People) {
+			kept = append(kept, pairing)
+		}
+	}
+	for _, pair := range rematched {
+		kept = append(kept, newPairingOutput(config, pairings, pair[0], pair[1]))
+		program.AddMeeting(history.ID(pair[0]), history.ID(pair[1]), date)
+		if initiator, designated := pairings.Initiator(pair[0], pair[1]); designated {
+			program.AddInitiator(history.ID(initiator))
+		}
+		if topic := pairings.Topic(pair[0], pair[1]); topic != "" {
+			program.AddTopic(history.ID(pair[0]), history.ID(pair[1]), topic)
+		}
+		infof(*quiet, "Re-matched %s & %s", pair[0], pair[1])
+	}
+	planRound.Pairings = kept
+
+	if len(rematched) == 0 {
+		infof(*quiet, "No one could be re-matched with %s or %s", declined[0], declined[1])
+	}
+
+	if err := writePlan(plan, *pathToPlan); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing updated plan: %v\n", err)
+		return exitCodeError
+	}
+
+	if err := writeHistoryToFile(hist, *pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing updated history to file: %s, %v\n", *pathToHistory, err)
+		return exitCodeError
+	}
+
+	if len(providers) > 0 && len(rematched) > 0 {
+		if _, err := notifyPairs(providers, config, *planRound, rematched, messageTemplate{template: tmpl, hist: *program}, false, *quiet); err != nil {
+			fmt.Fprintf(os.Stderr, "Error notifying new pairs: %v\n", err)
+			return notifyExitCode(err)
+		}
+	}
+
+	return exitCodeSuccess
+}
+
+func writePlan(plan generateOutput, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %w", path, err)
+	}
+
+	if err := writeJSON(file, plan); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing plan %s: %w", path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing plan %s: %w", path, err)
+	}
+	return signArtifact(path)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// executeDenyLists reports the exclusions of the deny lists and deny groups, optionally adding the missing reciprocal
+// entries to the deny lists of the config file.
+func executeDenyLists(args []string) int {
+	cmd := newFlagSet("yapper deny-lists")
+	pathsToConfig := addConfigFlag(cmd)
+	write := cmd.Bool("write", false, "Rewrite the config file so everyone on a deny list also lists the person who listed them.")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors and the exclusions.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	if *write && len(*pathsToConfig) != 1 {
+		fmt.Fprintln(os.Stderr, "A single config file is required to rewrite its deny lists")
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	exclusions := config.Exclusions()
+	if *format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(exclusions)
+	} else {
+		table := [][]string{{"PERSON", "EXCLUDES", "MUTUAL"}}
+		for _, exclusion := range exclusions {
+			mutual := "no"
+			if exclusion.Mutual {
+				mutual = "yes"
+			}
+			table = append(table, []string{string(exclusion.People[0]), string(exclusion.People[1]), mutual})
+		}
+
+		var sb strings.Builder
+		writeTable(&sb, table, func(row, column int, cell string) string { return cell })
+		_, err = os.Stdout.WriteString(sb.String())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing exclusions: %v\n", err)
+		return exitCodeError
+	}
+
+	if !*write {
+		return exitCodeSuccess
+	}
+
+	normalized, added := config.NormalizeDenyLists()
+	if added == 0 {
+		infof(*quiet, "The deny lists are already reciprocal")
+		return exitCodeSuccess
+	}
+
+	if err := writeConfigToFile(normalized, (*pathsToConfig)[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Added %d reciprocal deny list entries to %s", added, (*pathsToConfig)[0])
+	return exitCodeSuccess
+}
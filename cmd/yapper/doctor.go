@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/notify"
+	"github.com/AleksaSvitlica/yapper/server"
+)
+
+// checkStatus is the outcome of one of the checks of the doctor command.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "PASS"
+	checkWarn checkStatus = "WARN"
+	checkFail checkStatus = "FAIL"
+	checkSkip checkStatus = "SKIP"
+)
+
+// doctorReport prints the outcome of each check as it is made, counting the outcomes for the summary.
+type doctorReport struct {
+	counts map[checkStatus]int
+}
+
+func (r *doctorReport) add(status checkStatus, name, format string, a ...any) {
+	if r.counts == nil {
+		r.counts = map[checkStatus]int{}
+	}
+	r.counts[status]++
+	fmt.Printf("%s %s: %s\n", status, name, fmt.Sprintf(format, a...))
+}
+
+// summary returns how many checks had each outcome.
+func (r *doctorReport) summary() string {
+	return fmt.Sprintf("%d passed, %d warned, %d failed, %d skipped", r.counts[checkPass], r.counts[checkWarn], r.counts[checkFail], r.counts[checkSkip])
+}
+
+// executeDoctor checks the config, history, notifier and OpenID Connect provider, so a setup can be verified before
+// its first real run. The exit code is an error if any check fails.
+func executeDoctor(args []string) int {
+	cmd := newFlagSet("yapper doctor")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file.")
+	providerOptions := addProviderFlags(cmd, "", false)
+	oidcIssuer := cmd.String("oidc-issuer", "", "URL of the OpenID Connect provider serve will require signing in with, to check it can be reached.")
+	pathToTokens := cmd.String("tokens", "", "Path to the API tokens file serve will use, to check it can be read.")
+	timeout := cmd.Duration("timeout", 10*time.Second, "Time to wait for each of the notifier and OpenID Connect provider to respond.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	report := doctorReport{}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	configValid := err == nil
+	if configValid {
+		report.add(checkPass, "config", "%d people in %s", len(config.People), pathsToConfig)
+	} else {
+		report.add(checkFail, "config", "%v", err)
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	historyRead := err == nil
+	if historyRead {
+		report.add(checkPass, "history", "read %s", *pathToHistory)
+	} else if errors.Is(err, os.ErrNotExist) {
+		report.add(checkPass, "history", "%s does not exist yet and will be created by the first run", *pathToHistory)
+	} else {
+		report.add(checkFail, "history", "%v", err)
+	}
+
+	if err := history.CheckWrite(context.Background(), *pathToHistory); errors.Is(err, history.ErrReadOnly) {
+		report.add(checkWarn, "history writable", "%v, only commands run with -read-only and reports will work", err)
+	} else if err != nil {
+		report.add(checkFail, "history writable", "%v", err)
+	} else {
+		report.add(checkPass, "history writable", "%s can be written", *pathToHistory)
+	}
+
+	if !configValid || !historyRead {
+		report.add(checkSkip, "people", "requires a valid config and history")
+	} else if mismatch := yapper.CrossValidate(config, *hist.Namespace(config.Namespace)); !mismatch.Empty() {
+		report.add(checkWarn, "people", "the config and history have different people, %s", mismatch)
+	} else {
+		report.add(checkPass, "people", "the config and history have the same people")
+	}
+
+	if *providerOptions.name == "" {
+		report.add(checkSkip, "notifier", "no -provider given")
+	} else if provider, err := providerOptions.provider(true); err != nil {
+		report.add(checkFail, "notifier", "%v", err)
+	} else {
+		if configValid {
+			var unreachable []string
+			for _, person := range config.People {
+				if provider.Address(person) == "" {
+					unreachable = append(unreachable, string(person.ID))
+				}
+			}
+			if len(unreachable) > 0 {
+				report.add(checkWarn, "notifier addresses", "no address for %s: %s", provider.Name(), strings.Join(unreachable, ", "))
+			} else {
+				report.add(checkPass, "notifier addresses", "everyone has an address for %s", provider.Name())
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		err := notify.Check(ctx, provider)
+		cancel()
+		if errors.Is(err, errors.ErrUnsupported) {
+			report.add(checkSkip, "notifier", "%v", err)
+		} else if err != nil {
+			report.add(checkFail, "notifier", "%v", err)
+		} else {
+			report.add(checkPass, "notifier", "authenticated with %s", provider.Name())
+		}
+	}
+
+	if *oidcIssuer == "" {
+		report.add(checkSkip, "OpenID Connect", "no -oidc-issuer given")
+	} else if os.Getenv(sessionSecretEnv) == "" {
+		report.add(checkFail, "OpenID Connect", "a session secret is required in %s", sessionSecretEnv)
+	} else if err := server.CheckOIDC(server.OIDCConfig{Issuer: *oidcIssuer, Client: &http.Client{Timeout: *timeout}}); err != nil {
+		report.add(checkFail, "OpenID Connect", "%v", err)
+	} else {
+		report.add(checkPass, "OpenID Connect", "discovered %s", *oidcIssuer)
+	}
+
+	if *pathToTokens == "" {
+		report.add(checkSkip, "API tokens", "no -tokens given")
+	} else if tokens, err := server.LoadAPITokens(*pathToTokens); err != nil {
+		report.add(checkFail, "API tokens", "%v", err)
+	} else {
+		report.add(checkPass, "API tokens", "%d tokens in %s", len(tokens), *pathToTokens)
+	}
+
+	fmt.Println(report.summary())
+	if report.counts[checkFail] > 0 {
+		return exitCodeError
+	}
+	return exitCodeSuccess
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// evaluateOutput compares the recorded rounds with those each strategy would have produced for the same dates.
+type evaluateOutput struct {
+	Recorded   yapper.Metrics                     `json:"recorded"`
+	Strategies map[yapper.Strategy]yapper.Metrics `json:"strategies"`
+}
+
+// executeEvaluate replays the dates of the recorded history with each strategy and compares the metrics of the results.
+func executeEvaluate(args []string) int {
+	cmd := newFlagSet("yapper evaluate")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to the yapper history file to replay.")
+	strategies := cmd.String("strategies", "greedy,planned", "Comma separated strategies to compare with the recorded history.")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	strict := addStrictFlag(cmd)
+	profiles := addProfileFlags(cmd)
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	stopProfiling, err := profiles.start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error profiling: %v\n", err)
+		return exitCodeError
+	}
+	defer stopProfiling()
+
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	var compared []yapper.Strategy
+	for _, strategy := range splitList(*strategies) {
+		if !slices.Contains(yapper.Strategies, yapper.Strategy(strategy)) {
+			fmt.Fprintf(os.Stderr, "Unexpected strategy: %s\n", strategy)
+			return exitCodeInvalidArguments
+		}
+		compared = append(compared, yapper.Strategy(strategy))
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	if err := crossValidate(config, hist, *strict, false); err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating history against config: %v\n", err)
+		return exitCodeError
+	}
+
+	recorded := yapper.RecordedRounds(config, *hist.Namespace(config.Namespace))
+	if len(recorded) == 0 {
+		fmt.Fprintln(os.Stderr, "The history has no meetings of the people in the config to replay")
+		return exitCodeError
+	}
+
+	dates := make([]time.Time, 0, len(recorded))
+	for _, round := range recorded {
+		dates = append(dates, round.Date())
+	}
+
+	output := evaluateOutput{Recorded: yapper.Evaluate(config, recorded), Strategies: map[yapper.Strategy]yapper.Metrics{}}
+	for _, strategy := range compared {
+		rounds, err := yapper.Replay(config, strategy, dates)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error replaying with %s strategy: %v\n", strategy, err)
+			return exitCodeError
+		}
+		output.Strategies[strategy] = yapper.Evaluate(config, rounds)
+	}
+
+	if *format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(output)
+	} else {
+		err = writeEvaluation(os.Stdout, output, compared)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing evaluation: %v\n", err)
+		return exitCodeError
+	}
+
+	return exitCodeSuccess
+}
+
+// writeEvaluation renders a table with a column of metrics for the recorded history and each strategy.
+func writeEvaluation(writer io.Writer, output evaluateOutput, strategies []yapper.Strategy) error {
+	columns := []yapper.Metrics{output.Recorded}
+	table := [][]string{{"METRIC", "RECORDED"}}
+	for _, strategy := range strategies {
+		columns = append(columns, output.Strategies[strategy])
+		table[0] = append(table[0], strings.ToUpper(string(strategy)))
+	}
+
+	rows := []struct {
+		name  string
+		value func(yapper.Metrics) string
+	}{
+		{"Rounds", func(m yapper.Metrics) string { return fmt.Sprint(m.Rounds) }},
+		{"Pairings", func(m yapper.Metrics) string { return fmt.Sprint(m.Pairings) }},
+		{"Unique pairs", func(m yapper.Metrics) string { return fmt.Sprint(m.UniquePairs) }},
+		{"Repeats", func(m yapper.Metrics) string { return fmt.Sprint(m.Repeats) }},
+		{"Coverage", func(m yapper.Metrics) string { return fmt.Sprintf("%.1f%%", 100*m.Coverage) }},
+		{"Unpaired", func(m yapper.Metrics) string { return fmt.Sprint(m.Unpaired) }},
+		{"Penalty", func(m yapper.Metrics) string { return fmt.Sprintf("%.2f", m.Penalty) }},
+	}
+	for _, row := range rows {
+		cells := []string{row.name}
+		for _, metrics := range columns {
+			cells = append(cells, row.value(metrics))
+		}
+		table = append(table, cells)
+	}
+
+	var sb strings.Builder
+	writeTable(&sb, table, func(row, column int, cell string) string { return cell })
+	sb.WriteString("\nThe history only keeps the last meeting of each pair, so earlier meetings of pairs who met again are missing from the recorded rounds.\n")
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// executeEvent generates the short rounds of a one-off event such as a mixer, optionally recording them in the history.
+func executeEvent(args []string) int {
+	cmd := newFlagSet("yapper event")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file, preferring people who have not met.")
+	date := cmd.String("date", "", "Date of the event, in the format 2006-01-02. Defaults to today.")
+	rounds := cmd.Int("rounds", 3, "Number of rounds during the event. Nobody meets the same person twice.")
+	tableSize := cmd.Int("table-size", 0, "Seat people at tables of at most this many people instead of in pairs, mixing the tables each round. Overrides the group size in the config file.")
+	record := cmd.Bool("record", false, "Record the meetings of every round in the history.")
+	format := cmd.String("format", formatText, "Output format, text, json or pdf for a printable sheet.")
+	noColor := cmd.Bool("no-color", false, "Disable coloured text output, which is otherwise used when writing to a terminal.")
+	strict := addStrictFlag(cmd)
+	profiles := addProfileFlags(cmd)
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	stopProfiling, err := profiles.start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error profiling: %v\n", err)
+		return exitCodeError
+	}
+	defer stopProfiling()
+
+	if *format != formatText && *format != formatJSON && *format != formatPDF {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	if *rounds < 1 {
+		fmt.Fprintf(os.Stderr, "An event needs at least one round: %d\n", *rounds)
+		return exitCodeInvalidArguments
+	}
+
+	if *tableSize < 0 || *tableSize == 1 {
+		fmt.Fprintf(os.Stderr, "Tables must seat at least two people: %d\n", *tableSize)
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	location, err := config.Location()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading timezone: %v\n", err)
+		return exitCodeError
+	}
+
+	eventDate := time.Now().In(location)
+	if *date != "" {
+		if eventDate, err = time.ParseInLocation(time.DateOnly, *date, location); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing date: %v\n", err)
+			return exitCodeInvalidArguments
+		}
+	}
+
+	if *record {
+		unlock, err := lockHistory(*pathToHistory)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locking history: %v\n", err)
+			return historyExitCode(err)
+		}
+		defer unlock()
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	if err := crossValidate(config, hist, *strict, false); err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating history against config: %v\n", err)
+		return exitCodeError
+	}
+
+	if *tableSize == 0 && config.GroupSize() > 2 {
+		*tableSize = config.GroupSize()
+	}
+
+	if *tableSize > 0 {
+		err = writeEventTables(config, hist.Namespace(config.Namespace), eventDate, *tableSize, *rounds, *format, *noColor)
+	} else {
+		err = writeEventPairings(config, hist.Namespace(config.Namespace), eventDate, *rounds, *format, *noColor)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating event: %v\n", err)
+		return exitCodeError
+	}
+
+	if *record {
+		if err := writeHistoryToFile(hist, *pathToHistory); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing updated history to file: %s, %v\n", *pathToHistory, err)
+			return exitCodeError
+		}
+	}
+
+	return exitCodeSuccess
+}
+
+// writeEventPairings generates the pairs of each round of the event and writes them as the generate command would.
+func writeEventPairings(config yapper.Config, hist *history.History, date time.Time, rounds int, format string, noColor bool) error {
+	eventRounds, err := yapper.GenerateEvent(config, hist, date, rounds)
+	if err != nil {
+		return err
+	}
+
+	// The rounds of an event all happen on the same day, so they are not named as weeks.
+	config.Settings.Interval = 1
+	output := newGenerateOutput(config, eventRounds)
+	switch format {
+	case formatJSON:
+		return writeJSON(os.Stdout, output)
+	case formatPDF:
+		return writePDF(os.Stdout, config, output)
+	}
+	return writeText(os.Stdout, config, output, useColor(os.Stdout, noColor))
+}
+
+// writeEventTables seats people at the tables of each round of the event and writes them as text or JSON.
+func writeEventTables(config yapper.Config, hist *history.History, date time.Time, tableSize, rounds int, format string, noColor bool) error {
+	tables, err := yapper.GenerateTables(config, hist, date, tableSize, rounds)
+	if err != nil {
+		return err
+	}
+
+	output := newTablesOutput(date, tables)
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	case formatPDF:
+		return writeTablesPDF(os.Stdout, output)
+	}
+	return writeTables(os.Stdout, output, useColor(os.Stdout, noColor))
+}
+
+// tablesOutput is the document describing the tables of each round of an event, written as JSON or rendered as text.
+type tablesOutput struct {
+	Date   string           `json:"date"`
+	Rounds [][]yapper.Table `json:"rounds"`
+}
+
+func newTablesOutput(date time.Time, rounds [][]yapper.Table) tablesOutput {
+	return tablesOutput{Date: date.Format(time.DateOnly), Rounds: rounds}
+}
+
+// writeTables renders the people at each table of each round, using colour if enabled.
+func writeTables(writer io.Writer, output tablesOutput, color bool) error {
+	paint := func(code, text string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+
+	var sb strings.Builder
+	for i, tables := range output.Rounds {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(paint(colorBold, fmt.Sprintf("Round %d (%s)", i, output.Date)) + "\n")
+
+		if len(tables) == 0 {
+			sb.WriteString(paint(colorDim, "  No tables") + "\n")
+		}
+
+		for j, table := range tables {
+			people := make([]string, 0, len(table))
+			for _, id := range table {
+				people = append(people, string(id))
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s\n", paint(colorCyan, fmt.Sprintf("Table %d:", j+1)), strings.Join(people, ", ")))
+		}
+	}
+
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/AleksaSvitlica/yapper/events"
+)
+
+const (
+	publisherNATS    = "nats"
+	publisherKafka   = "kafka"
+	publisherSQS     = "sqs"
+	publisherWebhook = "webhook"
+)
+
+// publisherFlags choose and configure the message queues and webhooks events are published to.
+type publisherFlags struct {
+	names       *string
+	source      *string
+	natsURL     *string
+	natsSubject *string
+	kafkaURL    *string
+	kafkaTopic  *string
+	sqsQueueURL *string
+	webhookURL  *string
+}
+
+// addPublisherFlags adds the flags of the message queues to the command, which publishes no events by default.
+func addPublisherFlags(cmd *flag.FlagSet) publisherFlags {
+	return publisherFlags{
+		names:       cmd.String("publish", "", "Comma separated message queues to publish a pairings.generated CloudEvent to after the history is written, nats, kafka, sqs or webhook."),
+		source:      cmd.String("event-source", events.DefaultSource, "Source of the CloudEvents, a URI reference identifying the deployment."),
+		natsURL:     cmd.String("nats-url", "nats://localhost:4222", "URL of the NATS server, which can include a user and password or a token."),
+		natsSubject: cmd.String("nats-subject", "yapper.pairings.generated", "NATS subject to publish the events on."),
+		kafkaURL:    cmd.String("kafka-url", "", "URL of the Kafka REST Proxy to produce the events through."),
+		kafkaTopic:  cmd.String("kafka-topic", "yapper.pairings.generated", "Kafka topic to produce the events to."),
+		sqsQueueURL: cmd.String("sqs-queue-url", "", "URL of the SQS queue to send the events to. The credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, and the region from AWS_REGION if it is not in the URL."),
+		webhookURL:  cmd.String("webhook-url", "", "URL to post the CloudEvents to, such as a Knative broker."),
+	}
+}
+
+// publishers returns each of the comma separated message queues and webhooks chosen, in order, or nil if none were chosen.
+func (f publisherFlags) publishers() ([]events.Publisher, error) {
+	var publishers []events.Publisher
+	for _, name := range splitList(*f.names) {
+		if slices.ContainsFunc(publishers, func(publisher events.Publisher) bool { return publisher.Name() == name }) {
+			return nil, fmt.Errorf("message queue given more than once: %s", name)
+		}
+
+		switch name {
+		case publisherNATS:
+			publishers = append(publishers, events.NATS{URL: *f.natsURL, Subject: *f.natsSubject})
+		case publisherKafka:
+			if *f.kafkaURL == "" {
+				return nil, fmt.Errorf("publishing to kafka requires -kafka-url")
+			}
+			publishers = append(publishers, events.Kafka{URL: *f.kafkaURL, Topic: *f.kafkaTopic})
+		case publisherSQS:
+			if *f.sqsQueueURL == "" {
+				return nil, fmt.Errorf("publishing to sqs requires -sqs-queue-url")
+			}
+			publishers = append(publishers, events.SQS{
+				QueueURL:        *f.sqsQueueURL,
+				Region:          os.Getenv("AWS_REGION"),
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			})
+		case publisherWebhook:
+			if *f.webhookURL == "" {
+				return nil, fmt.Errorf("publishing to a webhook requires -webhook-url")
+			}
+			publishers = append(publishers, events.Webhook{URL: *f.webhookURL})
+		default:
+			return nil, fmt.Errorf("unexpected message queue: %s", name)
+		}
+	}
+	return publishers, nil
+}
+
+// event returns the event with the source chosen for the events.
+func (f publisherFlags) event(event events.Event) events.Event {
+	event.Source = *f.source
+	return event
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// executeFeed writes the meeting graph of the history as nodes and links JSON, for building dashboards in
+// visualization tools.
+func executeFeed(args []string) int {
+	cmd := newFlagSet("yapper feed")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to the yapper history file the meetings are read from.")
+	halfLife := cmd.Duration("half-life", 90*24*time.Hour, "Time for the weight of a link to halve since the pair last met, or 0 to weight every link 1.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *halfLife < 0 {
+		fmt.Fprintln(os.Stderr, "The half life must not be negative")
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	graph := yapper.NewMeetingGraph(config, *hist.Namespace(config.Namespace), time.Now(), *halfLife)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(graph); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing meeting graph: %v\n", err)
+		return exitCodeError
+	}
+	return exitCodeSuccess
+}
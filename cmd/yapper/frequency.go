@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// executeFrequency compares the cadence of each person with how often they met in the recorded history, to find the
+// people the constraints or strategy under-serve.
+func executeFrequency(args []string) int {
+	cmd := newFlagSet("yapper frequency")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to the yapper history file to compare the cadences with.")
+	since := cmd.String("since", "", "Start of the period to compare, as YYYY-MM-DD. Defaults to the round of the earliest meeting in the history.")
+	until := cmd.String("until", "", "End of the period to compare, as YYYY-MM-DD. Defaults to the end of the round of the latest meeting in the history.")
+	tolerance := cmd.Float64("tolerance", 1.5, "How many times the weeks of their cadence someone can go between meetings on average before being under-served.")
+	underServed := cmd.Bool("under-served", false, "Only report the people who are under-served.")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	if *tolerance < 0 {
+		fmt.Fprintf(os.Stderr, "Tolerance cannot be negative: %v\n", *tolerance)
+		return exitCodeInvalidArguments
+	}
+
+	options := yapper.FrequencyOptions{Tolerance: *tolerance}
+	if *since != "" {
+		var err error
+		if options.Since, err = time.Parse(time.DateOnly, *since); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing since: %v\n", err)
+			return exitCodeInvalidArguments
+		}
+	}
+	if *until != "" {
+		var err error
+		if options.Until, err = time.Parse(time.DateOnly, *until); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing until: %v\n", err)
+			return exitCodeInvalidArguments
+		}
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	frequencies := yapper.EffectiveFrequencies(config, *hist.Namespace(config.Namespace), options)
+	if *underServed {
+		reported := []yapper.Frequency{}
+		for _, frequency := range frequencies {
+			if frequency.UnderServed {
+				reported = append(reported, frequency)
+			}
+		}
+		frequencies = reported
+	}
+
+	if *format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(frequencies)
+	} else {
+		err = writeFrequencies(os.Stdout, frequencies)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing frequencies: %v\n", err)
+		return exitCodeError
+	}
+	return exitCodeSuccess
+}
+
+// writeFrequencies renders a table of the configured and actual weeks between the meetings of each person, followed
+// by the messages of those who are under-served.
+func writeFrequencies(writer io.Writer, frequencies []yapper.Frequency) error {
+	if len(frequencies) == 0 {
+		_, err := io.WriteString(writer, "No frequencies to report\n")
+		return err
+	}
+
+	weeks := func(weeks float64) string { return strconv.FormatFloat(weeks, 'f', 1, 64) }
+	table := [][]string{{"PERSON", "CADENCE", "MEETINGS", "TARGET WEEKS", "ACTUAL WEEKS", "UNDER-SERVED"}}
+	var underServed []string
+	for _, frequency := range frequencies {
+		actual := "-"
+		if frequency.Meetings > 0 {
+			actual = weeks(frequency.ActualWeeks)
+		}
+		mark := ""
+		if frequency.UnderServed {
+			mark = "yes"
+			underServed = append(underServed, frequency.Message)
+		}
+		table = append(table, []string{string(frequency.Person), string(frequency.Cadence), strconv.Itoa(frequency.Meetings), weeks(frequency.TargetWeeks), actual, mark})
+	}
+
+	var sb strings.Builder
+	writeTable(&sb, table, func(row, column int, cell string) string { return cell })
+	if len(underServed) > 0 {
+		sb.WriteString("\nUnder-served\n")
+		for _, message := range underServed {
+			fmt.Fprintf(&sb, "  %s\n", message)
+		}
+	}
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/events"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// executeGenerate generates pairings for the people in the config and records them in the history.
+func executeGenerate(args []string) int {
+	cmd := newFlagSet("yapper generate")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file. The updated history will be written to this file as well.")
+	pathToPairings := cmd.String("pairings", "", "Path to the pairings file of the current round. If it exists its pairings are used instead of generating the round, and only recorded in the history once, otherwise the generated round is written to it.")
+	force := cmd.Bool("force", false, "Generate the current round even if an earlier run already generated it, recording a second set of pairings for the round.")
+	weeksOfPairings := cmd.Int("weeks", 1, "Number of rounds of pairings to generate, one per week unless an interval is set.")
+	interval := cmd.Int("interval", 0, "Number of days between rounds of pairings. Overrides the interval in the config file.")
+	strategy := cmd.String("strategy", "", "Strategy for choosing pairings, greedy, planned or exact. Overrides the strategy or strategies in the config file.")
+	maxDuration := cmd.Duration("max-duration", 0, "Longest the planned strategy searches for improvements, such as 10s, before using the best plan found so far. Overrides the max duration in the config file.")
+	format := cmd.String("format", formatText, "Output format, text, json or pdf for a printable sheet.")
+	noColor := cmd.Bool("no-color", false, "Disable coloured text output, which is otherwise used when writing to a terminal.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors, only updating the history.")
+	explain := cmd.Bool("explain", false, "Explain why each person left unpaired was not paired and why each skipped pin was skipped, listing the constraints which blocked them.")
+	validateSchema := cmd.Bool("validate", false, "Validate the config and history files against their schemas, reporting the line and field of any problems.")
+	strict := addStrictFlag(cmd)
+	profiles := addProfileFlags(cmd)
+	publisherOptions := addPublisherFlags(cmd)
+	readOnly := addReadOnlyFlag(cmd, "Only show the pairings, without ever locking or writing the history, the pairings file or publishing events, e.g. to preview a round from a history which cannot be written.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	stopProfiling, err := profiles.start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error profiling: %v\n", err)
+		return exitCodeError
+	}
+	defer stopProfiling()
+
+	publishers, err := publisherOptions.publishers()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring events: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	if *format != formatText && *format != formatJSON && *format != formatPDF {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	if *validateSchema {
+		for _, path := range *pathsToConfig {
+			if err := validateFile(path, anyPath(yapper.ValidateConfigSchema)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error validating config file: %v\n", err)
+				return exitCodeInvalidConfig
+			}
+		}
+
+		if _, err := os.Stat(*pathToHistory); err == nil {
+			if err := validateFile(*pathToHistory, history.ValidateFileSchema); err != nil {
+				fmt.Fprintf(os.Stderr, "Error validating history file: %v\n", err)
+				return exitCodeCorruptHistory
+			}
+		}
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	if *interval < 0 {
+		fmt.Fprintf(os.Stderr, "Interval must not be negative: %d\n", *interval)
+		return exitCodeInvalidArguments
+	} else if *interval > 0 {
+		config.Settings.Interval = *interval
+	}
+
+	if *maxDuration < 0 {
+		fmt.Fprintf(os.Stderr, "Max duration must not be negative: %s\n", *maxDuration)
+		return exitCodeInvalidArguments
+	} else if *maxDuration > 0 {
+		config.Settings.MaxDuration = maxDuration.String()
+	}
+
+	if *strategy != "" {
+		if !slices.Contains(yapper.Strategies, yapper.Strategy(*strategy)) {
+			fmt.Fprintf(os.Stderr, "Unexpected strategy: %s\n", *strategy)
+			return exitCodeInvalidArguments
+		}
+		config.Settings.Strategy = yapper.Strategy(*strategy)
+		config.Settings.Strategies = nil
+	}
+
+	if !*readOnly {
+		if err := checkHistoryWritable(*pathToHistory); err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking history: %v\n", err)
+			return historyExitCode(err)
+		}
+	}
+
+	unlock, err := lockHistory(*pathToHistory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locking history: %v\n", err)
+		return historyExitCode(err)
+	}
+	defer unlock()
+
+	hist, err := getHistoryFromFile(*pathToHistory, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	if err := crossValidate(config, hist, *strict, *quiet); err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating history against config: %v\n", err)
+		return exitCodeError
+	}
+
+	decided, found, err := readDecidedPairings(*pathToPairings, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading pairings: %v\n", err)
+		return artifactExitCode(err)
+	}
+
+	program := hist.Namespace(config.Namespace)
+	generated, reuse := generatedRound(config, *program)
+	reuse = reuse && !found && !*force
+	if reuse {
+		infof(*quiet, "Using the pairings of the round starting %s generated by an earlier run, use -force to generate it again", generated.Date().Format(time.DateOnly))
+	}
+
+	var weeklyPairings []yapper.Pairings
+	switch {
+	case found:
+		weeklyPairings, err = yapper.ContinuePairings(commandContext, config, program, decided, *weeksOfPairings)
+	case reuse:
+		weeklyPairings, err = yapper.ContinuePairings(commandContext, config, program, generated, *weeksOfPairings)
+	default:
+		weeklyPairings, err = yapper.GeneratePairingsContext(commandContext, config, program, *weeksOfPairings)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating pairings: %v\n", err)
+		return exitCodeError
+	}
+	if len(weeklyPairings) > 0 {
+		yapper.SetLastRound(config, program, weeklyPairings[0])
+	}
+	program.SetLastRun(history.Run{Time: time.Now(), Command: "generate", Version: newVersionOutput().Version})
+
+	if *pathToPairings != "" && !found && len(weeklyPairings) > 0 && !*readOnly {
+		if err := writePairingsToFile(weeklyPairings[0], *pathToPairings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing pairings: %v\n", err)
+			return exitCodeError
+		}
+	}
+
+	output := newGenerateOutput(config, weeklyPairings)
+	if *explain {
+		output.explain(config, weeklyPairings)
+	}
+	switch {
+	case *quiet:
+		// Only the history is updated.
+	case *format == formatJSON:
+		err = writeJSON(os.Stdout, output)
+	case *format == formatPDF:
+		err = writePDF(os.Stdout, config, output)
+	default:
+		err = writeText(os.Stdout, config, output, useColor(os.Stdout, *noColor))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing pairings: %v\n", err)
+		return exitCodeError
+	}
+
+	if *readOnly {
+		infof(*quiet, "The history is read only, so the pairings were not recorded")
+	} else if err := writeHistoryToFile(hist, *pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing updated history to file: %s, %v\n", *pathToHistory, err)
+		return exitCodeError
+	}
+
+	// A round reused from an earlier run was already published by it.
+	if len(publishers) > 0 && !reuse && !*readOnly {
+		event := publisherOptions.event(events.NewPairingsGenerated(config, weeklyPairings, time.Now()))
+		if err := events.Publish(commandContext, publishers, event); err != nil {
+			fmt.Fprintf(os.Stderr, "Error publishing events: %v\n", err)
+			return exitCodeError
+		}
+	}
+
+	for _, pairings := range weeklyPairings {
+		if available, below := config.BelowQuorum(pairings.Date()); below && config.Quorum.Policy != yapper.QuorumPolicyWarn {
+			infof(*quiet, "Skipped the round starting %s as only %d people can meet, fewer than the quorum of %d", pairings.Date().Format(time.DateOnly), available, config.Quorum.MinPeople)
+		}
+	}
+
+	if warnings := generationWarnings(config, weeklyPairings); len(warnings) > 0 {
+		for _, warning := range warnings {
+			infof(*quiet, "Warning: %s", warning)
+		}
+		return exitCodeWarnings
+	}
+
+	return exitCodeSuccess
+}
+
+// readDecidedPairings reads the pairings of the current round from the file at path, checking them against the
+// config. False is returned if there is no path or the file does not exist, and an error if the pairings are of
+// another round, so the pairings of an earlier round are not mistaken for the current one.
+func readDecidedPairings(path string, config yapper.Config) (yapper.Pairings, bool, error) {
+	if path == "" {
+		return yapper.Pairings{}, false, nil
+	}
+	if err := verifyArtifact(path); err != nil {
+		return yapper.Pairings{}, false, err
+	}
+
+	pairings, err := yapper.NewPairingsFromFile(path, config)
+	if errors.Is(err, os.ErrNotExist) {
+		return yapper.Pairings{}, false, nil
+	} else if err != nil {
+		return yapper.Pairings{}, false, err
+	}
+
+	location, err := config.Location()
+	if err != nil {
+		return yapper.Pairings{}, false, err
+	}
+	if current := config.RoundStart(time.Now().In(location)); !pairings.Date().IsZero() && !pairings.Date().Equal(current) {
+		return yapper.Pairings{}, false, fmt.Errorf("%s has the pairings of the round starting %s rather than the current round starting %s", path, pairings.Date().Format(time.DateOnly), current.Format(time.DateOnly))
+	}
+	return pairings, true, nil
+}
+
+// generatedRound returns the pairings of the current round if an earlier run generated them, so running generate again
+// within the round returns the same pairings rather than recording a second set.
+func generatedRound(config yapper.Config, hist history.History) (yapper.Pairings, bool) {
+	location, err := config.Location()
+	if err != nil {
+		return yapper.Pairings{}, false
+	}
+	pairings, found := yapper.LastRound(hist)
+	if !found || !pairings.Date().Equal(config.RoundStart(time.Now().In(location))) {
+		return yapper.Pairings{}, false
+	}
+	return pairings, true
+}
+
+// writePairingsToFile writes the pairings with their date, initiators and topics to the file at path, so a later run
+// can read them with NewPairingsFromFile, signing the file with YAPPER_SIGNING_KEY if it is set.
+func writePairingsToFile(pairings yapper.Pairings, path string) error {
+	data, err := json.MarshalIndent(&pairings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding pairings: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing pairings to file: %s, %w", path, err)
+	}
+	return signArtifact(path)
+}
+
+// generationWarnings describes the pins skipped in each round, and the people left unpaired who could have met, as
+// opposed to those who were paused, absent or otherwise unable to meet. A single person left over from an odd number
+// of people is expected, and is not warned about. Rounds below the quorum are warned about when the quorum policy is
+// warn.
+func generationWarnings(config yapper.Config, weeklyPairings []yapper.Pairings) []string {
+	var warnings []string
+	for _, round := range yapper.NewResult(config, weeklyPairings).Rounds {
+		if available, below := config.BelowQuorum(round.Date); below && config.Quorum.Policy == yapper.QuorumPolicyWarn {
+			warnings = append(warnings, fmt.Sprintf("only %d people can meet in the round starting %s, fewer than the quorum of %d", available, round.Date.Format(time.DateOnly), config.Quorum.MinPeople))
+		}
+
+		for _, skipped := range round.SkippedPins {
+			warnings = append(warnings, fmt.Sprintf("in the round starting %s, %s", round.Date.Format(time.DateOnly), skipped.Message))
+		}
+
+		var unexpected []yapper.Diagnostic
+		for _, diagnostic := range round.Diagnostics {
+			if diagnostic.Reason == "" {
+				unexpected = append(unexpected, diagnostic)
+			}
+		}
+		if len(unexpected) < 2 {
+			continue
+		}
+
+		for _, diagnostic := range unexpected {
+			warnings = append(warnings, fmt.Sprintf("%s was not paired in the round starting %s, %s", diagnostic.Person, round.Date.Format(time.DateOnly), diagnostic.Message))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+const formatDOT = "dot"
+
+// graphEdge is a pair of people, along with the constraint denying the pairing unless they can be paired.
+type graphEdge struct {
+	People [2]yapper.ID         `json:"people"`
+	Denial yapper.ViolationKind `json:"denial,omitempty"`
+}
+
+// executeGraph writes the graph of the pairs of people the constraints of the config permit or deny.
+func executeGraph(args []string) int {
+	cmd := newFlagSet("yapper graph")
+	pathsToConfig := addConfigFlag(cmd)
+	validOnly := cmd.Bool("valid-only", false, "Only include the pairs which can be paired, leaving out those denied by a constraint.")
+	format := cmd.String("format", formatText, "Output format, text, json or dot for Graphviz.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *format != formatText && *format != formatJSON && *format != formatDOT {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	edges := []graphEdge{}
+	for i, person := range config.People {
+		for _, other := range config.People[i+1:] {
+			edge := graphEdge{People: [2]yapper.ID{person.ID, other.ID}, Denial: config.Denial(person.ID, other.ID)}
+			if *validOnly && edge.Denial != "" {
+				continue
+			}
+			edges = append(edges, edge)
+		}
+	}
+
+	switch *format {
+	case formatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(edges)
+	case formatDOT:
+		err = writeDOT(os.Stdout, config, edges)
+	default:
+		err = writeGraph(os.Stdout, edges, *validOnly)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing graph: %v\n", err)
+		return exitCodeError
+	}
+
+	return exitCodeSuccess
+}
+
+// writeGraph renders a table of the pairs, with the constraint denying each unless only valid pairs are included.
+func writeGraph(writer io.Writer, edges []graphEdge, validOnly bool) error {
+	table := [][]string{{"PERSON", "OTHER"}}
+	if !validOnly {
+		table[0] = append(table[0], "DENIED BY")
+	}
+
+	for _, edge := range edges {
+		row := []string{string(edge.People[0]), string(edge.People[1])}
+		if !validOnly {
+			row = append(row, string(edge.Denial))
+		}
+		table = append(table, row)
+	}
+
+	var sb strings.Builder
+	writeTable(&sb, table, func(row, column int, cell string) string { return cell })
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+// writeDOT renders the graph in the Graphviz DOT language, with a node for everyone so people who cannot be paired
+// with anyone are still shown. Denied pairs are dashed and labelled with the constraint denying them.
+func writeDOT(writer io.Writer, config yapper.Config, edges []graphEdge) error {
+	var sb strings.Builder
+	sb.WriteString("graph yapper {\n")
+	for _, person := range config.People {
+		fmt.Fprintf(&sb, "  %q;\n", person.ID)
+	}
+	for _, edge := range edges {
+		if edge.Denial == "" {
+			fmt.Fprintf(&sb, "  %q -- %q;\n", edge.People[0], edge.People[1])
+		} else {
+			fmt.Fprintf(&sb, "  %q -- %q [style=dashed, label=%q];\n", edge.People[0], edge.People[1], edge.Denial)
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
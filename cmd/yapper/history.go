@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// executeHistory runs the history subcommand named by the first argument.
+func executeHistory(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Expected a history subcommand: backfill, snapshot, snapshots, restore, journal, compact or state")
+		return exitCodeInvalidArguments
+	}
+
+	switch args[0] {
+	case "backfill":
+		return executeHistoryBackfill(args[1:])
+	case "snapshot":
+		return executeHistorySnapshot(args[1:])
+	case "snapshots":
+		return executeHistorySnapshots(args[1:])
+	case "restore":
+		return executeHistoryRestore(args[1:])
+	case "journal":
+		return executeHistoryJournal(args[1:])
+	case "compact":
+		return executeHistoryCompact(args[1:])
+	case "state":
+		return executeHistoryState(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unexpected history subcommand: %s\n", args[0])
+		return exitCodeInvalidArguments
+	}
+}
+
+// executeHistoryBackfill records past meetings from a CSV file in the history.
+func executeHistoryBackfill(args []string) int {
+	cmd := newFlagSet("yapper history backfill")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file. The updated history will be written to this file as well.")
+	namespace := cmd.String("namespace", "", "Namespace of the history to record the meetings in, matching the namespace of a program's config.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "Usage: yapper history backfill [flags] file.csv")
+		fmt.Fprintln(cmd.Output(), "The CSV file has the columns person1, person2 and date, with dates as YYYY-MM-DD.")
+		cmd.PrintDefaults()
+		printExamples(cmd)
+	}
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if cmd.NArg() != 1 {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+	pathToBackfill := cmd.Arg(0)
+
+	unlock, err := lockHistory(*pathToHistory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locking history: %v\n", err)
+		return historyExitCode(err)
+	}
+	defer unlock()
+
+	hist, err := getHistoryFromFile(*pathToHistory, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	file, err := os.Open(pathToBackfill)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening backfill file: %v\n", err)
+		return exitCodeError
+	}
+	defer file.Close()
+
+	recorded, err := hist.Namespace(*namespace).Backfill(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error backfilling history from %s: %v\n", pathToBackfill, err)
+		return exitCodeError
+	}
+
+	if err := writeHistoryToFile(hist, *pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing updated history to file: %s, %v\n", *pathToHistory, err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Recorded %d meetings from %s", recorded, pathToBackfill)
+	return exitCodeSuccess
+}
+
+// executeHistorySnapshot keeps a timestamped copy of the history.
+func executeHistorySnapshot(args []string) int {
+	cmd := newFlagSet("yapper history snapshot")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file.")
+	snapshotDir := cmd.String("snapshots", "", "Directory of the history snapshots. Defaults to the history path with a .snapshots suffix.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if !requireHistoryFile(*pathToHistory) {
+		return exitCodeInvalidArguments
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	snapshot, err := hist.Snapshot(getSnapshotDir(*snapshotDir, *pathToHistory), time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating snapshot: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Created snapshot %s", snapshot.Name)
+	return exitCodeSuccess
+}
+
+// executeHistorySnapshots lists the snapshots of the history.
+func executeHistorySnapshots(args []string) int {
+	cmd := newFlagSet("yapper history snapshots")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file.")
+	snapshotDir := cmd.String("snapshots", "", "Directory of the history snapshots. Defaults to the history path with a .snapshots suffix.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if !requireHistoryFile(*pathToHistory) {
+		return exitCodeInvalidArguments
+	}
+
+	snapshots, err := history.ListSnapshots(getSnapshotDir(*snapshotDir, *pathToHistory))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing snapshots: %v\n", err)
+		return exitCodeError
+	}
+
+	for _, snapshot := range snapshots {
+		fmt.Printf("%s\t%s\n", snapshot.Name, snapshot.Time.Format(time.RFC3339))
+	}
+	return exitCodeSuccess
+}
+
+// executeHistoryRestore replaces the history with a snapshot, first taking a snapshot of the current history.
+func executeHistoryRestore(args []string) int {
+	cmd := newFlagSet("yapper history restore")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file. The restored history will be written to this file.")
+	snapshotDir := cmd.String("snapshots", "", "Directory of the history snapshots. Defaults to the history path with a .snapshots suffix.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "Usage: yapper history restore [flags] <snapshot name|latest>")
+		cmd.PrintDefaults()
+		printExamples(cmd)
+	}
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if !requireHistoryFile(*pathToHistory) {
+		return exitCodeInvalidArguments
+	}
+
+	if cmd.NArg() != 1 {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+
+	dir := getSnapshotDir(*snapshotDir, *pathToHistory)
+	snapshots, err := history.ListSnapshots(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing snapshots: %v\n", err)
+		return exitCodeError
+	}
+
+	var toRestore *history.Snapshot
+	for i, snapshot := range snapshots {
+		if snapshot.Name == cmd.Arg(0) || (cmd.Arg(0) == "latest" && i == len(snapshots)-1) {
+			toRestore = &snapshots[i]
+		}
+	}
+
+	if toRestore == nil {
+		fmt.Fprintf(os.Stderr, "No snapshot found: %s\n", cmd.Arg(0))
+		return exitCodeError
+	}
+
+	restored, err := history.LoadSnapshot(*toRestore)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return exitCodeError
+	}
+
+	unlock, err := lockHistory(*pathToHistory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locking history: %v\n", err)
+		return historyExitCode(err)
+	}
+	defer unlock()
+
+	current, err := getHistoryFromFile(*pathToHistory, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	backup, err := current.Snapshot(dir, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating snapshot of the current history: %v\n", err)
+		return exitCodeError
+	}
+
+	if err := writeHistoryToFile(restored, *pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing restored history to file: %s, %v\n", *pathToHistory, err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Restored snapshot %s, the previous history was kept as snapshot %s", toRestore.Name, backup.Name)
+	return exitCodeSuccess
+}
+
+// executeHistoryJournal starts a journal for the history, so changes are appended to it instead of rewriting the file.
+func executeHistoryJournal(args []string) int {
+	cmd := newFlagSet("yapper history journal")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if !requireHistoryFile(*pathToHistory) {
+		return exitCodeInvalidArguments
+	}
+
+	unlock, err := lockHistory(*pathToHistory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locking history: %v\n", err)
+		return historyExitCode(err)
+	}
+	defer unlock()
+
+	if err := history.EnableJournal(*pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error enabling the journal: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Changes to %s will be appended to %s", *pathToHistory, *pathToHistory+history.JournalSuffix)
+	return exitCodeSuccess
+}
+
+// executeHistoryCompact writes the changes in the journal of the history into the history file.
+func executeHistoryCompact(args []string) int {
+	cmd := newFlagSet("yapper history compact")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file.")
+	indentOption := cmd.String("indent", "", "Indentation to write the history with so it can be reviewed: a number of spaces, \"tab\", or \"none\" for a single line. Later writes keep the indentation. Defaults to the current indentation of the file.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if !requireHistoryFile(*pathToHistory) {
+		return exitCodeInvalidArguments
+	}
+
+	unlock, err := lockHistory(*pathToHistory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locking history: %v\n", err)
+		return historyExitCode(err)
+	}
+	defer unlock()
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	if *indentOption != "" {
+		indent, err := parseIndent(*indentOption)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing indent: %v\n", err)
+			return exitCodeInvalidArguments
+		}
+		hist.SetIndent(indent)
+	}
+
+	if err := hist.CompactFile(*pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error compacting history: %v\n", err)
+		return exitCodeError
+	}
+	if err := signArtifact(*pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error signing history: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Compacted the journal into %s", *pathToHistory)
+	return exitCodeSuccess
+}
+
+// parseIndent returns the indentation given by the -indent flag: a number of spaces, "tab" or "none".
+func parseIndent(option string) (string, error) {
+	switch option {
+	case "none":
+		return "", nil
+	case "tab":
+		return "\t", nil
+	}
+	spaces, err := strconv.Atoi(option)
+	if err != nil || spaces < 0 {
+		return "", fmt.Errorf("expected a number of spaces, \"tab\" or \"none\", got: %s", option)
+	}
+	return strings.Repeat(" ", spaces), nil
+}
+
+// getSnapshotDir returns the snapshot directory, defaulting to one next to the history file.
+func getSnapshotDir(snapshotDir, pathToHistory string) string {
+	if snapshotDir != "" {
+		return snapshotDir
+	}
+	return pathToHistory + ".snapshots"
+}
+
+// executeHistoryState shows what the history keeps about the runs of a program besides its meetings: the last run,
+// the last round generated, the round proposed by serve but not yet confirmed and who was left unpaired.
+func executeHistoryState(args []string) int {
+	cmd := newFlagSet("yapper history state")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file.")
+	namespace := cmd.String("namespace", "", "Namespace of the history to show the state of, matching the namespace of a program's config.")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	state := hist.Namespace(*namespace).State()
+	switch *format {
+	case formatText:
+		if run := state.LastRun; run != nil {
+			fmt.Printf("Last run: %s at %s", run.Command, run.Time.Format(time.RFC3339))
+			if run.Version != "" {
+				fmt.Printf(" with version %s", run.Version)
+			}
+			fmt.Println()
+		}
+		if round := state.LastRound; round != nil {
+			fmt.Printf("Last round: starting %s with %d pairs\n", round.Date.Format(time.DateOnly), len(round.Pairs))
+		}
+		if pending := state.Pending; pending != nil {
+			fmt.Printf("Pending round: starting %s with %d pairs, %d pinned and %d re-rolled, awaiting confirmation\n", pending.Round.Date.Format(time.DateOnly), len(pending.Round.Pairs), len(pending.Pins), len(pending.Rerolled))
+		}
+		for _, id := range slices.Sorted(maps.Keys(state.Unpaired)) {
+			fmt.Printf("Unpaired rounds: %s %d\n", id, state.Unpaired[id])
+		}
+	case formatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(state); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing state: %v\n", err)
+			return exitCodeError
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+	return exitCodeSuccess
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// initOptions describe the program created by the init command.
+type initOptions struct {
+	// people are the comma separated IDs of the people, each optionally followed by = and their squad.
+	people   string
+	cadence  string
+	notifier string
+}
+
+// executeInit creates a starter config and an empty history for a new program, asking for the people, cadence and
+// notifier unless they are given as flags.
+func executeInit(args []string) int {
+	cmd := newFlagSet("yapper init")
+	pathToConfig := cmd.String("config", "config.json", "Path to write the new config file to.")
+	pathToHistory := cmd.String("history", "history.json", "Path to write the new, empty history file to.")
+	people := cmd.String("people", "", "Comma separated IDs of the people, each optionally followed by = and their squad, e.g. Mario=plumbers,Peach=royals. Asked for if empty.")
+	cadence := cmd.String("cadence", "", "Default cadence of the people, one-week or two-weeks.")
+	notifier := cmd.String("notifier", "", "Provider the pairs will be notified through, slack or email, adding a placeholder address for each person to fill in.")
+	force := cmd.Bool("force", false, "Overwrite the config and history files if they exist.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if !requireHistoryFile(*pathToHistory) {
+		return exitCodeInvalidArguments
+	}
+
+	if !*force {
+		for _, path := range []string{*pathToConfig, *pathToHistory} {
+			if _, err := os.Stat(path); err == nil {
+				fmt.Fprintf(os.Stderr, "%s already exists, use -force to overwrite it\n", path)
+				return exitCodeInvalidArguments
+			}
+		}
+	}
+
+	options := initOptions{people: *people, cadence: *cadence, notifier: *notifier}
+	if options.people == "" {
+		var err error
+		if options, err = askInitOptions(os.Stdin, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading answers: %v\n", err)
+			return exitCodeError
+		}
+	}
+
+	config, err := newStarterConfig(options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating config: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	if err := writeConfigToFile(config, *pathToConfig); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
+		return exitCodeError
+	}
+
+	hist := history.History{}
+	if err := hist.CompactFile(*pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing history: %v\n", err)
+		return exitCodeError
+	}
+	if err := signArtifact(*pathToHistory); err != nil {
+		fmt.Fprintf(os.Stderr, "Error signing history: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Created %s with %d people and an empty history in %s", *pathToConfig, len(config.People), *pathToHistory)
+	if options.notifier != "" {
+		infof(*quiet, "Replace the placeholder %s addresses in %s before notifying anyone", options.notifier, *pathToConfig)
+	}
+	return exitCodeSuccess
+}
+
+// askInitOptions asks for the options of the init command on prompts, reading the answers from input.
+func askInitOptions(input io.Reader, prompts io.Writer) (initOptions, error) {
+	scanner := bufio.NewScanner(input)
+	ask := func(question string) (string, error) {
+		fmt.Fprint(prompts, question+": ")
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.ErrUnexpectedEOF
+		}
+		return strings.TrimSpace(scanner.Text()), nil
+	}
+
+	var options initOptions
+	var err error
+	if options.people, err = ask("People, as comma separated IDs each optionally followed by =squad"); err != nil {
+		return initOptions{}, err
+	}
+	if options.cadence, err = ask("Cadence, one-week or two-weeks (default one-week)"); err != nil {
+		return initOptions{}, err
+	}
+	if options.notifier, err = ask("Notifier, slack, email or none (default none)"); err != nil {
+		return initOptions{}, err
+	}
+	if options.notifier == "none" {
+		options.notifier = ""
+	}
+	return options, nil
+}
+
+// newStarterConfig returns the config of a new program with the people, squads, cadence and notifier addresses of
+// the options, returning an error if it is not valid.
+func newStarterConfig(options initOptions) (yapper.Config, error) {
+	config := yapper.Config{Version: yapper.ConfigSchemaVersion, Settings: yapper.Settings{Cadence: yapper.Cadence(options.cadence)}}
+
+	for _, entry := range splitList(options.people) {
+		id, squad, _ := strings.Cut(entry, "=")
+		person := yapper.Person{ID: yapper.ID(strings.TrimSpace(id)), Squad: strings.TrimSpace(squad)}
+
+		switch options.notifier {
+		case "":
+		case providerSlack:
+			person.Slack = "U00000000"
+		case providerEmail:
+			person.Email = strings.ToLower(strings.ReplaceAll(string(person.ID), " ", ".")) + "@example.com"
+		default:
+			return yapper.Config{}, fmt.Errorf("unexpected notifier: %s", options.notifier)
+		}
+
+		config.People = append(config.People, person)
+	}
+
+	if len(config.People) == 0 {
+		return yapper.Config{}, errors.New("at least one person is required")
+	}
+	if err := config.Validate(); err != nil {
+		return yapper.Config{}, err
+	}
+	return config, nil
+}
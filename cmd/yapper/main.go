@@ -1,106 +1,323 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/AleksaSvitlica/yapper"
 	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/signing"
+	"github.com/AleksaSvitlica/yapper/telemetry"
 )
 
+// The exit codes of the commands, which automation wrapping yapper can rely on.
 const (
 	exitCodeSuccess          = 0
 	exitCodeError            = 1
 	exitCodeInvalidArguments = 2
+	// exitCodeInvalidConfig is returned when a config file cannot be read or is not valid.
+	exitCodeInvalidConfig = 3
+	// exitCodeCorruptHistory is returned when the history file or its journal cannot be decoded.
+	exitCodeCorruptHistory = 4
+	// exitCodeWarnings is returned when the pairings were generated and recorded, but people who could have met were
+	// left unpaired.
+	exitCodeWarnings = 5
+	// exitCodePartialNotification is returned when some of the messages were sent before sending the rest failed.
+	exitCodePartialNotification = 6
+	// exitCodeLocked is returned when another run holds the lock on the history file, or saved the history to its
+	// store since it was loaded.
+	exitCodeLocked = 7
+	// exitCodeInvalidSignature is returned when a file read is not signed, or was changed since it was signed.
+	exitCodeInvalidSignature = 8
 )
 
+// commandContext is the context of the command being run, carrying its span when telemetry is enabled so generation,
+// history access and notifications are traced as part of it.
+var commandContext = context.Background()
+
 func main() {
-	os.Exit(execute(os.Args[1:]))
+	shutdown := enableTelemetry()
+	ctx, span := telemetry.Start(context.Background(), "yapper "+commandName(os.Args[1:]))
+	commandContext = ctx
+
+	code := execute(os.Args[1:])
+	span.SetAttributes(telemetry.Int("yapper.exit_code", code))
+	span.End()
+	shutdown()
+	os.Exit(code)
 }
 
-func execute(args []string) int {
-	cmd := flag.NewFlagSet("yapper", flag.ContinueOnError)
-	pathToConfig := cmd.String("config", "", "Path to a yapper config file.")
-	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file. The updated history will be written to this file as well.")
-	weeksOfPairings := cmd.Int("weeks", 1, "Number of weeks of pairings to generate.")
-	if err := cmd.Parse(args); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing arguments: %v\n", err)
-		return exitCodeInvalidArguments
+// enableTelemetry starts exporting spans and metrics if the OpenTelemetry environment variables name an endpoint,
+// returning a function which exports what is left.
+func enableTelemetry() func() {
+	config, enabled := telemetry.ConfigFromEnv()
+	if !enabled {
+		return func() {}
+	}
+	config.OnError = func(err error) {
+		fmt.Fprintf(os.Stderr, "Error exporting telemetry: %v\n", err)
 	}
 
-	config, err := yapper.NewConfigFromFile(*pathToConfig)
+	shutdown, err := telemetry.Enable(config)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
-		return exitCodeError
+		fmt.Fprintf(os.Stderr, "Error enabling telemetry: %v\n", err)
+		return func() {}
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting telemetry: %v\n", err)
+		}
 	}
+}
 
-	hist, err := getHistoryFromFile(*pathToHistory, true)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
-		return exitCodeError
+// commandName returns the name of the command run with the arguments, such as "history backfill", for its span.
+func commandName(args []string) string {
+	if c, _, found := findCommand(args); found {
+		return c.name
 	}
+	return "generate"
+}
 
-	weeklyPairings, err := yapper.GeneratePairings(config, &hist, *weeksOfPairings)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating pairings: %v\n", err)
-		return exitCodeError
+// execute runs the subcommand named by the first argument, generating pairings if no subcommand is given.
+func execute(args []string) int {
+	if len(args) == 0 {
+		return executeGenerate(args)
 	}
 
-	for i, pairings := range weeklyPairings {
-		fmt.Printf("Week %d:\n", i)
-		for id1, id2 := range pairings.All() {
-			fmt.Printf("\tPairing: %s and %s\n", id1, id2)
-		}
+	switch args[0] {
+	case "__complete":
+		return executeComplete(args[1:])
+	case "check":
+		return executeCheck(args[1:])
+	case "completion":
+		return executeCompletion(args[1:])
+	case "event":
+		return executeEvent(args[1:])
+	case "evaluate":
+		return executeEvaluate(args[1:])
+	case "graph":
+		return executeGraph(args[1:])
+	case "anomalies":
+		return executeAnomalies(args[1:])
+	case "frequency":
+		return executeFrequency(args[1:])
+	case "suggest":
+		return executeSuggest(args[1:])
+	case "feed":
+		return executeFeed(args[1:])
+	case "generate":
+		return executeGenerate(args[1:])
+	case "absences":
+		return executeAbsences(args[1:])
+	case "deny-lists":
+		return executeDenyLists(args[1:])
+	case "decline":
+		return executeDecline(args[1:])
+	case "doctor":
+		return executeDoctor(args[1:])
+	case "help":
+		return executeHelp(args[1:])
+	case "history":
+		return executeHistory(args[1:])
+	case "init":
+		return executeInit(args[1:])
+	case "key":
+		return executeKey(args[1:])
+	case "schema":
+		return executeSchema(args[1:])
+	case "notify":
+		return executeNotify(args[1:])
+	case "schedule":
+		return executeSchedule(args[1:])
+	case "serve":
+		return executeServe(args[1:])
+	case "sign":
+		return executeSign(args[1:])
+	case "token":
+		return executeToken(args[1:])
+	case "verify":
+		return executeVerify(args[1:])
+	case "version":
+		return executeVersion(args[1:])
+	default:
+		return executeGenerate(args)
 	}
+}
 
-	if err := writeHistoryToFile(hist, *pathToHistory); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing updated history to file: %s, %v\n", *pathToHistory, err)
-		return exitCodeError
+// configFiles are the paths of the config files given by repeating the -config flag.
+type configFiles []string
+
+func (c *configFiles) String() string {
+	return strings.Join(*c, ",")
+}
+
+func (c *configFiles) Set(path string) error {
+	*c = append(*c, path)
+	return nil
+}
+
+// addConfigFlag adds the -config flag, which can be repeated to layer config files, to the command.
+func addConfigFlag(cmd *flag.FlagSet) *configFiles {
+	paths := &configFiles{}
+	cmd.Var(paths, "config", "Path to a yapper config file. Repeat to layer config files, each overriding the ones before it.")
+	return paths
+}
+
+// addStrictFlag adds the -strict flag, which fails the command when the config and history have different people.
+func addStrictFlag(cmd *flag.FlagSet) *bool {
+	return cmd.Bool("strict", false, "Fail instead of warning when the history has people who are not in the config or the config has people who are not in the history.")
+}
+
+// readOnlyHistory is set by the -read-only flag, after which the history is never locked or written.
+var readOnlyHistory bool
+
+// addReadOnlyFlag adds the -read-only flag, which guarantees the history is not changed by the command, e.g. when run
+// against production history with credentials which cannot write.
+func addReadOnlyFlag(cmd *flag.FlagSet, usage string) *bool {
+	cmd.BoolVar(&readOnlyHistory, "read-only", false, usage)
+	return &readOnlyHistory
+}
+
+// checkHistoryWritable returns an error if the history at path cannot be written, so commands which write it fail
+// before doing any work, suggesting -read-only if the permissions or credentials are read only.
+func checkHistoryWritable(path string) error {
+	err := history.CheckWrite(commandContext, path)
+	if errors.Is(err, history.ErrReadOnly) {
+		return fmt.Errorf("%w, use -read-only to run without changing it", err)
 	}
+	return err
+}
 
-	return exitCodeSuccess
+// crossValidate warns on stderr about the people who are only in one of the config and the history of its namespace,
+// unless quiet is set, returning an error listing them instead if strict is set.
+func crossValidate(config yapper.Config, hist history.History, strict, quiet bool) error {
+	mismatch := yapper.CrossValidate(config, *hist.Namespace(config.Namespace))
+	if mismatch.Empty() {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("the config and history have different people, %s", mismatch)
+	}
+	infof(quiet, "Warning: the config and history have different people, %s", mismatch)
+	return nil
 }
 
-// getHistoryFromFile will get the history from a file at the given path.
+// getHistoryFromFile will get the history from a file at the given path, along with its journal if it has one, or from
+// a store if the path is a store URL, see history.OpenStore. Files are verified with YAPPER_VERIFY_KEY if it is set.
 // If allowMissing is true then an empty history will be returned if the file does not exist.
 func getHistoryFromFile(path string, allowMissing bool) (history.History, error) {
-	file, err := os.Open(path)
-	if errors.Is(err, os.ErrNotExist) {
-		if allowMissing {
-			return history.History{}, nil
+	ctx, span := telemetry.Start(commandContext, "history.read", telemetry.String("yapper.history", path))
+	defer span.End()
+
+	var hist history.History
+	var err error
+	if history.IsStoreURL(path) {
+		var store history.Store
+		if store, err = history.OpenStore(path); err == nil {
+			hist, err = store.Load(ctx)
 		}
-		return history.History{}, fmt.Errorf("history file does not exist: %s, %w", path, err)
-	} else if err != nil {
-		return history.History{}, err
+	} else if err = verifyArtifact(path); err == nil {
+		hist, err = history.ReadFile(path)
 	}
-
-	hist, err := history.NewHistoryFromFile(file)
-	if err != nil {
-		return history.History{}, err
+	if errors.Is(err, os.ErrNotExist) && allowMissing {
+		return history.History{}, nil
 	}
+	span.RecordError(err)
+	return hist, err
+}
 
-	if err := file.Close(); err != nil {
-		return history.History{}, err
+// historyExitCode returns the exit code for an error reading, locking or writing the history.
+func historyExitCode(err error) int {
+	switch {
+	case errors.Is(err, history.ErrLocked), errors.Is(err, history.ErrConflict):
+		return exitCodeLocked
+	case errors.Is(err, history.ErrCorrupt):
+		return exitCodeCorruptHistory
+	case errors.Is(err, signing.ErrInvalid):
+		return exitCodeInvalidSignature
+	default:
+		return exitCodeError
 	}
+}
 
-	return hist, nil
+// infof reports progress on stderr, keeping stdout for the documents written by each command, unless quiet is set.
+func infof(quiet bool, format string, a ...any) {
+	if quiet {
+		return
+	}
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
 }
 
-func writeHistoryToFile(hist history.History, path string) error {
+// writeConfigToFile writes the config to the file at path in the current version, refusing configs which refer to
+// environment variables before the file is truncated.
+func writeConfigToFile(config yapper.Config, path string) error {
+	if config.Interpolated() {
+		return errors.New("the config refers to environment variables and cannot be written back")
+	}
+
 	file, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("error creating history output file: %s, %w", path, err)
+		return fmt.Errorf("error creating config file: %s, %w", path, err)
 	}
 
-	if err := hist.Export(file); err != nil {
-		return fmt.Errorf("error exporting history to file: %s, %w", path, err)
+	if err := config.Export(file); err != nil {
+		file.Close()
+		return fmt.Errorf("error exporting config to file: %s, %w", path, err)
 	}
 
-	if err := file.Close(); err != nil {
+	return file.Close()
+}
+
+// writeHistoryToFile saves the history to the file at path, appending the changes to its journal if it has one and
+// signing them with YAPPER_SIGNING_KEY if it is set. A history from a store is saved only if no other run saved it
+// since it was loaded.
+func writeHistoryToFile(hist history.History, path string) error {
+	if readOnlyHistory {
+		return fmt.Errorf("error writing history: %w", history.ErrReadOnly)
+	}
+
+	ctx, span := telemetry.Start(commandContext, "history.write", telemetry.String("yapper.history", path))
+	defer span.End()
+
+	if history.IsStoreURL(path) {
+		store, err := history.OpenStore(path)
+		if err == nil {
+			err = store.Save(ctx, &hist)
+		}
+		span.RecordError(err)
 		return err
 	}
 
-	return nil
+	if err := hist.WriteFile(path); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("error writing history to file: %s, %w", path, err)
+	}
+	return signArtifact(path)
+}
+
+// lockHistory locks the history file at path like history.Lock. Histories in a store are not locked, as they are saved
+// only if no other run saved them since they were loaded, and neither are read only histories.
+func lockHistory(path string) (func() error, error) {
+	if history.IsStoreURL(path) || readOnlyHistory {
+		return func() error { return nil }, nil
+	}
+	return history.Lock(path)
+}
+
+// requireHistoryFile reports an error if the history path is a store URL, for the commands which work on the files
+// of a history, returning false if the command should stop.
+func requireHistoryFile(path string) bool {
+	if history.IsStoreURL(path) {
+		fmt.Fprintf(os.Stderr, "%s is a history store, this command requires a history file\n", path)
+		return false
+	}
+	return true
 }
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/notify"
+)
+
+const (
+	providerSlack = "slack"
+	providerEmail = "email"
+)
+
+// errPartiallySent is wrapped by the errors of notifyPairs when some of the messages were sent before one failed.
+var errPartiallySent = errors.New("only some of the messages were sent")
+
+// providerFlags choose and configure the provider messages are sent through.
+type providerFlags struct {
+	name         *string
+	smtpAddr     *string
+	smtpFrom     *string
+	smtpUsername *string
+	rate         *float64
+	burst        *int
+	batch        *int
+}
+
+// addProviderFlags adds the flags of the provider to the command, which uses no provider if the default is empty.
+// Commands which route each person through their notify channel accept several comma separated providers.
+func addProviderFlags(cmd *flag.FlagSet, defaultProvider string, routed bool) providerFlags {
+	usage := "Provider to send the messages through, slack or email. The Slack bot token is read from YAPPER_SLACK_TOKEN."
+	if defaultProvider == "" {
+		usage = "Provider to notify new pairs through, slack or email, or none if empty. The Slack bot token is read from YAPPER_SLACK_TOKEN."
+	}
+	if routed {
+		usage += " Both can be given as slack,email, notifying each person through their notify channel."
+	}
+
+	return providerFlags{
+		name:         cmd.String("provider", defaultProvider, usage),
+		smtpAddr:     cmd.String("smtp-addr", "", "Host and port of the SMTP server used by the email provider. The password is read from YAPPER_SMTP_PASSWORD."),
+		smtpFrom:     cmd.String("smtp-from", "", "Address the emails are sent from."),
+		smtpUsername: cmd.String("smtp-username", "", "Username for the SMTP server, which is not authenticated with if empty."),
+		rate:         cmd.Float64("rate", 0, "Messages per second to send. Overrides the default of the provider."),
+		burst:        cmd.Int("burst", 0, "Messages which can be sent at once before the rate applies. Overrides the default of the provider."),
+		batch:        cmd.Int("batch", 0, "Messages sent in each batch, e.g. over one SMTP connection. Overrides the default of the provider."),
+	}
+}
+
+// provider returns the chosen provider with any overridden limits, or nil if none was chosen.
+// The Slack token is only required if the messages will be sent.
+func (f providerFlags) provider(send bool) (notify.Provider, error) {
+	providers, err := f.providers(send)
+	if err != nil || len(providers) == 0 {
+		return nil, err
+	}
+	if len(providers) > 1 {
+		return nil, errors.New("only one provider can be used")
+	}
+	return providers[0], nil
+}
+
+// providers returns each of the comma separated providers chosen, in order, or nil if none were chosen.
+func (f providerFlags) providers(send bool) ([]notify.Provider, error) {
+	var providers []notify.Provider
+	for _, name := range splitList(*f.name) {
+		if slices.ContainsFunc(providers, func(provider notify.Provider) bool { return provider.Name() == name }) {
+			return nil, fmt.Errorf("provider given more than once: %s", name)
+		}
+
+		provider, err := f.named(name, send)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// named returns the provider with the name and any overridden limits.
+func (f providerFlags) named(name string, send bool) (notify.Provider, error) {
+	var provider notify.Provider
+	switch name {
+	case providerSlack:
+		if os.Getenv("YAPPER_SLACK_TOKEN") == "" && send {
+			return nil, errors.New("the slack provider requires YAPPER_SLACK_TOKEN")
+		}
+		provider = notify.Slack{Token: os.Getenv("YAPPER_SLACK_TOKEN")}
+	case providerEmail:
+		if *f.smtpAddr == "" || *f.smtpFrom == "" {
+			return nil, errors.New("the email provider requires -smtp-addr and -smtp-from")
+		}
+		provider = notify.Email{Addr: *f.smtpAddr, From: *f.smtpFrom, Username: *f.smtpUsername, Password: os.Getenv("YAPPER_SMTP_PASSWORD")}
+	default:
+		return nil, fmt.Errorf("unexpected provider: %s", name)
+	}
+
+	if *f.rate < 0 || *f.burst < 0 || *f.batch < 0 {
+		return nil, errors.New("rate, burst and batch must not be negative")
+	}
+
+	if *f.rate > 0 || *f.burst > 0 || *f.batch > 0 {
+		limits := provider.Limits()
+		if *f.rate > 0 {
+			limits.Rate = *f.rate
+		}
+		if *f.burst > 0 {
+			limits.Burst = *f.burst
+		}
+		if *f.batch > 0 {
+			limits.BatchSize = *f.batch
+		}
+		provider = notify.WithLimits(provider, limits)
+	}
+
+	return provider, nil
+}
+
+// templateFlags choose the templates the messages are rendered from instead of the built-in messages.
+type templateFlags struct {
+	text    *string
+	subject *string
+}
+
+func addTemplateFlags(cmd *flag.FlagSet) templateFlags {
+	return templateFlags{
+		text:    cmd.String("template", "", "Path to a Go text/template file the text of each message is rendered from, instead of the built-in message. daysSinceLastMeeting, timesMet and firstMeeting can be called with .People."),
+		subject: cmd.String("subject", "", "Go text/template the subject of each message is rendered from when using -template."),
+	}
+}
+
+// template returns the parsed templates, or nil if no -template was given.
+func (f templateFlags) template() (*notify.Template, error) {
+	if *f.text == "" {
+		if *f.subject != "" {
+			return nil, errors.New("-subject requires -template")
+		}
+		return nil, nil
+	}
+
+	text, err := os.ReadFile(*f.text)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template: %w", err)
+	}
+	return notify.NewTemplate(*f.subject, string(text))
+}
+
+// messageTemplate is the template pairs are notified with, or nil for the built-in messages, along with the history
+// answering the functions of the template.
+type messageTemplate struct {
+	template *notify.Template
+	hist     history.History
+}
+
+// executeNotify messages each pair in the current round of a plan previously written by generate with -format json.
+func executeNotify(args []string) int {
+	cmd := newFlagSet("yapper notify")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToPlan := cmd.String("plan", "plan.json", "Path to pairings written by generate with -format json.")
+	round := cmd.String("round", "", "Date of the round to notify, in the format 2006-01-02. Defaults to the current round of the plan.")
+	providerOptions := addProviderFlags(cmd, providerSlack, true)
+	templateOptions := addTemplateFlags(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to the yapper history file answering the functions of the -template and the coverage of the organizer digest.")
+	dryRun := cmd.Bool("dry-run", false, "Print the messages instead of sending them.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	providers, err := providerOptions.providers(!*dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error choosing provider: %v\n", err)
+		return exitCodeInvalidArguments
+	} else if len(providers) == 0 {
+		fmt.Fprintln(os.Stderr, "A provider is required")
+		return exitCodeInvalidArguments
+	}
+
+	tmpl, err := templateOptions.template()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing template: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	messages := messageTemplate{template: tmpl}
+	if tmpl != nil || len(config.Organizers) > 0 {
+		hist, err := getHistoryFromFile(*pathToHistory, true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+			return historyExitCode(err)
+		}
+		messages.hist = *hist.Namespace(config.Namespace)
+	}
+
+	plan, err := readPlan(*pathToPlan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading plan: %v\n", err)
+		return artifactExitCode(err)
+	}
+
+	index, err := findRound(plan, *round)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding round: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	pairs := make([][2]yapper.ID, 0, len(plan.Rounds[index].Pairings))
+	for _, pairing := range plan.Rounds[index].Pairings {
+		pairs = append(pairs, pairing.People)
+	}
+
+	failures, err := notifyPairs(providers, config, plan.Rounds[index], pairs, messages, *dryRun, *quiet)
+	if err != nil {
+		failures = append(failures, err.Error())
+	}
+
+	if len(config.Organizers) > 0 {
+		if digestErr := notifyOrganizers(providers, config, plan.Rounds[index], pairs, messages.hist, failures, *dryRun, *quiet); digestErr != nil {
+			fmt.Fprintf(os.Stderr, "Error sending digest: %v\n", digestErr)
+			if err == nil {
+				return exitCodeError
+			}
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error sending messages: %v\n", err)
+		return notifyExitCode(err)
+	}
+
+	return exitCodeSuccess
+}
+
+// notifyOrganizers sends the organizers of the config a digest of the notified round, with the failures to notify
+// its pairs, or prints it for a dry run.
+func notifyOrganizers(providers []notify.Provider, config yapper.Config, round roundOutput, pairs [][2]yapper.ID, hist history.History, failures []string, dryRun, quiet bool) error {
+	date, err := time.Parse(time.DateOnly, round.Date)
+	if err != nil {
+		return fmt.Errorf("error parsing round date: %w", err)
+	}
+
+	pairings := yapper.NewPairings(date, pairs)
+	digest := notify.Digest{
+		Date:     date,
+		Pairs:    len(pairs),
+		Unpaired: yapper.NewResult(config, []yapper.Pairings{pairings}).Rounds[0].Unpaired,
+		Streaks:  yapper.UnpairedStreaks(config, hist, pairings),
+		Failures: failures,
+	}
+	digest.CoverageBefore, digest.CoverageAfter = yapper.Coverage(config, hist, pairings)
+
+	routes, unreachable := notify.DigestMessages(config, providers, digest)
+	for _, id := range unreachable {
+		infof(quiet, "Skipping organizer %s, who is missing an address for %s", id, providerNames(providers))
+	}
+	return sendRoutes(routes, dryRun, quiet)
+}
+
+// findRound returns the index of the round of the plan starting on the date, or the current round if the date is empty.
+func findRound(plan generateOutput, date string) (int, error) {
+	if len(plan.Rounds) == 0 {
+		return 0, errors.New("the plan has no rounds")
+	}
+
+	if date == "" {
+		return currentRound(plan, time.Now()), nil
+	}
+
+	for i, round := range plan.Rounds {
+		if round.Date == date {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("the plan has no round starting %s", date)
+}
+
+// notifyPairs messages each of the pairs of the round through the providers, routing each person to their notify
+// channel, rendered from the template if there is one, or prints the messages for a dry run. The pairings which were
+// skipped as someone could not be reached are described by the returned failures.
+func notifyPairs(providers []notify.Provider, config yapper.Config, round roundOutput, pairs [][2]yapper.ID, tmpl messageTemplate, dryRun, quiet bool) ([]string, error) {
+	date, err := time.Parse(time.DateOnly, round.Date)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing round date: %w", err)
+	}
+
+	pairings := make([]notify.Pairing, 0, len(pairs))
+	for _, pairing := range round.Pairings {
+		if containsPeople(pairs, pairing.People) {
+			notifyPairing := notify.Pairing{People: pairing.People, Icebreaker: pairing.Icebreaker, Initiator: pairing.Initiator, Topic: pairing.Topic, Milestones: pairing.Milestones, Notes: pairing.Notes}
+			if lastMet, err := time.Parse(time.DateOnly, pairing.LastMet); err == nil {
+				notifyPairing.LastMet = lastMet
+			}
+			pairings = append(pairings, notifyPairing)
+		}
+	}
+
+	var routes []notify.Routed
+	var skipped []notify.Pairing
+	if tmpl.template != nil {
+		routes, skipped, err = tmpl.template.RoutePairingMessages(config, providers, date, pairings, tmpl.hist)
+	} else {
+		routes, skipped, err = notify.RoutePairingMessages(config, providers, date, pairings)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating messages: %w", err)
+	}
+
+	failures := []string{}
+	for _, pairing := range skipped {
+		infof(quiet, "Skipping %s & %s, who are missing an address for %s", pairing.People[0], pairing.People[1], providerNames(providers))
+		failures = append(failures, fmt.Sprintf("%s & %s are missing an address for %s", pairing.People[0], pairing.People[1], providerNames(providers)))
+	}
+
+	return failures, sendRoutes(routes, dryRun, quiet)
+}
+
+// providerNames returns the names of the providers joined with or.
+func providerNames(providers []notify.Provider) string {
+	names := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, " or ")
+}
+
+// sendRoutes sends the messages of each route through its provider, or prints them for a dry run.
+func sendRoutes(routes []notify.Routed, dryRun, quiet bool) error {
+	if dryRun {
+		for _, route := range routes {
+			for _, message := range route.Messages {
+				if len(routes) > 1 {
+					fmt.Printf("Via: %s\n", route.Provider.Name())
+				}
+				fmt.Printf("To: %v\nSubject: %s\n\n%s\n\n", message.Recipients, message.Subject, message.Text)
+			}
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext, os.Interrupt)
+	defer stop()
+
+	totalSent := 0
+	for _, route := range routes {
+		if len(route.Messages) == 0 {
+			continue
+		}
+
+		sent, err := notify.Send(ctx, route.Provider, route.Messages)
+		infof(quiet, "Sent %d of %d messages through %s", sent, len(route.Messages), route.Provider.Name())
+		totalSent += sent
+		if err != nil && totalSent > 0 {
+			return fmt.Errorf("%w: %w", errPartiallySent, err)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyExitCode returns the exit code for an error notifying pairs, which distinguishes messages which were only
+// partly sent so that wrapping automation does not send every message again.
+func notifyExitCode(err error) int {
+	if errors.Is(err, errPartiallySent) {
+		return exitCodePartialNotification
+	}
+	return exitCodeError
+}
+
+func containsPeople(pairs [][2]yapper.ID, people [2]yapper.ID) bool {
+	for _, pair := range pairs {
+		if pair == people || pair == [2]yapper.ID{people[1], people[0]} {
+			return true
+		}
+	}
+	return false
+}
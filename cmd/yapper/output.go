@@ -0,0 +1,344 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatPDF  = "pdf"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorBold  = "\033[1m"
+	colorDim   = "\033[2m"
+	colorCyan  = "\033[36m"
+	colorGreen = "\033[32m"
+)
+
+// generateOutput is the document describing the generated pairings, written as JSON or rendered as tables.
+type generateOutput struct {
+	Rounds       []roundOutput `json:"rounds"`
+	TotalPenalty *float64      `json:"totalPenalty,omitempty"`
+	// Score is the score of the plan when the rounds were generated by the planned strategy.
+	Score *yapper.PlanScore `json:"score,omitempty"`
+}
+
+type roundOutput struct {
+	Date string `json:"date"`
+	// Topic is the topic of the curriculum for the round, which pairs who already discussed it advance past.
+	Topic    string          `json:"topic,omitempty"`
+	Pairings []pairingOutput `json:"pairings"`
+	Penalty  *float64        `json:"penalty,omitempty"`
+	// Declined are the pairs which declined to meet and were re-matched by the decline command.
+	Declined [][2]yapper.ID `json:"declined,omitempty"`
+	// Unpaired explain why each person left unpaired was not paired, when the rounds are explained.
+	Unpaired []yapper.Diagnostic `json:"unpaired,omitempty"`
+	// SkippedPins explain why each pin applying to the round was skipped, when the rounds are explained.
+	SkippedPins []yapper.SkippedPin `json:"skippedPins,omitempty"`
+}
+
+type pairingOutput struct {
+	People           [2]yapper.ID `json:"people"`
+	LastMet          string       `json:"lastMet,omitempty"`
+	DaysSinceLastMet *int         `json:"daysSinceLastMet,omitempty"`
+	PreferredDays    []yapper.Day `json:"preferredDays,omitempty"`
+	Icebreaker       string       `json:"icebreaker,omitempty"`
+	// Initiator is the person responsible for scheduling the meeting.
+	Initiator yapper.ID `json:"initiator,omitempty"`
+	// Topic is the topic of the curriculum for the pair to discuss.
+	Topic string `json:"topic,omitempty"`
+	// Milestones are the birthdays and work anniversaries of the pair during the round.
+	Milestones []yapper.Milestone `json:"milestones,omitempty"`
+	// Notes are the notes of the organizers about the pair and each of the people.
+	Notes []string `json:"notes,omitempty"`
+}
+
+func newGenerateOutput(config yapper.Config, weeklyPairings []yapper.Pairings) generateOutput {
+	output := generateOutput{Rounds: []roundOutput{}}
+	totalPenalty := 0.0
+
+	for _, pairings := range weeklyPairings {
+		round := roundOutput{
+			Date:     pairings.Date().Format(time.DateOnly),
+			Topic:    config.RoundTopic(pairings.Date()),
+			Pairings: []pairingOutput{},
+		}
+
+		for id1, id2 := range pairings.All() {
+			if id2 < id1 {
+				id1, id2 = id2, id1
+			}
+			round.Pairings = append(round.Pairings, newPairingOutput(config, pairings, id1, id2))
+		}
+		// Pairs are sorted so that saved plans only differ where the pairings do.
+		slices.SortFunc(round.Pairings, func(a, b pairingOutput) int {
+			return strings.Compare(string(a.People[0])+"\x00"+string(a.People[1]), string(b.People[0])+"\x00"+string(b.People[1]))
+		})
+
+		if config.HasSoftConstraints() {
+			penalty := pairings.Penalty()
+			round.Penalty = &penalty
+			totalPenalty += penalty
+		}
+
+		output.Rounds = append(output.Rounds, round)
+	}
+
+	if config.HasSoftConstraints() {
+		output.TotalPenalty = &totalPenalty
+	}
+
+	if len(weeklyPairings) > 0 {
+		if score, found := weeklyPairings[0].PlanScore(); found {
+			output.Score = &score
+		}
+	}
+
+	return output
+}
+
+// explain adds why each person left unpaired was not paired to the rounds of the output, along with why each skipped
+// pin was skipped.
+func (o *generateOutput) explain(config yapper.Config, weeklyPairings []yapper.Pairings) {
+	for i, round := range yapper.NewResult(config, weeklyPairings).Rounds {
+		o.Rounds[i].Unpaired = round.Diagnostics
+		o.Rounds[i].SkippedPins = round.SkippedPins
+	}
+}
+
+func newPairingOutput(config yapper.Config, pairings yapper.Pairings, id1, id2 yapper.ID) pairingOutput {
+	pairing := pairingOutput{People: [2]yapper.ID{id1, id2}, Icebreaker: config.Icebreaker(id1, id2, pairings.Date())}
+
+	if lastMet, met := pairings.LastMet(id1, id2); met {
+		days := int(pairings.Date().Sub(lastMet).Hours() / 24)
+		pairing.LastMet = lastMet.Format(time.DateOnly)
+		pairing.DaysSinceLastMet = &days
+	}
+
+	if days, err := config.CommonPreferredDays(id1, id2); err == nil {
+		pairing.PreferredDays = days
+	}
+
+	if initiator, designated := pairings.Initiator(id1, id2); designated {
+		pairing.Initiator = initiator
+	}
+	pairing.Topic = pairings.Topic(id1, id2)
+	pairing.Milestones = config.Milestones(id1, id2, pairings.Date())
+	pairing.Notes = config.Notes(id1, id2, pairings.Date())
+
+	return pairing
+}
+
+func writeJSON(writer io.Writer, output generateOutput) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// writeText renders each round as an aligned table, using colour if enabled.
+func writeText(writer io.Writer, config yapper.Config, output generateOutput, color bool) error {
+	paint := func(code, text string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+
+	roundName := "Week"
+	if config.RoundInterval() != 7 {
+		roundName = "Round"
+	}
+
+	var sb strings.Builder
+	for i, round := range output.Rounds {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(paint(colorBold, fmt.Sprintf("%s %d (%s)", roundName, i, round.Date)) + "\n")
+		if round.Topic != "" {
+			sb.WriteString(fmt.Sprintf("  Topic: %s\n", round.Topic))
+		}
+
+		if len(round.Pairings) == 0 {
+			sb.WriteString(paint(colorDim, "  No pairings") + "\n")
+		}
+
+		table := [][]string{{"PAIR", "LAST MET"}}
+		showInitiator := slices.ContainsFunc(round.Pairings, func(p pairingOutput) bool { return p.Initiator != "" })
+		if showInitiator {
+			table[0] = append(table[0], "INITIATOR")
+		}
+		showTopic := slices.ContainsFunc(round.Pairings, func(p pairingOutput) bool { return p.Topic != round.Topic })
+		if showTopic {
+			table[0] = append(table[0], "TOPIC")
+		}
+		showDays := slices.ContainsFunc(round.Pairings, func(p pairingOutput) bool { return p.PreferredDays != nil })
+		showIcebreaker := len(config.Icebreakers) > 0
+		showMilestones := slices.ContainsFunc(round.Pairings, func(p pairingOutput) bool { return len(p.Milestones) > 0 })
+		showNotes := slices.ContainsFunc(round.Pairings, func(p pairingOutput) bool { return len(p.Notes) > 0 })
+		if showDays {
+			table[0] = append(table[0], "PREFERRED DAYS")
+		}
+		if showIcebreaker {
+			table[0] = append(table[0], "ICEBREAKER")
+		}
+		if showMilestones {
+			table[0] = append(table[0], "MILESTONES")
+		}
+		if showNotes {
+			table[0] = append(table[0], "NOTES")
+		}
+
+		for _, pairing := range round.Pairings {
+			lastMet := "never"
+			if pairing.DaysSinceLastMet != nil {
+				lastMet = fmt.Sprintf("%d days ago", *pairing.DaysSinceLastMet)
+			}
+
+			row := []string{fmt.Sprintf("%s & %s", pairing.People[0], pairing.People[1]), lastMet}
+			if showInitiator {
+				row = append(row, string(pairing.Initiator))
+			}
+			if showTopic {
+				row = append(row, pairing.Topic)
+			}
+			if showDays {
+				row = append(row, formatDays(pairing.PreferredDays))
+			}
+			if showIcebreaker {
+				row = append(row, pairing.Icebreaker)
+			}
+			if showMilestones {
+				row = append(row, formatMilestones(pairing.Milestones))
+			}
+			if showNotes {
+				row = append(row, strings.Join(pairing.Notes, "; "))
+			}
+			table = append(table, row)
+		}
+
+		if len(round.Pairings) > 0 {
+			writeTable(&sb, table, func(row, column int, cell string) string {
+				switch {
+				case row == 0:
+					return paint(colorBold, cell)
+				case column == 0:
+					return paint(colorCyan, cell)
+				case column == 1 && strings.HasPrefix(cell, "never"):
+					return paint(colorGreen, cell)
+				default:
+					return cell
+				}
+			})
+		}
+
+		if round.Penalty != nil {
+			sb.WriteString(paint(colorDim, fmt.Sprintf("  Penalty: %.2f", *round.Penalty)) + "\n")
+		}
+
+		if len(round.Unpaired) > 0 {
+			sb.WriteString(paint(colorBold, "  Unpaired") + "\n")
+			for _, diagnostic := range round.Unpaired {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", diagnostic.Person, diagnostic.Message))
+				for _, blocker := range diagnostic.Blockers {
+					sb.WriteString(paint(colorDim, "      "+blocker.Message) + "\n")
+				}
+			}
+		}
+		if len(round.SkippedPins) > 0 {
+			sb.WriteString(paint(colorBold, "  Skipped pins") + "\n")
+			for _, skipped := range round.SkippedPins {
+				sb.WriteString(fmt.Sprintf("    %s\n", skipped.Message))
+			}
+		}
+	}
+
+	if output.TotalPenalty != nil {
+		sb.WriteString(fmt.Sprintf("\nTotal penalty: %.2f\n", *output.TotalPenalty))
+	}
+
+	if output.Score != nil {
+		score := fmt.Sprintf("\nPlan cost: %.2f, bound: %.2f", output.Score.Cost, output.Score.Bound)
+		if output.Score.Stopped {
+			score += " (stopped at the max duration)"
+		}
+		sb.WriteString(score + "\n")
+	}
+
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+// writeTable pads each cell to the width of its column before styling, so escape codes do not affect the alignment.
+func writeTable(sb *strings.Builder, table [][]string, style func(row, column int, cell string) string) {
+	var widths []int
+	for _, row := range table {
+		for column, cell := range row {
+			if column >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[column] = max(widths[column], utf8.RuneCountInString(cell))
+		}
+	}
+
+	for rowIndex, row := range table {
+		sb.WriteString("  ")
+		for column, cell := range row {
+			padded := cell
+			if column < len(row)-1 {
+				padded += strings.Repeat(" ", widths[column]-utf8.RuneCountInString(cell)+2)
+			}
+			sb.WriteString(style(rowIndex, column, padded))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func formatMilestones(milestones []yapper.Milestone) string {
+	descriptions := make([]string, 0, len(milestones))
+	for _, milestone := range milestones {
+		descriptions = append(descriptions, milestone.String())
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func formatDays(days []yapper.Day) string {
+	if days == nil {
+		return "any"
+	}
+	if len(days) == 0 {
+		return "none in common"
+	}
+
+	names := make([]string, 0, len(days))
+	for _, day := range days {
+		names = append(names, string(day))
+	}
+	return strings.Join(names, ", ")
+}
+
+// useColor reports whether output to the file should be coloured, which is only the case for terminals.
+// Colour is also disabled by the NO_COLOR environment variable.
+func useColor(file *os.File, noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+)
+
+// pdfSheet lays out lines of text on A4 pages, which are written as a PDF using the standard Helvetica fonts so no
+// fonts need to be embedded.
+type pdfSheet struct {
+	pages []*strings.Builder
+	// y is the baseline of the next line on the last page.
+	y float64
+}
+
+// newPage starts a new page, which the following lines are written to.
+func (s *pdfSheet) newPage() {
+	s.pages = append(s.pages, &strings.Builder{})
+	s.y = pdfPageHeight - pdfMargin
+}
+
+// space leaves a gap of the given height before the next line.
+func (s *pdfSheet) space(height float64) {
+	s.y -= height
+}
+
+// line writes the text in the font size, wrapping it at the right margin and starting a new page at the bottom one.
+func (s *pdfSheet) line(text string, size float64, bold bool, indent float64) {
+	font, charWidth := "F1", 0.5*size
+	if bold {
+		font, charWidth = "F2", 0.55*size
+	}
+
+	// Helvetica is not monospaced, so the width of a line is estimated from the average width of its characters.
+	maxChars := max(int((pdfPageWidth-2*pdfMargin-indent)/charWidth), 1)
+	for _, wrapped := range wrapWords(text, maxChars) {
+		if len(s.pages) == 0 || s.y-1.3*size < pdfMargin {
+			s.newPage()
+		}
+		s.y -= 1.3 * size
+		fmt.Fprintf(s.pages[len(s.pages)-1], "BT /%s %g Tf %g %g Td (%s) Tj ET\n", font, size, pdfMargin+indent, s.y, pdfString(wrapped))
+	}
+}
+
+// writeTo writes the pages as a PDF document.
+func (s *pdfSheet) writeTo(writer io.Writer) error {
+	if len(s.pages) == 0 {
+		s.newPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, 0, len(s.pages))
+	for i := range s.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(s.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range s.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := writer.Write(buf.Bytes())
+	return err
+}
+
+// wrapWords splits the text into lines of at most maxChars characters, breaking between words where possible.
+func wrapWords(text string, maxChars int) []string {
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		if len(current) > 0 && len(current)+1+len(runes) > maxChars {
+			lines = append(lines, string(current))
+			current = nil
+		}
+		for len(runes) > maxChars {
+			lines = append(lines, string(runes[:maxChars]))
+			runes = runes[maxChars:]
+		}
+		if len(current) > 0 {
+			current = append(current, ' ')
+		}
+		current = append(current, runes...)
+	}
+	if len(current) > 0 || len(lines) == 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
+
+// pdfString escapes the text for a PDF string in the WinAnsi encoding of the fonts, replacing the characters it
+// cannot represent with a question mark.
+func pdfString(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
+
+// writePDF renders a printable sheet of the pairings, each round on its own page.
+func writePDF(writer io.Writer, config yapper.Config, output generateOutput) error {
+	roundName := "Week"
+	if config.RoundInterval() != 7 {
+		roundName = "Round"
+	}
+
+	sheet := pdfSheet{}
+	for i, round := range output.Rounds {
+		sheet.newPage()
+		sheet.line(fmt.Sprintf("%s %d (%s)", roundName, i, round.Date), 18, true, 0)
+		if round.Topic != "" {
+			sheet.line("Topic: "+round.Topic, 11, false, 0)
+		}
+		sheet.space(10)
+
+		if len(round.Pairings) == 0 {
+			sheet.line("No pairings", 11, false, 0)
+		}
+
+		for _, pairing := range round.Pairings {
+			sheet.line(fmt.Sprintf("%s & %s", pairing.People[0], pairing.People[1]), 12, true, 0)
+
+			details := []string{"Last met: never"}
+			if pairing.DaysSinceLastMet != nil {
+				details[0] = fmt.Sprintf("Last met: %d days ago", *pairing.DaysSinceLastMet)
+			}
+			if pairing.Initiator != "" {
+				details = append(details, fmt.Sprintf("Initiator: %s", pairing.Initiator))
+			}
+			if pairing.Topic != "" && pairing.Topic != round.Topic {
+				details = append(details, "Topic: "+pairing.Topic)
+			}
+			if len(pairing.PreferredDays) > 0 {
+				details = append(details, "Preferred days: "+formatDays(pairing.PreferredDays))
+			}
+			if pairing.Icebreaker != "" {
+				details = append(details, "Icebreaker: "+pairing.Icebreaker)
+			}
+			if len(pairing.Milestones) > 0 {
+				details = append(details, "Milestones: "+formatMilestones(pairing.Milestones))
+			}
+			if len(pairing.Notes) > 0 {
+				details = append(details, "Notes: "+strings.Join(pairing.Notes, "; "))
+			}
+			for _, detail := range details {
+				sheet.line(detail, 10, false, 12)
+			}
+			sheet.space(6)
+		}
+	}
+
+	return sheet.writeTo(writer)
+}
+
+// writeTablesPDF renders a printable sheet of the tables of the event, each round on its own page.
+func writeTablesPDF(writer io.Writer, output tablesOutput) error {
+	sheet := pdfSheet{}
+	for i, tables := range output.Rounds {
+		sheet.newPage()
+		sheet.line(fmt.Sprintf("Round %d (%s)", i, output.Date), 18, true, 0)
+		sheet.space(10)
+
+		if len(tables) == 0 {
+			sheet.line("No tables", 11, false, 0)
+		}
+
+		for j, table := range tables {
+			people := make([]string, 0, len(table))
+			for _, id := range table {
+				people = append(people, string(id))
+			}
+			sheet.line(fmt.Sprintf("Table %d", j+1), 12, true, 0)
+			sheet.line(strings.Join(people, ", "), 11, false, 12)
+			sheet.space(6)
+		}
+	}
+
+	return sheet.writeTo(writer)
+}
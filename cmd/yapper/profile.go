@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// profileFlags are the paths given by -cpuprofile and -memprofile to write profiles of a command to, which can be read
+// with go tool pprof.
+type profileFlags struct {
+	cpu *string
+	mem *string
+}
+
+// addProfileFlags adds the -cpuprofile and -memprofile flags to the command.
+func addProfileFlags(cmd *flag.FlagSet) profileFlags {
+	return profileFlags{
+		cpu: cmd.String("cpuprofile", "", "Write a CPU profile of the command to this file."),
+		mem: cmd.String("memprofile", "", "Write a memory profile to this file once the command finishes."),
+	}
+}
+
+// start starts the CPU profile, returning a function which stops it and writes the memory profile, reporting any
+// errors on stderr.
+func (p profileFlags) start() (func(), error) {
+	var cpuFile *os.File
+	if *p.cpu != "" {
+		file, err := os.Create(*p.cpu)
+		if err != nil {
+			return nil, fmt.Errorf("error creating CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error starting CPU profile: %w", err)
+		}
+		cpuFile = file
+	}
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing CPU profile: %v\n", err)
+			}
+		}
+
+		if *p.mem != "" {
+			if err := writeMemProfile(*p.mem); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing memory profile: %v\n", err)
+			}
+		}
+	}, nil
+}
+
+func writeMemProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	runtime.GC()
+	if err := pprof.Lookup("allocs").WriteTo(file, 0); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+const formatICS = "ics"
+
+// scheduleEntry is one of a person's upcoming matches.
+type scheduleEntry struct {
+	Date             string       `json:"date"`
+	Partner          yapper.ID    `json:"partner"`
+	LastMet          string       `json:"lastMet,omitempty"`
+	DaysSinceLastMet *int         `json:"daysSinceLastMet,omitempty"`
+	PreferredDays    []yapper.Day `json:"preferredDays,omitempty"`
+	Icebreaker       string       `json:"icebreaker,omitempty"`
+}
+
+// executeSchedule writes the upcoming matches of a person from a plan previously written by generate with -format json.
+func executeSchedule(args []string) int {
+	cmd := newFlagSet("yapper schedule")
+	pathToPlan := cmd.String("plan", "plan.json", "Path to pairings written by generate with -format json.")
+	person := cmd.String("person", "", "ID of the person whose matches are shown.")
+	weeks := cmd.Int("weeks", 8, "Number of upcoming rounds to include, starting from the current round.")
+	format := cmd.String("format", formatText, "Output format, text, json or ics.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *person == "" {
+		fmt.Fprintln(os.Stderr, "A person is required")
+		return exitCodeInvalidArguments
+	}
+
+	if *weeks < 1 {
+		fmt.Fprintf(os.Stderr, "Weeks must be positive: %d\n", *weeks)
+		return exitCodeInvalidArguments
+	}
+
+	if *format != formatText && *format != formatJSON && *format != formatICS {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	plan, err := readPlan(*pathToPlan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading plan: %v\n", err)
+		return artifactExitCode(err)
+	}
+
+	schedule := personSchedule(plan, yapper.ID(*person), time.Now(), *weeks)
+
+	switch *format {
+	case formatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(schedule)
+	case formatICS:
+		err = writeICS(os.Stdout, yapper.ID(*person), schedule, time.Now())
+	default:
+		err = writeSchedule(os.Stdout, yapper.ID(*person), schedule)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing schedule: %v\n", err)
+		return exitCodeError
+	}
+
+	return exitCodeSuccess
+}
+
+// readPlan reads a plan written by generate, verifying it with YAPPER_VERIFY_KEY if it is set.
+func readPlan(path string) (generateOutput, error) {
+	if err := verifyArtifact(path); err != nil {
+		return generateOutput{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return generateOutput{}, fmt.Errorf("error reading file %s: %w", path, err)
+	}
+
+	var plan generateOutput
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return generateOutput{}, fmt.Errorf("error unmarshalling plan %s: %w", path, err)
+	}
+
+	return plan, nil
+}
+
+// currentRound returns the index of the latest round to start on or before now, or the first round if none have started.
+func currentRound(plan generateOutput, now time.Time) int {
+	today := now.Format(time.DateOnly)
+	current := 0
+	for i, round := range plan.Rounds {
+		if round.Date <= today {
+			current = i
+		}
+	}
+	return current
+}
+
+// personSchedule returns the matches of the person in the current round, which is the latest to start on or before now,
+// and the rounds after it, up to the given number of rounds.
+func personSchedule(plan generateOutput, person yapper.ID, now time.Time, rounds int) []scheduleEntry {
+	first := currentRound(plan, now)
+
+	schedule := []scheduleEntry{}
+	for i := first; i < len(plan.Rounds) && i < first+rounds; i++ {
+		round := plan.Rounds[i]
+		for _, pairing := range round.Pairings {
+			var partner yapper.ID
+			switch person {
+			case pairing.People[0]:
+				partner = pairing.People[1]
+			case pairing.People[1]:
+				partner = pairing.People[0]
+			default:
+				continue
+			}
+
+			schedule = append(schedule, scheduleEntry{
+				Date:             round.Date,
+				Partner:          partner,
+				LastMet:          pairing.LastMet,
+				DaysSinceLastMet: pairing.DaysSinceLastMet,
+				PreferredDays:    pairing.PreferredDays,
+				Icebreaker:       pairing.Icebreaker,
+			})
+		}
+	}
+
+	return schedule
+}
+
+func writeSchedule(writer io.Writer, person yapper.ID, schedule []scheduleEntry) error {
+	var sb strings.Builder
+	if len(schedule) == 0 {
+		fmt.Fprintf(&sb, "No upcoming matches for %s\n", person)
+		_, err := io.WriteString(writer, sb.String())
+		return err
+	}
+
+	fmt.Fprintf(&sb, "Upcoming matches for %s\n", person)
+	table := [][]string{{"DATE", "PARTNER"}}
+	showDays := slices.ContainsFunc(schedule, func(e scheduleEntry) bool { return e.PreferredDays != nil })
+	showIcebreaker := slices.ContainsFunc(schedule, func(e scheduleEntry) bool { return e.Icebreaker != "" })
+	if showDays {
+		table[0] = append(table[0], "PREFERRED DAYS")
+	}
+	if showIcebreaker {
+		table[0] = append(table[0], "ICEBREAKER")
+	}
+
+	for _, entry := range schedule {
+		row := []string{entry.Date, string(entry.Partner)}
+		if showDays {
+			row = append(row, formatDays(entry.PreferredDays))
+		}
+		if showIcebreaker {
+			row = append(row, entry.Icebreaker)
+		}
+		table = append(table, row)
+	}
+	writeTable(&sb, table, func(_, _ int, cell string) string { return cell })
+
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+// writeICS writes the schedule as an iCalendar file with an all day event for the start of each round.
+func writeICS(writer io.Writer, person yapper.ID, schedule []scheduleEntry, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//yapper//schedule//EN",
+		"CALSCALE:GREGORIAN",
+	}
+
+	for _, entry := range schedule {
+		date, err := time.Parse(time.DateOnly, entry.Date)
+		if err != nil {
+			return fmt.Errorf("error parsing date of match with %s: %w", entry.Partner, err)
+		}
+
+		description := entry.Icebreaker
+		if entry.PreferredDays != nil {
+			description = strings.TrimSpace(fmt.Sprintf("Preferred days: %s\n%s", formatDays(entry.PreferredDays), description))
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%s-%s@yapper", date.Format("20060102"), escapeICS(string(person)), escapeICS(string(entry.Partner))),
+			"DTSTAMP:"+now.UTC().Format("20060102T150405Z"),
+			"DTSTART;VALUE=DATE:"+date.Format("20060102"),
+			"DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+escapeICS(fmt.Sprintf("Yap with %s", entry.Partner)),
+		)
+		if description != "" {
+			lines = append(lines, "DESCRIPTION:"+escapeICS(description))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+	_, err := io.WriteString(writer, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+// escapeICS escapes text values as required by RFC 5545.
+func escapeICS(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/schema"
+)
+
+// schemas are the JSON Schemas of each file format, along with the function validating the data of a file against it.
+var schemas = map[string]struct {
+	schema   func() *schema.Schema
+	validate func(path string, data []byte) error
+}{
+	"config":   {yapper.ConfigSchema, anyPath(yapper.ValidateConfigSchema)},
+	"history":  {history.Schema, history.ValidateFileSchema},
+	"pairings": {yapper.PairingsSchema, anyPath(yapper.ValidatePairingsSchema)},
+}
+
+// anyPath returns the validation of the data of a file whatever its path.
+func anyPath(validate func([]byte) error) func(string, []byte) error {
+	return func(_ string, data []byte) error {
+		return validate(data)
+	}
+}
+
+// executeSchema writes the JSON Schema of a file format, or validates a file against it.
+func executeSchema(args []string) int {
+	cmd := newFlagSet("yapper schema")
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "Usage: yapper schema <config|history|pairings> [file to validate]")
+		printExamples(cmd)
+	}
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	args = cmd.Args()
+	if len(args) == 0 || len(args) > 2 {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+
+	format, exists := schemas[args[0]]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Unexpected schema: %s\n", args[0])
+		return exitCodeInvalidArguments
+	}
+
+	if len(args) == 2 {
+		if err := validateFile(args[1], format.validate); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return exitCodeError
+		}
+		fmt.Fprintf(os.Stderr, "%s is a valid %s file\n", args[1], args[0])
+		return exitCodeSuccess
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(format.schema()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing schema: %v\n", err)
+		return exitCodeError
+	}
+
+	return exitCodeSuccess
+}
+
+// validateFile reads the file and validates it, including the path in any errors.
+func validateFile(path string, validate func(path string, data []byte) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %w", path, err)
+	}
+
+	if err := validate(path, data); err != nil {
+		return fmt.Errorf("%s does not match the schema:\n%w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/server"
+	"github.com/AleksaSvitlica/yapper/signing"
+	"github.com/AleksaSvitlica/yapper/telemetry"
+)
+
+// The environment variables holding the secrets of the server.
+const (
+	// portalSecretEnv holds the secret which enables the participant portal.
+	portalSecretEnv = "YAPPER_PORTAL_SECRET"
+	// oidcClientSecretEnv holds the client secret registered with the OpenID Connect provider.
+	oidcClientSecretEnv = "YAPPER_OIDC_CLIENT_SECRET"
+	// sessionSecretEnv holds the secret signing the session cookies when signing in with OpenID Connect.
+	sessionSecretEnv = "YAPPER_SESSION_SECRET"
+	// webhookSecretEnv holds the secret which enables the meeting status webhook.
+	webhookSecretEnv = "YAPPER_WEBHOOK_SECRET"
+	// slackSigningSecretEnv holds the signing secret of the Slack app, which enables the Slack interactivity webhook.
+	slackSigningSecretEnv = "YAPPER_SLACK_SIGNING_SECRET"
+)
+
+// executeServe serves the REST API and web dashboard until the server fails or is stopped.
+func executeServe(args []string) int {
+	cmd := newFlagSet("yapper serve")
+	pathToConfig := cmd.String("config", "", "Path to a yapper config file.")
+	pathToHistory := cmd.String("history", "history.json", "Path to a yapper history file. Confirmed pairings will be written to this file as well.")
+	addr := cmd.String("addr", "localhost:8080", "Address to listen on.")
+	oidcIssuer := cmd.String("oidc-issuer", "", "URL of an OpenID Connect provider to require signing in with. The client secret is read from "+oidcClientSecretEnv+".")
+	oidcClientID := cmd.String("oidc-client-id", "", "Client ID registered with the OpenID Connect provider.")
+	oidcRedirectURL := cmd.String("oidc-redirect-url", "", "URL of /auth/callback on this server, as registered with the OpenID Connect provider.")
+	oidcTrustUnverified := cmd.Bool("oidc-trust-unverified-emails", false, "Accept emails the OpenID Connect provider does not mark as verified, for providers leaving out the email_verified claim.")
+	organizers := cmd.String("organizers", "", "Comma separated emails of the organizers when signing in with OpenID Connect. Everyone else is a participant.")
+	pathToTenants := cmd.String("tenants", "", "Path to a tenants file to host several organisations, each with its own config, history, API tokens and quota, instead of the program of -config and -history.")
+	pathToTokens := cmd.String("tokens", "", "Path to an API tokens file, created with the token command, to require API tokens or signing in.")
+	providerOptions := addProviderFlags(cmd, "", false)
+	maxRequestSize := cmd.Int64("max-request-size", server.DefaultMaxRequestSize, "Most bytes read from the body of a request, larger requests are rejected.")
+	leaderElection := cmd.Bool("leader-election", false, "Only serve requests while this replica holds the lease on the history, for running several replicas sharing a history.")
+	leaderID := cmd.String("leader-id", "", "Identity of this replica in the leader election, such as its pod name. Defaults to the hostname.")
+	leaseTTL := cmd.Duration("lease-ttl", server.DefaultLeaseTTL, "How long the lease of the leader lasts without being renewed, after which another replica takes over.")
+	shutdownTimeout := cmd.Duration("shutdown-timeout", 30*time.Second, "Time to wait for requests and notifications to finish on SIGTERM or interrupt, after which the requests are aborted.")
+	otelEndpoint := cmd.String("otel-endpoint", "", "Base URL of an OTLP/HTTP receiver to export traces and metrics to, such as http://localhost:4318, when OTEL_EXPORTER_OTLP_ENDPOINT is not set.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	strict := addStrictFlag(cmd)
+	readOnly := addReadOnlyFlag(cmd, "Serve without ever changing the config or history, rejecting re-rolls, pins, confirmations and portal changes, e.g. to serve the dashboard from a history which cannot be written.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *otelEndpoint != "" {
+		telemetryConfig, _ := telemetry.ConfigFromEnv()
+		telemetryConfig.Endpoint = *otelEndpoint
+		telemetryConfig.OnError = func(err error) {
+			fmt.Fprintf(os.Stderr, "Error exporting telemetry: %v\n", err)
+		}
+		shutdown, err := telemetry.Enable(telemetryConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error enabling telemetry: %v\n", err)
+			return exitCodeInvalidArguments
+		}
+		defer shutdown(context.Background())
+	}
+
+	var tenants []server.Tenant
+	if *pathToTenants != "" {
+		if *pathToTokens != "" || *oidcIssuer != "" {
+			fmt.Fprintln(os.Stderr, "-tokens and -oidc-issuer cannot be used with -tenants, each tenant has its own tokens file")
+			return exitCodeInvalidArguments
+		}
+
+		var err error
+		if tenants, err = server.LoadTenants(*pathToTenants); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tenants: %v\n", err)
+			return exitCodeInvalidConfig
+		}
+	} else {
+		tenants = []server.Tenant{{Config: *pathToConfig, History: *pathToHistory}}
+	}
+
+	for _, tenant := range tenants {
+		config, err := yapper.NewConfigFromFile(tenant.Config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+			return exitCodeInvalidConfig
+		}
+
+		if !*readOnly {
+			if err := checkHistoryWritable(tenant.History); err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking history: %v\n", err)
+				return historyExitCode(err)
+			}
+		}
+
+		hist, err := getHistoryFromFile(tenant.History, true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+			return historyExitCode(err)
+		}
+
+		if err := crossValidate(config, hist, *strict, *quiet); err != nil {
+			fmt.Fprintf(os.Stderr, "Error validating history against config: %v\n", err)
+			return exitCodeError
+		}
+	}
+
+	options := []server.Option{server.WithMaxRequestSize(*maxRequestSize)}
+	if secret := os.Getenv(portalSecretEnv); secret != "" {
+		options = append(options, server.WithPortalSecret([]byte(secret)))
+	}
+	if secret := os.Getenv(webhookSecretEnv); secret != "" {
+		options = append(options, server.WithWebhookSecret([]byte(secret)))
+	}
+	if secret := os.Getenv(slackSigningSecretEnv); secret != "" {
+		options = append(options, server.WithSlackSigningSecret([]byte(secret)))
+	}
+	if keyPath := os.Getenv(signingKeyEnv); keyPath != "" {
+		key, err := signing.ReadPrivateKey(keyPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading signing key: %v\n", err)
+			return exitCodeInvalidArguments
+		}
+		options = append(options, server.WithSigningKey(key))
+	}
+
+	provider, err := providerOptions.provider(true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error choosing provider: %v\n", err)
+		return exitCodeInvalidArguments
+	} else if provider != nil {
+		options = append(options, server.WithNotifier(provider))
+	}
+
+	if *pathToTokens != "" {
+		tokens, err := server.LoadAPITokens(*pathToTokens)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tokens: %v\n", err)
+			return exitCodeError
+		}
+		options = append(options, server.WithAPITokens(tokens))
+	}
+
+	if *oidcIssuer != "" {
+		if *oidcClientID == "" || *oidcRedirectURL == "" {
+			fmt.Fprintln(os.Stderr, "A client ID and redirect URL are required when signing in with OpenID Connect")
+			return exitCodeInvalidArguments
+		}
+
+		sessionSecret := os.Getenv(sessionSecretEnv)
+		if sessionSecret == "" {
+			fmt.Fprintf(os.Stderr, "A session secret is required in %s when signing in with OpenID Connect\n", sessionSecretEnv)
+			return exitCodeInvalidArguments
+		}
+
+		options = append(options, server.WithOIDC(server.OIDCConfig{
+			Issuer:                *oidcIssuer,
+			ClientID:              *oidcClientID,
+			ClientSecret:          os.Getenv(oidcClientSecretEnv),
+			RedirectURL:           *oidcRedirectURL,
+			Organizers:            splitList(*organizers),
+			SessionSecret:         []byte(sessionSecret),
+			TrustUnverifiedEmails: *oidcTrustUnverified,
+		}))
+	}
+
+	if *readOnly {
+		options = append(options, server.WithReadOnly())
+	}
+
+	if *leaderElection {
+		if slices.ContainsFunc(tenants, func(tenant server.Tenant) bool { return history.IsStoreURL(tenant.History) }) {
+			fmt.Fprintln(os.Stderr, "-leader-election requires history files, replicas can share a history store without it")
+			return exitCodeInvalidArguments
+		}
+		holder := *leaderID
+		if holder == "" {
+			var err error
+			if holder, err = os.Hostname(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting hostname for the leader ID: %v\n", err)
+				return exitCodeInvalidArguments
+			}
+		}
+		options = append(options, server.WithLeaderElection(server.LeaderElection{Holder: holder, TTL: *leaseTTL}))
+	}
+
+	var running daemon
+	if *pathToTenants != "" {
+		hosted, err := server.NewTenants(tenants, options...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating tenants: %v\n", err)
+			return exitCodeInvalidConfig
+		}
+		running = daemon{handler: hosted, elect: hosted.Elect, shutdown: hosted.Shutdown}
+		for _, tenant := range tenants {
+			infof(*quiet, "Serving the dashboard of %s on http://%s/t/%s/", tenant.Name, *addr, tenant.Name)
+		}
+	} else {
+		single := server.New(*pathToConfig, *pathToHistory, options...)
+		running = daemon{handler: single.Handler(), elect: single.Elect, shutdown: single.Shutdown}
+		infof(*quiet, "Serving the dashboard on http://%s", *addr)
+	}
+
+	return running.serve(*addr, *shutdownTimeout, *quiet)
+}
+
+// daemon is the handler of the server, and its leader election and shutdown, see server.Server.
+type daemon struct {
+	handler  http.Handler
+	elect    func(context.Context) error
+	shutdown func(context.Context) error
+}
+
+// serve serves the handler on the address until SIGTERM or an interrupt is received, then stops accepting requests
+// and waits up to the timeout for those in progress and any notifications to finish. Requests still running after
+// the timeout are aborted through their context, which stops any generation in progress. The lease of the leader is
+// only released once the requests have finished, so another replica does not take over while they are changing the
+// history.
+func (d daemon) serve(addr string, timeout time.Duration, quiet bool) int {
+	ctx, stop := signal.NotifyContext(commandContext, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	electCtx, stopElecting := context.WithCancel(context.WithoutCancel(commandContext))
+	elected := make(chan error, 1)
+	go func() { elected <- d.elect(electCtx) }()
+
+	requestCtx, abort := context.WithCancel(context.WithoutCancel(commandContext))
+	defer abort()
+	httpServer := &http.Server{Addr: addr, Handler: d.handler, BaseContext: func(net.Listener) context.Context { return requestCtx }}
+	served := make(chan error, 1)
+	go func() { served <- httpServer.ListenAndServe() }()
+
+	code := exitCodeSuccess
+	select {
+	case err := <-served:
+		fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+		code = exitCodeError
+	case <-ctx.Done():
+		infof(quiet, "Shutting down, waiting up to %s for requests to finish", timeout)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		context.AfterFunc(shutdownCtx, abort)
+
+		err := httpServer.Shutdown(shutdownCtx)
+		if err == nil {
+			err = d.shutdown(shutdownCtx)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error shutting down: %v\n", err)
+			code = exitCodeError
+		}
+	}
+
+	stopElecting()
+	if err := <-elected; err != nil {
+		fmt.Fprintf(os.Stderr, "Error in the leader election: %v\n", err)
+		code = exitCodeError
+	}
+	return code
+}
+
+// splitList splits a comma separated list, ignoring empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
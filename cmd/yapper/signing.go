@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/signing"
+)
+
+const (
+	// signingKeyEnv holds the path of the private key the history, plans and pairings files written are signed with.
+	signingKeyEnv = "YAPPER_SIGNING_KEY"
+	// verifyKeyEnv holds the path of the public key the history, plans and pairings files read are verified with.
+	verifyKeyEnv = "YAPPER_VERIFY_KEY"
+)
+
+// signArtifact signs the file at path, and its journal if it has one, with the key in YAPPER_SIGNING_KEY if it is set.
+// Histories in a store are not signed.
+func signArtifact(path string) error {
+	keyPath := os.Getenv(signingKeyEnv)
+	if keyPath == "" || history.IsStoreURL(path) {
+		return nil
+	}
+	key, err := signing.ReadPrivateKey(keyPath)
+	if err != nil {
+		return err
+	}
+	return signing.SignFile(key, path)
+}
+
+// verifyArtifact verifies the signature of the file at path, and its journal if it has one, with the key in
+// YAPPER_VERIFY_KEY if it is set, returning an error wrapping signing.ErrInvalid if it was changed since it was signed.
+// Missing files and histories in a store are not verified.
+func verifyArtifact(path string) error {
+	keyPath := os.Getenv(verifyKeyEnv)
+	if keyPath == "" || history.IsStoreURL(path) {
+		return nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	key, err := signing.ReadPublicKey(keyPath)
+	if err != nil {
+		return err
+	}
+	return signing.VerifyFile(key, path)
+}
+
+// artifactExitCode returns the exit code for an error reading a plan or pairings file.
+func artifactExitCode(err error) int {
+	if errors.Is(err, signing.ErrInvalid) {
+		return exitCodeInvalidSignature
+	}
+	return exitCodeError
+}
+
+// executeKey runs the key subcommand named by the first argument.
+func executeKey(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Expected a key subcommand: create")
+		return exitCodeInvalidArguments
+	}
+
+	switch args[0] {
+	case "create":
+		return executeKeyCreate(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unexpected key subcommand: %s\n", args[0])
+		return exitCodeInvalidArguments
+	}
+}
+
+// executeKeyCreate writes a new Ed25519 key pair for signing and verifying files.
+func executeKeyCreate(args []string) int {
+	cmd := newFlagSet("yapper key create")
+	pathToPrivate := cmd.String("private", "signing.pem", "Path to write the private key files are signed with to, which must be kept secret.")
+	pathToPublic := cmd.String("public", "verify.pem", "Path to write the public key signatures are verified with to.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	privatePEM, publicPEM, err := signing.NewKeys()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating keys: %v\n", err)
+		return exitCodeError
+	}
+
+	file, err := os.OpenFile(*pathToPrivate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating private key: %v\n", err)
+		return exitCodeError
+	}
+	_, err = file.Write(privatePEM)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing private key: %v\n", err)
+		return exitCodeError
+	}
+
+	if err := os.WriteFile(*pathToPublic, publicPEM, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing public key: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Created %s and %s, set %s to the private key to sign files and %s to the public key to verify them", *pathToPrivate, *pathToPublic, signingKeyEnv, verifyKeyEnv)
+	return exitCodeSuccess
+}
+
+// executeSign signs each of the files given as arguments, such as plans written by generate.
+func executeSign(args []string) int {
+	cmd := newFlagSet("yapper sign")
+	pathToKey := cmd.String("key", os.Getenv(signingKeyEnv), "Path to the private key, defaulting to "+signingKeyEnv+".")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if cmd.NArg() == 0 || *pathToKey == "" {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+
+	key, err := signing.ReadPrivateKey(*pathToKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading key: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	for _, path := range cmd.Args() {
+		if err := signing.SignFile(key, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error signing %s: %v\n", path, err)
+			return exitCodeError
+		}
+		infof(*quiet, "Signed %s in %s", path, path+signing.Suffix)
+	}
+	return exitCodeSuccess
+}
+
+// executeVerify verifies the signature of each of the files given as arguments, failing if any was changed since it
+// was signed.
+func executeVerify(args []string) int {
+	cmd := newFlagSet("yapper verify")
+	pathToKey := cmd.String("key", os.Getenv(verifyKeyEnv), "Path to the public key, defaulting to "+verifyKeyEnv+".")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+	if cmd.NArg() == 0 || *pathToKey == "" {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+
+	key, err := signing.ReadPublicKey(*pathToKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading key: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	code := exitCodeSuccess
+	for _, path := range cmd.Args() {
+		if err := signing.VerifyFile(key, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error verifying %s: %v\n", path, err)
+			code = historyExitCode(err)
+			continue
+		}
+		infof(*quiet, "Verified %s", path)
+	}
+	return code
+}
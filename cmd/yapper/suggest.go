@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// executeSuggest reports the changes to the config suggested by its constraints and the recorded history.
+func executeSuggest(args []string) int {
+	cmd := newFlagSet("yapper suggest")
+	pathsToConfig := addConfigFlag(cmd)
+	pathToHistory := cmd.String("history", "history.json", "Path to the yapper history file the squads are compared with.")
+	smallSquad := cmd.Int("small-squad", 2, "Most members a squad can have to be suggested as too small.")
+	rareShare := cmd.Float64("rare-share", 0.5, "Share of the average meetings of everyone the members of a small squad must have met less than on average to be suggested.")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+
+	config, err := yapper.NewConfigFromFiles(*pathsToConfig...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
+		return exitCodeInvalidConfig
+	}
+
+	hist, err := getHistoryFromFile(*pathToHistory, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting history from file: %v\n", err)
+		return historyExitCode(err)
+	}
+
+	options := yapper.SuggestionOptions{SmallSquad: *smallSquad, RareShare: *rareShare}
+	suggestions := yapper.SuggestConfigChanges(config, *hist.Namespace(config.Namespace), options)
+
+	if *format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(suggestions); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing suggestions: %v\n", err)
+			return exitCodeError
+		}
+		return exitCodeSuccess
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Println("No suggestions")
+	}
+	for _, suggestion := range suggestions {
+		fmt.Printf("%s: %s\n", suggestion.Kind, suggestion.Message)
+	}
+	return exitCodeSuccess
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/AleksaSvitlica/yapper/server"
+)
+
+// executeToken runs the token subcommand named by the first argument.
+func executeToken(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Expected a token subcommand: create, list or revoke")
+		return exitCodeInvalidArguments
+	}
+
+	switch args[0] {
+	case "create":
+		return executeTokenCreate(args[1:])
+	case "list":
+		return executeTokenList(args[1:])
+	case "revoke":
+		return executeTokenRevoke(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unexpected token subcommand: %s\n", args[0])
+		return exitCodeInvalidArguments
+	}
+}
+
+// executeTokenCreate adds an API token to the tokens file, writing the token to stdout as it cannot be shown again.
+func executeTokenCreate(args []string) int {
+	cmd := newFlagSet("yapper token create")
+	pathToTokens := cmd.String("tokens", "tokens.json", "Path to the API tokens file used by serve.")
+	name := cmd.String("name", "", "Name of the integration using the token.")
+	scopes := cmd.String("scopes", string(server.ScopeReadPairings), "Comma separated scopes of the token: pairings:read, pairings:generate, meetings:write or portal:links.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors and the token.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	var tokenScopes []server.Scope
+	for _, scope := range splitList(*scopes) {
+		tokenScopes = append(tokenScopes, server.Scope(scope))
+	}
+
+	token, apiToken, err := server.NewAPIToken(*name, tokenScopes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating token: %v\n", err)
+		return exitCodeInvalidArguments
+	}
+
+	tokens, err := server.LoadAPITokens(*pathToTokens)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tokens: %v\n", err)
+		return exitCodeError
+	}
+
+	if slices.ContainsFunc(tokens, func(t server.APIToken) bool { return t.Name == *name }) {
+		fmt.Fprintf(os.Stderr, "A token named %s already exists\n", *name)
+		return exitCodeError
+	}
+
+	if err := server.SaveAPITokens(*pathToTokens, append(tokens, apiToken)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving tokens: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Created token %s, which will not be shown again", *name)
+	fmt.Println(token)
+	return exitCodeSuccess
+}
+
+// executeTokenList writes the name and scopes of each API token.
+func executeTokenList(args []string) int {
+	cmd := newFlagSet("yapper token list")
+	pathToTokens := cmd.String("tokens", "tokens.json", "Path to the API tokens file used by serve.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	tokens, err := server.LoadAPITokens(*pathToTokens)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tokens: %v\n", err)
+		return exitCodeError
+	}
+
+	for _, token := range tokens {
+		fmt.Printf("%s\t%v\n", token.Name, token.Scopes)
+	}
+	return exitCodeSuccess
+}
+
+// executeTokenRevoke removes an API token from the tokens file, taking effect when the server is restarted.
+func executeTokenRevoke(args []string) int {
+	cmd := newFlagSet("yapper token revoke")
+	pathToTokens := cmd.String("tokens", "tokens.json", "Path to the API tokens file used by serve.")
+	quiet := cmd.Bool("quiet", false, "Suppress all output other than errors.")
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "Usage: yapper token revoke [flags] <name>")
+		cmd.PrintDefaults()
+		printExamples(cmd)
+	}
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	if cmd.NArg() != 1 {
+		cmd.Usage()
+		return exitCodeInvalidArguments
+	}
+
+	tokens, err := server.LoadAPITokens(*pathToTokens)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tokens: %v\n", err)
+		return exitCodeError
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(tokens), func(t server.APIToken) bool { return t.Name == cmd.Arg(0) })
+	if len(remaining) == len(tokens) {
+		fmt.Fprintf(os.Stderr, "No token found: %s\n", cmd.Arg(0))
+		return exitCodeError
+	}
+
+	if err := server.SaveAPITokens(*pathToTokens, remaining); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving tokens: %v\n", err)
+		return exitCodeError
+	}
+
+	infof(*quiet, "Revoked token %s", cmd.Arg(0))
+	return exitCodeSuccess
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// version is the semantic version of the release, set when building with -ldflags "-X main.version=v1.2.3".
+// Builds installed with go install use the version of the module instead.
+var version = ""
+
+// versionOutput describes the build of yapper and the file versions it reads and writes.
+type versionOutput struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	// CommitTime is when the commit was made, in RFC 3339.
+	CommitTime string `json:"commitTime,omitempty"`
+	// Modified is set if the build had changes which were not committed.
+	Modified             bool   `json:"modified,omitempty"`
+	GoVersion            string `json:"goVersion"`
+	ConfigSchemaVersion  int    `json:"configSchemaVersion"`
+	HistorySchemaVersion int    `json:"historySchemaVersion"`
+}
+
+// newVersionOutput returns the version of the build, read from the build info Go embeds in the binary.
+func newVersionOutput() versionOutput {
+	out := versionOutput{Version: version, ConfigSchemaVersion: yapper.ConfigSchemaVersion, HistorySchemaVersion: history.SchemaVersion}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		out.GoVersion = info.GoVersion
+		if out.Version == "" && info.Main.Version != "(devel)" {
+			out.Version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				out.Commit = setting.Value
+			case "vcs.time":
+				out.CommitTime = setting.Value
+			case "vcs.modified":
+				out.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if out.Version == "" {
+		out.Version = "dev"
+	}
+	return out
+}
+
+// executeVersion writes the version, commit and supported file versions, e.g. for bug reports.
+func executeVersion(args []string) int {
+	cmd := newFlagSet("yapper version")
+	format := cmd.String("format", formatText, "Output format, text or json.")
+	if code, ok := parseFlags(cmd, args); !ok {
+		return code
+	}
+
+	out := newVersionOutput()
+	switch *format {
+	case formatText:
+		fmt.Printf("yapper %s\n", out.Version)
+		if out.Commit != "" {
+			modified := ""
+			if out.Modified {
+				modified = " with uncommitted changes"
+			}
+			fmt.Printf("Commit: %s (%s)%s\n", out.Commit, out.CommitTime, modified)
+		}
+		fmt.Printf("Go: %s\n", out.GoVersion)
+		fmt.Printf("Config version: %d\n", out.ConfigSchemaVersion)
+		fmt.Printf("History version: %d\n", out.HistorySchemaVersion)
+	case formatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing version: %v\n", err)
+			return exitCodeError
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unexpected format: %s\n", *format)
+		return exitCodeInvalidArguments
+	}
+	return exitCodeSuccess
+}
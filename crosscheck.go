@@ -0,0 +1,75 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Mismatch is the people who are only in one of a config and its history, which are usually stale IDs or typos.
+type Mismatch struct {
+	// NotInConfig are the people with meetings in the history who are not in the config.
+	NotInConfig []ID `json:"notInConfig"`
+	// NotInHistory are the people in the config without any meetings in the history.
+	NotInHistory []ID `json:"notInHistory"`
+}
+
+// Empty returns whether the config and history have the same people.
+func (m Mismatch) Empty() bool {
+	return len(m.NotInConfig) == 0 && len(m.NotInHistory) == 0
+}
+
+// String lists the people in the mismatch.
+func (m Mismatch) String() string {
+	var parts []string
+	if len(m.NotInConfig) > 0 {
+		parts = append(parts, fmt.Sprintf("in the history but not the config: %s", joinIDs(m.NotInConfig)))
+	}
+	if len(m.NotInHistory) > 0 {
+		parts = append(parts, fmt.Sprintf("in the config but not the history: %s", joinIDs(m.NotInHistory)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CrossValidate compares the people of the config with the people who have met in the history, which should be the
+// namespace of the config. The people in the mismatch are sorted.
+// The history of a new program has no meetings, so nobody is reported as missing from an empty history.
+func CrossValidate(config Config, hist history.History) Mismatch {
+	mismatch := Mismatch{NotInConfig: []ID{}, NotInHistory: []ID{}}
+	index := config.Index()
+
+	met := map[ID]bool{}
+	for pair := range hist.All() {
+		for _, id := range pair {
+			met[ID(id)] = true
+		}
+	}
+	if len(met) == 0 {
+		return mismatch
+	}
+
+	for id := range met {
+		if _, found := index[id]; !found {
+			mismatch.NotInConfig = append(mismatch.NotInConfig, id)
+		}
+	}
+	for _, id := range config.IDs() {
+		if !met[id] {
+			mismatch.NotInHistory = append(mismatch.NotInHistory, id)
+		}
+	}
+
+	slices.Sort(mismatch.NotInConfig)
+	slices.Sort(mismatch.NotInHistory)
+	return mismatch
+}
+
+func joinIDs(ids []ID) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = string(id)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,46 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestCrossValidate(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}}}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+	hist.AddMeeting("Mario", "Yoshi", time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+	hist.AddMeeting("Luigi", "Bowser", time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC))
+
+	expected := Mismatch{NotInConfig: []ID{"Bowser", "Yoshi"}, NotInHistory: []ID{"Peach", "Toad"}}
+	mismatch := CrossValidate(config, hist)
+	if !reflect.DeepEqual(expected, mismatch) {
+		t.Errorf("Expected:\n%+v\nGot:\n%+v", expected, mismatch)
+	}
+
+	expectedMessage := "in the history but not the config: Bowser, Yoshi; in the config but not the history: Peach, Toad"
+	if message := mismatch.String(); message != expectedMessage {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedMessage, message)
+	}
+}
+
+func TestCrossValidateMatchingPeople(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+
+	if mismatch := CrossValidate(config, hist); !mismatch.Empty() {
+		t.Errorf("Expected no mismatch, got %+v", mismatch)
+	}
+}
+
+func TestCrossValidateEmptyHistory(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+
+	if mismatch := CrossValidate(config, history.History{}); !mismatch.Empty() {
+		t.Errorf("Expected no mismatch for a new program, got %+v", mismatch)
+	}
+}
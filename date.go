@@ -0,0 +1,42 @@
+package yapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Date is a calendar date without a time of day, written as YYYY-MM-DD in config files.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the Date of the given time in its own location.
+func NewDate(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("date must be a string: %w", err)
+	}
+
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD: %w", value, err)
+	}
+
+	d.Time = parsed
+	return nil
+}
+
+// Within reports whether the date is between start and end, inclusive.
+func (d Date) Within(start, end Date) bool {
+	return !d.Before(start.Time) && !d.After(end.Time)
+}
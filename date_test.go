@@ -0,0 +1,38 @@
+package yapper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateUnmarshalJSONParsesDateOnly(t *testing.T) {
+	var date Date
+	if err := json.Unmarshal([]byte(`"2025-08-01"`), &date); err != nil {
+		t.Fatalf("Unexpected error from Date.UnmarshalJSON: %v", err)
+	}
+
+	expected := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	if !date.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, date)
+	}
+}
+
+func TestDateUnmarshalJSONReturnsErrorForInvalidDate(t *testing.T) {
+	var date Date
+	if err := json.Unmarshal([]byte(`"01/08/2025"`), &date); err == nil {
+		t.Errorf("Expected error due to invalid date format")
+	}
+}
+
+func TestDateMarshalJSONWritesDateOnly(t *testing.T) {
+	date := NewDate(time.Date(2025, time.August, 1, 18, 30, 0, 0, time.UTC))
+	data, err := json.Marshal(date)
+	if err != nil {
+		t.Fatalf("Unexpected error from Date.MarshalJSON: %v", err)
+	}
+
+	if expected := `"2025-08-01"`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
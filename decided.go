@@ -0,0 +1,147 @@
+package yapper
+
+import (
+	"context"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// ContinuePairings generates rounds of pairings like GeneratePairingsContext, taking the decided pairings, such as
+// those read by NewPairingsFromFile, as the first round instead of generating it. Decided pairings without a date are
+// taken as the current round. They are recorded in the history unless it already records each of their pairs meeting
+// in the round, so a run can be repeated within a round without recording the round twice.
+func ContinuePairings(ctx context.Context, config Config, hist *history.History, decided Pairings, rounds int) ([]Pairings, error) {
+	location, err := config.Location()
+	if err != nil {
+		return nil, err
+	}
+	first := decided
+	if first.date.IsZero() {
+		first.date = config.RoundStart(time.Now().In(location))
+	}
+	date := first.date
+
+	if Recorded(*hist, first) {
+		adoptPairings(config, *hist, &first)
+	} else {
+		recordPairings(config, hist, &first)
+	}
+	if rounds <= 1 {
+		return []Pairings{first}, nil
+	}
+
+	dates := make([]time.Time, 0, rounds-1)
+	for i := 1; i < rounds; i++ {
+		dates = append(dates, date.AddDate(0, 0, i*config.RoundInterval()))
+	}
+	later, err := generateForDatesContext(ctx, config, hist, dates)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Pairings{first}, later...), nil
+}
+
+// Recorded reports whether the history records each pair of the pairings meeting on the date of the pairings, as it
+// does once the pairings have been generated or confirmed.
+func Recorded(hist history.History, pairings Pairings) bool {
+	if pairings.Len() == 0 {
+		return false
+	}
+	for id1, id2 := range pairings.All() {
+		if lastMet, met := hist.GetPersonToLastMeetingMap(history.ID(id1))[history.ID(id2)]; !met || !lastMet.Equal(pairings.Date()) {
+			return false
+		}
+	}
+	return true
+}
+
+// adoptPairings fills in the initiators and topics missing from pairings which are already recorded in the history,
+// without recording them again. When pairs previously met is only known if the pairings were read with it, as the
+// history only keeps the last meeting.
+func adoptPairings(conf Config, hist history.History, pairings *Pairings) {
+	for id1, id2 := range pairings.All() {
+		key := pairKey(id1, id2)
+		if _, designated := pairings.initiators[key]; !designated {
+			if pairings.initiators == nil {
+				pairings.initiators = make(map[[2]ID]ID)
+			}
+			pairings.initiators[key] = Initiator(hist, id1, id2)
+		}
+
+		if pairings.topics[key] != "" || conf.RoundTopic(pairings.Date()) == "" {
+			continue
+		}
+		if discussed := hist.Topics(history.ID(id1), history.ID(id2)); len(discussed) > 0 {
+			if pairings.topics == nil {
+				pairings.topics = make(map[[2]ID]string)
+			}
+			pairings.topics[key] = discussed[len(discussed)-1]
+		}
+	}
+}
+
+// SetLastRound records the pairings in the history as the last round generated, so that LastRound can return them to
+// a run repeated within the round. The people in the config who could have met but were not paired are counted too,
+// see history.History.TimesUnpaired.
+func SetLastRound(config Config, hist *history.History, pairings Pairings) {
+	hist.SetLastRound(NewHistoryRound(config, pairings))
+}
+
+// LastRound returns the pairings last recorded by SetLastRound, and false if there are none.
+func LastRound(hist history.History) (Pairings, bool) {
+	round, found := hist.LastRound()
+	if !found {
+		return Pairings{}, false
+	}
+	return NewPairingsFromRound(round), true
+}
+
+// NewHistoryRound returns the pairings as a round of the history, with the people left unpaired who could have met.
+func NewHistoryRound(config Config, pairings Pairings) history.Round {
+	round := history.Round{Date: pairings.Date(), Pairs: make([]history.RoundPair, 0, pairings.Len())}
+	for _, pair := range pairings.data {
+		key := pairKey(pair[0], pair[1])
+		roundPair := history.RoundPair{People: [2]history.ID{history.ID(pair[0]), history.ID(pair[1])}, Initiator: history.ID(pairings.initiators[key]), Topic: pairings.topics[key]}
+		if lastMeeting, met := pairings.lastMeetings[key]; met {
+			roundPair.LastMet = &lastMeeting
+		}
+		round.Pairs = append(round.Pairs, roundPair)
+	}
+
+	for _, diagnostic := range NewResult(config, []Pairings{pairings}).Rounds[0].Diagnostics {
+		if diagnostic.Reason == "" {
+			round.Unpaired = append(round.Unpaired, history.ID(diagnostic.Person))
+		}
+	}
+	return round
+}
+
+// NewPairingsFromRound returns the pairings of the round of the history, such as one written by NewHistoryRound.
+func NewPairingsFromRound(round history.Round) Pairings {
+	pairings := Pairings{data: make([][2]ID, 0, len(round.Pairs)), date: round.Date}
+	for _, roundPair := range round.Pairs {
+		pair := [2]ID{ID(roundPair.People[0]), ID(roundPair.People[1])}
+		pairings.data = append(pairings.data, pair)
+		key := pairKey(pair[0], pair[1])
+		if roundPair.LastMet != nil {
+			if pairings.lastMeetings == nil {
+				pairings.lastMeetings = make(map[[2]ID]time.Time)
+			}
+			pairings.lastMeetings[key] = *roundPair.LastMet
+		}
+		if roundPair.Initiator != "" {
+			if pairings.initiators == nil {
+				pairings.initiators = make(map[[2]ID]ID)
+			}
+			pairings.initiators[key] = ID(roundPair.Initiator)
+		}
+		if roundPair.Topic != "" {
+			if pairings.topics == nil {
+				pairings.topics = make(map[[2]ID]string)
+			}
+			pairings.topics[key] = roundPair.Topic
+		}
+	}
+	return pairings
+}
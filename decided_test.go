@@ -0,0 +1,112 @@
+package yapper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestContinuePairingsOnlyRecordsDecidedPairingsOnce(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}}}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	decided := NewPairings(date, [][2]ID{{"Mario", "Peach"}, {"Luigi", "Toad"}})
+
+	hist := history.History{}
+	first, err := ContinuePairings(context.Background(), config, &hist, decided, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error continuing pairings: %v", err)
+	}
+	recorded := hist.Clone()
+
+	again, err := ContinuePairings(context.Background(), config, &hist, first[0], 1)
+	if err != nil {
+		t.Fatalf("Unexpected error continuing pairings: %v", err)
+	}
+
+	if !reflect.DeepEqual(hist, recorded) {
+		t.Errorf("Expected the history to be unchanged by the second run\nExpected:\n%v\nGot:\n%v", recorded, hist)
+	}
+	if times := hist.TimesMet("Mario", "Peach"); times != 1 {
+		t.Errorf("Expected Mario and Peach to have met once, got %d", times)
+	}
+	if !reflect.DeepEqual(again[0].initiators, first[0].initiators) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", first[0].initiators, again[0].initiators)
+	}
+}
+
+func TestContinuePairingsGeneratesTheRoundsAfterTheDecidedPairings(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}}}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	decided := NewPairings(date, [][2]ID{{"Mario", "Peach"}, {"Luigi", "Toad"}})
+
+	hist := history.History{}
+	weeklyPairings, err := ContinuePairings(context.Background(), config, &hist, decided, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error continuing pairings: %v", err)
+	}
+
+	if len(weeklyPairings) != 3 {
+		t.Fatalf("Expected 3 rounds, got %d", len(weeklyPairings))
+	}
+	if !weeklyPairings[0].Contains("Mario", "Peach") || !weeklyPairings[0].Contains("Luigi", "Toad") {
+		t.Errorf("Expected the decided pairings as the first round, got %v", weeklyPairings[0].data)
+	}
+	for i, pairings := range weeklyPairings {
+		if expected := date.AddDate(0, 0, 7*i); !pairings.Date().Equal(expected) {
+			t.Errorf("Expected:\n%v\nGot:\n%v", expected, pairings.Date())
+		}
+	}
+	if weeklyPairings[1].Contains("Mario", "Peach") {
+		t.Error("Expected the second round to avoid the pairs of the decided round")
+	}
+}
+
+func TestRecorded(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	pairings := NewPairings(date, [][2]ID{{"Mario", "Peach"}})
+
+	hist := history.History{}
+	if Recorded(hist, pairings) {
+		t.Error("Expected pairings missing from the history not to be recorded")
+	}
+
+	hist.AddMeeting("Mario", "Peach", date.AddDate(0, 0, -7))
+	if Recorded(hist, pairings) {
+		t.Error("Expected pairings whose pairs last met in another round not to be recorded")
+	}
+
+	hist.AddMeeting("Mario", "Peach", date)
+	if !Recorded(hist, pairings) {
+		t.Error("Expected pairings whose pairs met in the round to be recorded")
+	}
+}
+
+func TestLastRoundReturnsThePairingsSetAsTheLastRound(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}}}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Peach", date.AddDate(0, 0, -7))
+
+	generated, err := ContinuePairings(context.Background(), config, &hist, NewPairings(date, [][2]ID{{"Mario", "Peach"}, {"Luigi", "Toad"}}), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error continuing pairings: %v", err)
+	}
+	if _, found := LastRound(hist); found {
+		t.Errorf("Expected no last round before one is set")
+	}
+
+	SetLastRound(config, &hist, generated[0])
+	last, found := LastRound(hist)
+	if !found {
+		t.Fatalf("Expected the last round to be found")
+	}
+	if !reflect.DeepEqual(generated[0].data, last.data) || !last.Date().Equal(date) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", generated[0].data, last.data)
+	}
+	if !reflect.DeepEqual(generated[0].initiators, last.initiators) || !reflect.DeepEqual(generated[0].lastMeetings, last.lastMeetings) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", generated[0], last)
+	}
+}
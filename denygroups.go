@@ -0,0 +1,33 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+)
+
+// validateDenyGroups checks the deny groups referred to by each person are defined.
+func (c Config) validateDenyGroups() error {
+	for _, person := range c.People {
+		for _, group := range person.DenyGroups {
+			if _, exists := c.DenyGroups[group]; !exists {
+				return fmt.Errorf("unknown deny group for %s: %s", person.ID, group)
+			}
+		}
+	}
+	return nil
+}
+
+// denies reports whether the person will not be paired with the other, either through their deny list or the members
+// of their deny groups.
+func (c Config) denies(person, other Person) bool {
+	if slices.Contains(person.DenyList, other.ID) {
+		return true
+	}
+
+	for _, group := range person.DenyGroups {
+		if slices.Contains(c.DenyGroups[group], other.ID) {
+			return true
+		}
+	}
+	return false
+}
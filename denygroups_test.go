@@ -0,0 +1,44 @@
+package yapper
+
+import "testing"
+
+func TestDetermineValidPairingsExcludesDenyGroupMembers(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", DenyGroups: []string{"legal-conflicts"}},
+			{ID: "Luigi"},
+			{ID: "Peach"},
+			{ID: "Toad"},
+		},
+		DenyGroups: map[string][]ID{"legal-conflicts": {"Luigi", "Peach"}},
+	}
+
+	expected := map[ID][]ID{
+		"Mario": {"Toad"},
+		"Luigi": {"Peach", "Toad"},
+		"Peach": {"Luigi", "Toad"},
+		"Toad":  {"Mario", "Luigi", "Peach"},
+	}
+	diffPairings(t, determineValidPairings(config), expected)
+}
+
+func TestValidatePairingsReportsDenyGroupMembers(t *testing.T) {
+	config := Config{
+		People:     []Person{{ID: "Mario"}, {ID: "Luigi", DenyGroups: []string{"plumbers"}}},
+		DenyGroups: map[string][]ID{"plumbers": {"Mario"}},
+	}
+	pairings := Pairings{}
+	pairings.Add("Mario", "Luigi")
+
+	violations := ValidatePairings(config, pairings)
+	if len(violations) != 1 || violations[0].Kind != ViolationDenyList {
+		t.Errorf("Expected a deny list violation, got %v", violations)
+	}
+}
+
+func TestConfigValidateReturnsErrorForUnknownDenyGroup(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario", DenyGroups: []string{"koopas"}}}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected error due to unknown deny group")
+	}
+}
@@ -0,0 +1,56 @@
+package yapper
+
+import "slices"
+
+// Exclusion is a pair of people who are never paired because of a deny list or deny group.
+type Exclusion struct {
+	// People are the two people, with the person excluding the other first when it is not mutual.
+	People [2]ID `json:"people"`
+	// Mutual is whether both people have excluded each other.
+	Mutual bool `json:"mutual"`
+}
+
+// Exclusions returns the pairs of people excluded by the deny lists and deny groups in config order. Pairings treat
+// each exclusion as applying both ways, even when only one of the people listed the other.
+func (c Config) Exclusions() []Exclusion {
+	exclusions := []Exclusion{}
+	for i, person := range c.People {
+		for _, other := range c.People[i+1:] {
+			excludes, excluded := c.denies(person, other), c.denies(other, person)
+			switch {
+			case excludes && excluded:
+				exclusions = append(exclusions, Exclusion{People: [2]ID{person.ID, other.ID}, Mutual: true})
+			case excludes:
+				exclusions = append(exclusions, Exclusion{People: [2]ID{person.ID, other.ID}})
+			case excluded:
+				exclusions = append(exclusions, Exclusion{People: [2]ID{other.ID, person.ID}})
+			}
+		}
+	}
+	return exclusions
+}
+
+// NormalizeDenyLists returns the config with everyone on a deny list also listing the person who listed them, so the
+// deny lists read the same way pairings treat them. People already excluding the other through a deny group are left
+// as they are. The number of entries added is also returned.
+func (c Config) NormalizeDenyLists() (Config, int) {
+	people := slices.Clone(c.People)
+	for i := range people {
+		people[i].DenyList = slices.Clone(people[i].DenyList)
+	}
+
+	added := 0
+	for _, person := range c.People {
+		for _, id := range person.DenyList {
+			index := slices.IndexFunc(people, func(p Person) bool { return p.ID == id })
+			if index == -1 || id == person.ID || c.denies(people[index], person) {
+				continue
+			}
+			people[index].DenyList = append(people[index].DenyList, person.ID)
+			added++
+		}
+	}
+
+	c.People = people
+	return c, added
+}
@@ -0,0 +1,58 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExclusionsReportsWhetherEachIsMutual(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", DenyList: []ID{"Wario", "Bowser"}},
+			{ID: "Wario", DenyList: []ID{"Mario"}},
+			{ID: "Peach", DenyGroups: []string{"koopas"}},
+			{ID: "Bowser"},
+		},
+		DenyGroups: map[string][]ID{"koopas": {"Bowser"}},
+	}
+
+	expected := []Exclusion{
+		{People: [2]ID{"Mario", "Wario"}, Mutual: true},
+		{People: [2]ID{"Mario", "Bowser"}},
+		{People: [2]ID{"Peach", "Bowser"}},
+	}
+	if exclusions := config.Exclusions(); !reflect.DeepEqual(expected, exclusions) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, exclusions)
+	}
+}
+
+func TestNormalizeDenyListsAddsReciprocalEntries(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", DenyList: []ID{"Wario", "Bowser", "Toad"}},
+			{ID: "Wario", DenyList: []ID{"Mario"}},
+			{ID: "Bowser", DenyGroups: []string{"bros"}},
+			{ID: "Toad"},
+		},
+		DenyGroups: map[string][]ID{"bros": {"Mario"}},
+	}
+
+	normalized, added := config.NormalizeDenyLists()
+	if added != 1 {
+		t.Errorf("Expected 1 entry to be added, got %d", added)
+	}
+
+	expected := []Person{
+		{ID: "Mario", DenyList: []ID{"Wario", "Bowser", "Toad"}},
+		{ID: "Wario", DenyList: []ID{"Mario"}},
+		{ID: "Bowser", DenyGroups: []string{"bros"}},
+		{ID: "Toad", DenyList: []ID{"Mario"}},
+	}
+	if !reflect.DeepEqual(expected, normalized.People) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, normalized.People)
+	}
+
+	if config.People[3].DenyList != nil {
+		t.Errorf("Expected the original config to be unchanged, got %v", config.People[3])
+	}
+}
@@ -0,0 +1,94 @@
+package yapper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// DiversityAttribute is the attribute of people whose distinct values the partners of someone should span.
+type DiversityAttribute string
+
+const (
+	// DiversityBySquad counts the distinct squads of the partners.
+	DiversityBySquad DiversityAttribute = "squad"
+	// DiversityByLocation counts the distinct locations of the partners.
+	DiversityByLocation DiversityAttribute = "location"
+)
+
+func (a DiversityAttribute) validate() error {
+	switch a {
+	case "", DiversityBySquad, DiversityByLocation:
+		return nil
+	default:
+		return fmt.Errorf("unexpected diversity attribute: %s", a)
+	}
+}
+
+// of returns the value of the attribute for the person, defaulting to their squad.
+func (a DiversityAttribute) of(person Person) string {
+	if a == DiversityByLocation {
+		return person.Location
+	}
+	return person.Squad
+}
+
+// diversityEnabled reports whether the partner diversity soft constraint can result in a penalty.
+func (s SoftConstraints) diversityEnabled() bool {
+	return s.Diversity > 0 && s.DiversityWeeks > 0 && s.DiversityMin > 0
+}
+
+// diversityPenalty returns the penalty for pairing the two people when either of them met fewer than the minimum
+// distinct squads or locations within the diversity weeks before date, and the other is of one they already met. It
+// is applied once for each of the people it applies to.
+func diversityPenalty(conf Config, recent partnerValues, person1, person2 Person) float64 {
+	weights := conf.SoftConstraints
+	if !weights.diversityEnabled() {
+		return 0
+	}
+
+	penalty := 0.0
+	for _, pair := range [][2]Person{{person1, person2}, {person2, person1}} {
+		value := weights.DiversityBy.of(pair[1])
+		if value == "" {
+			continue
+		}
+		met := recent[pair[0].ID]
+		if _, repeated := met[value]; repeated && len(met) < weights.DiversityMin {
+			penalty += weights.Diversity
+		}
+	}
+	return penalty
+}
+
+// partnerValues are the distinct squads or locations of the recent partners of each person, by their ID.
+type partnerValues map[ID]map[string]struct{}
+
+// recentPartnerValues returns the distinct squads or locations of the partners each of the people last met within the
+// diversity weeks before date, looking the partners up in the index of the config. Nil is returned without the
+// diversity soft constraint.
+func recentPartnerValues(conf Config, index map[ID]Person, hist history.History, date time.Time, people []Person) partnerValues {
+	weights := conf.SoftConstraints
+	if !weights.diversityEnabled() {
+		return nil
+	}
+	since := date.AddDate(0, 0, -7*weights.DiversityWeeks)
+
+	recent := make(partnerValues, len(people))
+	for _, person := range people {
+		values := map[string]struct{}{}
+		for other, lastMeeting := range hist.PartnersOf(history.ID(person.ID)) {
+			if lastMeeting.Before(since) || !lastMeeting.Before(date) {
+				continue
+			}
+			if partner, found := index[ID(other)]; found {
+				if value := weights.DiversityBy.of(partner); value != "" {
+					values[value] = struct{}{}
+				}
+			}
+		}
+		recent[person.ID] = values
+	}
+	return recent
+}
@@ -0,0 +1,98 @@
+package yapper
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestDiversityPenalty(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Squad: "plumbers", Location: "Mushroom Kingdom"},
+			{ID: "Peach", Squad: "royals", Location: "Mushroom Kingdom"},
+			{ID: "Daisy", Squad: "royals", Location: "Sarasaland"},
+			{ID: "Bowser", Squad: "koopas", Location: "Koopa Kingdom"},
+			{ID: "Luigi", Squad: "plumbers", Location: "Mushroom Kingdom"},
+		},
+		SoftConstraints: SoftConstraints{Diversity: 2, DiversityWeeks: 4, DiversityMin: 2},
+	}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Peach", date.AddDate(0, 0, -7))
+	hist.AddMeeting("Mario", "Bowser", date.AddDate(0, 0, -70))
+
+	tests := map[string]struct {
+		by       DiversityAttribute
+		other    ID
+		expected float64
+	}{
+		"squad met recently":        {"", "Daisy", 2},
+		"squad not met recently":    {"", "Bowser", 0},
+		"location met recently":     {DiversityByLocation, "Luigi", 2},
+		"location not met recently": {DiversityByLocation, "Daisy", 0},
+		"squad new to both":         {"", "Luigi", 0},
+	}
+
+	for name, test := range tests {
+		config := config
+		config.SoftConstraints.DiversityBy = test.by
+		mario, _ := config.GetPerson("Mario")
+		other, _ := config.GetPerson(test.other)
+		if got := diversityPenalty(config, recentPartnerValues(config, config.Index(), hist, date, config.People), mario, other); got != test.expected {
+			t.Errorf("Expected %s penalty:\n%v\nGot:\n%v", name, test.expected, got)
+		}
+	}
+}
+
+func TestDiversityPenaltyStopsOnceTheMinimumIsMet(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Squad: "plumbers"},
+			{ID: "Peach", Squad: "royals"},
+			{ID: "Bowser", Squad: "koopas"},
+			{ID: "Daisy", Squad: "royals"},
+		},
+		SoftConstraints: SoftConstraints{Diversity: 1, DiversityWeeks: 4, DiversityMin: 2},
+	}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Peach", date.AddDate(0, 0, -14))
+	hist.AddMeeting("Mario", "Bowser", date.AddDate(0, 0, -7))
+
+	mario, _ := config.GetPerson("Mario")
+	daisy, _ := config.GetPerson("Daisy")
+	if got := diversityPenalty(config, recentPartnerValues(config, config.Index(), hist, date, config.People), mario, daisy); got != 0 {
+		t.Errorf("Expected no penalty once the partners span the minimum squads, got %v", got)
+	}
+}
+
+func TestPrioritiseLowestPenaltyPrefersPartnersOfOtherSquads(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Squad: "plumbers"},
+			{ID: "Peach", Squad: "royals"},
+			{ID: "Daisy", Squad: "royals"},
+			{ID: "Bowser", Squad: "koopas"},
+		},
+		SoftConstraints: SoftConstraints{Diversity: 1, DiversityWeeks: 4, DiversityMin: 2},
+	}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Peach", date.AddDate(0, 0, -7))
+
+	expected := []ID{"Bowser", "Daisy"}
+	got := prioritiseLowestPenalty(newRoundScorer(config, hist, date), "Mario", []ID{"Daisy", "Bowser"})
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+	}
+}
+
+func TestValidateRejectsUnexpectedDiversityAttribute(t *testing.T) {
+	config := Config{SoftConstraints: SoftConstraints{DiversityBy: "team"}}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected an error validating an unexpected diversity attribute")
+	}
+}
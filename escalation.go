@@ -0,0 +1,85 @@
+package yapper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Escalation alerts the organizers when someone is left unpaired round after round, which is usually a config
+// problem such as over-restrictive deny lists.
+type Escalation struct {
+	// UnpairedRounds is how many consecutive rounds someone is unpaired in before the organizers are alerted.
+	UnpairedRounds int `json:"unpairedRounds"`
+}
+
+func (e Escalation) validate() error {
+	if e.UnpairedRounds <= 0 {
+		return fmt.Errorf("escalation unpaired rounds must be positive: %d", e.UnpairedRounds)
+	}
+	return nil
+}
+
+// Streak is how many consecutive rounds someone was not paired in, up to and including the latest.
+type Streak struct {
+	Person ID  `json:"person"`
+	Rounds int `json:"rounds"`
+	// Message explains why they were not paired in the latest round.
+	Message string `json:"message"`
+}
+
+// UnpairedStreaks returns the people who were not paired in the round of the pairings, nor in the rounds recorded in
+// the history before it, for at least the unpaired rounds of the escalation of the config. Rounds people were paused
+// or away in are not counted, and nothing is returned without an escalation.
+func UnpairedStreaks(config Config, hist history.History, pairings Pairings) []Streak {
+	if config.Escalation == nil {
+		return nil
+	}
+
+	var earlier []time.Time
+	for _, round := range RecordedRounds(config, hist) {
+		if round.date.Before(pairings.date) {
+			earlier = append(earlier, round.date)
+		}
+	}
+
+	round := NewResult(config, []Pairings{pairings}).Rounds[0]
+	streaks := []Streak{}
+	index := config.Index()
+	quorums := map[time.Time]roundQuorum{}
+	for _, diagnostic := range round.Diagnostics {
+		if diagnostic.Reason != "" {
+			continue
+		}
+
+		person, found := index[diagnostic.Person]
+		if !found {
+			continue
+		}
+
+		var lastMeeting time.Time
+		for _, meetingTime := range hist.GetPersonToLastMeetingMap(history.ID(person.ID)) {
+			if meetingTime.After(lastMeeting) {
+				lastMeeting = meetingTime
+			}
+		}
+
+		rounds := 1
+		for i := len(earlier) - 1; i >= 0 && lastMeeting.Before(earlier[i]); i-- {
+			quorum, found := quorums[earlier[i]]
+			if !found {
+				quorum = config.quorumOf(earlier[i])
+				quorums[earlier[i]] = quorum
+			}
+			if config.ineligibility(person, earlier[i], quorum) == "" {
+				rounds++
+			}
+		}
+
+		if rounds >= config.Escalation.UnpairedRounds {
+			streaks = append(streaks, Streak{Person: person.ID, Rounds: rounds, Message: diagnostic.Message})
+		}
+	}
+	return streaks
+}
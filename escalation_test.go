@@ -0,0 +1,50 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestValidateReturnsErrorForNonPositiveUnpairedRounds(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}}, Escalation: &Escalation{}}
+
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to escalating after no unpaired rounds")
+	}
+}
+
+func TestUnpairedStreaksCountConsecutiveUnpairedRounds(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario"},
+			{ID: "Luigi"},
+			{ID: "Peach"},
+			{ID: "Toad", DenyList: []ID{"Mario", "Luigi", "Peach"}},
+			{ID: "Yoshi", Paused: true},
+		},
+		Escalation: &Escalation{UnpairedRounds: 3},
+	}
+	first := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Toad", first)
+	hist.AddMeeting("Mario", "Luigi", first.AddDate(0, 0, 7))
+	hist.AddMeeting("Luigi", "Peach", first.AddDate(0, 0, 14))
+
+	streaks := UnpairedStreaks(config, hist, NewPairings(first.AddDate(0, 0, 21), [][2]ID{{"Mario", "Peach"}}))
+
+	expected := []Streak{{Person: "Toad", Rounds: 3, Message: "everyone Toad can be paired with was already paired or cannot meet"}}
+	if !reflect.DeepEqual(expected, streaks) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, streaks)
+	}
+}
+
+func TestUnpairedStreaksAreEmptyWithoutEscalation(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}}}
+
+	if streaks := UnpairedStreaks(config, history.History{}, NewPairings(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), nil)); streaks != nil {
+		t.Errorf("Expected no streaks, got %v", streaks)
+	}
+}
@@ -0,0 +1,156 @@
+package yapper
+
+import (
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Metrics describe the quality of rounds of pairings, for comparing strategies on the same people and dates.
+type Metrics struct {
+	Rounds   int `json:"rounds"`
+	Pairings int `json:"pairings"`
+	// UniquePairs is the number of different pairs who met.
+	UniquePairs int `json:"uniquePairs"`
+	// Repeats is the number of pairings of people who had already met in an earlier round.
+	Repeats int `json:"repeats"`
+	// Coverage is the fraction of the valid pairs who met.
+	Coverage float64 `json:"coverage"`
+	// Unpaired is the number of times someone who could meet in a round was not paired.
+	Unpaired int `json:"unpaired"`
+	// Penalty is the total penalty of the soft constraints.
+	Penalty float64 `json:"penalty"`
+}
+
+// RecordedRounds returns the rounds of the history, grouping meetings by the round containing them.
+// The history only keeps the last meeting of each pair, so earlier meetings of pairs who met again are missing.
+func RecordedRounds(config Config, hist history.History) []Pairings {
+	byDate := map[time.Time]*Pairings{}
+	index := config.Index()
+	for _, person := range config.People {
+		for other, meetingTime := range hist.GetPersonToLastMeetingMap(history.ID(person.ID)) {
+			if _, found := index[ID(other)]; !found || ID(other) < person.ID {
+				continue
+			}
+
+			year, month, day := meetingTime.Date()
+			date := config.RoundStart(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+			if byDate[date] == nil {
+				byDate[date] = &Pairings{date: date}
+			}
+			byDate[date].Add(person.ID, ID(other))
+		}
+	}
+
+	rounds := make([]Pairings, 0, len(byDate))
+	for _, pairings := range byDate {
+		rounds = append(rounds, *pairings)
+	}
+	slices.SortFunc(rounds, func(a, b Pairings) int { return a.date.Compare(b.date) })
+	return rounds
+}
+
+// Replay generates a round of pairings for each of the dates with the strategy, starting from an empty history.
+func Replay(config Config, strategy Strategy, dates []time.Time) ([]Pairings, error) {
+	config.Settings.Strategy = strategy
+	config.Settings.Strategies = nil
+	hist := history.History{}
+	return generateForDates(config, &hist, dates)
+}
+
+// Evaluate returns the metrics of the rounds, which are replayed in order from an empty history.
+func Evaluate(config Config, rounds []Pairings) Metrics {
+	metrics := Metrics{Rounds: len(rounds)}
+	idToValidPairings := determineValidPairings(config)
+	hist := history.History{}
+	met := map[[2]ID]bool{}
+
+	for _, round := range rounds {
+		metrics.Penalty += Penalty(config, hist, round)
+
+		paired := map[ID]bool{}
+		for id1, id2 := range round.All() {
+			metrics.Pairings++
+			if met[pairKey(id1, id2)] {
+				metrics.Repeats++
+			}
+			met[pairKey(id1, id2)] = true
+			paired[id1], paired[id2] = true, true
+			hist.AddMeeting(history.ID(id1), history.ID(id2), round.date)
+		}
+
+		ineligible := getIneligiblePeople(config, idToValidPairings, round.date)
+		for id := range idToValidPairings {
+			if !paired[id] && !slices.Contains(ineligible, id) {
+				metrics.Unpaired++
+			}
+		}
+	}
+
+	validPairs := 0
+	for id, validPairings := range idToValidPairings {
+		for _, other := range validPairings {
+			if id < other {
+				validPairs++
+			}
+		}
+	}
+
+	metrics.UniquePairs = len(met)
+	if validPairs > 0 {
+		metrics.Coverage = float64(len(met)) / float64(validPairs)
+	}
+	return metrics
+}
+
+// Coverage returns the fraction of the valid pairs of people in the config who met before the round of the pairings,
+// and the fraction who will have met once the pairings meet. Pairs whose last meeting is in or after the round count
+// as having met before it only if the history has counted more than one meeting of theirs.
+func Coverage(config Config, hist history.History, pairings Pairings) (before, after float64) {
+	inRound := map[[2]ID]bool{}
+	for id1, id2 := range pairings.All() {
+		inRound[pairKey(id1, id2)] = true
+	}
+
+	validPairs, metBefore, metAfter := 0, 0, 0
+	for id, validPairings := range determineValidPairings(config) {
+		for _, other := range validPairings {
+			if id >= other {
+				continue
+			}
+			validPairs++
+
+			lastMeeting, met := hist.GetPersonToLastMeetingMap(history.ID(id))[history.ID(other)]
+			if met && (lastMeeting.Before(pairings.date) || hist.TimesMet(history.ID(id), history.ID(other)) > 1) {
+				metBefore++
+				metAfter++
+			} else if inRound[pairKey(id, other)] {
+				metAfter++
+			}
+		}
+	}
+
+	if validPairs == 0 {
+		return 0, 0
+	}
+	return float64(metBefore) / float64(validPairs), float64(metAfter) / float64(validPairs)
+}
+
+// UnmetPairs returns the valid pairs of people in the config who have never met, in config order.
+func UnmetPairs(config Config, hist history.History) [][2]ID {
+	people := make([]history.ID, 0, len(config.People))
+	for _, person := range config.People {
+		people = append(people, history.ID(person.ID))
+	}
+
+	idToValidPairings := determineValidPairings(config)
+	unmet := [][2]ID{}
+	for _, pair := range history.UnmetPairs(hist, people) {
+		id1, id2 := ID(pair[0]), ID(pair[1])
+		if slices.Contains(idToValidPairings[id1], id2) {
+			unmet = append(unmet, [2]ID{id1, id2})
+		}
+	}
+	return unmet
+}
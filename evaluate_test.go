@@ -0,0 +1,84 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+var evaluateConfig = Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}}}
+
+func TestRecordedRoundsGroupsMeetingsByRound(t *testing.T) {
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+	hist.AddMeeting("Peach", "Toad", time.Date(2025, time.August, 6, 0, 0, 0, 0, time.UTC))
+	hist.AddMeeting("Mario", "Peach", time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC))
+	hist.AddMeeting("Mario", "Bowser", time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC))
+
+	rounds := RecordedRounds(evaluateConfig, hist)
+
+	if len(rounds) != 2 {
+		t.Fatalf("Expected 2 rounds, got %v", rounds)
+	}
+	if expected := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC); !rounds[0].Date().Equal(expected) || len(rounds[0].data) != 2 {
+		t.Errorf("Expected 2 pairings in the round starting %v, got %v", expected, rounds[0])
+	}
+	if !reflect.DeepEqual(rounds[1].data, [][2]ID{{"Mario", "Peach"}}) {
+		t.Errorf("Expected Mario and Peach in the second round, got %v", rounds[1].data)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	first := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	rounds := []Pairings{
+		{date: first, data: [][2]ID{{"Mario", "Luigi"}, {"Peach", "Toad"}}},
+		{date: first.AddDate(0, 0, 7), data: [][2]ID{{"Luigi", "Mario"}}},
+	}
+
+	expected := Metrics{Rounds: 2, Pairings: 3, UniquePairs: 2, Repeats: 1, Coverage: 2.0 / 6, Unpaired: 2}
+	if metrics := Evaluate(evaluateConfig, rounds); !reflect.DeepEqual(expected, metrics) {
+		t.Errorf("Expected:\n%+v\nGot:\n%+v", expected, metrics)
+	}
+}
+
+func TestReplayStartsFromEmptyHistory(t *testing.T) {
+	first := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	dates := []time.Time{first, first.AddDate(0, 0, 7), first.AddDate(0, 0, 14)}
+
+	for _, strategy := range Strategies {
+		rounds, err := Replay(evaluateConfig, strategy, dates)
+		if err != nil {
+			t.Fatalf("Unexpected error replaying with %s: %v", strategy, err)
+		}
+
+		if metrics := Evaluate(evaluateConfig, rounds); metrics.Repeats != 0 || metrics.Coverage != 1 {
+			t.Errorf("Expected %s to meet everyone without repeats in 3 rounds, got %+v", strategy, metrics)
+		}
+	}
+}
+
+func TestUnmetPairsLeavesOutPairsWhoCannotMeet(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario", DenyList: []ID{"Bowser"}}, {ID: "Luigi"}, {ID: "Bowser"}}}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+
+	expected := [][2]ID{{"Luigi", "Bowser"}}
+	if unmet := UnmetPairs(config, hist); !reflect.DeepEqual(expected, unmet) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, unmet)
+	}
+}
+
+func TestCoverageCountsThePairsMetBeforeAndAfterTheRound(t *testing.T) {
+	date := time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -7))
+	hist.AddMeeting("Peach", "Toad", date)
+
+	before, after := Coverage(evaluateConfig, hist, NewPairings(date, [][2]ID{{"Mario", "Peach"}, {"Toad", "Peach"}}))
+
+	if before != 1.0/6 || after != 3.0/6 {
+		t.Errorf("Expected:\n%v %v\nGot:\n%v %v", 1.0/6, 3.0/6, before, after)
+	}
+}
@@ -0,0 +1,117 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// GenerateEvent generates the given number of short rounds of a one-off event on the date, such as a mixer,
+// recording each in the history. Nobody meets the same person twice during the event.
+// The event is treated as a round lasting only the date, so absences, holidays and pins only apply if they cover it,
+// and cadences, onboarding and the curriculum do not apply as the event is not one of the regular rounds.
+func GenerateEvent(config Config, hist *history.History, date time.Time, rounds int) ([]Pairings, error) {
+	if rounds < 1 {
+		return nil, fmt.Errorf("an event needs at least one round: %d", rounds)
+	}
+
+	event := eventConfig(config)
+	year, month, day := date.Date()
+	date = time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+
+	idToValidPairings := determineValidPairings(event)
+	eventRounds := make([]Pairings, 0, rounds)
+	for range rounds {
+		pairings := pairPeople(event, idToValidPairings, *hist, date)
+		pairEveryone(event, idToValidPairings, &pairings)
+		pairings.penalty = Penalty(event, *hist, pairings)
+		recordPairings(event, hist, &pairings)
+		eventRounds = append(eventRounds, pairings)
+
+		for id1, id2 := range pairings.All() {
+			idToValidPairings[id1] = slices.DeleteFunc(idToValidPairings[id1], func(id ID) bool { return id == id2 })
+			idToValidPairings[id2] = slices.DeleteFunc(idToValidPairings[id2], func(id ID) bool { return id == id1 })
+		}
+	}
+
+	return eventRounds, nil
+}
+
+// pairEveryone pairs anyone left without a partner by moving the partners of others along an augmenting path,
+// so everyone who can meet in a round of the event has a partner where possible. Pinned pairs are not moved.
+func pairEveryone(conf Config, idToValidPairings map[ID][]ID, pairings *Pairings) {
+	ineligible := getIneligiblePeople(conf, idToValidPairings, pairings.date)
+	pinned := pinnedPairings(conf, idToValidPairings, ineligible, pairings.date)
+
+	partners := map[ID]ID{}
+	for id1, id2 := range pairings.All() {
+		partners[id1], partners[id2] = id2, id1
+	}
+
+	var augment func(id ID, visited map[ID]bool) bool
+	augment = func(id ID, visited map[ID]bool) bool {
+		visited[id] = true
+		for _, other := range idToValidPairings[id] {
+			if visited[other] || slices.Contains(ineligible, other) {
+				continue
+			}
+			visited[other] = true
+
+			partner, paired := partners[other]
+			if !paired || augment(partner, visited) {
+				partners[id], partners[other] = other, id
+				return true
+			}
+		}
+		return false
+	}
+
+	changed := false
+	for _, person := range conf.People {
+		if _, paired := partners[person.ID]; paired || slices.Contains(ineligible, person.ID) {
+			continue
+		}
+
+		visited := map[ID]bool{}
+		for _, pin := range pinned {
+			visited[pin[0]], visited[pin[1]] = true, true
+		}
+		changed = augment(person.ID, visited) || changed
+	}
+
+	if !changed {
+		return
+	}
+
+	var pairs [][2]ID
+	for id1, id2 := range pairings.All() {
+		if partners[id1] == id2 {
+			pairs = append(pairs, [2]ID{id1, id2})
+			delete(partners, id1)
+			delete(partners, id2)
+		}
+	}
+	for _, person := range conf.People {
+		if partner, paired := partners[person.ID]; paired {
+			pairs = append(pairs, [2]ID{person.ID, partner})
+			delete(partners, partner)
+		}
+	}
+	pairings.data = pairs
+}
+
+// eventConfig returns the config for pairing an event on a single day, without the cadences and curriculum of the
+// regular rounds.
+func eventConfig(config Config) Config {
+	config.Settings.Interval = 1
+	config.Settings.Cadence = ""
+	config.Curriculum = nil
+	config.Onboarding = nil
+	config.People = slices.Clone(config.People)
+	for i := range config.People {
+		config.People[i].Cadence = ""
+	}
+	return config
+}
@@ -0,0 +1,75 @@
+package yapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestGenerateEventHasNoRepeats(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario"}, {ID: "Luigi", Cadence: CadenceTwoWeeks}, {ID: "Peach"}, {ID: "Toad"}, {ID: "Yoshi"}, {ID: "Daisy"},
+	}}
+	date := time.Date(2025, time.August, 7, 18, 30, 0, 0, time.UTC)
+	hist := history.History{}
+
+	rounds, err := GenerateEvent(config, &hist, date, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rounds) != 3 {
+		t.Fatalf("Expected 3 rounds, got %d", len(rounds))
+	}
+
+	met := map[[2]ID]bool{}
+	for _, round := range rounds {
+		if expected := time.Date(2025, time.August, 7, 0, 0, 0, 0, time.UTC); !round.Date().Equal(expected) {
+			t.Errorf("Expected the rounds to be on %v, got %v", expected, round.Date())
+		}
+
+		for id1, id2 := range round.All() {
+			if met[pairKey(id1, id2)] {
+				t.Errorf("Expected %s and %s to meet only once during the event", id1, id2)
+			}
+			met[pairKey(id1, id2)] = true
+		}
+	}
+
+	if len(met) != 9 {
+		t.Errorf("Expected everyone to be paired in every round, got %d pairings", len(met))
+	}
+
+	for id1, id2 := range rounds[2].All() {
+		if _, recorded := hist.GetPersonToLastMeetingMap(history.ID(id1))[history.ID(id2)]; !recorded {
+			t.Errorf("Expected the meeting of %s and %s to be recorded in the history", id1, id2)
+		}
+	}
+}
+
+func TestGenerateEventSkipsAbsentPeople(t *testing.T) {
+	config := Config{
+		People:   []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}},
+		Absences: []Absence{{Person: "Peach", Start: date(2025, time.August, 7), End: date(2025, time.August, 7)}},
+	}
+
+	rounds, err := GenerateEvent(config, &history.History{}, time.Date(2025, time.August, 7, 0, 0, 0, 0, time.UTC), 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rounds[0].data) != 1 || rounds[0].data[0] != [2]ID{"Mario", "Luigi"} && rounds[0].data[0] != [2]ID{"Luigi", "Mario"} {
+		t.Errorf("Expected only Mario and Luigi to be paired, got %v", rounds[0].data)
+	}
+
+	if len(rounds[1].data) != 0 {
+		t.Errorf("Expected no one left to meet in the second round, got %v", rounds[1].data)
+	}
+}
+
+func TestGenerateEventRequiresRounds(t *testing.T) {
+	if _, err := GenerateEvent(Config{}, &history.History{}, time.Now(), 0); err == nil {
+		t.Errorf("Expected an error for an event without rounds")
+	}
+}
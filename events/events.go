@@ -0,0 +1,110 @@
+// Package events publishes events about pairings, such as pairings.generated, to message queues such as NATS, Kafka
+// and Amazon SQS, or to webhooks, so other systems can react to them without polling files. Events are encoded as
+// CloudEvents in the structured JSON mode, so they can be consumed by existing event-driven infrastructure such as
+// Knative triggers.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/telemetry"
+)
+
+const (
+	// TypePairingsGenerated is the type of the event published after pairings are generated and recorded.
+	TypePairingsGenerated = "pairings.generated"
+	// DefaultSource is the source of events unless another is set, such as the URL of the server or the name of the
+	// deployment.
+	DefaultSource = "yapper"
+	// SpecVersion is the version of the CloudEvents specification events conform to.
+	SpecVersion = "1.0"
+	// ContentType is the media type of the payloads of events, in the structured mode of CloudEvents.
+	ContentType = "application/cloudevents+json; charset=UTF-8"
+)
+
+// Event is a CloudEvent, published as a JSON object with its attributes and data.
+type Event struct {
+	SpecVersion string `json:"specversion"`
+	// ID is unique to each event, so consumers can ignore events delivered more than once.
+	ID string `json:"id"`
+	// Source is a URI reference identifying what published the event, DefaultSource unless set otherwise.
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	// Subject is the namespace of the program the event is about, if it has one.
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            any       `json:"data"`
+}
+
+// PairingsGenerated is the data of a pairings.generated event, with each round generated in the run.
+type PairingsGenerated struct {
+	// Namespace is the namespace of the program in the history, see yapper.Config.Namespace.
+	Namespace string         `json:"namespace,omitempty"`
+	Rounds    []yapper.Round `json:"rounds"`
+}
+
+// NewPairingsGenerated returns the event of the pairings being generated for the config at the time.
+func NewPairingsGenerated(config yapper.Config, weeklyPairings []yapper.Pairings, now time.Time) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              newID(),
+		Source:          DefaultSource,
+		Type:            TypePairingsGenerated,
+		Subject:         config.Namespace,
+		Time:            now,
+		DataContentType: "application/json",
+		Data:            PairingsGenerated{Namespace: config.Namespace, Rounds: yapper.NewResult(config, weeklyPairings).Rounds},
+	}
+}
+
+// newID returns a random ID for an event.
+func newID() string {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		panic(fmt.Sprintf("error generating event ID: %v", err))
+	}
+	return hex.EncodeToString(data)
+}
+
+// Publisher delivers the payloads of events to a message queue.
+type Publisher interface {
+	// Name identifies the publisher in errors.
+	Name() string
+	// Publish delivers the JSON payload of an event.
+	Publish(ctx context.Context, payload []byte) error
+}
+
+// Publish encodes the event and publishes it through each of the publishers. Every publisher is tried even if one
+// fails, and the errors of those which failed are returned together.
+func Publish(ctx context.Context, publishers []Publisher, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+
+	var errs []error
+	for _, publisher := range publishers {
+		if err := publish(ctx, publisher, event.Type, payload); err != nil {
+			errs = append(errs, fmt.Errorf("error publishing to %s: %w", publisher.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func publish(ctx context.Context, publisher Publisher, eventType string, payload []byte) error {
+	ctx, span := telemetry.Start(ctx, "events.Publish", telemetry.String("yapper.publisher", publisher.Name()), telemetry.String("yapper.event", eventType))
+	defer span.End()
+
+	err := publisher.Publish(ctx, payload)
+	span.RecordError(err)
+	telemetry.Add("yapper.events.published", 1, telemetry.String("yapper.publisher", publisher.Name()), telemetry.Bool("yapper.error", err != nil))
+	return err
+}
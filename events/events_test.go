@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// fakePublisher records each payload it publishes, failing with err if it is set.
+type fakePublisher struct {
+	name     string
+	err      error
+	payloads [][]byte
+}
+
+func (p *fakePublisher) Name() string {
+	return p.name
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, payload []byte) error {
+	p.payloads = append(p.payloads, payload)
+	return p.err
+}
+
+func TestPublishTriesEveryPublisher(t *testing.T) {
+	failing := &fakePublisher{name: "failing", err: errors.New("unreachable")}
+	working := &fakePublisher{name: "working"}
+
+	err := Publish(context.Background(), []Publisher{failing, working}, Event{Type: TypePairingsGenerated})
+	if err == nil || !strings.Contains(err.Error(), "error publishing to failing: unreachable") {
+		t.Errorf("Expected:\nerror publishing to failing\nGot:\n%v", err)
+	}
+	if len(working.payloads) != 1 {
+		t.Errorf("Expected:\n%v\nGot:\n%v", 1, len(working.payloads))
+	}
+}
+
+func TestPairingsGeneratedPayload(t *testing.T) {
+	config := yapper.Config{Namespace: "plumbing", People: []yapper.Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}}}
+	date := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	pairings := yapper.NewPairings(date, [][2]yapper.ID{{"Mario", "Luigi"}})
+	publisher := &fakePublisher{name: "fake"}
+
+	event := NewPairingsGenerated(config, []yapper.Pairings{pairings}, date)
+	if err := Publish(context.Background(), []Publisher{publisher}, event); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		SpecVersion string `json:"specversion"`
+		ID          string `json:"id"`
+		Source      string `json:"source"`
+		Type        string `json:"type"`
+		Subject     string `json:"subject"`
+		Data        struct {
+			Rounds []struct {
+				Unpaired []yapper.ID `json:"unpaired"`
+			} `json:"rounds"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(publisher.payloads[0], &payload); err != nil {
+		t.Fatal(err)
+	}
+	expected := [4]string{SpecVersion, DefaultSource, TypePairingsGenerated, "plumbing"}
+	if got := [4]string{payload.SpecVersion, payload.Source, payload.Type, payload.Subject}; got != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+	}
+	if payload.ID == "" || payload.ID == NewPairingsGenerated(config, []yapper.Pairings{pairings}, date).ID {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "a unique ID", payload.ID)
+	}
+	if len(payload.Data.Rounds) != 1 || len(payload.Data.Rounds[0].Unpaired) != 1 || payload.Data.Rounds[0].Unpaired[0] != "Peach" {
+		t.Errorf("Expected:\n%v\nGot:\n%+v", "one round with Peach unpaired", payload.Data.Rounds)
+	}
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Kafka produces each payload as a record of a topic through a Kafka REST Proxy, which speaks HTTP rather than the
+// binary protocol of the brokers.
+type Kafka struct {
+	// URL of the REST Proxy, such as http://localhost:8082.
+	URL   string
+	Topic string
+	// Client is used for requests to the REST Proxy, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+func (k Kafka) Name() string {
+	return "kafka"
+}
+
+// Publish produces the payload as the JSON value of one record.
+func (k Kafka) Publish(ctx context.Context, payload []byte) error {
+	if k.Topic == "" {
+		return fmt.Errorf("a Kafka topic is required")
+	}
+	body, err := json.Marshal(map[string]any{"records": []map[string]json.RawMessage{{"value": payload}}})
+	if err != nil {
+		return fmt.Errorf("error encoding Kafka records: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(k.URL, "/")+"/topics/"+url.PathEscape(k.Topic), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating Kafka request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	client := k.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling the Kafka REST Proxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	json.Unmarshal(data, &response)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the Kafka REST Proxy responded with %s: %s", resp.Status, response.Message)
+	}
+	for _, offset := range response.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("Kafka rejected the record: %s", offset.Error)
+		}
+	}
+	return nil
+}
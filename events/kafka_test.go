@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestKafkaProducesRecord(t *testing.T) {
+	var records struct {
+		Records []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/pairings" || r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&records)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":1}]}`))
+	}))
+	defer server.Close()
+
+	if err := (Kafka{URL: server.URL, Topic: "pairings"}).Publish(context.Background(), []byte(`{"type":"test"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Records) != 1 || string(records.Records[0].Value) != `{"type":"test"}` {
+		t.Errorf("Expected:\n%v\nGot:\n%+v", `one record with the payload`, records)
+	}
+}
+
+func TestKafkaReportsRejectedRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"offsets":[{"error_code":40403,"error":"Topic not found."}]}`))
+	}))
+	defer server.Close()
+
+	err := (Kafka{URL: server.URL, Topic: "pairings"}).Publish(context.Background(), []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "Topic not found.") {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "Topic not found.", err)
+	}
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// natsTimeout is how long a NATS server has to respond when the context has no deadline.
+const natsTimeout = 10 * time.Second
+
+// NATS publishes each payload to a subject of a NATS server, speaking its text protocol over TCP. TLS is not
+// supported, so servers requiring it are reported as errors.
+type NATS struct {
+	// URL of the server, such as nats://localhost:4222. A user and password, or a token as the user, can be included.
+	URL     string
+	Subject string
+}
+
+func (n NATS) Name() string {
+	return "nats"
+}
+
+// Publish connects to the server, publishes the payload and waits for the server to acknowledge it with a PONG, so
+// an error is returned if the server rejects the payload.
+func (n NATS) Publish(ctx context.Context, payload []byte) error {
+	if n.Subject == "" || strings.ContainsAny(n.Subject, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject: %q", n.Subject)
+	}
+	address, err := url.Parse(n.URL)
+	if err != nil || address.Scheme != "nats" || address.Host == "" {
+		return fmt.Errorf("invalid NATS URL, expected nats://host:port: %s", n.URL)
+	}
+	host := address.Host
+	if address.Port() == "" {
+		host = net.JoinHostPort(address.Hostname(), "4222")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS: %w", err)
+	}
+	defer conn.Close()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(natsTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	reader := bufio.NewReader(conn)
+	info, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("error reading NATS server info: %w", err)
+	}
+	var serverInfo struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	if rest, found := strings.CutPrefix(info, "INFO "); !found {
+		return fmt.Errorf("unexpected NATS greeting: %s", strings.TrimSpace(info))
+	} else if err := json.Unmarshal([]byte(rest), &serverInfo); err != nil {
+		return fmt.Errorf("error decoding NATS server info: %w", err)
+	}
+	if serverInfo.TLSRequired {
+		return errors.New("the NATS server requires TLS, which is not supported")
+	}
+
+	connect := map[string]any{"verbose": false, "pedantic": false, "name": "yapper", "lang": "go"}
+	if user := address.User; user != nil {
+		if password, set := user.Password(); set {
+			connect["user"], connect["pass"] = user.Username(), password
+		} else {
+			connect["auth_token"] = user.Username()
+		}
+	}
+	options, err := json.Marshal(connect)
+	if err != nil {
+		return fmt.Errorf("error encoding NATS connect options: %w", err)
+	}
+
+	command := fmt.Sprintf("CONNECT %s\r\nPUB %s %d\r\n%s\r\nPING\r\n", options, n.Subject, len(payload), payload)
+	if _, err := conn.Write([]byte(command)); err != nil {
+		return fmt.Errorf("error publishing to NATS: %w", err)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("error reading NATS response: %w", err)
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS rejected the event: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
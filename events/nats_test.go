@@ -0,0 +1,74 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeNATS accepts one connection, answering a PING with a PONG unless reject is set, and returns the CONNECT line
+// and published payload it received.
+func fakeNATS(t *testing.T, reject bool) (string, <-chan [2]string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan [2]string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+
+		reader := bufio.NewReader(conn)
+		connect, _ := reader.ReadString('\n')
+		pub, _ := reader.ReadString('\n')
+		var size int
+		fmt.Sscanf(strings.TrimSpace(pub), "PUB events %d", &size)
+		payload := make([]byte, size+2)
+		io.ReadFull(reader, payload)
+		reader.ReadString('\n')
+
+		if reject {
+			fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+		} else {
+			fmt.Fprint(conn, "PONG\r\n")
+		}
+		received <- [2]string{strings.TrimSpace(connect), string(payload[:size])}
+	}()
+	return "nats://secret@" + listener.Addr().String(), received
+}
+
+func TestNATSPublishes(t *testing.T) {
+	url, received := fakeNATS(t, false)
+
+	if err := (NATS{URL: url, Subject: "events"}).Publish(context.Background(), []byte(`{"type":"test"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-received
+	if !strings.Contains(got[0], `"auth_token":"secret"`) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "CONNECT with the token", got[0])
+	}
+	if got[1] != `{"type":"test"}` {
+		t.Errorf("Expected:\n%v\nGot:\n%v", `{"type":"test"}`, got[1])
+	}
+}
+
+func TestNATSReportsErrors(t *testing.T) {
+	url, _ := fakeNATS(t, true)
+
+	err := (NATS{URL: url, Subject: "events"}).Publish(context.Background(), []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "Authorization Violation") {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "Authorization Violation", err)
+	}
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/internal/sigv4"
+)
+
+// SQS sends each payload as a message to an Amazon SQS queue, signing the requests with AWS Signature Version 4.
+type SQS struct {
+	// QueueURL is the URL of the queue, such as https://sqs.eu-west-1.amazonaws.com/123456789012/pairings. Messages
+	// are sent to the host of the queue.
+	QueueURL string
+	// Region of the queue, read from the host of the queue URL if empty.
+	Region string
+	// AccessKeyID, SecretAccessKey and the optional SessionToken are the AWS credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Client is used for requests to SQS, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+func (s SQS) Name() string {
+	return "sqs"
+}
+
+// Publish sends the payload as the body of one message with the SendMessage action of the JSON protocol.
+func (s SQS) Publish(ctx context.Context, payload []byte) error {
+	queue, err := url.Parse(s.QueueURL)
+	if err != nil || queue.Host == "" {
+		return fmt.Errorf("invalid SQS queue URL: %s", s.QueueURL)
+	}
+	region := s.Region
+	if region == "" {
+		var found bool
+		if region, found = sigv4.RegionFromHost(queue.Hostname(), "sqs"); !found {
+			return fmt.Errorf("the region of SQS queue %s is required", s.QueueURL)
+		}
+	}
+	if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+		return fmt.Errorf("AWS credentials are required for SQS")
+	}
+
+	body, err := json.Marshal(map[string]string{"QueueUrl": s.QueueURL, "MessageBody": string(payload)})
+	if err != nil {
+		return fmt.Errorf("error encoding SQS message: %w", err)
+	}
+
+	endpoint := url.URL{Scheme: queue.Scheme, Host: queue.Host, Path: "/"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating SQS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS.SendMessage")
+	sigv4.Sign(req, body, sigv4.Credentials{AccessKeyID: s.AccessKeyID, SecretAccessKey: s.SecretAccessKey, SessionToken: s.SessionToken}, region, "sqs", time.Now())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling SQS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		json.Unmarshal(data, &response)
+		return fmt.Errorf("SQS responded with %s: %s %s", resp.Status, response.Type, response.Message)
+	}
+	return nil
+}
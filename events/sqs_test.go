@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSQSSendsMessage(t *testing.T) {
+	var message map[string]string
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "AmazonSQS.SendMessage" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&message)
+		w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	queue := SQS{QueueURL: server.URL + "/123456789012/pairings", Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret"}
+	if err := queue.Publish(context.Background(), []byte(`{"type":"test"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if message["MessageBody"] != `{"type":"test"}` || message["QueueUrl"] != queue.QueueURL {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "the payload sent to the queue", message)
+	}
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(authorization, "/eu-west-1/sqs/aws4_request") {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "a signature for sqs in eu-west-1", authorization)
+	}
+}
+
+func TestSQSRequiresRegion(t *testing.T) {
+	queue := SQS{QueueURL: "http://localhost:9324/queue/pairings", AccessKeyID: "AKID", SecretAccessKey: "secret"}
+
+	err := queue.Publish(context.Background(), []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "an error for the missing region", err)
+	}
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Webhook posts each payload to a URL as a CloudEvent in the structured mode of the HTTP binding, which brokers such
+// as those of Knative accept directly.
+type Webhook struct {
+	URL string
+	// Client is used for requests to the URL, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+func (w Webhook) Name() string {
+	return "webhook"
+}
+
+// Publish posts the payload, returning an error unless the response is successful.
+func (w Webhook) Publish(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentType)
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling webhook: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("the webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookPostsStructuredEvent(t *testing.T) {
+	var contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	if err := (Webhook{URL: server.URL}).Publish(context.Background(), []byte(`{"specversion":"1.0"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if contentType != ContentType || body != `{"specversion":"1.0"}` {
+		t.Errorf("Expected:\n%v\nGot:\n%v", ContentType+" "+`{"specversion":"1.0"}`, contentType+" "+body)
+	}
+}
+
+func TestWebhookReportsFailedResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := (Webhook{URL: server.URL}).Publish(context.Background(), []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "502 Bad Gateway", err)
+	}
+}
@@ -0,0 +1,135 @@
+package yapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/internal/ilp"
+)
+
+// exactMaxPeople is the most people a round can have to be paired by the exact strategy, as the size of its program
+// grows with the square of the people.
+const exactMaxPeople = 60
+
+// errTooManyForExact is returned when a round has too many people to be paired by the exact strategy.
+var errTooManyForExact = fmt.Errorf("more than %d people can meet", exactMaxPeople)
+
+// generateExact pairs each round in turn like the greedy strategy, choosing the best pairings of each round by solving
+// an integer program. Rounds which are too large to solve are paired greedily instead. It stops with the error of the
+// context once it is done.
+func generateExact(ctx context.Context, config Config, idToValidPairings map[ID][]ID, hist history.History, dates []time.Time) ([]Pairings, error) {
+	weeklyPairings := make([]Pairings, 0, len(dates))
+	simulated := copyHistory(config, hist)
+
+	for _, date := range dates {
+		pairings, err := pairExactly(ctx, config, idToValidPairings, simulated, date)
+		if errors.Is(err, errTooManyForExact) || errors.Is(err, ilp.ErrNodeLimit) {
+			Logger().Warn("round cannot be paired exactly, pairing it greedily", "date", date.Format(time.DateOnly), "error", err)
+			pairings = improveRound(ctx, config, idToValidPairings, simulated, pairPeople(config, idToValidPairings, simulated, date))
+		} else if err != nil {
+			return nil, err
+		}
+
+		for id1, id2 := range pairings.All() {
+			simulated.AddMeeting(history.ID(id1), history.ID(id2), date)
+		}
+		weeklyPairings = append(weeklyPairings, pairings)
+	}
+
+	return weeklyPairings, nil
+}
+
+// pairExactly returns the pairings of the round starting on date with the most people paired, then the lowest penalty
+// of the soft constraints, then the most people who have not met and then the longest time since the others last met,
+// weighed as by the planned strategy. Pins, onboarding cohorts, squad limits and people who cannot meet are kept to as
+// by the greedy strategy.
+func pairExactly(ctx context.Context, conf Config, idToValidPairings map[ID][]ID, hist history.History, date time.Time) (Pairings, error) {
+	pairings := Pairings{date: date}
+	idToValidPairings = restrictToCohorts(conf.onboardingCohorts(date), idToValidPairings)
+	ineligible := getIneligiblePeople(conf, idToValidPairings, date)
+	people := newOrdinals(conf)
+	alreadyPaired := newPersonSet(people, ineligible...)
+	scorer := newRoundScorer(conf, hist, date)
+
+	for _, pin := range pinnedPairings(conf, idToValidPairings, ineligible, date) {
+		pairings.Add(pin[0], pin[1])
+		pairings.penalty += scorer.penalty(pin[0], pin[1])
+		alreadyPaired.add(pin[0])
+		alreadyPaired.add(pin[1])
+	}
+
+	var eligible []Person
+	for _, person := range conf.People {
+		if _, found := idToValidPairings[person.ID]; found && !alreadyPaired.contains(person.ID) {
+			eligible = append(eligible, person)
+		}
+	}
+	if len(eligible) > exactMaxPeople {
+		return Pairings{}, errTooManyForExact
+	}
+
+	index := make(map[ID]int, len(eligible))
+	for i, person := range eligible {
+		index[person.ID] = i
+	}
+	var pairs [][2]Person
+	var penalties []float64
+	problem := ilp.Problem{Constraints: make([][]float64, len(eligible)), Bounds: make([]float64, len(eligible))}
+	for i, person := range eligible {
+		for _, other := range idToValidPairings[person.ID] {
+			if j, found := index[other]; found && i < j {
+				penalty := pairPenalty(conf, hist, scorer.recent, person, eligible[j], date)
+				pairs = append(pairs, [2]Person{person, eligible[j]})
+				penalties = append(penalties, penalty)
+				problem.Objective = append(problem.Objective, exactPairValue(hist, person, eligible[j], date)-penalty)
+			}
+		}
+	}
+	for i, person := range eligible {
+		problem.Constraints[i] = make([]float64, len(pairs))
+		for p, pair := range pairs {
+			if pair[0].ID == person.ID || pair[1].ID == person.ID {
+				problem.Constraints[i][p] = 1
+			}
+		}
+		problem.Bounds[i] = 1
+	}
+	pinnedCounts := newSquadCountsOf(conf, pairings.data)
+	for l, limit := range conf.SquadLimits {
+		constraint := make([]float64, len(pairs))
+		for p, pair := range pairs {
+			if limit.appliesTo(pair[0].Squad, pair[1].Squad) {
+				constraint[p] = 1
+			}
+		}
+		problem.Constraints = append(problem.Constraints, constraint)
+		problem.Bounds = append(problem.Bounds, float64(max(limit.MaxPairs-pinnedCounts.counts[l], 0)))
+	}
+
+	chosen, _, err := ilp.Solve(ctx, problem)
+	if err != nil {
+		return Pairings{}, fmt.Errorf("error pairing round of %s exactly: %w", date.Format(time.DateOnly), err)
+	}
+	for p, pair := range pairs {
+		if chosen[p] {
+			pairings.Add(pair[0].ID, pair[1].ID)
+			pairings.penalty += penalties[p]
+		}
+	}
+	return pairings, nil
+}
+
+// exactPairValue returns the value of pairing the people before the penalty of the soft constraints, which is mostly
+// the two people no longer being unpaired, with a bonus for people who have not met or met a long time ago.
+func exactPairValue(hist history.History, person1, person2 Person, date time.Time) float64 {
+	value := 2 * planUnpairedCost
+	if lastMeeting, met := hist.GetPersonToLastMeetingMap(history.ID(person1.ID))[history.ID(person2.ID)]; met {
+		value += min(date.Sub(lastMeeting).Hours()/24, planMaxStalenessDays) / planMaxStalenessDays
+	} else {
+		value += planNewPairBonus
+	}
+	return value
+}
@@ -0,0 +1,114 @@
+package yapper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestPairExactlyPairsTheMostPeople(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	// Only Mario and Bowser can meet Luigi and Peach respectively, who have not met each other.
+	config := Config{People: []Person{
+		{ID: "Mario", DenyList: []ID{"Peach", "Bowser"}},
+		{ID: "Luigi", DenyList: []ID{"Bowser"}},
+		{ID: "Peach"},
+		{ID: "Bowser"},
+	}}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -7))
+	hist.AddMeeting("Peach", "Bowser", date.AddDate(0, 0, -7))
+
+	pairings, err := pairExactly(context.Background(), config, determineValidPairings(config), hist, date)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pairings.Len() != 2 || !pairings.Contains("Mario", "Luigi") || !pairings.Contains("Peach", "Bowser") {
+		t.Errorf("Expected everyone to be paired, got %v", pairings.data)
+	}
+}
+
+func TestPairExactlyPrefersTheLowestPenalty(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{
+			{ID: "Mario", DenyList: []ID{"Bowser"}},
+			{ID: "Luigi", DenyList: []ID{"Peach"}},
+			{ID: "Peach"},
+			{ID: "Bowser"},
+		},
+		SoftConstraints: SoftConstraints{RecentlyMet: 1, RecentlyMetDays: 28},
+	}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Peach", date.AddDate(0, 0, -21))
+	hist.AddMeeting("Bowser", "Peach", date.AddDate(0, 0, -7))
+
+	pairings, err := pairExactly(context.Background(), config, determineValidPairings(config), hist, date)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !pairings.Contains("Mario", "Peach") || !pairings.Contains("Luigi", "Bowser") {
+		t.Errorf("Expected Bowser not to meet Peach again, got %v", pairings.data)
+	}
+	if expected := 0.25; pairings.Penalty() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, pairings.Penalty())
+	}
+}
+
+func TestPairExactlyKeepsPinsAndAbsences(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Bowser", Paused: true}},
+		Pins:   []Pin{{People: [2]ID{"Mario", "Peach"}}},
+	}
+
+	pairings, err := pairExactly(context.Background(), config, determineValidPairings(config), history.History{}, date)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pairings.Len() != 1 || !pairings.Contains("Mario", "Peach") {
+		t.Errorf("Expected only the pinned pair, got %v", pairings.data)
+	}
+}
+
+func TestGenerateExactPairsLargeRoundsGreedily(t *testing.T) {
+	config := Config{}
+	for i := range exactMaxPeople + 2 {
+		config.People = append(config.People, Person{ID: ID(fmt.Sprintf("Toad %d", i))})
+	}
+	dates := []time.Time{time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)}
+
+	weeklyPairings, err := generateExact(context.Background(), config, determineValidPairings(config), history.History{}, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := (exactMaxPeople + 2) / 2; weeklyPairings[0].Len() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, weeklyPairings[0].Len())
+	}
+}
+
+func TestGenerateWithExactStrategy(t *testing.T) {
+	config := Config{
+		People:   []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Daisy"}},
+		Settings: Settings{Strategy: StrategyExact},
+	}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", time.Date(2025, time.July, 28, 0, 0, 0, 0, time.UTC))
+	hist.AddMeeting("Peach", "Daisy", time.Date(2025, time.July, 28, 0, 0, 0, 0, time.UTC))
+
+	weeklyPairings, err := GeneratePairings(config, &hist, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, pairings := range weeklyPairings {
+		if pairings.Len() != 2 || pairings.Contains("Mario", "Luigi") || pairings.Contains("Peach", "Daisy") {
+			t.Errorf("Expected everyone to meet someone new, got %v", pairings.data)
+		}
+	}
+	if weeklyPairings[0].Contains(weeklyPairings[1].data[0][0], weeklyPairings[1].data[0][1]) {
+		t.Errorf("Expected the second round not to repeat the first, got %v and %v", weeklyPairings[0].data, weeklyPairings[1].data)
+	}
+}
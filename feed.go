@@ -0,0 +1,75 @@
+package yapper
+
+import (
+	"math"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// MeetingGraph is the graph of who has met whom, shaped as the nodes and links used by visualization tools such as
+// D3, so dashboards can be built without reading the history format.
+type MeetingGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Links []GraphLink `json:"links"`
+}
+
+// GraphNode is a person in the config.
+type GraphNode struct {
+	ID    ID     `json:"id"`
+	Squad string `json:"squad,omitempty"`
+	// Meetings is the number of times the person has met anyone in the config.
+	Meetings int `json:"meetings"`
+}
+
+// GraphLink is a pair of people in the config who have met.
+type GraphLink struct {
+	Source           ID     `json:"source"`
+	Target           ID     `json:"target"`
+	LastMet          string `json:"lastMet"`
+	DaysSinceLastMet int    `json:"daysSinceLastMet"`
+	TimesMet         int    `json:"timesMet"`
+	// Weight is the recency of the last meeting, 1 for a meeting now and halving every half life. It is always 1
+	// without a half life.
+	Weight float64 `json:"weight"`
+}
+
+// NewMeetingGraph returns the meeting graph of the people in the config, in config order, weighting each link by how
+// recently the pair last met as of now.
+func NewMeetingGraph(config Config, hist history.History, now time.Time, halfLife time.Duration) MeetingGraph {
+	graph := MeetingGraph{Nodes: make([]GraphNode, 0, len(config.People)), Links: []GraphLink{}}
+	meetings := map[ID]int{}
+
+	for i, person := range config.People {
+		lastMeetings := hist.GetPersonToLastMeetingMap(history.ID(person.ID))
+		for _, other := range config.People[i+1:] {
+			lastMet, met := lastMeetings[history.ID(other.ID)]
+			if !met {
+				continue
+			}
+
+			timesMet := hist.TimesMet(history.ID(person.ID), history.ID(other.ID))
+			meetings[person.ID] += timesMet
+			meetings[other.ID] += timesMet
+
+			age := max(now.Sub(lastMet), 0)
+			link := GraphLink{
+				Source:           person.ID,
+				Target:           other.ID,
+				LastMet:          lastMet.Format(time.DateOnly),
+				DaysSinceLastMet: int(age.Hours() / 24),
+				TimesMet:         timesMet,
+				Weight:           1,
+			}
+			if halfLife > 0 {
+				link.Weight = math.Pow(0.5, float64(age)/float64(halfLife))
+			}
+			graph.Links = append(graph.Links, link)
+		}
+	}
+
+	for _, person := range config.People {
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: person.ID, Squad: person.Squad, Meetings: meetings[person.ID]})
+	}
+	return graph
+}
@@ -0,0 +1,32 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestNewMeetingGraphWeightsLinksByRecency(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario", Squad: "plumbers"}, {ID: "Luigi", Squad: "plumbers"}, {ID: "Peach"}}}
+	now := time.Date(2025, time.August, 31, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", now.AddDate(0, 0, -20))
+	hist.AddMeeting("Mario", "Luigi", now.AddDate(0, 0, -10))
+	hist.AddMeeting("Peach", "Mario", now)
+	hist.AddMeeting("Peach", "Bowser", now)
+
+	graph := NewMeetingGraph(config, hist, now, 10*24*time.Hour)
+
+	expected := MeetingGraph{
+		Nodes: []GraphNode{{ID: "Mario", Squad: "plumbers", Meetings: 3}, {ID: "Luigi", Squad: "plumbers", Meetings: 2}, {ID: "Peach", Meetings: 1}},
+		Links: []GraphLink{
+			{Source: "Mario", Target: "Luigi", LastMet: "2025-08-21", DaysSinceLastMet: 10, TimesMet: 2, Weight: 0.5},
+			{Source: "Mario", Target: "Peach", LastMet: "2025-08-31", DaysSinceLastMet: 0, TimesMet: 1, Weight: 1},
+		},
+	}
+	if !reflect.DeepEqual(expected, graph) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, graph)
+	}
+}
@@ -0,0 +1,124 @@
+package yapper
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Frequency compares how often someone is configured to meet, by their cadence, with how often they met in the
+// history.
+type Frequency struct {
+	Person  ID      `json:"person"`
+	Cadence Cadence `json:"cadence"`
+	// TargetWeeks is the weeks between meetings of the cadence of the person and the round interval.
+	TargetWeeks float64 `json:"targetWeeks"`
+	// Weeks is how long the person was in the program for, from the start of the history or their start date.
+	Weeks    float64 `json:"weeks"`
+	Meetings int     `json:"meetings"`
+	// ActualWeeks is the average weeks between the meetings of the person, zero if they have not met anyone.
+	ActualWeeks float64 `json:"actualWeeks"`
+	// UnderServed is whether the person met less often than their cadence by more than the tolerance. People who are
+	// paused are never under-served.
+	UnderServed bool   `json:"underServed"`
+	Message     string `json:"message"`
+}
+
+// FrequencyOptions are the period and tolerance the frequencies are found for.
+type FrequencyOptions struct {
+	// Since is the start of the period, defaulting to the round of the earliest meeting in the history. As the history
+	// only keeps when each pair last met along with how many times, a pair is counted if they last met within the
+	// period, at most once for each round of the period up to their last meeting.
+	Since time.Time
+	// Until is the end of the period, defaulting to the end of the round of the latest meeting in the history.
+	Until time.Time
+	// Tolerance is how many times their target weeks someone can go between meetings on average before being
+	// under-served, e.g. 1.5.
+	Tolerance float64
+}
+
+// EffectiveFrequencies returns how often each of the people in the config met in the history compared with their
+// cadence, in config order, highlighting those the constraints or strategy under-serve. People who started after the
+// period are left out, and nothing is returned if no one in the config has met.
+func EffectiveFrequencies(config Config, hist history.History, options FrequencyOptions) []Frequency {
+	frequencies := []Frequency{}
+	index := config.Index()
+
+	var earliest, latest time.Time
+	for _, person := range config.People {
+		for other, meetingTime := range hist.PartnersOf(history.ID(person.ID)) {
+			if _, found := index[ID(other)]; !found {
+				continue
+			}
+			if earliest.IsZero() || meetingTime.Before(earliest) {
+				earliest = meetingTime
+			}
+			if meetingTime.After(latest) {
+				latest = meetingTime
+			}
+		}
+	}
+	if latest.IsZero() {
+		return frequencies
+	}
+
+	since, until := options.Since, options.Until
+	if since.IsZero() {
+		since = config.RoundStart(earliest)
+	}
+	if until.IsZero() {
+		until = config.RoundStart(latest).AddDate(0, 0, config.RoundInterval())
+	}
+
+	for _, person := range config.People {
+		start := since
+		if person.StartDate != nil && person.StartDate.After(start) {
+			start = config.RoundStart(person.StartDate.Time)
+		}
+		if !start.Before(until) {
+			continue
+		}
+
+		frequency := Frequency{Person: person.ID, Cadence: config.cadence(person), Weeks: until.Sub(start).Hours() / (24 * 7)}
+		if frequency.Cadence == "" {
+			frequency.Cadence = CadenceOneWeek
+		}
+		rounds := 1
+		if frequency.Cadence == CadenceTwoWeeks {
+			rounds = 2
+		}
+		frequency.TargetWeeks = float64(rounds*config.RoundInterval()) / 7
+
+		for other, lastMeeting := range hist.PartnersOf(history.ID(person.ID)) {
+			if _, found := index[ID(other)]; !found || lastMeeting.Before(start) || !lastMeeting.Before(until) {
+				continue
+			}
+			roundsMet := int(lastMeeting.Sub(start).Hours()/24)/config.RoundInterval() + 1
+			frequency.Meetings += min(hist.TimesMet(history.ID(person.ID), other), roundsMet)
+		}
+
+		limit := options.Tolerance * frequency.TargetWeeks
+		if frequency.Meetings == 0 {
+			frequency.UnderServed = !person.Paused && frequency.Weeks > limit
+			frequency.Message = fmt.Sprintf("%s is configured to meet every %s and has not met anyone in %s", person.ID, describeWeeks(frequency.TargetWeeks), describeWeeks(frequency.Weeks))
+		} else {
+			frequency.ActualWeeks = frequency.Weeks / float64(frequency.Meetings)
+			frequency.UnderServed = !person.Paused && frequency.ActualWeeks > limit
+			frequency.Message = fmt.Sprintf("%s is configured to meet every %s and met every %s on average", person.ID, describeWeeks(frequency.TargetWeeks), describeWeeks(frequency.ActualWeeks))
+		}
+		frequencies = append(frequencies, frequency)
+	}
+	return frequencies
+}
+
+// describeWeeks returns the weeks rounded to one decimal place, e.g. "1 week" or "3.4 weeks".
+func describeWeeks(weeks float64) string {
+	weeks = math.Round(weeks*10) / 10
+	if weeks == 1 {
+		return "1 week"
+	}
+	return strconv.FormatFloat(weeks, 'f', -1, 64) + " weeks"
+}
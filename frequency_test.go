@@ -0,0 +1,88 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestEffectiveFrequencies(t *testing.T) {
+	start := date(2025, time.August, 11)
+	config := Config{People: []Person{
+		{ID: "Mario"},
+		{ID: "Luigi"},
+		{ID: "Peach", Cadence: CadenceTwoWeeks},
+		{ID: "Toad"},
+		{ID: "Bowser"},
+		{ID: "Yoshi", StartDate: &start},
+	}}
+	hist := history.History{}
+	monday := time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC)
+	for week := range 4 {
+		hist.AddMeeting("Mario", "Luigi", monday.AddDate(0, 0, 7*week))
+	}
+	hist.AddMeeting("Peach", "Toad", monday)
+
+	frequencies := EffectiveFrequencies(config, hist, FrequencyOptions{Tolerance: 1.5})
+	expected := []Frequency{
+		{Person: "Mario", Cadence: CadenceOneWeek, TargetWeeks: 1, Weeks: 4, Meetings: 4, ActualWeeks: 1, Message: "Mario is configured to meet every 1 week and met every 1 week on average"},
+		{Person: "Luigi", Cadence: CadenceOneWeek, TargetWeeks: 1, Weeks: 4, Meetings: 4, ActualWeeks: 1, Message: "Luigi is configured to meet every 1 week and met every 1 week on average"},
+		{Person: "Peach", Cadence: CadenceTwoWeeks, TargetWeeks: 2, Weeks: 4, Meetings: 1, ActualWeeks: 4, UnderServed: true, Message: "Peach is configured to meet every 2 weeks and met every 4 weeks on average"},
+		{Person: "Toad", Cadence: CadenceOneWeek, TargetWeeks: 1, Weeks: 4, Meetings: 1, ActualWeeks: 4, UnderServed: true, Message: "Toad is configured to meet every 1 week and met every 4 weeks on average"},
+		{Person: "Bowser", Cadence: CadenceOneWeek, TargetWeeks: 1, Weeks: 4, UnderServed: true, Message: "Bowser is configured to meet every 1 week and has not met anyone in 4 weeks"},
+	}
+	if !reflect.DeepEqual(expected, frequencies) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, frequencies)
+	}
+}
+
+func TestEffectiveFrequenciesSkipPausedPeople(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach", Paused: true}}}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC))
+	since := time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC)
+
+	frequencies := EffectiveFrequencies(config, hist, FrequencyOptions{Since: since, Tolerance: 1.5})
+	if len(frequencies) != 3 || frequencies[2].UnderServed || !frequencies[0].UnderServed {
+		t.Errorf("Expected only those not paused to be under-served since June, got %+v", frequencies)
+	}
+}
+
+func TestEffectiveFrequenciesOnlyCountMeetingsWithinThePeriod(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}}}
+	hist := history.History{}
+	monday := time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC)
+	for week := range 4 {
+		hist.AddMeeting("Mario", "Luigi", monday.AddDate(0, 0, 7*week))
+	}
+	hist.AddMeeting("Peach", "Mario", monday.AddDate(0, 0, 35))
+	since, until := monday.AddDate(0, 0, 14), monday.AddDate(0, 0, 28)
+
+	frequencies := EffectiveFrequencies(config, hist, FrequencyOptions{Since: since, Until: until, Tolerance: 1.5})
+	meetings := map[ID]int{}
+	for _, frequency := range frequencies {
+		meetings[frequency.Person] = frequency.Meetings
+	}
+	expected := map[ID]int{"Mario": 2, "Luigi": 2, "Peach": 0}
+	if !reflect.DeepEqual(expected, meetings) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, meetings)
+	}
+}
+
+func TestEffectiveFrequenciesWithoutMeetings(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+	if frequencies := EffectiveFrequencies(config, history.History{}, FrequencyOptions{}); len(frequencies) != 0 {
+		t.Errorf("Expected no frequencies, got %v", frequencies)
+	}
+}
+
+func TestDescribeWeeks(t *testing.T) {
+	tests := map[float64]string{1: "1 week", 2: "2 weeks", 3.44: "3.4 weeks", 0.96: "1 week"}
+	for weeks, expected := range tests {
+		if got := describeWeeks(weeks); got != expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+		}
+	}
+}
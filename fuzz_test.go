@@ -0,0 +1,74 @@
+package yapper
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// malformedFiles are inputs which each parser must reject with an error rather than panicking or hanging.
+var malformedFiles = []string{
+	"",
+	"null",
+	"[",
+	`{"version": 2, "people": ` + strings.Repeat("[", 100_000) + strings.Repeat("]", 100_000) + "}",
+	`{"version": 2, "people": [{"id": "Mario", "birthday": "2025-13-45"}]}`,
+	`{"version": 2, "rules": [{"deny": "` + strings.Repeat("!", 100_000) + `person.id == \"Mario\""}]}`,
+	`{"version": 2, "timezone": "Mars/Olympus_Mons"}`,
+	`{"version": 99999999999999999999}`,
+	`[["id1", "id2", "id3"]]`,
+	`[[null, {}]]`,
+}
+
+// writeFuzzFile writes the data to a file in a temporary directory, returning its path.
+func writeFuzzFile(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Unexpected error writing file: %v", err)
+	}
+	return path
+}
+
+func TestParsersReturnErrorsForMalformedFiles(t *testing.T) {
+	for _, data := range malformedFiles {
+		path := writeFuzzFile(t, []byte(data))
+		if _, err := NewConfigFromFile(path); err == nil {
+			t.Errorf("Expected an error reading config %.80q", data)
+		}
+		if _, err := NewPairingsFromFile(path); err == nil && !json.Valid([]byte(data)) {
+			t.Errorf("Expected an error reading pairings %.80q", data)
+		}
+	}
+}
+
+func FuzzNewConfigFromFile(f *testing.F) {
+	valid, err := os.ReadFile(filepath.Join("testdata", validConfigName))
+	if err != nil {
+		f.Fatalf("Unexpected error reading config: %v", err)
+	}
+	f.Add(valid)
+	for _, data := range malformedFiles {
+		f.Add([]byte(data))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		NewConfigFromFile(writeFuzzFile(t, data))
+	})
+}
+
+func FuzzNewPairingsFromFile(f *testing.F) {
+	valid, err := os.ReadFile(filepath.Join("testdata", "expectedPairings.json"))
+	if err != nil {
+		f.Fatalf("Unexpected error reading pairings: %v", err)
+	}
+	f.Add(valid)
+	for _, data := range malformedFiles {
+		f.Add([]byte(data))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		NewPairingsFromFile(writeFuzzFile(t, data))
+	})
+}
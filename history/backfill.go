@@ -0,0 +1,62 @@
+package history
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Backfill adds the meetings from CSV data with the columns person1, person2 and date, returning how many were recorded.
+// Dates are either YYYY-MM-DD or RFC 3339, and a header row starting with "person1" is skipped.
+// A meeting only replaces the last meeting of the pair if it is more recent, so the order of the rows does not matter,
+// while an older meeting is still counted. A row with the same date as the last meeting is taken to be that meeting.
+func (h *History) Backfill(reader io.Reader) (int, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 3
+	csvReader.TrimLeadingSpace = true
+
+	recorded := 0
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return recorded, nil
+		} else if err != nil {
+			return recorded, fmt.Errorf("error reading backfill data: %w", err)
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "person1") {
+			continue
+		}
+
+		person1, person2 := ID(strings.TrimSpace(record[0])), ID(strings.TrimSpace(record[1]))
+		if person1 == "" || person2 == "" || person1 == person2 {
+			return recorded, fmt.Errorf("invalid pair on line %d: %q and %q", line, person1, person2)
+		}
+
+		meetingTime, err := parseBackfillDate(strings.TrimSpace(record[2]))
+		if err != nil {
+			return recorded, fmt.Errorf("invalid date on line %d: %w", line, err)
+		}
+
+		lastMeeting, exists := h.GetPersonToLastMeetingMap(person1)[person2]
+		switch {
+		case exists && meetingTime.Equal(lastMeeting):
+			continue
+		case exists && meetingTime.Before(lastMeeting):
+			h.addEarlierMeeting(person1, person2)
+		default:
+			h.AddMeeting(person1, person2, meetingTime)
+		}
+		recorded++
+	}
+}
+
+func parseBackfillDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBackfillResultsInExpectedHistory(t *testing.T) {
+	backfillFile := "./testdata/backfill.csv"
+	file, err := os.Open(backfillFile)
+	if err != nil {
+		t.Fatalf("error opening file %s: %v", backfillFile, err)
+	}
+	defer file.Close()
+
+	hist := History{}
+	recorded, err := hist.Backfill(file)
+	if err != nil {
+		t.Fatalf("unexpected error from Backfill: %v", err)
+	}
+
+	if recorded != 4 {
+		t.Errorf("Expected 4 meetings to be recorded, got %d", recorded)
+	}
+
+	expected := getExpectedHistory()
+	expected.setTimesMet(mario, luigi, 2)
+	assertHistoriesEqual(t, expected, hist)
+}
+
+func TestBackfillCountsOlderMeetingsWithoutReplacingMoreRecentOnes(t *testing.T) {
+	hist := getExpectedHistory()
+
+	recorded, err := hist.Backfill(strings.NewReader("mario,luigi,2024-01-01\nmario,luigi,2025-07-20\n"))
+	if err != nil {
+		t.Fatalf("unexpected error from Backfill: %v", err)
+	}
+
+	if recorded != 1 {
+		t.Errorf("Expected the older meeting to be recorded, got %d", recorded)
+	}
+	if times := hist.TimesMet(mario, luigi); times != 2 {
+		t.Errorf("Expected mario and luigi to have met 2 times, got %d", times)
+	}
+
+	expected := getExpectedHistory()
+	expected.setTimesMet(mario, luigi, 2)
+	assertHistoriesEqual(t, expected, hist)
+}
+
+func TestBackfillReturnsErrorForInvalidRows(t *testing.T) {
+	invalid := []string{
+		"mario,luigi\n",
+		"mario,luigi,yesterday\n",
+		"mario,mario,2025-01-01\n",
+		",luigi,2025-01-01\n",
+	}
+
+	for _, data := range invalid {
+		hist := History{}
+		if _, err := hist.Backfill(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected error from Backfill for: %q", data)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// GzipSuffix ends the paths of history files compressed with gzip.
+	GzipSuffix = ".gz"
+	// ZstdSuffix ends the paths of history files compressed with zstd, which are not supported yet.
+	ZstdSuffix = ".zst"
+)
+
+// errZstdUnsupported is returned for history files compressed with zstd, as there is no zstd package in the standard
+// library.
+var errZstdUnsupported = errors.New("zstd compressed histories are not supported, use " + GzipSuffix + " instead")
+
+// decompress returns the data of the history file at path, decompressing it if the path ends in GzipSuffix.
+func decompress(path string, data []byte) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(path, GzipSuffix):
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, corruptError{fmt.Errorf("error decompressing history: %w", err)}
+		}
+		defer reader.Close()
+
+		decompressed, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, corruptError{fmt.Errorf("error decompressing history: %w", err)}
+		}
+		return decompressed, nil
+	case strings.HasSuffix(path, ZstdSuffix):
+		return nil, errZstdUnsupported
+	default:
+		return data, nil
+	}
+}
+
+// nopCloser is a writer with a Close method which does nothing.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// compress returns a writer which compresses what is written to the writer if the path ends in GzipSuffix. The
+// returned writer must be closed to write the end of the compressed data, without closing the writer.
+func compress(path string, writer io.Writer) (io.WriteCloser, error) {
+	switch {
+	case strings.HasSuffix(path, GzipSuffix):
+		return gzip.NewWriter(writer), nil
+	case strings.HasSuffix(path, ZstdSuffix):
+		return nil, errZstdUnsupported
+	default:
+		return nopCloser{writer}, nil
+	}
+}
@@ -0,0 +1,58 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCompactFileCompressesGzipHistories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json"+GzipSuffix)
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	hist := History{}
+	for i := range 100 {
+		hist.AddMeeting(mario, ID(fmt.Sprint("toad", i)), day)
+	}
+	if err := hist.CompactFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history file: %v", err)
+	}
+	var exported bytes.Buffer
+	if err := hist.Export(&exported); err != nil {
+		t.Fatalf("Unexpected error from Export: %v", err)
+	}
+	if len(data) >= exported.Len() || !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		t.Errorf("Expected the history file to be compressed with gzip, got %d bytes for %d exported", len(data), exported.Len())
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	assertHistoriesEqual(t, hist, read)
+}
+
+func TestZstdHistoriesReturnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json"+ZstdSuffix)
+
+	hist := History{}
+	if err := hist.CompactFile(path); !errors.Is(err, errZstdUnsupported) {
+		t.Errorf("Expected error %v, got: %v", errZstdUnsupported, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("Unexpected error writing history file: %v", err)
+	}
+	if _, err := ReadFile(path); !errors.Is(err, errZstdUnsupported) {
+		t.Errorf("Expected error %v, got: %v", errZstdUnsupported, err)
+	}
+}
@@ -0,0 +1,107 @@
+package history
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Clone returns a deep copy of the history and its namespaces, which can be changed without affecting the original.
+func (h *History) Clone() History {
+	clone := h.cloneMeetings()
+	for name, namespace := range h.namespaces {
+		if name == h.namespace {
+			continue
+		}
+		clonedNamespace := namespace.cloneMeetings()
+		clone.Namespace(name).data = clonedNamespace.data
+		clone.Namespace(name).initiators = clonedNamespace.initiators
+		clone.Namespace(name).topics = clonedNamespace.topics
+		clone.Namespace(name).counts = clonedNamespace.counts
+		clone.Namespace(name).state = clonedNamespace.state
+	}
+	clone.revision = h.revision
+	return clone
+}
+
+// cloneMeetings returns a deep copy of the meetings, counts, initiators and topics of the history without its
+// namespaces. The state is shared, as it is replaced rather than changed.
+func (h *History) cloneMeetings() History {
+	clone := History{initiators: maps.Clone(h.initiators), state: h.state}
+
+	if h.data != nil {
+		clone.data = make(map[ID]map[ID]time.Time, len(h.data))
+		for person, personHistory := range h.data {
+			clone.data[person] = maps.Clone(personHistory)
+		}
+	}
+
+	if h.topics != nil {
+		clone.topics = make(map[ID]map[ID][]string, len(h.topics))
+		for person, pairTopics := range h.topics {
+			clone.topics[person] = make(map[ID][]string, len(pairTopics))
+			for other, topics := range pairTopics {
+				clone.topics[person][other] = slices.Clone(topics)
+			}
+		}
+	}
+
+	if h.counts != nil {
+		clone.counts = make(map[ID]map[ID]int, len(h.counts))
+		for person, pairCounts := range h.counts {
+			clone.counts[person] = maps.Clone(pairCounts)
+		}
+	}
+
+	return clone
+}
+
+// MeetingChange is a difference in the last meeting of a pair between two histories.
+type MeetingChange struct {
+	// People are the pair, with the first ID sorting before the second.
+	People [2]ID
+	// Before is the time of the last meeting in the earlier history, zero if the pair had not met.
+	Before time.Time
+	// After is the time of the last meeting in the later history, zero if the meeting was removed.
+	After time.Time
+}
+
+// Added reports whether the pair had not met in the earlier history.
+func (c MeetingChange) Added() bool {
+	return c.Before.IsZero()
+}
+
+// Removed reports whether the pair's meeting is missing from the later history.
+func (c MeetingChange) Removed() bool {
+	return c.After.IsZero()
+}
+
+// Diff returns the meetings added, updated or removed between the histories, ordered by the time of the later
+// meeting and then by the pair. Removed meetings come first. The namespaces of the histories are not compared.
+func Diff(before, after History) []MeetingChange {
+	changes := []MeetingChange{}
+	for pair, afterTime := range after.All() {
+		beforeTime := before.data[pair[0]][pair[1]]
+		if !beforeTime.Equal(afterTime) {
+			changes = append(changes, MeetingChange{People: pair, Before: beforeTime, After: afterTime})
+		}
+	}
+
+	for pair, beforeTime := range before.All() {
+		if _, exists := after.data[pair[0]][pair[1]]; !exists {
+			changes = append(changes, MeetingChange{People: pair, Before: beforeTime})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b MeetingChange) int {
+		if order := a.After.Compare(b.After); order != 0 {
+			return order
+		}
+		if order := strings.Compare(string(a.People[0]), string(b.People[0])); order != 0 {
+			return order
+		}
+		return strings.Compare(string(a.People[1]), string(b.People[1]))
+	})
+	return changes
+}
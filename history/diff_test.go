@@ -0,0 +1,59 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCloneDoesNotShareData(t *testing.T) {
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.AddMeeting(mario, luigi, day)
+	hist.AddInitiator(mario)
+	hist.AddTopic(mario, luigi, "Karts")
+
+	clone := hist.Clone()
+	clone.AddMeeting(mario, luigi, day.AddDate(0, 0, 7))
+	clone.AddMeeting(mario, peach, day)
+	clone.AddInitiator(mario)
+	clone.AddTopic(mario, luigi, "Castles")
+
+	if !hist.GetPersonToLastMeetingMap(mario)[luigi].Equal(day) || len(hist.GetPersonToLastMeetingMap(mario)) != 1 {
+		t.Errorf("Expected the original meetings to be unchanged, got %v", hist.GetPersonToLastMeetingMap(mario))
+	}
+	if hist.TimesInitiated(mario) != 1 {
+		t.Errorf("Expected the original initiators to be unchanged, got %d", hist.TimesInitiated(mario))
+	}
+	if topics := hist.Topics(mario, luigi); !reflect.DeepEqual([]string{"Karts"}, topics) {
+		t.Errorf("Expected the original topics to be unchanged, got %v", topics)
+	}
+}
+
+func TestDiffReturnsChangedMeetings(t *testing.T) {
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	before := History{}
+	before.AddMeeting(mario, luigi, day)
+	before.AddMeeting(mario, peach, day)
+	before.AddMeeting(bowser, peach, day)
+
+	after := before.Clone()
+	after.AddMeeting(mario, luigi, day.AddDate(0, 0, 7))
+	after.AddMeeting(bowser, luigi, day.AddDate(0, 0, 7))
+	delete(after.data[bowser], peach)
+	delete(after.data[peach], bowser)
+
+	expected := []MeetingChange{
+		{People: [2]ID{bowser, peach}, Before: day},
+		{People: [2]ID{bowser, luigi}, After: day.AddDate(0, 0, 7)},
+		{People: [2]ID{luigi, mario}, Before: day, After: day.AddDate(0, 0, 7)},
+	}
+	changes := Diff(before, after)
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, changes)
+	}
+
+	if !changes[0].Removed() || !changes[1].Added() || changes[2].Added() || changes[2].Removed() {
+		t.Errorf("Unexpected kinds of changes: %v", changes)
+	}
+}
@@ -0,0 +1,188 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/internal/sigv4"
+)
+
+const (
+	schemeDynamoDB = "dynamodb"
+	// dynamoDBKeyAttribute is the name of the string partition key of the tables histories are stored in.
+	dynamoDBKeyAttribute = "id"
+)
+
+// DynamoDB stores a history as an item of an Amazon DynamoDB table, whose partition key is a string named id. The item
+// has the compressed history as the binary attribute history, and a number attribute revision which is incremented
+// each time the history is saved and conditions each save.
+type DynamoDB struct {
+	Table string
+	// Key is the partition key of the item, so several histories can be kept in one table.
+	Key    string
+	Region string
+	// Endpoint is the URL of DynamoDB, such as that of DynamoDB Local, defaulting to the endpoint of the region.
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Client is used for requests to DynamoDB, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+// dynamoDBValue is an attribute value of the DynamoDB JSON protocol, of which only strings, numbers and binary data
+// are used.
+type dynamoDBValue struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+func (d DynamoDB) Load(ctx context.Context) (History, error) {
+	request := map[string]any{
+		"TableName":      d.Table,
+		"Key":            map[string]dynamoDBValue{dynamoDBKeyAttribute: {S: &d.Key}},
+		"ConsistentRead": true,
+	}
+	var response struct {
+		Item map[string]dynamoDBValue `json:"Item"`
+	}
+	if err := d.call(ctx, "GetItem", request, &response); err != nil {
+		return History{}, fmt.Errorf("error loading history from DynamoDB: %w", err)
+	}
+	if response.Item == nil {
+		return History{}, fmt.Errorf("history is not stored in DynamoDB table %s: %s, %w", d.Table, d.Key, os.ErrNotExist)
+	}
+
+	revision := response.Item["revision"].N
+	if revision == nil {
+		return History{}, corruptError{fmt.Errorf("history in DynamoDB table %s has no revision: %s", d.Table, d.Key)}
+	}
+	return decodeStored(response.Item["history"].B, *revision)
+}
+
+func (d DynamoDB) Save(ctx context.Context, hist *History) error {
+	data, err := encodeStored(hist)
+	if err != nil {
+		return err
+	}
+
+	var previous int64
+	if hist.revision != "" {
+		if previous, err = strconv.ParseInt(hist.revision, 10, 64); err != nil {
+			return fmt.Errorf("unexpected DynamoDB revision: %s", hist.revision)
+		}
+	}
+	revision := strconv.FormatInt(previous+1, 10)
+
+	request := map[string]any{
+		"TableName": d.Table,
+		"Item": map[string]dynamoDBValue{
+			dynamoDBKeyAttribute: {S: &d.Key},
+			"history":            {B: data},
+			"revision":           {N: &revision},
+		},
+	}
+	// A history which was not loaded must not overwrite one saved by another run since.
+	if hist.revision == "" {
+		request["ConditionExpression"] = "attribute_not_exists(#key)"
+		request["ExpressionAttributeNames"] = map[string]string{"#key": dynamoDBKeyAttribute}
+	} else {
+		request["ConditionExpression"] = "#revision = :revision"
+		request["ExpressionAttributeNames"] = map[string]string{"#revision": "revision"}
+		request["ExpressionAttributeValues"] = map[string]dynamoDBValue{":revision": {N: &hist.revision}}
+	}
+
+	if err := d.call(ctx, "PutItem", request, nil); err != nil {
+		return fmt.Errorf("error saving history to DynamoDB: %w", err)
+	}
+	hist.revision = revision
+	return nil
+}
+
+// CheckWrite puts an item under a condition which never holds, which DynamoDB only evaluates once the credentials are
+// allowed to put the item, so nothing is written either way.
+func (d DynamoDB) CheckWrite(ctx context.Context) error {
+	request := map[string]any{
+		"TableName":                d.Table,
+		"Item":                     map[string]dynamoDBValue{dynamoDBKeyAttribute: {S: &d.Key}},
+		"ConditionExpression":      "attribute_exists(#key) AND attribute_not_exists(#key)",
+		"ExpressionAttributeNames": map[string]string{"#key": dynamoDBKeyAttribute},
+	}
+	if err := d.call(ctx, "PutItem", request, nil); err != nil && !errors.Is(err, ErrConflict) {
+		return fmt.Errorf("error checking DynamoDB history can be saved: %w", err)
+	}
+	return nil
+}
+
+// call makes the request of the action of the DynamoDB API, decoding the response into response unless it is nil.
+// Failed conditions are returned as errors wrapping ErrConflict, and denied access as errors wrapping ErrReadOnly.
+func (d DynamoDB) call(ctx context.Context, action string, request any, response any) error {
+	if d.Region == "" {
+		return fmt.Errorf("the region of DynamoDB table %s is required", d.Table)
+	}
+	if d.AccessKeyID == "" || d.SecretAccessKey == "" {
+		return fmt.Errorf("AWS credentials are required for DynamoDB")
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error encoding DynamoDB request: %w", err)
+	}
+
+	endpoint := d.Endpoint
+	if endpoint == "" {
+		endpoint = "https://dynamodb." + d.Region + ".amazonaws.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating DynamoDB request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "DynamoDB_20120810."+action)
+	sigv4.Sign(req, body, sigv4.Credentials{AccessKeyID: d.AccessKeyID, SecretAccessKey: d.SecretAccessKey, SessionToken: d.SessionToken}, d.Region, "dynamodb", time.Now())
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling DynamoDB: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading DynamoDB response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &failure)
+		if strings.HasSuffix(failure.Type, "#ConditionalCheckFailedException") {
+			return fmt.Errorf("history in DynamoDB table %s: %s, %w", d.Table, d.Key, ErrConflict)
+		}
+		if strings.HasSuffix(failure.Type, "#AccessDeniedException") {
+			return fmt.Errorf("%s, %w", failure.Message, ErrReadOnly)
+		}
+		return fmt.Errorf("DynamoDB responded with %s: %s %s", resp.Status, failure.Type, failure.Message)
+	}
+	if response == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("error decoding DynamoDB response: %w", err)
+	}
+	return nil
+}
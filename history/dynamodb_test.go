@@ -0,0 +1,94 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDynamoDB stores one item per key, checking the conditions of the puts as DynamoDB would. The access key READER
+// is not allowed to put items.
+func fakeDynamoDB(t *testing.T) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	items := map[string]map[string]dynamoDBValue{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/") && !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=READER/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var request struct {
+			Key                       map[string]dynamoDBValue `json:"Key"`
+			Item                      map[string]dynamoDBValue `json:"Item"`
+			ConditionExpression       string                   `json:"ConditionExpression"`
+			ExpressionAttributeValues map[string]dynamoDBValue `json:"ExpressionAttributeValues"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.GetItem":
+			json.NewEncoder(w).Encode(map[string]any{"Item": items[*request.Key["id"].S]})
+		case "DynamoDB_20120810.PutItem":
+			if strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=READER/") {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"__type":"com.amazon.coral.service#AccessDeniedException","message":"not authorized to perform dynamodb:PutItem"}`))
+				return
+			}
+
+			key := *request.Item["id"].S
+			existing, exists := items[key]
+			var holds bool
+			switch request.ConditionExpression {
+			case "attribute_not_exists(#key)":
+				holds = !exists
+			case "#revision = :revision":
+				holds = exists && *existing["revision"].N == *request.ExpressionAttributeValues[":revision"].N
+			}
+			if !holds {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+				return
+			}
+			items[key] = request.Item
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDynamoDBSavesConditionally(t *testing.T) {
+	server := fakeDynamoDB(t)
+
+	testStoreConflicts(t, DynamoDB{Table: "yapper", Key: "plumbers", Region: "eu-west-1", Endpoint: server.URL, AccessKeyID: "AKID", SecretAccessKey: "secret"})
+}
+
+func TestDynamoDBCheckWrite(t *testing.T) {
+	server := fakeDynamoDB(t)
+	writer := DynamoDB{Table: "yapper", Key: "plumbers", Region: "eu-west-1", Endpoint: server.URL, AccessKeyID: "AKID", SecretAccessKey: "secret"}
+	reader := writer
+	reader.AccessKeyID = "READER"
+
+	if err := writer.CheckWrite(context.Background()); err != nil {
+		t.Errorf("Expected the history to be writable, got %v", err)
+	}
+	if _, err := writer.Load(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected nothing to be written, got %v", err)
+	}
+	if err := reader.CheckWrite(context.Background()); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", ErrReadOnly, err)
+	}
+}
@@ -0,0 +1,160 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	schemeEtcd    = "etcd"
+	schemeEtcdTLS = "etcds"
+)
+
+// Etcd stores a history as the value of a key in etcd, through the JSON gateway of its v3 API. Each save is a
+// transaction conditioned on the modification revision of the key, so it fails if another run saved the history since.
+type Etcd struct {
+	// Endpoint is the URL of an etcd server, such as http://localhost:2379.
+	Endpoint string
+	Key      string
+	// Username and Password authenticate with etcd if the username is set.
+	Username string
+	Password string
+	// Client is used for requests to etcd, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+// etcdRevision is an int64 of the etcd JSON gateway, which encodes them as strings.
+type etcdRevision int64
+
+func (r *etcdRevision) UnmarshalJSON(data []byte) error {
+	revision, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	*r = etcdRevision(revision)
+	return err
+}
+
+func (e Etcd) Load(ctx context.Context) (History, error) {
+	var response struct {
+		KVs []struct {
+			Value       []byte       `json:"value"`
+			ModRevision etcdRevision `json:"mod_revision"`
+		} `json:"kvs"`
+	}
+	if err := e.call(ctx, "/v3/kv/range", map[string]any{"key": []byte(e.Key)}, &response); err != nil {
+		return History{}, fmt.Errorf("error loading history from etcd: %w", err)
+	}
+	if len(response.KVs) == 0 {
+		return History{}, fmt.Errorf("history is not stored in etcd: %s, %w", e.Key, os.ErrNotExist)
+	}
+	return decodeStored(response.KVs[0].Value, strconv.FormatInt(int64(response.KVs[0].ModRevision), 10))
+}
+
+func (e Etcd) Save(ctx context.Context, hist *History) error {
+	data, err := encodeStored(hist)
+	if err != nil {
+		return err
+	}
+
+	// A history which was not loaded must not overwrite one saved by another run since.
+	compare := map[string]any{"key": []byte(e.Key), "result": "EQUAL", "target": "CREATE", "create_revision": "0"}
+	if hist.revision != "" {
+		compare = map[string]any{"key": []byte(e.Key), "result": "EQUAL", "target": "MOD", "mod_revision": hist.revision}
+	}
+	request := map[string]any{
+		"compare": []any{compare},
+		"success": []any{map[string]any{"request_put": map[string]any{"key": []byte(e.Key), "value": data}}},
+	}
+
+	var response struct {
+		Header struct {
+			Revision etcdRevision `json:"revision"`
+		} `json:"header"`
+		Succeeded bool `json:"succeeded"`
+	}
+	if err := e.call(ctx, "/v3/kv/txn", request, &response); err != nil {
+		return fmt.Errorf("error saving history to etcd: %w", err)
+	}
+	if !response.Succeeded {
+		return fmt.Errorf("history in etcd: %s, %w", e.Key, ErrConflict)
+	}
+	hist.revision = strconv.FormatInt(int64(response.Header.Revision), 10)
+	return nil
+}
+
+// CheckWrite runs a transaction putting the key only if it was created at a revision which never exists, which etcd
+// only runs once the user is allowed to put the key, so nothing is written either way.
+func (e Etcd) CheckWrite(ctx context.Context) error {
+	request := map[string]any{
+		"compare": []any{map[string]any{"key": []byte(e.Key), "result": "EQUAL", "target": "CREATE", "create_revision": "-1"}},
+		"success": []any{map[string]any{"request_put": map[string]any{"key": []byte(e.Key), "value": []byte{}}}},
+	}
+	var response struct{}
+	if err := e.call(ctx, "/v3/kv/txn", request, &response); err != nil {
+		return fmt.Errorf("error checking etcd history can be saved: %w", err)
+	}
+	return nil
+}
+
+// call posts the request to the path of the etcd JSON gateway, decoding the response into response. The request is
+// authenticated with a token for the username and password if there is a username. Denied permissions are returned as
+// errors wrapping ErrReadOnly.
+func (e Etcd) call(ctx context.Context, path string, request any, response any) error {
+	var token string
+	if e.Username != "" && path != "/v3/auth/authenticate" {
+		var authentication struct {
+			Token string `json:"token"`
+		}
+		if err := e.call(ctx, "/v3/auth/authenticate", map[string]string{"name": e.Username, "password": e.Password}, &authentication); err != nil {
+			return fmt.Errorf("error authenticating with etcd: %w", err)
+		}
+		token = authentication.Token
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error encoding etcd request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(e.Endpoint, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating etcd request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling etcd: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading etcd response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &failure)
+		if resp.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("etcd responded with %s: %s, %w", resp.Status, failure.Message, ErrReadOnly)
+		}
+		return fmt.Errorf("etcd responded with %s: %s", resp.Status, failure.Message)
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("error decoding etcd response: %w", err)
+	}
+	return nil
+}
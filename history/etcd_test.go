@@ -0,0 +1,125 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakeEtcd keeps the values and modification revisions of keys, checking the comparisons of transactions as etcd
+// would, and requires authenticating as root, or as reader who is only allowed to read.
+func fakeEtcd(t *testing.T) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	revision := 1
+	values := map[string][]byte{}
+	revisions := map[string]int{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/auth/authenticate", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+		json.NewDecoder(r.Body).Decode(&request)
+		switch {
+		case request["name"] == "root" && request["password"] == "pass":
+			w.Write([]byte(`{"token":"token-1"}`))
+		case request["name"] == "reader" && request["password"] == "pass":
+			w.Write([]byte(`{"token":"token-2"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"authentication failed"}`))
+		}
+	})
+	mux.HandleFunc("POST /v3/kv/range", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var request struct {
+			Key []byte `json:"key"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if value, exists := values[string(request.Key)]; exists && r.Header.Get("Authorization") != "" {
+			json.NewEncoder(w).Encode(map[string]any{"kvs": []any{map[string]any{"key": request.Key, "value": value, "mod_revision": strconv.Itoa(revisions[string(request.Key)])}}})
+			return
+		}
+		w.Write([]byte(`{"header":{}}`))
+	})
+	mux.HandleFunc("POST /v3/kv/txn", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Authorization") != "token-1" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"etcdserver: permission denied","code":7,"message":"etcdserver: permission denied"}`))
+			return
+		}
+		var request struct {
+			Compare []struct {
+				Key            []byte `json:"key"`
+				Target         string `json:"target"`
+				ModRevision    string `json:"mod_revision"`
+				CreateRevision string `json:"create_revision"`
+			} `json:"compare"`
+			Success []struct {
+				RequestPut struct {
+					Key   []byte `json:"key"`
+					Value []byte `json:"value"`
+				} `json:"request_put"`
+			} `json:"success"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		compare := request.Compare[0]
+		current, exists := revisions[string(compare.Key)]
+		created := 0
+		if exists {
+			created = 1
+		}
+		succeeded := (compare.Target == "CREATE" && compare.CreateRevision == strconv.Itoa(created)) || (compare.Target == "MOD" && exists && compare.ModRevision == strconv.Itoa(current))
+		if succeeded {
+			revision++
+			put := request.Success[0].RequestPut
+			values[string(put.Key)], revisions[string(put.Key)] = put.Value, revision
+		}
+		fmt.Fprintf(w, `{"header":{"revision":"%d"},"succeeded":%t}`, revision, succeeded)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEtcdSavesConditionally(t *testing.T) {
+	server := fakeEtcd(t)
+
+	testStoreConflicts(t, Etcd{Endpoint: server.URL, Key: "yapper/plumbers", Username: "root", Password: "pass"})
+}
+
+func TestEtcdReportsFailedAuthentication(t *testing.T) {
+	server := fakeEtcd(t)
+
+	_, err := Etcd{Endpoint: server.URL, Key: "yapper/plumbers", Username: "root", Password: "wrong"}.Load(context.Background())
+	if err == nil {
+		t.Errorf("Expected an error authenticating with the wrong password")
+	}
+}
+
+func TestEtcdCheckWrite(t *testing.T) {
+	server := fakeEtcd(t)
+	writer := Etcd{Endpoint: server.URL, Key: "yapper/plumbers", Username: "root", Password: "pass"}
+	reader := Etcd{Endpoint: server.URL, Key: "yapper/plumbers", Username: "reader", Password: "pass"}
+
+	if err := writer.CheckWrite(context.Background()); err != nil {
+		t.Errorf("Expected the history to be writable, got %v", err)
+	}
+	if _, err := writer.Load(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected nothing to be written, got %v", err)
+	}
+	if err := reader.CheckWrite(context.Background()); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", ErrReadOnly, err)
+	}
+}
@@ -0,0 +1,53 @@
+package history
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// malformedHistories are inputs which NewHistoryFromFile must reject with an error rather than panicking or hanging.
+var malformedHistories = []string{
+	"",
+	"[",
+	`{"version": 2, "meetings": ` + strings.Repeat("[", 100_000) + strings.Repeat("]", 100_000) + "}",
+	`{"version": 2, "meetings": {"Mario": {"Luigi": "2025-13-45T00:00:00Z"}}}`,
+	`{"version": 2, "meetings": {"Mario": {"Luigi": "yesterday"}}}`,
+	`{"version": 99999999999999999999}`,
+	`{"Mario": {"Luigi": 1}}`,
+}
+
+func TestNewHistoryFromFileReturnsErrorsForMalformedInput(t *testing.T) {
+	for _, data := range malformedHistories {
+		if _, err := NewHistoryFromFile(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected an error reading history %.80q", data)
+		}
+	}
+}
+
+func FuzzNewHistoryFromFile(f *testing.F) {
+	for _, name := range []string{"expected_history.json", "unversioned_history.json"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			f.Fatalf("Unexpected error reading history: %v", err)
+		}
+		f.Add(data)
+	}
+	for _, data := range malformedHistories {
+		f.Add([]byte(data))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		hist, err := NewHistoryFromFile(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		var buffer bytes.Buffer
+		if err := hist.Export(&buffer); err != nil {
+			t.Errorf("Unexpected error exporting history read from %q: %v", data, err)
+		}
+	})
+}
@@ -2,38 +2,107 @@ package history
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"slices"
+	"strings"
 	"time"
 )
 
 type ID string
 
-// History keeps track of which people have met and when their last meeting was.
+// ErrCorrupt is wrapped by the errors of reading history data or a journal which cannot be decoded.
+var ErrCorrupt = errors.New("history is corrupt")
+
+// corruptError keeps the message of an error decoding history data while also wrapping ErrCorrupt.
+type corruptError struct {
+	error
+}
+
+func (e corruptError) Unwrap() []error {
+	return []error{e.error, ErrCorrupt}
+}
+
+// History keeps track of which people have met, how many times and when their last meeting was,
+// along with how many times each person was responsible for scheduling a meeting and the topics each pair discussed.
+// The meetings of separate programs can be kept apart in namespaces of the same history, see Namespace.
 type History struct {
-	data map[ID]map[ID]time.Time
+	data       map[ID]map[ID]time.Time
+	initiators map[ID]int
+	topics     map[ID]map[ID][]string
+	// counts are the number of times each pair met, keyed by the IDs of the pair in sorted order, for the pairs who
+	// met more than once.
+	counts map[ID]map[ID]int
+	// state is what the history keeps about the runs of yapper, nil until it is first changed. It is replaced rather
+	// than changed, so it can be shared by copies of the history.
+	state *State
+	// changes are the changes since the history was read from a file with a journal, see ReadFile.
+	changes *[]journalEntry
+	// namespace is the name of the namespace, empty for the history containing the namespaces.
+	namespace string
+	// namespaces are shared by the history and each of its namespaces.
+	namespaces map[string]*History
+	// indent is the indentation of the history file it was read from, kept when the file is written, see SetIndent.
+	indent string
+	// revision is the revision of the history in the store it was loaded from, empty if it was not, see Store.
+	revision string
 }
 
 // NewHistoryFromFile attempts to unmarshal the data from the given reader and return a History.
+// Data in older formats is migrated to the current format.
 func NewHistoryFromFile(reader io.Reader) (History, error) {
 	history := History{}
 
+	var data json.RawMessage
 	decoder := json.NewDecoder(reader)
-	if err := decoder.Decode(&history.data); err != nil {
-		return history, fmt.Errorf("error decoding history: %w", err)
+	if err := decoder.Decode(&data); err != nil {
+		return history, corruptError{fmt.Errorf("error decoding history: %w", err)}
+	}
+
+	file, err := decodeHistoryData(data)
+	if err != nil {
+		return history, corruptError{fmt.Errorf("error decoding history: %w", err)}
+	}
+
+	history.data = file.Meetings
+	history.initiators = file.Initiators
+	history.topics = file.Topics
+	history.counts = file.Counts
+	history.state = file.State
+	for name, namespace := range file.Namespaces {
+		program := history.Namespace(name)
+		program.data = namespace.Meetings
+		program.initiators = namespace.Initiators
+		program.topics = namespace.Topics
+		program.counts = namespace.Counts
+		program.state = namespace.State
 	}
 	return history, nil
 }
 
-// AddMeeting updates the meeting time for the given people.
+// AddMeeting updates the meeting time for the given people, counting the meeting if it is after their last one.
+// Adding the last meeting of a pair again is not counted, so replaying a journal over its history is harmless.
 func (h *History) AddMeeting(person1 ID, person2 ID, meetingTime time.Time) {
 	if h.data == nil {
 		h.data = make(map[ID]map[ID]time.Time)
 	}
 
+	if last, met := h.data[person1][person2]; met && meetingTime.After(last) {
+		h.setTimesMet(person1, person2, h.TimesMet(person1, person2)+1)
+	}
+
 	h.addMeetingToPersonsHistory(person1, person2, meetingTime)
 	h.addMeetingToPersonsHistory(person2, person1, meetingTime)
+	h.record(journalEntry{People: []ID{person1, person2}, Time: &meetingTime})
+}
+
+// addEarlierMeeting counts a meeting of a pair who have met since, leaving the time of their last meeting unchanged.
+func (h *History) addEarlierMeeting(person1, person2 ID) {
+	count := h.TimesMet(person1, person2) + 1
+	h.setTimesMet(person1, person2, count)
+	h.record(journalEntry{People: []ID{person1, person2}, Count: count})
 }
 
 // GetPersonToLastMeetingMap returns a map of the people they have met and the time of that meeting.
@@ -46,9 +115,127 @@ func (h *History) GetPersonToLastMeetingMap(person ID) map[ID]time.Time {
 	return personHistory
 }
 
-// Export writes the history data to the given writer, typically a file.
+// All yields each pair of people who have met once, with the time of their last meeting.
+// The first ID of each pair sorts before the second, and the pairs are yielded in no particular order.
+func (h *History) All() iter.Seq2[[2]ID, time.Time] {
+	return func(yield func([2]ID, time.Time) bool) {
+		for person, personHistory := range h.data {
+			for other, meetingTime := range personHistory {
+				if person < other && !yield([2]ID{person, other}, meetingTime) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// PartnersOf yields the people the person has met, with the time of their last meeting, in no particular order.
+func (h *History) PartnersOf(person ID) iter.Seq2[ID, time.Time] {
+	return func(yield func(ID, time.Time) bool) {
+		for other, meetingTime := range h.data[person] {
+			if !yield(other, meetingTime) {
+				return
+			}
+		}
+	}
+}
+
+// TimesMet returns the number of times the pair has met. Pairs who met before the meetings were counted are counted
+// as having met once.
+func (h *History) TimesMet(person1, person2 ID) int {
+	if _, met := h.data[person1][person2]; !met {
+		return 0
+	}
+	if person2 < person1 {
+		person1, person2 = person2, person1
+	}
+	return max(h.counts[person1][person2], 1)
+}
+
+// setTimesMet sets the number of times the pair has met, which is only kept for pairs who met more than once.
+func (h *History) setTimesMet(person1, person2 ID, count int) {
+	if person2 < person1 {
+		person1, person2 = person2, person1
+	}
+
+	if count <= 1 {
+		delete(h.counts[person1], person2)
+		return
+	}
+	if h.counts == nil {
+		h.counts = make(map[ID]map[ID]int)
+	}
+	if h.counts[person1] == nil {
+		h.counts[person1] = make(map[ID]int)
+	}
+	h.counts[person1][person2] = count
+}
+
+// AddInitiator counts a meeting the person was responsible for scheduling.
+func (h *History) AddInitiator(person ID) {
+	if h.initiators == nil {
+		h.initiators = make(map[ID]int)
+	}
+	h.initiators[person]++
+	h.record(journalEntry{Initiator: person, Count: h.initiators[person]})
+}
+
+// TimesInitiated returns the number of meetings the person was responsible for scheduling.
+func (h *History) TimesInitiated(person ID) int {
+	return h.initiators[person]
+}
+
+// AddTopic records a conversation topic the pair discussed.
+func (h *History) AddTopic(person1, person2 ID, topic string) {
+	if person2 < person1 {
+		person1, person2 = person2, person1
+	}
+
+	if h.topics == nil {
+		h.topics = make(map[ID]map[ID][]string)
+	}
+	if h.topics[person1] == nil {
+		h.topics[person1] = make(map[ID][]string)
+	}
+	if !slices.Contains(h.topics[person1][person2], topic) {
+		h.topics[person1][person2] = append(h.topics[person1][person2], topic)
+		h.record(journalEntry{People: []ID{person1, person2}, Topic: topic})
+	}
+}
+
+// Topics returns the conversation topics the pair has discussed, in the order they were first discussed.
+func (h *History) Topics(person1, person2 ID) []string {
+	if person2 < person1 {
+		person1, person2 = person2, person1
+	}
+	return h.topics[person1][person2]
+}
+
+// Export writes the history data to the given writer, typically a file, in the current format.
+// The people and namespaces are written in sorted order, so the same history always exports the same data and
+// saved histories only differ where their meetings do.
 func (h *History) Export(writer io.Writer) error {
-	data, err := json.Marshal(h.data)
+	return h.ExportIndent(writer, "")
+}
+
+// ExportIndent writes the history like Export, with each element on its own line indented by indent so large
+// histories can be read and diffed. Nothing is indented if indent is empty.
+func (h *History) ExportIndent(writer io.Writer, indent string) error {
+	file := historyFile{Version: SchemaVersion, Meetings: h.data, Initiators: h.initiators, Topics: h.topics, Counts: h.counts, State: h.state}
+	for name, namespace := range h.namespaces {
+		if file.Namespaces == nil {
+			file.Namespaces = make(map[string]namespaceFile, len(h.namespaces))
+		}
+		file.Namespaces[name] = namespaceFile{Meetings: namespace.data, Initiators: namespace.initiators, Topics: namespace.topics, Counts: namespace.counts, State: namespace.state}
+	}
+
+	var data []byte
+	var err error
+	if indent == "" {
+		data, err = json.Marshal(file)
+	} else {
+		data, err = json.MarshalIndent(file, "", indent)
+	}
 	if err != nil {
 		return fmt.Errorf("error marshalling history: %w", err)
 	}
@@ -70,21 +257,19 @@ func (h *History) addMeetingToPersonsHistory(person ID, otherPerson ID, meetingT
 }
 
 // GetPeopleMetSortedByLastMeeting returns a slice of people they have met in decreasing time since last meeting.
+// People last met at the same time are sorted by ID.
 func GetPeopleMetSortedByLastMeeting(hist History, person ID) []ID {
 	peopleToTime := hist.GetPersonToLastMeetingMap(person)
-	var sortedPeople []ID
-
-	for p, meetingTime := range peopleToTime {
-		index := 0
-		for _, sortedPerson := range sortedPeople {
-			if meetingTime.Before(peopleToTime[sortedPerson]) {
-				break
-			}
-			index++
-		}
-
-		sortedPeople = slices.Insert(sortedPeople, index, p)
+	sortedPeople := make([]ID, 0, len(peopleToTime))
+	for p := range peopleToTime {
+		sortedPeople = append(sortedPeople, p)
 	}
 
+	slices.SortFunc(sortedPeople, func(a, b ID) int {
+		if order := peopleToTime[a].Compare(peopleToTime[b]); order != 0 {
+			return order
+		}
+		return strings.Compare(string(a), string(b))
+	})
 	return sortedPeople
 }
@@ -2,6 +2,7 @@ package history
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -92,6 +93,30 @@ func TestHistoryExportWritesExpectedData(t *testing.T) {
 	}
 }
 
+func TestHistoryExportIsIndependentOfOrder(t *testing.T) {
+	meetings := [][2]ID{{"Mario", "Luigi"}, {"Peach", "Toad"}, {"Luigi", "Peach"}, {"Yoshi", "Mario"}}
+	meetingTime := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	var exports []string
+	for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}} {
+		hist := History{}
+		for _, i := range order {
+			hist.AddMeeting(meetings[i][0], meetings[i][1], meetingTime)
+			hist.Namespace(fmt.Sprint("program-", i)).AddMeeting(meetings[i][0], meetings[i][1], meetingTime)
+		}
+
+		var buffer bytes.Buffer
+		if err := hist.Export(&buffer); err != nil {
+			t.Fatalf("Unexpected error from Export: %v", err)
+		}
+		exports = append(exports, buffer.String())
+	}
+
+	if exports[0] != exports[1] {
+		t.Errorf("Expected the same export regardless of order:\n%v\n%v", exports[0], exports[1])
+	}
+}
+
 func TestNewHistoryFromFileResultsInExpectedHistory(t *testing.T) {
 	expectedHistory := getExpectedHistory()
 	expectedDataFile := "./testdata/expected_history.json"
@@ -113,6 +138,51 @@ func TestNewHistoryFromFileResultsInExpectedHistory(t *testing.T) {
 	assertHistoriesEqual(t, expectedHistory, hist)
 }
 
+func TestInitiatorsAreKeptWhenExported(t *testing.T) {
+	hist := getExpectedHistory()
+	hist.AddInitiator(mario)
+	hist.AddInitiator(mario)
+	hist.AddInitiator(luigi)
+
+	var buffer bytes.Buffer
+	if err := hist.Export(&buffer); err != nil {
+		t.Fatalf("unexpected error from Export: %v", err)
+	}
+
+	imported, err := NewHistoryFromFile(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error from NewHistoryFromFile: %v", err)
+	}
+
+	for person, expected := range map[ID]int{mario: 2, luigi: 1, peach: 0} {
+		if initiated := imported.TimesInitiated(person); initiated != expected {
+			t.Errorf("Expected %s to have initiated %d meetings, got %d", person, expected, initiated)
+		}
+	}
+}
+
+func TestTopicsAreKeptWhenExported(t *testing.T) {
+	hist := History{}
+	hist.AddTopic(mario, luigi, "Career goals")
+	hist.AddTopic(luigi, mario, "Feedback")
+	hist.AddTopic(mario, luigi, "Career goals")
+
+	var buffer bytes.Buffer
+	if err := hist.Export(&buffer); err != nil {
+		t.Fatalf("unexpected error from Export: %v", err)
+	}
+
+	imported, err := NewHistoryFromFile(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error from NewHistoryFromFile: %v", err)
+	}
+
+	expected := []string{"Career goals", "Feedback"}
+	if topics := imported.Topics(luigi, mario); !reflect.DeepEqual(expected, topics) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, topics)
+	}
+}
+
 func getExpectedHistory() History {
 	date1 := time.Date(2025, time.July, 20, 0, 0, 0, 0, time.UTC)
 	date2 := time.Date(2025, time.June, 5, 0, 0, 0, 0, time.UTC)
@@ -163,3 +233,99 @@ func readExpectedData(t *testing.T, path string) (string, error) {
 
 	return strings.TrimSpace(string(fileBytes)), nil
 }
+
+func TestAllYieldsEachPairOnce(t *testing.T) {
+	hist := History{}
+	day1 := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 7)
+	hist.AddMeeting(mario, luigi, day1)
+	hist.AddMeeting(peach, mario, day2)
+
+	meetings := map[[2]ID]time.Time{}
+	for pair, meetingTime := range hist.All() {
+		meetings[pair] = meetingTime
+	}
+
+	expected := map[[2]ID]time.Time{{luigi, mario}: day1, {mario, peach}: day2}
+	if !reflect.DeepEqual(expected, meetings) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, meetings)
+	}
+
+	for range hist.All() {
+		break
+	}
+}
+
+func TestPartnersOfYieldsEveryoneMet(t *testing.T) {
+	hist := History{}
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist.AddMeeting(mario, luigi, day)
+	hist.AddMeeting(peach, mario, day)
+
+	partners := map[ID]time.Time{}
+	for other, meetingTime := range hist.PartnersOf(mario) {
+		partners[other] = meetingTime
+	}
+
+	expected := map[ID]time.Time{luigi: day, peach: day}
+	if !reflect.DeepEqual(expected, partners) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, partners)
+	}
+
+	for range hist.PartnersOf(bowser) {
+		t.Errorf("Expected no partners for %s", bowser)
+	}
+}
+
+func TestHistoryExportIndentReadsBack(t *testing.T) {
+	hist := getExpectedHistory()
+
+	var buffer bytes.Buffer
+	if err := hist.ExportIndent(&buffer, "  "); err != nil {
+		t.Fatalf("Unexpected error from ExportIndent: %v", err)
+	}
+	if !strings.Contains(buffer.String(), "\n  \"version\"") {
+		t.Errorf("Expected the export to be indented, got:\n%s", buffer.String())
+	}
+
+	read, err := NewHistoryFromFile(&buffer)
+	if err != nil {
+		t.Fatalf("Unexpected error from NewHistoryFromFile: %v", err)
+	}
+	assertHistoriesEqual(t, hist, read)
+}
+
+func TestNewHistoryFromFileReturnsErrCorrupt(t *testing.T) {
+	for _, data := range []string{`{"version": 2, "meetings": [`, `{"version": 2, "meetings": {"Mario": "yesterday"}}`} {
+		if _, err := NewHistoryFromFile(strings.NewReader(data)); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Expected error %v for %s, got: %v", ErrCorrupt, data, err)
+		}
+	}
+}
+
+func TestTimesMetCountsLaterMeetingsOnce(t *testing.T) {
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.AddMeeting("Mario", "Luigi", date)
+	hist.AddMeeting("Luigi", "Mario", date.AddDate(0, 0, 7))
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, 7))
+
+	if times := hist.TimesMet("Luigi", "Mario"); times != 2 {
+		t.Errorf("Expected Mario and Luigi to have met 2 times, got %d", times)
+	}
+	if times := hist.TimesMet("Mario", "Peach"); times != 0 {
+		t.Errorf("Expected Mario and Peach not to have met, got %d", times)
+	}
+
+	var buffer bytes.Buffer
+	if err := hist.Export(&buffer); err != nil {
+		t.Fatalf("Unexpected error exporting history: %v", err)
+	}
+	read, err := NewHistoryFromFile(&buffer)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if times := read.TimesMet("Mario", "Luigi"); times != 2 {
+		t.Errorf("Expected the count to be kept when exported, got %d", times)
+	}
+}
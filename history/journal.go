@@ -0,0 +1,227 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// JournalSuffix is appended to the path of a history file for the path of its journal.
+const JournalSuffix = ".journal"
+
+// journalEntry is a line of a journal, recording a meeting, a topic a pair discussed, the number of meetings of a pair
+// or a person has initiated or the state of the history. The number of meetings is the total rather than an increment, and
+// the state is the whole state, so that applying an entry twice after a compaction is interrupted gives the same
+// history.
+type journalEntry struct {
+	Namespace string     `json:"namespace,omitempty"`
+	People    []ID       `json:"people,omitempty"`
+	Time      *time.Time `json:"time,omitempty"`
+	Topic     string     `json:"topic,omitempty"`
+	Initiator ID         `json:"initiator,omitempty"`
+	Count     int        `json:"count,omitempty"`
+	State     *State     `json:"state,omitempty"`
+}
+
+// record keeps the change to be appended to the journal, if the history was read from a file with one.
+func (h *History) record(entry journalEntry) {
+	if h.changes != nil {
+		entry.Namespace = h.namespace
+		*h.changes = append(*h.changes, entry)
+	}
+}
+
+// trackChanges starts recording the changes to the history and its namespaces for appending to the journal.
+func (h *History) trackChanges() {
+	h.changes = &[]journalEntry{}
+	for _, namespace := range h.namespaces {
+		namespace.changes = h.changes
+	}
+}
+
+// apply makes the change of the journal entry to the history, which must not be recording changes yet.
+func (h *History) apply(entry journalEntry) error {
+	h = h.Namespace(entry.Namespace)
+	switch {
+	case entry.State != nil:
+		h.state = entry.State
+	case entry.Initiator != "":
+		if h.initiators == nil {
+			h.initiators = make(map[ID]int)
+		}
+		h.initiators[entry.Initiator] = entry.Count
+	case len(entry.People) != 2:
+		return fmt.Errorf("expected a pair of people, got %v", entry.People)
+	case entry.Time != nil:
+		h.AddMeeting(entry.People[0], entry.People[1], *entry.Time)
+	case entry.Topic != "":
+		h.AddTopic(entry.People[0], entry.People[1], entry.Topic)
+	case entry.Count > 0:
+		h.setTimesMet(entry.People[0], entry.People[1], entry.Count)
+	default:
+		return fmt.Errorf("expected a meeting time, topic or count for %v", entry.People)
+	}
+	return nil
+}
+
+// EnableJournal creates an empty journal for the history file at path, so later changes are appended to the journal
+// by WriteFile instead of rewriting the whole file.
+func EnableJournal(path string) error {
+	file, err := os.OpenFile(path+JournalSuffix, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating journal: %w", err)
+	}
+	return file.Close()
+}
+
+// ReadFile reads the history file at path, applying the changes in its journal if there is one. The history can
+// be read from just the journal if the file does not exist yet. An error wrapping os.ErrNotExist is returned if
+// there is neither. Files with paths ending in GzipSuffix are decompressed, while their journal is not compressed.
+func ReadFile(path string) (History, error) {
+	hist := History{}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if data, err = decompress(path, data); err != nil {
+			return History{}, err
+		}
+		hist, err = NewHistoryFromFile(bytes.NewReader(data))
+		if err != nil {
+			return History{}, err
+		}
+		hist.indent = detectIndent(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return History{}, fmt.Errorf("error opening history: %w", err)
+	}
+	missing := err != nil
+
+	journal, err := os.Open(path + JournalSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		if missing {
+			return History{}, fmt.Errorf("history file does not exist: %s, %w", path, err)
+		}
+		return hist, nil
+	} else if err != nil {
+		return History{}, fmt.Errorf("error opening journal: %w", err)
+	}
+	defer journal.Close()
+
+	decoder := json.NewDecoder(journal)
+	for line := 1; ; line++ {
+		var entry journalEntry
+		if err := decoder.Decode(&entry); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return History{}, corruptError{fmt.Errorf("error decoding journal entry %d: %w", line, err)}
+		}
+
+		if err := hist.apply(entry); err != nil {
+			return History{}, corruptError{fmt.Errorf("error in journal entry %d: %w", line, err)}
+		}
+	}
+
+	hist.trackChanges()
+	return hist, nil
+}
+
+// WriteFile saves the history to the file at path. If the history was read with a journal by ReadFile, the changes
+// since are appended to the journal, which is compacted into the file once it is larger than the file. Otherwise the
+// whole file is written.
+func (h *History) WriteFile(path string) error {
+	if h.changes == nil {
+		return h.CompactFile(path)
+	}
+
+	journal, err := os.OpenFile(path+JournalSuffix, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening journal: %w", err)
+	}
+
+	encoder := json.NewEncoder(journal)
+	for _, entry := range *h.changes {
+		if err := encoder.Encode(entry); err != nil {
+			journal.Close()
+			return fmt.Errorf("error writing journal: %w", err)
+		}
+	}
+	if err := journal.Close(); err != nil {
+		return fmt.Errorf("error writing journal: %w", err)
+	}
+	*h.changes = (*h.changes)[:0]
+
+	journalInfo, err := os.Stat(path + JournalSuffix)
+	if err != nil {
+		return fmt.Errorf("error checking journal: %w", err)
+	}
+	fileInfo, err := os.Stat(path)
+	if err == nil && journalInfo.Size() <= fileInfo.Size() {
+		return nil
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error checking history: %w", err)
+	}
+
+	return h.CompactFile(path)
+}
+
+// SetIndent sets the indentation the history file is written with by WriteFile and CompactFile. The history is
+// written on a single line if indent is empty. A history read with ReadFile keeps the indentation of its file.
+func (h *History) SetIndent(indent string) {
+	h.indent = indent
+}
+
+// detectIndent returns the indentation of the second line of the history file data, which is empty if the history
+// was written on a single line.
+func detectIndent(data []byte) string {
+	_, rest, found := bytes.Cut(data, []byte("\n"))
+	if !found {
+		return ""
+	}
+	return string(rest[:len(rest)-len(bytes.TrimLeft(rest, " \t"))])
+}
+
+// CompactFile writes the whole history to the file at path, emptying its journal if there is one.
+// The file is replaced only once the history has been written in full. It is indented as set by SetIndent, and
+// compressed with gzip if the path ends in GzipSuffix.
+func (h *History) CompactFile(path string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("error creating history: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return fmt.Errorf("error creating history: %w", err)
+	}
+	writer, err := compress(path, file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := h.ExportIndent(writer, h.indent); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		file.Close()
+		return fmt.Errorf("error compressing history: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing history: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("error replacing history: %w", err)
+	}
+
+	if err := os.Truncate(path+JournalSuffix, 0); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error emptying journal: %w", err)
+	}
+	if h.changes != nil {
+		*h.changes = (*h.changes)[:0]
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAppendsChangesToJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	hist := History{}
+	for i := range 20 {
+		hist.AddMeeting(mario, ID(fmt.Sprint("toad", i)), day)
+	}
+	if err := hist.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+	if err := EnableJournal(path); err != nil {
+		t.Fatalf("Unexpected error enabling journal: %v", err)
+	}
+
+	journaled, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	journaled.AddMeeting(mario, luigi, day.AddDate(0, 0, 7))
+	journaled.AddInitiator(luigi)
+	journaled.AddTopic(mario, luigi, "Karts")
+	if err := journaled.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	base, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history file: %v", err)
+	}
+	journal, err := os.ReadFile(path + JournalSuffix)
+	if err != nil {
+		t.Fatalf("Unexpected error reading journal: %v", err)
+	}
+	if len(journal) == 0 || len(journal) > len(base) {
+		t.Errorf("Expected the changes to be appended to the journal, got:\n%s", journal)
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if !read.GetPersonToLastMeetingMap(luigi)[mario].Equal(day.AddDate(0, 0, 7)) || len(read.GetPersonToLastMeetingMap(mario)) != 21 {
+		t.Errorf("Expected the journaled meeting, got %v", read.GetPersonToLastMeetingMap(mario))
+	}
+	if read.TimesInitiated(luigi) != 1 {
+		t.Errorf("Expected the journaled initiator, got %d", read.TimesInitiated(luigi))
+	}
+	if topics := read.Topics(mario, luigi); !reflect.DeepEqual([]string{"Karts"}, topics) {
+		t.Errorf("Expected the journaled topic, got %v", topics)
+	}
+}
+
+func TestWriteFileCompactsLargeJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := EnableJournal(path); err != nil {
+		t.Fatalf("Unexpected error enabling journal: %v", err)
+	}
+
+	hist, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	hist.AddMeeting(mario, luigi, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	hist.AddInitiator(mario)
+	if err := hist.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	if journal, err := os.ReadFile(path + JournalSuffix); err != nil || len(journal) != 0 {
+		t.Errorf("Expected the journal to be compacted into the file, got %q, %v", journal, err)
+	}
+
+	// Initiators are journaled as totals, so replaying the journal after an interrupted compaction does not count
+	// them twice.
+	if err := os.WriteFile(path+JournalSuffix, []byte(`{"initiator":"mario","count":1}`+"\n"), 0o644); err != nil {
+		t.Fatalf("Unexpected error writing journal: %v", err)
+	}
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if read.TimesInitiated(mario) != 1 || !read.GetPersonToLastMeetingMap(mario)[luigi].Equal(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the compacted history, got %v", read)
+	}
+}
+
+func TestReadFileReturnsNotExistWithoutFileOrJournal(t *testing.T) {
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "history.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not exist error, got %v", err)
+	}
+}
+
+// benchmarkHistory returns a history of about 100k meetings between 450 people.
+func benchmarkHistory() History {
+	hist := History{}
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	for i := range 450 {
+		for j := range i {
+			hist.AddMeeting(ID(fmt.Sprint("person", i)), ID(fmt.Sprint("person", j)), day.AddDate(0, 0, 7*((i+j)%52)))
+		}
+	}
+	return hist
+}
+
+// benchmarkRound records a round of meetings between the 450 people.
+func benchmarkRound(hist *History, round int) {
+	day := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 7*round)
+	for i := 0; i < 450; i += 2 {
+		hist.AddMeeting(ID(fmt.Sprint("person", i)), ID(fmt.Sprint("person", (i+1+2*round)%450)), day)
+	}
+}
+
+func BenchmarkWriteFileRewrite(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "history.json")
+	hist := benchmarkHistory()
+
+	b.ResetTimer()
+	for i := range b.N {
+		benchmarkRound(&hist, i)
+		if err := hist.WriteFile(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteFileJournal(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "history.json")
+	initial := benchmarkHistory()
+	if err := initial.WriteFile(path); err != nil {
+		b.Fatal(err)
+	}
+	if err := EnableJournal(path); err != nil {
+		b.Fatal(err)
+	}
+	hist, err := ReadFile(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := range b.N {
+		benchmarkRound(&hist, i)
+		if err := hist.WriteFile(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestWriteFileKeepsIndentationOfFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	hist := History{}
+	hist.AddMeeting(mario, luigi, day)
+	hist.SetIndent("\t")
+	if err := hist.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	read.AddMeeting(mario, luigi, day.AddDate(0, 0, 7))
+	if err := read.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history file: %v", err)
+	}
+	var expected bytes.Buffer
+	if err := read.ExportIndent(&expected, "\t"); err != nil {
+		t.Fatalf("Unexpected error from ExportIndent: %v", err)
+	}
+	if string(data) != expected.String() {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected.String(), data)
+	}
+}
+
+func TestJournalKeepsCountsOfOlderBackfilledMeetings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	hist := History{}
+	hist.AddMeeting(mario, luigi, time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC))
+	if err := hist.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+	if err := EnableJournal(path); err != nil {
+		t.Fatalf("Unexpected error enabling journal: %v", err)
+	}
+
+	journaled, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if _, err := journaled.Backfill(strings.NewReader("mario,luigi,2025-01-06\n")); err != nil {
+		t.Fatalf("Unexpected error from Backfill: %v", err)
+	}
+	if err := journaled.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	replayed, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if times := replayed.TimesMet(mario, luigi); times != 2 {
+		t.Errorf("Expected mario and luigi to have met 2 times, got %d", times)
+	}
+}
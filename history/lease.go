@@ -0,0 +1,102 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// LeaseSuffix is appended to the path of a history file for the path of its lease file.
+const LeaseSuffix = ".lease"
+
+// Lease is held by the one of several replicas sharing a history which is allowed to change it, until it expires.
+type Lease struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+// AcquireLease takes the lease on the history file at path for the holder until ttl after now, or renews it if the
+// holder already has it. False is returned along with the current lease if another holder has a lease which has not
+// expired. Unlike Lock, a replica which crashes only keeps the lease until it expires.
+func AcquireLease(path, holder string, ttl time.Duration, now time.Time) (Lease, bool, error) {
+	leasePath := path + LeaseSuffix
+	unlock, err := Lock(leasePath)
+	if errors.Is(err, ErrLocked) {
+		// Another replica is acquiring the lease. Its lock is only left behind if it crashed while doing so.
+		if info, statErr := os.Stat(leasePath + LockSuffix); statErr == nil && now.Sub(info.ModTime()) > ttl {
+			os.Remove(leasePath + LockSuffix)
+		}
+		return Lease{}, false, nil
+	} else if err != nil {
+		return Lease{}, false, fmt.Errorf("error acquiring lease: %w", err)
+	}
+	defer unlock()
+
+	current, err := readLease(leasePath)
+	if err != nil {
+		return Lease{}, false, err
+	}
+	if current.Holder != "" && current.Holder != holder && now.Before(current.Expires) {
+		return current, false, nil
+	}
+
+	lease := Lease{Holder: holder, Expires: now.Add(ttl)}
+	if err := writeLease(leasePath, lease); err != nil {
+		return Lease{}, false, err
+	}
+	return lease, true, nil
+}
+
+// ReleaseLease gives up the lease on the history file at path if the holder has it, so another replica can take it
+// without waiting for it to expire.
+func ReleaseLease(path, holder string) error {
+	leasePath := path + LeaseSuffix
+	unlock, err := Lock(leasePath)
+	if err != nil {
+		return fmt.Errorf("error releasing lease: %w", err)
+	}
+	defer unlock()
+
+	current, err := readLease(leasePath)
+	if err != nil || current.Holder != holder {
+		return err
+	}
+	if err := os.Remove(leasePath); err != nil {
+		return fmt.Errorf("error releasing lease: %w", err)
+	}
+	return nil
+}
+
+func readLease(path string) (Lease, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Lease{}, nil
+	} else if err != nil {
+		return Lease{}, fmt.Errorf("error reading lease file %s: %w", path, err)
+	}
+
+	var lease Lease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return Lease{}, fmt.Errorf("error unmarshalling lease file %s: %w", path, err)
+	}
+	return lease, nil
+}
+
+func writeLease(path string, lease Lease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return fmt.Errorf("error marshalling lease: %w", err)
+	}
+
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0o644); err != nil {
+		return fmt.Errorf("error writing lease file %s: %w", path, err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("error writing lease file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireLeaseIsHeldByOneHolderUntilItExpires(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	if _, acquired, err := AcquireLease(path, "replica-1", time.Minute, now); err != nil || !acquired {
+		t.Fatalf("Expected the lease to be acquired, got %v: %v", acquired, err)
+	}
+	lease, acquired, err := AcquireLease(path, "replica-2", time.Minute, now.Add(30*time.Second))
+	if err != nil || acquired {
+		t.Fatalf("Expected the lease to be held by another replica, got %v: %v", acquired, err)
+	}
+	if lease.Holder != "replica-1" {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "replica-1", lease.Holder)
+	}
+
+	if _, acquired, err := AcquireLease(path, "replica-1", time.Minute, now.Add(50*time.Second)); err != nil || !acquired {
+		t.Fatalf("Expected the lease to be renewed, got %v: %v", acquired, err)
+	}
+	if _, acquired, _ := AcquireLease(path, "replica-2", time.Minute, now.Add(90*time.Second)); acquired {
+		t.Errorf("Expected the renewed lease to still be held")
+	}
+	if _, acquired, err := AcquireLease(path, "replica-2", time.Minute, now.Add(2*time.Minute)); err != nil || !acquired {
+		t.Errorf("Expected the expired lease to be taken over, got %v: %v", acquired, err)
+	}
+}
+
+func TestReleaseLeaseOnlyReleasesTheHoldersLease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	if _, _, err := AcquireLease(path, "replica-1", time.Minute, now); err != nil {
+		t.Fatalf("Unexpected error acquiring lease: %v", err)
+	}
+	if err := ReleaseLease(path, "replica-2"); err != nil {
+		t.Fatalf("Unexpected error releasing lease: %v", err)
+	}
+	if _, err := os.Stat(path + LeaseSuffix); err != nil {
+		t.Errorf("Expected the lease of another holder to be kept: %v", err)
+	}
+
+	if err := ReleaseLease(path, "replica-1"); err != nil {
+		t.Fatalf("Unexpected error releasing lease: %v", err)
+	}
+	if _, acquired, _ := AcquireLease(path, "replica-2", time.Minute, now); !acquired {
+		t.Errorf("Expected the released lease to be acquired")
+	}
+}
@@ -0,0 +1,48 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// LockSuffix is appended to the path of a history file for the path of its lock file.
+const LockSuffix = ".lock"
+
+// ErrLocked is returned by Lock when the history file is already locked.
+var ErrLocked = errors.New("history is locked")
+
+// Lock takes the lock on the history file at path, so that runs changing the history cannot overwrite each other's
+// changes. The lock is a file next to the history holding the process ID of its owner, which is removed by the
+// returned unlock function. An error wrapping ErrLocked is returned if the history is already locked, including by a
+// run which crashed, whose lock file has to be removed by hand.
+func Lock(path string) (func() error, error) {
+	lockPath := path + LockSuffix
+	file, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		owner := ""
+		if pid, err := os.ReadFile(lockPath); err == nil && len(pid) > 0 {
+			owner = " of process " + string(pid)
+		}
+		return nil, fmt.Errorf("%w by %s%s, remove it if no other run is using the history", ErrLocked, lockPath, owner)
+	} else if err != nil {
+		return nil, fmt.Errorf("error locking history: %w", err)
+	}
+
+	_, err = file.WriteString(strconv.Itoa(os.Getpid()))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(lockPath)
+		return nil, fmt.Errorf("error locking history: %w", err)
+	}
+
+	return func() error {
+		if err := os.Remove(lockPath); err != nil {
+			return fmt.Errorf("error unlocking history: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,34 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockReturnsErrLockedUntilUnlocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Unexpected error locking: %v", err)
+	}
+
+	if _, err := Lock(path); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected error %v, got: %v", ErrLocked, err)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("Unexpected error unlocking: %v", err)
+	}
+	if _, err := os.Stat(path + LockSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the lock file to be removed, got: %v", err)
+	}
+
+	unlock, err = Lock(path)
+	if err != nil {
+		t.Fatalf("Unexpected error locking again: %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,84 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion is the version of the history format written and understood by this version of yapper.
+// Version 1 wraps the meetings in an object with the version. Files written before versioning was added,
+// containing only the meetings, are migrated automatically when read. Version 2 adds namespaces, which older versions
+// of yapper would drop when rewriting the file.
+const SchemaVersion = 2
+
+// historyFile is the versioned format of a history file.
+type historyFile struct {
+	Version  int                     `json:"version"`
+	Meetings map[ID]map[ID]time.Time `json:"meetings"`
+	// Initiators are the number of meetings each person was responsible for scheduling.
+	Initiators map[ID]int `json:"initiators,omitempty"`
+	// Topics are the conversation topics each pair discussed, keyed by the IDs of the pair in sorted order.
+	Topics map[ID]map[ID][]string `json:"topics,omitempty"`
+	// Counts are the number of times each pair met, keyed by the IDs of the pair in sorted order, for the pairs who
+	// met more than once. Pairs without a count met once.
+	Counts map[ID]map[ID]int `json:"counts,omitempty"`
+	// State is what the history keeps about the runs of yapper, such as the last round generated.
+	State *State `json:"state,omitempty"`
+	// Namespaces are the histories of separate programs sharing the file.
+	Namespaces map[string]namespaceFile `json:"namespaces,omitempty"`
+}
+
+// namespaceFile is the history of a namespace within a history file.
+type namespaceFile struct {
+	Meetings   map[ID]map[ID]time.Time `json:"meetings"`
+	Initiators map[ID]int              `json:"initiators,omitempty"`
+	Topics     map[ID]map[ID][]string  `json:"topics,omitempty"`
+	Counts     map[ID]map[ID]int       `json:"counts,omitempty"`
+	State      *State                  `json:"state,omitempty"`
+}
+
+// decodeHistoryData decodes either the versioned or the original unversioned format of history data.
+func decodeHistoryData(data json.RawMessage) (historyFile, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return historyFile{}, err
+	}
+
+	rawVersion, versioned := raw["version"]
+	if versioned {
+		var version int
+		// An unversioned history of someone with the ID "version" would have an object rather than a number.
+		if err := json.Unmarshal(rawVersion, &version); err != nil {
+			versioned = false
+		} else if version > SchemaVersion {
+			return historyFile{}, fmt.Errorf(
+				"history version %d is newer than the supported version %d, a newer version of yapper is required",
+				version,
+				SchemaVersion,
+			)
+		} else if version < 1 {
+			return historyFile{}, fmt.Errorf("invalid history version: %d", version)
+		}
+	}
+
+	if !versioned {
+		meetings, err := migrateUnversioned(data)
+		return historyFile{Version: SchemaVersion, Meetings: meetings}, err
+	}
+
+	var versionedFile historyFile
+	if err := json.Unmarshal(data, &versionedFile); err != nil {
+		return historyFile{}, err
+	}
+	return versionedFile, nil
+}
+
+// migrateUnversioned decodes the original format, which only contained the meetings.
+func migrateUnversioned(data json.RawMessage) (map[ID]map[ID]time.Time, error) {
+	var meetings map[ID]map[ID]time.Time
+	if err := json.Unmarshal(data, &meetings); err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
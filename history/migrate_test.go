@@ -0,0 +1,41 @@
+package history
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewHistoryFromFileMigratesUnversionedHistory(t *testing.T) {
+	unversionedDataFile := "./testdata/unversioned_history.json"
+
+	file, err := os.Open(unversionedDataFile)
+	if err != nil {
+		t.Fatalf("error opening file %s: %v", unversionedDataFile, err)
+	}
+	defer file.Close()
+
+	hist, err := NewHistoryFromFile(file)
+	if err != nil {
+		t.Fatalf("unexpected error from NewHistoryFromFile: %v", err)
+	}
+
+	assertHistoriesEqual(t, getExpectedHistory(), hist)
+}
+
+func TestNewHistoryFromFileReturnsErrorForFutureVersion(t *testing.T) {
+	if _, err := NewHistoryFromFile(strings.NewReader(`{"version": 99, "meetings": {}}`)); err == nil {
+		t.Errorf("Expected error due to history version newer than %d", SchemaVersion)
+	}
+}
+
+func TestNewHistoryFromFileAllowsUnversionedPersonNamedVersion(t *testing.T) {
+	hist, err := NewHistoryFromFile(strings.NewReader(`{"version": {"mario": "2025-06-05T00:00:00Z"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error from NewHistoryFromFile: %v", err)
+	}
+
+	if _, exists := hist.GetPersonToLastMeetingMap("version")["mario"]; !exists {
+		t.Errorf("Expected a meeting between version and mario")
+	}
+}
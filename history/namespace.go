@@ -0,0 +1,75 @@
+package history
+
+import (
+	"maps"
+	"slices"
+)
+
+// Namespace returns the history of the program with the name, kept apart from the meetings of the history itself and
+// of every other namespace, so programs sharing a history file do not affect each other's pairings. Changes to the
+// namespace are saved along with the history it belongs to. The empty name returns the history itself, and the
+// namespaces of a namespace are its siblings rather than nested within it.
+func (h *History) Namespace(name string) *History {
+	if name == "" || name == h.namespace {
+		return h
+	}
+
+	if h.namespaces == nil {
+		h.namespaces = make(map[string]*History)
+	}
+	namespace, exists := h.namespaces[name]
+	if !exists {
+		namespace = &History{changes: h.changes, namespace: name, namespaces: h.namespaces}
+		h.namespaces[name] = namespace
+	}
+	return namespace
+}
+
+// Namespaces returns the names of the namespaces of the history in sorted order.
+func (h *History) Namespaces() []string {
+	return slices.Sorted(maps.Keys(h.namespaces))
+}
+
+// Including returns a history combining the meetings of the history with those of the named namespaces, keeping the
+// most recent meeting of each pair and adding up the times they met, for when a program should take the meetings of others into account.
+// The combined history is a copy, so changes to it are not saved.
+func (h *History) Including(names ...string) History {
+	combined := History{state: h.state}
+	timesMet := map[[2]ID]int{}
+	for _, source := range append([]*History{h}, h.namespacesOf(names)...) {
+		for pair, meetingTime := range source.All() {
+			if last, met := combined.data[pair[0]][pair[1]]; !met || meetingTime.After(last) {
+				combined.AddMeeting(pair[0], pair[1], meetingTime)
+			}
+			timesMet[pair] += source.TimesMet(pair[0], pair[1])
+		}
+		for person, count := range source.initiators {
+			if combined.initiators == nil {
+				combined.initiators = make(map[ID]int)
+			}
+			combined.initiators[person] += count
+		}
+		for person1, pairTopics := range source.topics {
+			for person2, topics := range pairTopics {
+				for _, topic := range topics {
+					combined.AddTopic(person1, person2, topic)
+				}
+			}
+		}
+	}
+	for pair, count := range timesMet {
+		combined.setTimesMet(pair[0], pair[1], count)
+	}
+	return combined
+}
+
+// namespacesOf returns the existing namespaces with the names, leaving out the history itself.
+func (h *History) namespacesOf(names []string) []*History {
+	var namespaces []*History
+	for _, name := range names {
+		if namespace, exists := h.namespaces[name]; exists && namespace != h {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,106 @@
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNamespacesAreKeptApart(t *testing.T) {
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.AddMeeting(mario, luigi, day)
+	hist.Namespace("coffee").AddMeeting(mario, peach, day)
+	hist.Namespace("tea").AddMeeting(mario, bowser, day)
+
+	if partners := hist.GetPersonToLastMeetingMap(mario); len(partners) != 1 {
+		t.Errorf("Expected only the meetings outside of the namespaces, got %v", partners)
+	}
+	if partners := hist.Namespace("coffee").GetPersonToLastMeetingMap(mario); len(partners) != 1 || partners[peach].IsZero() {
+		t.Errorf("Expected only the meetings of the namespace, got %v", partners)
+	}
+	if partners := hist.Namespace("coffee").Namespace("tea").GetPersonToLastMeetingMap(mario); len(partners) != 1 || partners[bowser].IsZero() {
+		t.Errorf("Expected the sibling namespace, got %v", partners)
+	}
+
+	if names := hist.Namespaces(); !reflect.DeepEqual([]string{"coffee", "tea"}, names) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", []string{"coffee", "tea"}, names)
+	}
+}
+
+func TestNamespacesAreKeptWhenExported(t *testing.T) {
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.Namespace("coffee").AddMeeting(mario, peach, day)
+	hist.Namespace("coffee").AddInitiator(peach)
+
+	var buffer bytes.Buffer
+	if err := hist.Export(&buffer); err != nil {
+		t.Fatalf("Unexpected error exporting history: %v", err)
+	}
+	if err := ValidateSchema(buffer.Bytes()); err != nil {
+		t.Errorf("Unexpected error validating exported history: %v", err)
+	}
+
+	read, err := NewHistoryFromFile(&buffer)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	coffee := read.Namespace("coffee")
+	if !coffee.GetPersonToLastMeetingMap(mario)[peach].Equal(day) || coffee.TimesInitiated(peach) != 1 {
+		t.Errorf("Expected the namespace to be kept, got %v", coffee.data)
+	}
+	if len(read.GetPersonToLastMeetingMap(mario)) != 0 {
+		t.Errorf("Expected no meetings outside of the namespace, got %v", read.data)
+	}
+}
+
+func TestIncludingCombinesTheLatestMeetings(t *testing.T) {
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.AddMeeting(mario, luigi, day.AddDate(0, 0, 7))
+	hist.Namespace("coffee").AddMeeting(mario, luigi, day)
+	hist.Namespace("coffee").AddMeeting(mario, peach, day)
+	hist.Namespace("tea").AddMeeting(mario, bowser, day)
+
+	combined := hist.Including("coffee")
+	expected := map[ID]time.Time{luigi: day.AddDate(0, 0, 7), peach: day}
+	if partners := combined.GetPersonToLastMeetingMap(mario); !reflect.DeepEqual(expected, partners) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, partners)
+	}
+}
+
+func TestNamespaceChangesAreJournaled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	for i := range 20 {
+		hist.AddMeeting(luigi, ID(fmt.Sprint("toad", i)), day)
+	}
+	if err := hist.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+	if err := EnableJournal(path); err != nil {
+		t.Fatalf("Unexpected error enabling journal: %v", err)
+	}
+
+	journaled, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	journaled.Namespace("coffee").AddMeeting(mario, peach, day)
+	if err := journaled.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if !read.Namespace("coffee").GetPersonToLastMeetingMap(mario)[peach].Equal(day) || len(read.GetPersonToLastMeetingMap(mario)) != 0 {
+		t.Errorf("Expected the journaled meeting in the namespace, got %v", read.Namespace("coffee").data)
+	}
+}
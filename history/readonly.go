@@ -0,0 +1,56 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrReadOnly is wrapped by the errors of saving a history which cannot be written with the permissions or credentials
+// at hand, or which was opened read only.
+var ErrReadOnly = errors.New("history is read only")
+
+// WriteChecker is implemented by stores which can check whether a history could be saved with their credentials
+// without changing the stored history.
+type WriteChecker interface {
+	// CheckWrite returns an error wrapping ErrReadOnly if the credentials cannot save the history.
+	CheckWrite(ctx context.Context) error
+}
+
+// CheckWrite returns an error wrapping ErrReadOnly if the history at path, a file or a store URL, could not be saved
+// with the permissions or credentials at hand, without changing it, so a read only history is detected before any
+// work is done. A file must be writable, and a file must be able to be created next to it, as it is replaced when it
+// is written. Stores which are not WriteCheckers are assumed to be writable.
+func CheckWrite(ctx context.Context, path string) error {
+	if IsStoreURL(path) {
+		store, err := OpenStore(path)
+		if err != nil {
+			return err
+		}
+		if checker, ok := store.(WriteChecker); ok {
+			return checker.CheckWrite(ctx)
+		}
+		return nil
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".check-*")
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("error creating a file next to the history: %w, %w", err, ErrReadOnly)
+	} else if err != nil {
+		return fmt.Errorf("error creating a file next to the history: %w", err)
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	file, err = os.OpenFile(path, os.O_WRONLY, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("error opening history for writing: %w, %w", err, ErrReadOnly)
+	} else if err != nil {
+		return fmt.Errorf("error opening history for writing: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,37 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.json")
+
+	if err := CheckWrite(context.Background(), path); err != nil {
+		t.Errorf("Expected a missing history to be writable, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckWrite(context.Background(), path); err != nil {
+		t.Errorf("Expected the history to be writable, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected:\n%v\nGot:\n%v", 1, len(entries))
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permissions do not apply to root")
+	}
+	if err := os.Chmod(path, 0o444); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckWrite(context.Background(), path); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", ErrReadOnly, err)
+	}
+}
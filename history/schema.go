@@ -0,0 +1,34 @@
+package history
+
+import (
+	"github.com/AleksaSvitlica/yapper/schema"
+)
+
+// Schema returns the JSON Schema of the current history file format.
+func Schema() *schema.Schema {
+	return schema.Generate("yapper history", historyFile{})
+}
+
+// ValidateSchema checks the history data against the schema, reporting the line and field of any problems.
+// Only the current format is valid, older formats should be migrated by reading and exporting them first.
+func ValidateSchema(data []byte) error {
+	return schema.Join(schema.Validate(Schema(), data))
+}
+
+// ValidateFileSchema checks the data of the history file at path against the schema like ValidateSchema, decompressing
+// it first like ReadFile if the path ends in GzipSuffix.
+func ValidateFileSchema(path string, data []byte) error {
+	data, err := decompress(path, data)
+	if err != nil {
+		return err
+	}
+	return ValidateSchema(data)
+}
+
+func (historyFile) SchemaRequired() []string {
+	return []string{"version", "meetings"}
+}
+
+func (namespaceFile) SchemaRequired() []string {
+	return []string{"meetings"}
+}
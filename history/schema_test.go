@@ -0,0 +1,48 @@
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+)
+
+func TestValidateSchemaAcceptsExportedHistory(t *testing.T) {
+	data, err := os.ReadFile("./testdata/expected_history.json")
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+
+	if err := ValidateSchema(data); err != nil {
+		t.Errorf("unexpected error from ValidateSchema: %v", err)
+	}
+}
+
+func TestValidateSchemaReportsInvalidMeetingTime(t *testing.T) {
+	data := []byte(`{"version": 1, "meetings": {"mario": {"luigi": "last tuesday"}}}`)
+	if err := ValidateSchema(data); err == nil {
+		t.Errorf("Expected error due to invalid meeting time")
+	}
+}
+
+func TestValidateFileSchemaDecompressesGzipHistory(t *testing.T) {
+	data, err := os.ReadFile("./testdata/expected_history.json")
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("error compressing history: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("error compressing history: %v", err)
+	}
+
+	if err := ValidateFileSchema("history.json"+GzipSuffix, compressed.Bytes()); err != nil {
+		t.Errorf("unexpected error from ValidateFileSchema: %v", err)
+	}
+	if err := ValidateFileSchema("history.json", compressed.Bytes()); err == nil {
+		t.Errorf("Expected error due to compressed data without the gzip suffix")
+	}
+}
@@ -0,0 +1,94 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	snapshotPrefix     = "history-"
+	snapshotSuffix     = ".json"
+	snapshotTimeLayout = "20060102T150405.000000000Z"
+)
+
+// Snapshot is a timestamped copy of a history.
+type Snapshot struct {
+	Name string
+	Time time.Time
+	Path string
+}
+
+// Snapshot writes a copy of the history to the directory, named after the given time, and returns it.
+func (h *History) Snapshot(dir string, now time.Time) (Snapshot, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Snapshot{}, fmt.Errorf("error creating snapshot directory %s: %w", dir, err)
+	}
+
+	name := snapshotPrefix + now.UTC().Format(snapshotTimeLayout)
+	path := filepath.Join(dir, name+snapshotSuffix)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("error creating snapshot %s: %w", path, err)
+	}
+
+	if err := h.Export(file); err != nil {
+		file.Close()
+		return Snapshot{}, fmt.Errorf("error writing snapshot %s: %w", path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{Name: name, Time: now.UTC(), Path: path}, nil
+}
+
+// ListSnapshots returns the snapshots in the directory, oldest first.
+// A directory which does not exist has no snapshots.
+func ListSnapshots(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading snapshot directory %s: %w", dir, err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name, found := strings.CutSuffix(entry.Name(), snapshotSuffix)
+		if entry.IsDir() || !found || !strings.HasPrefix(name, snapshotPrefix) {
+			continue
+		}
+
+		snapshotTime, err := time.Parse(snapshotTimeLayout, strings.TrimPrefix(name, snapshotPrefix))
+		if err != nil {
+			continue
+		}
+
+		snapshots = append(snapshots, Snapshot{Name: name, Time: snapshotTime, Path: filepath.Join(dir, entry.Name())})
+	}
+
+	slices.SortFunc(snapshots, func(a, b Snapshot) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return snapshots, nil
+}
+
+// LoadSnapshot returns the history stored in the snapshot.
+func LoadSnapshot(snapshot Snapshot) (History, error) {
+	file, err := os.Open(snapshot.Path)
+	if err != nil {
+		return History{}, fmt.Errorf("error opening snapshot %s: %w", snapshot.Path, err)
+	}
+	defer file.Close()
+
+	return NewHistoryFromFile(file)
+}
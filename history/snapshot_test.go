@@ -0,0 +1,49 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotCanBeListedAndLoaded(t *testing.T) {
+	dir := t.TempDir()
+	hist := getExpectedHistory()
+	older := time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, time.August, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := (&History{}).Snapshot(dir, older); err != nil {
+		t.Fatalf("unexpected error from Snapshot: %v", err)
+	}
+
+	snapshot, err := hist.Snapshot(dir, newer)
+	if err != nil {
+		t.Fatalf("unexpected error from Snapshot: %v", err)
+	}
+
+	snapshots, err := ListSnapshots(dir)
+	if err != nil {
+		t.Fatalf("unexpected error from ListSnapshots: %v", err)
+	}
+
+	if len(snapshots) != 2 || !snapshots[0].Time.Equal(older) || snapshots[1] != snapshot {
+		t.Fatalf("Expected the two snapshots oldest first, got: %v", snapshots)
+	}
+
+	loaded, err := LoadSnapshot(snapshots[1])
+	if err != nil {
+		t.Fatalf("unexpected error from LoadSnapshot: %v", err)
+	}
+
+	assertHistoriesEqual(t, hist, loaded)
+}
+
+func TestListSnapshotsReturnsNothingForMissingDirectory(t *testing.T) {
+	snapshots, err := ListSnapshots("./testdata/no-snapshots")
+	if err != nil {
+		t.Fatalf("unexpected error from ListSnapshots: %v", err)
+	}
+
+	if len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots, got: %v", snapshots)
+	}
+}
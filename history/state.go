@@ -0,0 +1,181 @@
+package history
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// State is what the history keeps about the runs of yapper besides the meetings, so a program is managed with one
+// file rather than a file for each. Each namespace of the history has a state of its own.
+type State struct {
+	// LastRun describes the last run which changed the history.
+	LastRun *Run `json:"lastRun,omitempty"`
+	// LastRound is the last round of pairings generated, so a run repeated within the round returns the same pairings.
+	LastRound *Round `json:"lastRound,omitempty"`
+	// Pending is the round proposed by the server whose pairings have not been confirmed yet.
+	Pending *Pending `json:"pending,omitempty"`
+	// Unpaired are the number of rounds each person was left unpaired in while able to meet.
+	Unpaired map[ID]int `json:"unpaired,omitempty"`
+}
+
+// Run describes a run of yapper.
+type Run struct {
+	Time time.Time `json:"time"`
+	// Command is the command run, e.g. "generate".
+	Command string `json:"command"`
+	// Version is the version of yapper run, if it is known.
+	Version string `json:"version,omitempty"`
+}
+
+// Round is a round of pairings generated from the history.
+type Round struct {
+	Date  time.Time   `json:"date"`
+	Pairs []RoundPair `json:"pairs"`
+	// Unpaired are the people who could have met in the round but were left without a partner.
+	Unpaired []ID `json:"unpaired,omitempty"`
+}
+
+// RoundPair is a pair of a round, with when they previously met, who was designated to schedule their meeting and the
+// topic they were given.
+type RoundPair struct {
+	People    [2]ID      `json:"people"`
+	LastMet   *time.Time `json:"lastMet,omitempty"`
+	Initiator ID         `json:"initiator,omitempty"`
+	Topic     string     `json:"topic,omitempty"`
+	// Status is the progress of the meeting of the pair reported before the round was confirmed, e.g. "scheduled".
+	Status string `json:"status,omitempty"`
+}
+
+// Pending is a proposed round along with the changes made to it before it is confirmed.
+type Pending struct {
+	Round Round `json:"round"`
+	// Pins are the pairs which must stay paired when the round is generated again.
+	Pins [][2]ID `json:"pins,omitempty"`
+	// Rerolled are the pairs to avoid when the round is generated again.
+	Rerolled [][2]ID `json:"rerolled,omitempty"`
+}
+
+func (Run) SchemaRequired() []string {
+	return []string{"time", "command"}
+}
+
+func (Round) SchemaRequired() []string {
+	return []string{"date", "pairs"}
+}
+
+func (RoundPair) SchemaRequired() []string {
+	return []string{"people"}
+}
+
+func (Pending) SchemaRequired() []string {
+	return []string{"round"}
+}
+
+// State returns a copy of the state of the history.
+func (h *History) State() State {
+	if h.state == nil {
+		return State{}
+	}
+	return h.state.clone()
+}
+
+// LastRound returns the last round generated, and false if none has been recorded.
+func (h *History) LastRound() (Round, bool) {
+	state := h.State()
+	if state.LastRound == nil {
+		return Round{}, false
+	}
+	return *state.LastRound, true
+}
+
+// SetLastRound records the round as the last one generated, replacing the one before it. The people left unpaired in
+// the round are counted in Unpaired unless the round replaces an earlier version of itself.
+func (h *History) SetLastRound(round Round) {
+	h.changeState(func(state *State) {
+		if state.LastRound == nil || !state.LastRound.Date.Equal(round.Date) {
+			for _, id := range round.Unpaired {
+				if state.Unpaired == nil {
+					state.Unpaired = make(map[ID]int)
+				}
+				state.Unpaired[id]++
+			}
+		}
+		state.LastRound = &round
+	})
+}
+
+// TimesUnpaired returns the number of rounds the person was left unpaired in while able to meet.
+func (h *History) TimesUnpaired(id ID) int {
+	if h.state == nil {
+		return 0
+	}
+	return h.state.Unpaired[id]
+}
+
+// SetLastRun records the run as the last one which changed the history.
+func (h *History) SetLastRun(run Run) {
+	h.changeState(func(state *State) {
+		state.LastRun = &run
+	})
+}
+
+// Pending returns the round proposed but not yet confirmed, and false if there is none.
+func (h *History) Pending() (Pending, bool) {
+	state := h.State()
+	if state.Pending == nil {
+		return Pending{}, false
+	}
+	return *state.Pending, true
+}
+
+// SetPending records the round proposed but not yet confirmed, replacing the one before it.
+func (h *History) SetPending(pending Pending) {
+	h.changeState(func(state *State) {
+		state.Pending = &pending
+	})
+}
+
+// ClearPending removes the proposed round, such as once it has been confirmed.
+func (h *History) ClearPending() {
+	if h.state == nil || h.state.Pending == nil {
+		return
+	}
+	h.changeState(func(state *State) {
+		state.Pending = nil
+	})
+}
+
+// changeState replaces the state with a changed copy, so copies of the history taken before keep the state they had,
+// and records the new state in the journal.
+func (h *History) changeState(change func(*State)) {
+	state := h.State()
+	change(&state)
+	state = state.clone()
+	h.state = &state
+	h.record(journalEntry{State: &state})
+}
+
+// clone returns a deep copy of the state.
+func (s State) clone() State {
+	clone := State{Unpaired: maps.Clone(s.Unpaired)}
+	if s.LastRun != nil {
+		run := *s.LastRun
+		clone.LastRun = &run
+	}
+	if s.LastRound != nil {
+		round := s.LastRound.clone()
+		clone.LastRound = &round
+	}
+	if s.Pending != nil {
+		pending := Pending{Round: s.Pending.Round.clone(), Pins: slices.Clone(s.Pending.Pins), Rerolled: slices.Clone(s.Pending.Rerolled)}
+		clone.Pending = &pending
+	}
+	return clone
+}
+
+func (r Round) clone() Round {
+	r.Pairs = slices.Clone(r.Pairs)
+	r.Unpaired = slices.Clone(r.Unpaired)
+	return r
+}
@@ -0,0 +1,94 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLastRoundIsWrittenToAndReadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	lastMet := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	round := Round{Date: lastMet.AddDate(0, 0, 7), Pairs: []RoundPair{{People: [2]ID{mario, luigi}, LastMet: &lastMet, Initiator: luigi, Topic: "Karts"}}}
+
+	hist := History{}
+	hist.Namespace("plumbers").SetLastRound(round)
+	if err := hist.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error writing history: %v", err)
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if _, found := read.LastRound(); found {
+		t.Errorf("Expected no last round outside the namespace")
+	}
+	got, found := read.Namespace("plumbers").LastRound()
+	if !found || !reflect.DeepEqual(round, got) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", round, got)
+	}
+}
+
+func TestLastRoundIsJournaled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := EnableJournal(path); err != nil {
+		t.Fatalf("Unexpected error enabling journal: %v", err)
+	}
+	first := Round{Date: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), Pairs: []RoundPair{{People: [2]ID{mario, luigi}}}}
+	second := Round{Date: first.Date.AddDate(0, 0, 7), Pairs: []RoundPair{{People: [2]ID{mario, peach}, Initiator: peach}}}
+
+	for _, round := range []Round{first, second} {
+		hist, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error reading history: %v", err)
+		}
+		hist.SetLastRound(round)
+		if err := hist.WriteFile(path); err != nil {
+			t.Fatalf("Unexpected error writing history: %v", err)
+		}
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if got, found := read.LastRound(); !found || !reflect.DeepEqual(second, got) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", second, got)
+	}
+}
+
+func TestSetLastRoundCountsUnpairedOncePerRound(t *testing.T) {
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.SetLastRound(Round{Date: date, Pairs: []RoundPair{{People: [2]ID{mario, luigi}}}, Unpaired: []ID{peach}})
+	hist.SetLastRound(Round{Date: date, Pairs: []RoundPair{{People: [2]ID{mario, luigi}}}, Unpaired: []ID{peach}})
+	hist.SetLastRound(Round{Date: date.AddDate(0, 0, 7), Pairs: []RoundPair{{People: [2]ID{mario, peach}}}, Unpaired: []ID{luigi}})
+
+	if times := hist.TimesUnpaired(peach); times != 1 {
+		t.Errorf("Expected Peach to have been unpaired once, got %d", times)
+	}
+	if times := hist.TimesUnpaired(luigi); times != 1 {
+		t.Errorf("Expected Luigi to have been unpaired once, got %d", times)
+	}
+}
+
+func TestStateIsNotSharedWithClones(t *testing.T) {
+	hist := History{}
+	hist.SetPending(Pending{Round: Round{Date: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)}, Pins: [][2]ID{{mario, luigi}}})
+	clone := hist.Clone()
+
+	hist.ClearPending()
+	hist.SetLastRun(Run{Time: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), Command: "generate"})
+
+	if _, found := hist.Pending(); found {
+		t.Errorf("Expected the pending round to be cleared")
+	}
+	if _, found := clone.Pending(); !found {
+		t.Errorf("Expected the clone to keep the pending round")
+	}
+	if state := clone.State(); state.LastRun != nil {
+		t.Errorf("Expected the clone to have no last run, got %+v", state.LastRun)
+	}
+}
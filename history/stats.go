@@ -0,0 +1,57 @@
+package history
+
+import (
+	"slices"
+	"time"
+)
+
+// meetingTimes returns the times of the person's last meeting with each partner, oldest first. Only the last meeting
+// of each pair is kept, so the statistics do not count earlier meetings of pairs who met again.
+func meetingTimes(hist History, person ID) []time.Time {
+	var times []time.Time
+	for _, meetingTime := range hist.PartnersOf(person) {
+		times = append(times, meetingTime)
+	}
+	slices.SortFunc(times, time.Time.Compare)
+	return times
+}
+
+// LongestGap returns the longest time the person went without meeting anyone, between their meetings or from their
+// last meeting until now. Zero is returned if they have never met anyone.
+func LongestGap(hist History, person ID, now time.Time) time.Duration {
+	times := meetingTimes(hist, person)
+	if len(times) == 0 {
+		return 0
+	}
+
+	longest := now.Sub(times[len(times)-1])
+	for i := 1; i < len(times); i++ {
+		longest = max(longest, times[i].Sub(times[i-1]))
+	}
+	return max(longest, 0)
+}
+
+// AverageDaysBetweenMeetings returns the average number of days from one of the person's meetings to the next,
+// counting the time from their last meeting until now. Zero is returned if they have never met anyone.
+func AverageDaysBetweenMeetings(hist History, person ID, now time.Time) float64 {
+	times := meetingTimes(hist, person)
+	if len(times) == 0 {
+		return 0
+	}
+
+	return now.Sub(times[0]).Hours() / 24 / float64(len(times))
+}
+
+// UnmetPairs returns the pairs of the people who have never met, in the order of the people given.
+func UnmetPairs(hist History, people []ID) [][2]ID {
+	unmet := [][2]ID{}
+	for i, person := range people {
+		met := hist.GetPersonToLastMeetingMap(person)
+		for _, other := range people[i+1:] {
+			if _, exists := met[other]; !exists && other != person {
+				unmet = append(unmet, [2]ID{person, other})
+			}
+		}
+	}
+	return unmet
+}
@@ -0,0 +1,56 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLongestGapIncludesTimeSinceLastMeeting(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.AddMeeting(mario, luigi, start)
+	hist.AddMeeting(mario, peach, start.AddDate(0, 0, 21))
+
+	tests := []struct {
+		now      time.Time
+		expected time.Duration
+	}{
+		{start.AddDate(0, 0, 28), 21 * 24 * time.Hour},
+		{start.AddDate(0, 0, 56), 35 * 24 * time.Hour},
+	}
+	for _, test := range tests {
+		if gap := LongestGap(hist, mario, test.now); gap != test.expected {
+			t.Errorf("Expected %v, got %v", test.expected, gap)
+		}
+	}
+
+	if gap := LongestGap(hist, bowser, start); gap != 0 {
+		t.Errorf("Expected no gap for someone who never met anyone, got %v", gap)
+	}
+}
+
+func TestAverageDaysBetweenMeetings(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := History{}
+	hist.AddMeeting(mario, luigi, start)
+	hist.AddMeeting(mario, peach, start.AddDate(0, 0, 14))
+
+	if average := AverageDaysBetweenMeetings(hist, mario, start.AddDate(0, 0, 21)); average != 10.5 {
+		t.Errorf("Expected 10.5, got %v", average)
+	}
+
+	if average := AverageDaysBetweenMeetings(hist, bowser, start); average != 0 {
+		t.Errorf("Expected 0 for someone who never met anyone, got %v", average)
+	}
+}
+
+func TestUnmetPairs(t *testing.T) {
+	hist := History{}
+	hist.AddMeeting(mario, luigi, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+
+	expected := [][2]ID{{mario, peach}, {luigi, peach}}
+	if unmet := UnmetPairs(hist, []ID{mario, luigi, peach}); !reflect.DeepEqual(expected, unmet) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, unmet)
+	}
+}
@@ -0,0 +1,118 @@
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrConflict is wrapped by the errors of saving a history to a store which was changed since the history was loaded
+// from it, so the changes of another run are not overwritten. The history should be loaded again and the changes
+// made again.
+var ErrConflict = errors.New("history was changed since it was loaded")
+
+// Store keeps a history in a database rather than a file, such as a key-value store for a serverless deployment.
+// Stores use optimistic concurrency rather than locks, only saving a history if the stored history has not changed
+// since it was loaded.
+type Store interface {
+	// Load returns the stored history, remembering its revision so Save can check it was not changed since. An
+	// error wrapping os.ErrNotExist is returned if no history is stored yet.
+	Load(ctx context.Context) (History, error)
+	// Save stores the history if the stored history has the revision the history was loaded with, or if nothing is
+	// stored and the history was not loaded, returning an error wrapping ErrConflict otherwise. The revision of the
+	// history is updated, so it can be saved again.
+	Save(ctx context.Context, hist *History) error
+}
+
+// IsStoreURL returns whether the history path is the URL of a store rather than the path of a file, see OpenStore.
+func IsStoreURL(path string) bool {
+	scheme, _, found := strings.Cut(path, "://")
+	return found && (scheme == schemeDynamoDB || scheme == schemeEtcd || scheme == schemeEtcdTLS)
+}
+
+// OpenStore returns the store at the URL, which is one of
+//
+//	dynamodb://<table>/<key>?region=<region>&endpoint=<url>
+//	etcd://[<user>:<password>@]<host>:<port>/<key>
+//
+// or etcds:// for etcd over TLS. The AWS credentials of DynamoDB are read from AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, and its region from AWS_REGION if the URL has none.
+func OpenStore(rawURL string) (Store, error) {
+	address, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing history store URL: %w", err)
+	}
+	key := strings.TrimPrefix(address.Path, "/")
+	if key == "" {
+		return nil, fmt.Errorf("history store URL has no key: %s", rawURL)
+	}
+
+	switch address.Scheme {
+	case schemeDynamoDB:
+		store := DynamoDB{
+			Table:           address.Host,
+			Key:             key,
+			Region:          address.Query().Get("region"),
+			Endpoint:        address.Query().Get("endpoint"),
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if store.Region == "" {
+			store.Region = os.Getenv("AWS_REGION")
+		}
+		return store, nil
+	case schemeEtcd, schemeEtcdTLS:
+		endpoint := url.URL{Scheme: "http", Host: address.Host}
+		if address.Scheme == schemeEtcdTLS {
+			endpoint.Scheme = "https"
+		}
+		store := Etcd{Endpoint: endpoint.String(), Key: key}
+		if address.User != nil {
+			store.Username = address.User.Username()
+			store.Password, _ = address.User.Password()
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unexpected history store: %s", address.Scheme)
+	}
+}
+
+// encodeStored returns the history as compressed data for a store, keeping it within the limits stores have on the
+// size of each value.
+func encodeStored(hist *History) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if err := hist.Export(writer); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error compressing history: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// decodeStored returns the history stored as data by encodeStored, at the revision.
+func decodeStored(data []byte, revision string) (History, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return History{}, corruptError{fmt.Errorf("error decompressing history: %w", err)}
+	}
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return History{}, corruptError{fmt.Errorf("error decompressing history: %w", err)}
+	}
+
+	hist, err := NewHistoryFromFile(bytes.NewReader(decompressed))
+	if err != nil {
+		return History{}, err
+	}
+	hist.revision = revision
+	return hist, nil
+}
@@ -0,0 +1,64 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+type HolidayPolicy string
+
+const (
+	// HolidayPolicySkip does not pair people whose round is mostly public holidays in their location.
+	HolidayPolicySkip HolidayPolicy = "skip"
+	// HolidayPolicyDeprioritize pairs people whose round is mostly public holidays last, with the holiday soft constraint as the penalty.
+	HolidayPolicyDeprioritize HolidayPolicy = "deprioritize"
+)
+
+func (p HolidayPolicy) validate() error {
+	switch p {
+	case "", HolidayPolicySkip, HolidayPolicyDeprioritize:
+		return nil
+	default:
+		return fmt.Errorf("unexpected holiday policy: %s", p)
+	}
+}
+
+// holidayPolicy returns the configured holiday policy, defaulting to skip.
+func (c Config) holidayPolicy() HolidayPolicy {
+	if c.HolidayPolicy == "" {
+		return HolidayPolicySkip
+	}
+	return c.HolidayPolicy
+}
+
+// onHoliday reports whether most of the days the person works in the round starting on date are public holidays in
+// their location. The days a person works are their preferred days, or otherwise Monday to Friday.
+func (c Config) onHoliday(person Person, date time.Time) bool {
+	holidays := c.Holidays[person.Location]
+	if person.Location == "" || len(holidays) == 0 {
+		return false
+	}
+
+	working, off := 0, 0
+	for i := range c.RoundInterval() {
+		day := date.AddDate(0, 0, i)
+		if !worksOn(person, day.Weekday()) {
+			continue
+		}
+
+		working++
+		if slices.ContainsFunc(holidays, func(holiday Date) bool { return holiday.Equal(NewDate(day).Time) }) {
+			off++
+		}
+	}
+
+	return working > 0 && off*2 > working
+}
+
+func worksOn(person Person, weekday time.Weekday) bool {
+	if len(person.PreferredDays) == 0 {
+		return weekday != time.Saturday && weekday != time.Sunday
+	}
+	return slices.ContainsFunc(person.PreferredDays, func(day Day) bool { return day.Weekday() == weekday })
+}
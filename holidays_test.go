@@ -0,0 +1,78 @@
+package yapper
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// A round starting on Monday 2025-12-22 with holidays in Berlin on the 24th, 25th and 26th.
+var holidayRound = time.Date(2025, time.December, 22, 0, 0, 0, 0, time.UTC)
+
+func holidayConfig(policy HolidayPolicy) Config {
+	return Config{
+		People: []Person{
+			{ID: "Mario", Location: "berlin"},
+			{ID: "Luigi", Location: "berlin", PreferredDays: []Day{DayMonday, DayTuesday}},
+			{ID: "Peach", Location: "london"},
+			{ID: "Toad"},
+		},
+		Holidays: map[string][]Date{
+			"berlin": {date(2025, time.December, 24), date(2025, time.December, 25), date(2025, time.December, 26)},
+			"london": {date(2025, time.December, 25), date(2025, time.December, 26)},
+		},
+		HolidayPolicy: policy,
+	}
+}
+
+func TestOnHolidayWhenMostWorkingDaysAreHolidays(t *testing.T) {
+	config := holidayConfig("")
+
+	for id, expected := range map[ID]bool{"Mario": true, "Luigi": false, "Peach": false, "Toad": false} {
+		person, _ := config.GetPerson(id)
+		if onHoliday := config.onHoliday(person, holidayRound); onHoliday != expected {
+			t.Errorf("Expected %s on holiday to be %v, got %v", id, expected, onHoliday)
+		}
+	}
+}
+
+func TestHolidayPolicySkipMakesPeopleIneligible(t *testing.T) {
+	config := holidayConfig(HolidayPolicySkip)
+
+	ineligible := getIneligiblePeople(config, determineValidPairings(config), holidayRound)
+	if !slices.Equal(ineligible, []ID{"Mario"}) {
+		t.Errorf("Expected only Mario to be ineligible, got %v", ineligible)
+	}
+
+	if ineligible := getIneligiblePeople(config, determineValidPairings(config), holidayRound.AddDate(0, 0, 7)); len(ineligible) != 0 {
+		t.Errorf("Expected everyone to be eligible the week after, got %v", ineligible)
+	}
+}
+
+func TestHolidayPolicyDeprioritizePenalizesPairs(t *testing.T) {
+	config := holidayConfig(HolidayPolicyDeprioritize)
+	config.SoftConstraints.Holiday = 2
+
+	if ineligible := getIneligiblePeople(config, determineValidPairings(config), holidayRound); len(ineligible) != 0 {
+		t.Errorf("Expected no one to be ineligible, got %v", ineligible)
+	}
+
+	mario, _ := config.GetPerson("Mario")
+	peach, _ := config.GetPerson("Peach")
+	toad, _ := config.GetPerson("Toad")
+	if penalty := PairPenalty(config, history.History{}, mario, peach, holidayRound); penalty != 2 {
+		t.Errorf("Expected a penalty of 2 for pairing Mario, got %v", penalty)
+	}
+	if penalty := PairPenalty(config, history.History{}, toad, peach, holidayRound); penalty != 0 {
+		t.Errorf("Expected no penalty for pairing Toad and Peach, got %v", penalty)
+	}
+}
+
+func TestValidateRejectsUnexpectedHolidayPolicy(t *testing.T) {
+	config := holidayConfig("ignore")
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected an error validating an unexpected holiday policy")
+	}
+}
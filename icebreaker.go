@@ -0,0 +1,24 @@
+package yapper
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// Icebreaker suggests one of the configured icebreakers for the pair, or returns an empty string if there are none.
+// The choice is stable for a pair in a round, regardless of the order of the people, but varies between rounds.
+func (c Config) Icebreaker(id1, id2 ID, date time.Time) string {
+	if len(c.Icebreakers) == 0 {
+		return ""
+	}
+
+	key := pairKey(id1, id2)
+	hash := fnv.New32a()
+	hash.Write([]byte(key[0]))
+	hash.Write([]byte{0})
+	hash.Write([]byte(key[1]))
+	hash.Write([]byte{0})
+	hash.Write([]byte(date.Format(time.DateOnly)))
+
+	return c.Icebreakers[hash.Sum32()%uint32(len(c.Icebreakers))]
+}
@@ -0,0 +1,27 @@
+package yapper
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestConfigIcebreakerIsStableForPair(t *testing.T) {
+	config := Config{Icebreakers: []string{"Favourite power-up?", "Best kart?", "Worst castle?"}}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	icebreaker := config.Icebreaker("Mario", "Luigi", date)
+	if !slices.Contains(config.Icebreakers, icebreaker) {
+		t.Fatalf("Expected one of the configured icebreakers, got: %q", icebreaker)
+	}
+
+	if reversed := config.Icebreaker("Luigi", "Mario", date); reversed != icebreaker {
+		t.Errorf("Expected the same icebreaker regardless of order, got %q and %q", icebreaker, reversed)
+	}
+}
+
+func TestConfigIcebreakerIsEmptyWithoutIcebreakers(t *testing.T) {
+	if icebreaker := (Config{}).Icebreaker("Mario", "Luigi", time.Now()); icebreaker != "" {
+		t.Errorf("Expected no icebreaker, got: %q", icebreaker)
+	}
+}
@@ -0,0 +1,186 @@
+package yapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// defaultImprovementIterations is the number of swaps tried for each round when the improvement does not set it.
+const defaultImprovementIterations = 1000
+
+// Improvement searches for swaps of partners which lower the penalty of the soft constraints of each round paired by
+// the greedy strategy, such as pairs who met recently. It is much cheaper than the planned strategy, as each round is
+// improved on its own.
+type Improvement struct {
+	// Iterations is the number of swaps tried for each round, defaulting to 1000.
+	Iterations int `json:"iterations,omitempty"`
+	// MaxDuration is the longest spent improving each round, such as 100ms, after which the best pairings found so far
+	// are used.
+	MaxDuration string `json:"maxDuration,omitempty"`
+	// Temperature is how much of an increase in penalty a swap may make at first and still be kept, in the hope of
+	// finding a lower penalty after it. It decreases to nothing by the last iteration. Only swaps lowering the penalty
+	// are kept without it.
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
+func (i Improvement) validate() error {
+	if i.Iterations < 0 {
+		return fmt.Errorf("improvement iterations must not be negative: %d", i.Iterations)
+	}
+	if i.Temperature < 0 {
+		return fmt.Errorf("improvement temperature must not be negative: %v", i.Temperature)
+	}
+
+	if i.MaxDuration == "" {
+		return nil
+	}
+	if maxDuration, err := time.ParseDuration(i.MaxDuration); err != nil {
+		return fmt.Errorf("error parsing improvement max duration: %w", err)
+	} else if maxDuration <= 0 {
+		return errors.New("improvement max duration must be positive")
+	}
+	return nil
+}
+
+// iterations returns the number of swaps tried for each round, defaulting to defaultImprovementIterations.
+func (i Improvement) iterations() int {
+	if i.Iterations == 0 {
+		return defaultImprovementIterations
+	}
+	return i.Iterations
+}
+
+// improveRound swaps the partners of pairs in the pairings while that lowers their total penalty, returning the
+// pairings with the lowest penalty found. Pinned pairs are not changed and swaps creating pairs which cannot be paired,
+// or going over the squad limits, are not made. The swaps are random, seeded by the tie break of the config and the
+// date, so the same pairings are improved the same way each time unless the max duration of the improvement stops
+// them. The pairings are returned as they are without the improvement of the config, or soft constraints to improve.
+func improveRound(ctx context.Context, conf Config, idToValidPairings map[ID][]ID, hist history.History, pairings Pairings) Pairings {
+	improvement := conf.Settings.Improvement
+	if improvement == nil || !conf.HasSoftConstraints() {
+		return pairings
+	}
+
+	date := pairings.Date()
+	cohorts := conf.onboardingCohorts(date)
+	valid := make(map[[2]ID]bool)
+	for id, validPairings := range idToValidPairings {
+		for _, other := range validPairings {
+			if !onboardingDenied(cohorts, id, other) {
+				valid[pairKey(id, other)] = true
+			}
+		}
+	}
+
+	pinned := map[[2]ID]bool{}
+	for _, pin := range pinnedPairings(conf, idToValidPairings, getIneligiblePeople(conf, idToValidPairings, date), date) {
+		pinned[pairKey(pin[0], pin[1])] = true
+	}
+	pairs := slices.Clone(pairings.data)
+	var movable []int
+	for i, pair := range pairs {
+		if !pinned[pairKey(pair[0], pair[1])] {
+			movable = append(movable, i)
+		}
+	}
+	if len(movable) < 2 {
+		return pairings
+	}
+
+	scorer := newRoundScorer(conf, hist, date)
+	penalties := map[[2]ID]float64{}
+	penalty := func(pair [2]ID) float64 {
+		key := pairKey(pair[0], pair[1])
+		if cached, found := penalties[key]; found {
+			return cached
+		}
+		penalties[key] = scorer.penalty(pair[0], pair[1])
+		return penalties[key]
+	}
+
+	var deadline time.Time
+	if maxDuration, _ := time.ParseDuration(improvement.MaxDuration); maxDuration > 0 {
+		deadline = time.Now().Add(maxDuration)
+	}
+	random := seededRandom(conf, "improve", date.Format(time.DateOnly))
+
+	current := 0.0
+	for _, pair := range pairs {
+		current += penalty(pair)
+	}
+	best, bestPairs := current, slices.Clone(pairs)
+	squadCounts := newSquadCountsOf(conf, pairs)
+
+	iterations := improvement.iterations()
+	for iteration := range iterations {
+		if ctx.Err() != nil || (!deadline.IsZero() && time.Now().After(deadline)) {
+			break
+		}
+
+		i, j := movable[random.IntN(len(movable))], movable[random.IntN(len(movable))]
+		if i == j {
+			continue
+		}
+		first, second := pairs[i], pairs[j]
+		swapped := [2][2]ID{{first[0], second[0]}, {first[1], second[1]}}
+		if random.IntN(2) == 1 {
+			swapped = [2][2]ID{{first[0], second[1]}, {first[1], second[0]}}
+		}
+		if !valid[pairKey(swapped[0][0], swapped[0][1])] || !valid[pairKey(swapped[1][0], swapped[1][1])] {
+			continue
+		}
+		if !swapWithinSquadLimits(squadCounts, [2][2]ID{first, second}, swapped) {
+			continue
+		}
+
+		change := penalty(swapped[0]) + penalty(swapped[1]) - penalty(first) - penalty(second)
+		temperature := improvement.Temperature * (1 - float64(iteration)/float64(iterations))
+		if change >= 0 && (temperature <= 0 || random.Float64() >= math.Exp(-change/temperature)) {
+			continue
+		}
+
+		pairs[i], pairs[j] = swapped[0], swapped[1]
+		squadCounts.remove(first[0], first[1])
+		squadCounts.remove(second[0], second[1])
+		squadCounts.add(swapped[0][0], swapped[0][1])
+		squadCounts.add(swapped[1][0], swapped[1][1])
+		current += change
+		if current < best-1e-9 {
+			best, bestPairs = current, slices.Clone(pairs)
+		}
+	}
+
+	pairings.data = bestPairs
+	pairings.penalty = best
+	return pairings
+}
+
+// swapWithinSquadLimits reports whether replacing the pairs with the swapped pairs keeps the squad counts from going
+// further over their limits.
+func swapWithinSquadLimits(counts *squadCounts, pairs, swapped [2][2]ID) bool {
+	if counts == nil {
+		return true
+	}
+
+	excess := counts.excess()
+	for _, pair := range pairs {
+		counts.remove(pair[0], pair[1])
+	}
+	for _, pair := range swapped {
+		counts.add(pair[0], pair[1])
+	}
+	within := counts.excess() <= excess
+	for _, pair := range swapped {
+		counts.remove(pair[0], pair[1])
+	}
+	for _, pair := range pairs {
+		counts.add(pair[0], pair[1])
+	}
+	return within
+}
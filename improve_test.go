@@ -0,0 +1,130 @@
+package yapper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// recentlyMetRound returns a config and history in which Mario and Luigi, and Peach and Daisy, met last week, with
+// pairings pairing them again.
+func recentlyMetRound(improvement *Improvement) (Config, history.History, Pairings) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People:          []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Daisy"}},
+		Settings:        Settings{Improvement: improvement},
+		SoftConstraints: SoftConstraints{RecentlyMet: 1, RecentlyMetDays: 28},
+	}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -7))
+	hist.AddMeeting("Peach", "Daisy", date.AddDate(0, 0, -7))
+	return config, hist, NewPairings(date, [][2]ID{{"Mario", "Luigi"}, {"Peach", "Daisy"}})
+}
+
+func TestImproveRoundSwapsPartnersWhoMetRecently(t *testing.T) {
+	config, hist, pairings := recentlyMetRound(&Improvement{})
+
+	improved := improveRound(context.Background(), config, determineValidPairings(config), hist, pairings)
+	if improved.Contains("Mario", "Luigi") || improved.Contains("Peach", "Daisy") || improved.Len() != 2 {
+		t.Errorf("Expected the partners to be swapped, got %v", improved.data)
+	}
+	if improved.Penalty() != 0 {
+		t.Errorf("Expected no penalty, got %v", improved.Penalty())
+	}
+}
+
+func TestImproveRoundWithoutImprovement(t *testing.T) {
+	config, hist, pairings := recentlyMetRound(nil)
+
+	improved := improveRound(context.Background(), config, determineValidPairings(config), hist, pairings)
+	if !improved.Contains("Mario", "Luigi") || !improved.Contains("Peach", "Daisy") {
+		t.Errorf("Expected the pairings to be unchanged, got %v", improved.data)
+	}
+}
+
+func TestImproveRoundKeepsPinsAndHardConstraints(t *testing.T) {
+	config, hist, pairings := recentlyMetRound(&Improvement{Temperature: 1})
+	config.People[0].DenyList = []ID{"Peach"}
+	config.Pins = []Pin{{People: [2]ID{"Peach", "Daisy"}}}
+
+	improved := improveRound(context.Background(), config, determineValidPairings(config), hist, pairings)
+	if !improved.Contains("Mario", "Luigi") || !improved.Contains("Peach", "Daisy") {
+		t.Errorf("Expected the pinned pair to be kept, got %v", improved.data)
+	}
+
+	config.Pins = nil
+	improved = improveRound(context.Background(), config, determineValidPairings(config), hist, pairings)
+	if improved.Contains("Mario", "Peach") {
+		t.Errorf("Expected Mario not to be paired with Peach, got %v", improved.data)
+	}
+	if !improved.Contains("Mario", "Daisy") {
+		t.Errorf("Expected the only valid swap, got %v", improved.data)
+	}
+}
+
+func TestGenerateImprovesGreedyRounds(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{
+			{ID: "Mario", DenyList: []ID{"Bowser"}},
+			{ID: "Luigi", DenyList: []ID{"Peach"}},
+			{ID: "Peach"},
+			{ID: "Bowser"},
+		},
+		Settings:        Settings{Improvement: &Improvement{}},
+		SoftConstraints: SoftConstraints{RecentlyMet: 1, RecentlyMetDays: 28},
+	}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Peach", date.AddDate(0, 0, -21))
+	hist.AddMeeting("Bowser", "Peach", date.AddDate(0, 0, -7))
+
+	// Depending on who is paired first the greedy strategy may pair Mario with Luigi, leaving Bowser to meet Peach again.
+	weeklyPairings, err := generateGreedy(context.Background(), config, determineValidPairings(config), hist, []time.Time{date})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pairings := weeklyPairings[0]; !pairings.Contains("Mario", "Peach") || !pairings.Contains("Luigi", "Bowser") {
+		t.Errorf("Expected Bowser not to meet Peach again, got %v", pairings.data)
+	}
+}
+
+func TestGenerateImprovesGreedyRoundsRepeatably(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		Settings:        Settings{Improvement: &Improvement{Temperature: 1}},
+		SoftConstraints: SoftConstraints{RecentlyMet: 1, RecentlyMetDays: 28},
+		TieBreak:        &TieBreak{Seed: 1},
+	}
+	hist := history.History{}
+	for _, id := range []ID{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J"} {
+		config.People = append(config.People, Person{ID: id})
+	}
+	for i := 0; i < len(config.People); i += 2 {
+		hist.AddMeeting(history.ID(config.People[i].ID), history.ID(config.People[i+1].ID), date.AddDate(0, 0, -7))
+	}
+
+	var expected []Pairings
+	for run := range 10 {
+		weeklyPairings, err := generateGreedy(context.Background(), config, determineValidPairings(config), hist, []time.Time{date, date.AddDate(0, 0, 7)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if run == 0 {
+			expected = weeklyPairings
+		} else if !reflect.DeepEqual(expected, weeklyPairings) {
+			t.Fatalf("Expected:\n%v\nGot:\n%v", expected, weeklyPairings)
+		}
+	}
+}
+
+func TestValidateRejectsInvalidImprovement(t *testing.T) {
+	for _, improvement := range []Improvement{{Iterations: -1}, {Temperature: -1}, {MaxDuration: "soon"}, {MaxDuration: "-1s"}} {
+		config := Config{Settings: Settings{Improvement: &improvement}}
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected an error validating %+v", improvement)
+		}
+	}
+}
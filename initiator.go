@@ -0,0 +1,30 @@
+package yapper
+
+import "github.com/AleksaSvitlica/yapper/history"
+
+// Initiator returns which of the pair is responsible for scheduling their meeting, rotating the duty by choosing
+// whoever has scheduled fewer meetings according to the history. Ties go to the first person of the pair.
+func Initiator(hist history.History, id1, id2 ID) ID {
+	if hist.TimesInitiated(history.ID(id2)) < hist.TimesInitiated(history.ID(id1)) {
+		return id2
+	}
+	return id1
+}
+
+// Initiator returns the person responsible for scheduling the meeting of the pair,
+// as designated when the pairings were recorded.
+func (p *Pairings) Initiator(id1, id2 ID) (ID, bool) {
+	initiator, designated := p.initiators[pairKey(id1, id2)]
+	return initiator, designated
+}
+
+// designateInitiator chooses the initiator of the pair from the history and counts it in the history.
+func (p *Pairings) designateInitiator(hist *history.History, id1, id2 ID) {
+	if p.initiators == nil {
+		p.initiators = make(map[[2]ID]ID)
+	}
+
+	initiator := Initiator(*hist, id1, id2)
+	p.initiators[pairKey(id1, id2)] = initiator
+	hist.AddInitiator(history.ID(initiator))
+}
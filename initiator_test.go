@@ -0,0 +1,47 @@
+package yapper
+
+import (
+	"testing"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestInitiatorRotatesBetweenPeople(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+	hist := history.History{}
+
+	rounds, err := GeneratePairings(config, &hist, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	initiated := map[ID]int{}
+	for _, pairings := range rounds {
+		initiator, designated := pairings.Initiator("Mario", "Luigi")
+		if !designated {
+			t.Fatalf("Expected an initiator to be designated in the round starting %v", pairings.Date())
+		}
+		initiated[initiator]++
+	}
+
+	if initiated["Mario"] != 2 || initiated["Luigi"] != 2 {
+		t.Errorf("Expected Mario and Luigi to each initiate twice, got %v", initiated)
+	}
+
+	if hist.TimesInitiated("Mario") != 2 || hist.TimesInitiated("Luigi") != 2 {
+		t.Errorf("Expected the initiators to be counted in the history")
+	}
+}
+
+func TestInitiatorPrefersWhoeverInitiatedLess(t *testing.T) {
+	hist := history.History{}
+	hist.AddInitiator("Mario")
+
+	if initiator := Initiator(hist, "Mario", "Luigi"); initiator != "Luigi" {
+		t.Errorf("Expected Luigi to initiate, got %s", initiator)
+	}
+
+	if initiator := Initiator(history.History{}, "Mario", "Luigi"); initiator != "Mario" {
+		t.Errorf("Expected the first person to initiate on a tie, got %s", initiator)
+	}
+}
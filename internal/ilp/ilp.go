@@ -0,0 +1,288 @@
+// Package ilp solves small 0-1 integer linear programs of the packing kind, which maximise the value of the variables
+// set to 1 subject to constraints with non-negative coefficients and bounds, such as matchings. It searches by branch
+// and bound over the linear relaxations of the program, which are solved with the simplex method.
+package ilp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultMaxNodes is the number of branches searched when the problem does not set it.
+const DefaultMaxNodes = 10000
+
+// epsilon is the tolerance of the comparisons of values computed by the simplex method.
+const epsilon = 1e-9
+
+// ErrNodeLimit is returned when the search could not prove a solution is the best within the node limit.
+var ErrNodeLimit = errors.New("the search exceeded its node limit")
+
+// Problem is a program maximising the dot product of the objective and the variables, each of which is 0 or 1, such
+// that the dot product of each constraint and the variables is at most its bound.
+type Problem struct {
+	// Objective is the value of each variable being 1.
+	Objective []float64
+	// Constraints have a non-negative coefficient for each variable.
+	Constraints [][]float64
+	// Bounds are the non-negative bound of each constraint.
+	Bounds []float64
+	// MaxNodes is the number of branches searched before giving up, defaulting to DefaultMaxNodes.
+	MaxNodes int
+}
+
+func (p Problem) validate() error {
+	if len(p.Constraints) != len(p.Bounds) {
+		return fmt.Errorf("expected a bound for each of the %d constraints, got %d", len(p.Constraints), len(p.Bounds))
+	}
+	for i, value := range p.Objective {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("objective of variable %d is not a number: %v", i, value)
+		}
+	}
+	for r, constraint := range p.Constraints {
+		if len(constraint) != len(p.Objective) {
+			return fmt.Errorf("expected %d coefficients in constraint %d, got %d", len(p.Objective), r, len(constraint))
+		}
+		for _, coefficient := range constraint {
+			if !(coefficient >= 0) || math.IsInf(coefficient, 0) {
+				return fmt.Errorf("coefficients of constraint %d must not be negative: %v", r, coefficient)
+			}
+		}
+		if !(p.Bounds[r] >= 0) || math.IsInf(p.Bounds[r], 0) {
+			return fmt.Errorf("bound of constraint %d must not be negative: %v", r, p.Bounds[r])
+		}
+	}
+	return nil
+}
+
+// Solve returns the best values of the variables and their total value. ErrNodeLimit is returned if the search runs
+// over the node limit, and the error of the context once it is done.
+func Solve(ctx context.Context, p Problem) ([]bool, float64, error) {
+	if err := p.validate(); err != nil {
+		return nil, 0, err
+	}
+
+	s := &solver{ctx: ctx, objective: p.Objective, maxNodes: p.MaxNodes, fixed: make([]int8, len(p.Objective))}
+	if s.maxNodes == 0 {
+		s.maxNodes = DefaultMaxNodes
+	}
+	s.constraints, s.bounds = withUpperBounds(p.Constraints, p.Bounds, len(p.Objective))
+	for i := range s.fixed {
+		s.fixed[i] = free
+	}
+	s.best = make([]bool, len(p.Objective))
+
+	if err := s.branch(); err != nil {
+		return nil, 0, err
+	}
+	return s.best, s.bestValue, nil
+}
+
+// withUpperBounds returns the constraints with one limiting each variable to at most 1 added for the variables which
+// no constraint already limits, so that the relaxations are bounded.
+func withUpperBounds(constraints [][]float64, bounds []float64, variables int) ([][]float64, []float64) {
+	for i := range variables {
+		limited := false
+		for r, constraint := range constraints {
+			if constraint[i] > 0 && bounds[r] <= constraint[i] {
+				limited = true
+				break
+			}
+		}
+		if !limited {
+			upper := make([]float64, variables)
+			upper[i] = 1
+			constraints = append(constraints, upper)
+			bounds = append(bounds, 1)
+		}
+	}
+	return constraints, bounds
+}
+
+// The states of the variables during the search.
+const (
+	free int8 = -1
+	zero int8 = 0
+	one  int8 = 1
+)
+
+type solver struct {
+	ctx         context.Context
+	objective   []float64
+	constraints [][]float64
+	bounds      []float64
+	maxNodes    int
+	nodes       int
+	// fixed is the state of each variable in the branch being searched.
+	fixed     []int8
+	best      []bool
+	bestValue float64
+}
+
+// branch searches the programs with the fixed variables, keeping the best solution found.
+func (s *solver) branch() error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	s.nodes++
+	if s.nodes > s.maxNodes {
+		return ErrNodeLimit
+	}
+
+	value := 0.0
+	residual := make([]float64, len(s.bounds))
+	copy(residual, s.bounds)
+	var variables []int
+	for i, state := range s.fixed {
+		switch {
+		case state == one:
+			value += s.objective[i]
+			for r, constraint := range s.constraints {
+				residual[r] -= constraint[i]
+			}
+		case state == free && s.objective[i] > epsilon:
+			// Variables without value are left as 0, which never breaks a constraint as the coefficients are not negative.
+			variables = append(variables, i)
+		}
+	}
+	for _, bound := range residual {
+		if bound < -epsilon {
+			return nil
+		}
+	}
+
+	relaxed, relaxedValue, err := s.relax(variables, residual)
+	if err != nil {
+		return err
+	}
+	if value+relaxedValue <= s.bestValue+epsilon {
+		return nil
+	}
+
+	fractional, distance := -1, 0.5
+	for j, x := range relaxed {
+		if d := math.Abs(x - 0.5); x > epsilon && x < 1-epsilon && d <= distance {
+			fractional, distance = variables[j], d
+		}
+	}
+	if fractional == -1 {
+		for i, state := range s.fixed {
+			s.best[i] = state == one
+		}
+		for j, x := range relaxed {
+			s.best[variables[j]] = x > 0.5
+		}
+		s.bestValue = value + relaxedValue
+		return nil
+	}
+
+	for _, state := range []int8{one, zero} {
+		s.fixed[fractional] = state
+		if err := s.branch(); err != nil {
+			return err
+		}
+	}
+	s.fixed[fractional] = free
+	return nil
+}
+
+// relax solves the linear relaxation of the program with only the variables, allowing each to be anywhere between 0
+// and 1, and the bounds of the constraints, returning the values of the variables and their total.
+func (s *solver) relax(variables []int, bounds []float64) ([]float64, float64, error) {
+	rows, columns := len(bounds), len(variables)+len(bounds)
+	if len(variables) == 0 {
+		return nil, 0, nil
+	}
+
+	// The tableau has a row for each constraint followed by the objective, and a column for each variable and the slack
+	// of each constraint followed by the bounds.
+	tableau := make([][]float64, rows+1)
+	for r := range rows {
+		tableau[r] = make([]float64, columns+1)
+		for j, i := range variables {
+			tableau[r][j] = s.constraints[r][i]
+		}
+		tableau[r][len(variables)+r] = 1
+		tableau[r][columns] = max(bounds[r], 0)
+	}
+	tableau[rows] = make([]float64, columns+1)
+	for j, i := range variables {
+		tableau[rows][j] = -s.objective[i]
+	}
+	basis := make([]int, rows)
+	for r := range basis {
+		basis[r] = len(variables) + r
+	}
+
+	for iteration := 0; ; iteration++ {
+		if iteration > 1000*columns {
+			return nil, 0, errors.New("the simplex method did not converge")
+		}
+		// Bland's rule of choosing the first improving column avoids cycling, which is used once the quicker choice of
+		// the most improving column has taken many iterations.
+		bland := iteration > 10*columns
+
+		entering := -1
+		for j := range columns {
+			if tableau[rows][j] < -epsilon && (entering == -1 || (!bland && tableau[rows][j] < tableau[rows][entering])) {
+				entering = j
+				if bland {
+					break
+				}
+			}
+		}
+		if entering == -1 {
+			break
+		}
+
+		leaving := -1
+		for r := range rows {
+			if tableau[r][entering] <= epsilon {
+				continue
+			}
+			if leaving == -1 {
+				leaving = r
+				continue
+			}
+			ratio, best := tableau[r][columns]/tableau[r][entering], tableau[leaving][columns]/tableau[leaving][entering]
+			if ratio < best-epsilon || (ratio <= best+epsilon && basis[r] < basis[leaving]) {
+				leaving = r
+			}
+		}
+		if leaving == -1 {
+			return nil, 0, errors.New("the relaxation is unbounded")
+		}
+
+		pivot(tableau, leaving, entering)
+		basis[leaving] = entering
+	}
+
+	values := make([]float64, len(variables))
+	for r, column := range basis {
+		if column < len(variables) {
+			values[column] = tableau[r][columns]
+		}
+	}
+	return values, tableau[rows][columns], nil
+}
+
+// pivot makes the column a basic variable of the row, eliminating it from every other row of the tableau.
+func pivot(tableau [][]float64, row, column int) {
+	pivotRow := tableau[row]
+	scale := pivotRow[column]
+	for j := range pivotRow {
+		pivotRow[j] /= scale
+	}
+
+	for r, other := range tableau {
+		if r == row || other[column] == 0 {
+			continue
+		}
+		factor := other[column]
+		for j := range other {
+			other[j] -= factor * pivotRow[j]
+		}
+	}
+}
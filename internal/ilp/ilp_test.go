@@ -0,0 +1,141 @@
+package ilp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestSolveKnapsack(t *testing.T) {
+	problem := Problem{
+		Objective:   []float64{10, 13, 7, 8},
+		Constraints: [][]float64{{5, 7, 4, 3}},
+		Bounds:      []float64{10},
+	}
+
+	solution, value, err := Solve(context.Background(), problem)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []bool{false, true, false, true}
+	if !reflect.DeepEqual(expected, solution) || value != 21 {
+		t.Errorf("Expected:\n%v 21\nGot:\n%v %v", expected, solution, value)
+	}
+}
+
+func TestSolveOddCycle(t *testing.T) {
+	// A triangle of pairs, whose relaxation pairs everyone half way.
+	problem := Problem{
+		Objective:   []float64{1, 1, 1},
+		Constraints: [][]float64{{1, 0, 1}, {1, 1, 0}, {0, 1, 1}},
+		Bounds:      []float64{1, 1, 1},
+	}
+
+	solution, value, err := Solve(context.Background(), problem)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	chosen := 0
+	for _, x := range solution {
+		if x {
+			chosen++
+		}
+	}
+	if chosen != 1 || value != 1 {
+		t.Errorf("Expected a single pair, got %v with value %v", solution, value)
+	}
+}
+
+func TestSolveMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+	for range 50 {
+		people := 2 + random.IntN(6)
+		var pairs [][2]int
+		for i := range people {
+			for j := i + 1; j < people; j++ {
+				if random.IntN(3) > 0 {
+					pairs = append(pairs, [2]int{i, j})
+				}
+			}
+		}
+
+		problem := Problem{Objective: make([]float64, len(pairs)), Bounds: make([]float64, people)}
+		for i := range pairs {
+			problem.Objective[i] = float64(random.IntN(20)) - 5
+		}
+		for person := range people {
+			constraint := make([]float64, len(pairs))
+			for i, pair := range pairs {
+				if pair[0] == person || pair[1] == person {
+					constraint[i] = 1
+				}
+			}
+			problem.Constraints = append(problem.Constraints, constraint)
+			problem.Bounds[person] = 1
+		}
+
+		_, value, err := Solve(context.Background(), problem)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := bestMatching(pairs, problem.Objective, 0, map[int]bool{}); math.Abs(expected-value) > 1e-6 {
+			t.Errorf("Expected the best matching of %v with values %v:\n%v\nGot:\n%v", pairs, problem.Objective, expected, value)
+		}
+	}
+}
+
+// bestMatching returns the highest total value of the pairs from the index on, without the people already paired.
+func bestMatching(pairs [][2]int, values []float64, index int, paired map[int]bool) float64 {
+	if index == len(pairs) {
+		return 0
+	}
+	best := bestMatching(pairs, values, index+1, paired)
+	if pair := pairs[index]; !paired[pair[0]] && !paired[pair[1]] {
+		paired[pair[0]], paired[pair[1]] = true, true
+		best = max(best, values[index]+bestMatching(pairs, values, index+1, paired))
+		paired[pair[0]], paired[pair[1]] = false, false
+	}
+	return best
+}
+
+func TestSolveNodeLimit(t *testing.T) {
+	problem := Problem{
+		Objective:   []float64{1, 1, 1},
+		Constraints: [][]float64{{1, 0, 1}, {1, 1, 0}, {0, 1, 1}},
+		Bounds:      []float64{1, 1, 1},
+		MaxNodes:    1,
+	}
+
+	if _, _, err := Solve(context.Background(), problem); !errors.Is(err, ErrNodeLimit) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", ErrNodeLimit, err)
+	}
+}
+
+func TestSolveStopsWhenDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	problem := Problem{Objective: []float64{1}, Constraints: [][]float64{{1}}, Bounds: []float64{1}}
+	if _, _, err := Solve(ctx, problem); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", context.Canceled, err)
+	}
+}
+
+func TestSolveRejectsInvalidProblems(t *testing.T) {
+	problems := map[string]Problem{
+		"missing bound":        {Objective: []float64{1}, Constraints: [][]float64{{1}}},
+		"missing coefficient":  {Objective: []float64{1, 1}, Constraints: [][]float64{{1}}, Bounds: []float64{1}},
+		"negative coefficient": {Objective: []float64{1}, Constraints: [][]float64{{-1}}, Bounds: []float64{1}},
+		"negative bound":       {Objective: []float64{1}, Constraints: [][]float64{{1}}, Bounds: []float64{-1}},
+		"not a number":         {Objective: []float64{math.NaN()}},
+	}
+
+	for name, problem := range problems {
+		if _, _, err := Solve(context.Background(), problem); err == nil {
+			t.Errorf("Expected an error solving a problem with a %s", name)
+		}
+	}
+}
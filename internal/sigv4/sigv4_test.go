@@ -0,0 +1,42 @@
+package sigv4
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSigningKey checks the key derivation against the example of the AWS Signature Version 4 documentation.
+func TestSigningKey(t *testing.T) {
+	expected := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+
+	got := hex.EncodeToString(signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam"))
+	if got != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+	}
+}
+
+func TestSignSetsHeaders(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://dynamodb.eu-west-1.amazonaws.com/", nil)
+	req.Header.Set("X-Amz-Target", "DynamoDB_20120810.GetItem")
+
+	Sign(req, nil, Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"}, "eu-west-1", "dynamodb", time.Date(2025, 8, 4, 9, 0, 0, 0, time.UTC))
+
+	if got := req.Header.Get("X-Amz-Date"); got != "20250804T090000Z" {
+		t.Errorf("Expected:\n%v\nGot:\n%v", "20250804T090000Z", got)
+	}
+	expected := "AWS4-HMAC-SHA256 Credential=AKID/20250804/eu-west-1/dynamodb/aws4_request, SignedHeaders=host;x-amz-date;x-amz-security-token;x-amz-target, Signature="
+	if got := req.Header.Get("Authorization"); !strings.HasPrefix(got, expected) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+	}
+}
+
+func TestRegionFromHost(t *testing.T) {
+	for host, expected := range map[string]string{"sqs.eu-west-1.amazonaws.com": "eu-west-1", "localhost": "", "dynamodb.us-east-1.amazonaws.com": ""} {
+		if got, _ := RegionFromHost(host, "sqs"); got != expected {
+			t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package yapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+)
+
+// environmentReference matches ${NAME} references to environment variables, and $$ which escapes a dollar sign.
+var environmentReference = regexp.MustCompile(`\$\$|\$\{([^}]*)\}`)
+
+// environmentName matches the names of environment variables which can be allowed.
+var environmentName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// interpolate expands the ${NAME} references in the string values of the config fields with the environment variables
+// of the same name, which must be listed in the environment field. It reports whether any value was changed.
+func interpolate(raw map[string]json.RawMessage) (map[string]json.RawMessage, bool, error) {
+	var allowed []string
+	if rawEnvironment, exists := raw["environment"]; exists {
+		if err := json.Unmarshal(rawEnvironment, &allowed); err != nil {
+			return nil, false, fmt.Errorf("error decoding environment: %w", err)
+		}
+	}
+
+	for _, name := range allowed {
+		if !environmentName.MatchString(name) {
+			return nil, false, fmt.Errorf("invalid environment variable name: %q", name)
+		}
+	}
+
+	interpolated := false
+	var expand func(value any) (any, error)
+	expand = func(value any) (any, error) {
+		switch value := value.(type) {
+		case string:
+			var err error
+			expanded := environmentReference.ReplaceAllStringFunc(value, func(reference string) string {
+				interpolated = true
+				if reference == "$$" {
+					return "$"
+				}
+
+				name := reference[2 : len(reference)-1]
+				variable, set := os.LookupEnv(name)
+				switch {
+				case !slices.Contains(allowed, name):
+					err = fmt.Errorf("environment variable %s is not listed in the environment of the config", name)
+				case !set:
+					err = fmt.Errorf("environment variable %s is not set", name)
+				}
+				return variable
+			})
+			return expanded, err
+		case []any:
+			for i := range value {
+				expanded, err := expand(value[i])
+				if err != nil {
+					return nil, err
+				}
+				value[i] = expanded
+			}
+			return value, nil
+		case map[string]any:
+			for key := range value {
+				expanded, err := expand(value[key])
+				if err != nil {
+					return nil, err
+				}
+				value[key] = expanded
+			}
+			return value, nil
+		default:
+			return value, nil
+		}
+	}
+
+	expanded := make(map[string]json.RawMessage, len(raw))
+	for key, rawValue := range raw {
+		if key == "environment" || !environmentReference.Match(rawValue) {
+			expanded[key] = rawValue
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(rawValue))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false, fmt.Errorf("error decoding %s: %w", key, err)
+		}
+
+		value, err := expand(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("error interpolating %s: %w", key, err)
+		}
+
+		if expanded[key], err = json.Marshal(value); err != nil {
+			return nil, false, fmt.Errorf("error encoding %s: %w", key, err)
+		}
+	}
+
+	return expanded, interpolated, nil
+}
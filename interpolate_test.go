@@ -0,0 +1,86 @@
+package yapper
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewConfigFromFilesInterpolatesEnvironment(t *testing.T) {
+	t.Setenv("YAPPER_DOMAIN", "mushroom.kingdom")
+	path := writeLayer(t, t.TempDir(), "config.json", `{
+	"version": 1,
+	"environment": ["YAPPER_DOMAIN"],
+	"people": [
+		{"id": "Mario", "email": "mario@${YAPPER_DOMAIN}"},
+		{"id": "Luigi", "email": "luigi@${YAPPER_DOMAIN}", "location": "$${YAPPER_DOMAIN}"}
+	]
+}`)
+
+	config, err := NewConfigFromFiles(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Person{
+		{ID: "Mario", Email: "mario@mushroom.kingdom"},
+		{ID: "Luigi", Email: "luigi@mushroom.kingdom", Location: "${YAPPER_DOMAIN}"},
+	}
+	if !reflect.DeepEqual(expected, config.People) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, config.People)
+	}
+
+	if !config.Interpolated() {
+		t.Error("Expected the config to be interpolated")
+	}
+	if err := config.Export(&bytes.Buffer{}); err == nil {
+		t.Error("Expected an error exporting an interpolated config")
+	}
+}
+
+func TestNewConfigFromFilesRejectsUnlistedEnvironment(t *testing.T) {
+	t.Setenv("YAPPER_SECRET", "hunter2")
+	t.Setenv("YAPPER_DOMAIN", "mushroom.kingdom")
+	dir := t.TempDir()
+
+	tests := map[string]struct {
+		data     string
+		expected string
+	}{
+		"unlisted": {
+			data:     `{"version": 1, "environment": ["YAPPER_DOMAIN"], "people": [{"id": "Mario", "email": "${YAPPER_SECRET}"}]}`,
+			expected: "environment variable YAPPER_SECRET is not listed",
+		},
+		"unset": {
+			data:     `{"version": 1, "environment": ["YAPPER_UNSET"], "people": [{"id": "Mario", "email": "${YAPPER_UNSET}"}]}`,
+			expected: "environment variable YAPPER_UNSET is not set",
+		},
+		"invalid name": {
+			data:     `{"version": 1, "environment": ["YAPPER-DOMAIN"], "people": [{"id": "Mario"}]}`,
+			expected: "invalid environment variable name",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeLayer(t, dir, strings.ReplaceAll(name, " ", "-")+".json", test.data)
+			_, err := NewConfigFromFiles(path)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected:\n%v\nGot:\n%v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestNewConfigFromFilesWithoutReferencesIsNotInterpolated(t *testing.T) {
+	path := writeLayer(t, t.TempDir(), "config.json", `{"version": 1, "people": [{"id": "Mario", "email": "mario@mushroom.kingdom"}]}`)
+
+	config, err := NewConfigFromFiles(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Interpolated() {
+		t.Error("Expected the config not to be interpolated")
+	}
+}
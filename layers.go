@@ -0,0 +1,184 @@
+package yapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// NewConfigFromFiles reads the config files as layers, each overriding the ones before it, e.g. a base config with
+// organisation wide rules followed by the people of a team. Each file is migrated to the current version first.
+//
+// Layers are merged field by field. Objects such as the soft constraints are merged recursively, lists such as the
+// rules are appended to, and any other value replaces the one before it. People are merged by their ID, so a layer
+// can add people or change the settings of people from earlier layers.
+//
+// References to environment variables as ${NAME} in string values are then expanded, see Config.Environment.
+func NewConfigFromFiles(paths ...string) (Config, error) {
+	if len(paths) == 0 {
+		return Config{}, errors.New("no config file given")
+	}
+
+	var merged map[string]json.RawMessage
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("error reading file %s: %w", path, err)
+		}
+
+		layer, err := migrateConfig(data)
+		if err != nil {
+			return Config{}, fmt.Errorf("error in config file %s: %w", path, err)
+		}
+
+		if merged == nil {
+			merged = layer
+			continue
+		}
+
+		if merged, err = mergeObjects(merged, layer); err != nil {
+			return Config{}, fmt.Errorf("error merging config file %s: %w", path, err)
+		}
+	}
+
+	merged, interpolated, err := interpolate(merged)
+	if err != nil {
+		return Config{}, err
+	}
+
+	config, err := decodeMigratedConfig(merged)
+	if err != nil {
+		return Config{}, err
+	}
+	config.interpolated = interpolated
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+// mergeObjects merges the fields of the overlay into the base, with the people merged by their IDs.
+func mergeObjects(base, overlay map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	merged := make(map[string]json.RawMessage, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range overlay {
+		existing, exists := merged[key]
+		if !exists {
+			merged[key] = value
+			continue
+		}
+
+		var err error
+		if key == "people" {
+			merged[key], err = mergePeople(existing, value)
+		} else {
+			merged[key], err = mergeValues(existing, value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error merging %s: %w", key, err)
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeValues merges two objects recursively, appends two lists and otherwise returns the overlay.
+func mergeValues(base, overlay json.RawMessage) (json.RawMessage, error) {
+	switch {
+	case isJSON(base, '{') && isJSON(overlay, '{'):
+		var baseObject, overlayObject map[string]json.RawMessage
+		if err := json.Unmarshal(base, &baseObject); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(overlay, &overlayObject); err != nil {
+			return nil, err
+		}
+
+		merged, err := mergeObjects(baseObject, overlayObject)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(merged)
+	case isJSON(base, '[') && isJSON(overlay, '['):
+		var baseList, overlayList []json.RawMessage
+		if err := json.Unmarshal(base, &baseList); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(overlay, &overlayList); err != nil {
+			return nil, err
+		}
+		return json.Marshal(append(baseList, overlayList...))
+	default:
+		return overlay, nil
+	}
+}
+
+// mergePeople merges each person of the overlay into the person of the base with the same ID, or adds them.
+func mergePeople(base, overlay json.RawMessage) (json.RawMessage, error) {
+	var basePeople, overlayPeople []json.RawMessage
+	if err := json.Unmarshal(base, &basePeople); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(overlay, &overlayPeople); err != nil {
+		return nil, err
+	}
+
+	ids := make([]ID, 0, len(basePeople))
+	for _, person := range basePeople {
+		id, err := personID(person)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	for _, person := range overlayPeople {
+		id, err := personID(person)
+		if err != nil {
+			return nil, err
+		}
+
+		index := -1
+		for i, existing := range ids {
+			if existing == id {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			basePeople = append(basePeople, person)
+			ids = append(ids, id)
+			continue
+		}
+
+		if basePeople[index], err = mergeValues(basePeople[index], person); err != nil {
+			return nil, fmt.Errorf("error merging person %s: %w", id, err)
+		}
+	}
+
+	return json.Marshal(basePeople)
+}
+
+func personID(person json.RawMessage) (ID, error) {
+	var identified struct {
+		ID ID `json:"id"`
+	}
+	if err := json.Unmarshal(person, &identified); err != nil {
+		return "", fmt.Errorf("error decoding person: %w", err)
+	}
+	return identified.ID, nil
+}
+
+// isJSON reports whether the JSON value starts with the delimiter, e.g. '{' for an object.
+func isJSON(value json.RawMessage, delim byte) bool {
+	trimmed := bytes.TrimSpace(value)
+	return len(trimmed) > 0 && trimmed[0] == delim
+}
@@ -0,0 +1,71 @@
+package yapper
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeLayer(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Error writing %s: %v", path, err)
+	}
+	return path
+}
+
+func TestNewConfigFromFilesMergesLayers(t *testing.T) {
+	dir := t.TempDir()
+	base := writeLayer(t, dir, "base.json", `{
+	"version": 1,
+	"people": [{"id": "Bowser", "squad": "koopas"}],
+	"rules": [{"deny": "person.level == \"intern\" && other.level == \"vp\""}],
+	"softConstraints": {"sameLocation": 1, "recentlyMet": 2, "recentlyMetDays": 14},
+	"strategy": "planned"
+}`)
+	team := writeLayer(t, dir, "team.json", `{
+	"people": [{"id": "Mario", "squad": "bros"}, {"id": "Bowser", "denyList": ["Mario"]}],
+	"rules": [{"deny": "person.squad == other.squad"}],
+	"softConstraints": {"recentlyMet": 3},
+	"strategy": "greedy"
+}`)
+
+	config, err := NewConfigFromFiles(base, team)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedPeople := []Person{{ID: "Bowser", Squad: "koopas", DenyList: []ID{"Mario"}}, {ID: "Mario", Squad: "bros"}}
+	if !reflect.DeepEqual(expectedPeople, config.People) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedPeople, config.People)
+	}
+
+	if len(config.Rules) != 2 {
+		t.Errorf("Expected the rules of both layers, got %v", config.Rules)
+	}
+
+	expectedConstraints := SoftConstraints{SameLocation: 1, RecentlyMet: 3, RecentlyMetDays: 14}
+	if config.SoftConstraints != expectedConstraints {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedConstraints, config.SoftConstraints)
+	}
+
+	if config.Settings.Strategy != StrategyGreedy {
+		t.Errorf("Expected the strategy of the last layer, got %s", config.Settings.Strategy)
+	}
+}
+
+func TestNewConfigFromFilesValidatesMergedConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := writeLayer(t, dir, "base.json", `{"people": [{"id": "Mario"}]}`)
+	pins := writeLayer(t, dir, "pins.json", `{"pins": [{"people": ["Mario", "Luigi"]}]}`)
+
+	if _, err := NewConfigFromFiles(base, pins); err == nil {
+		t.Errorf("Expected an error for a pin of someone in no layer")
+	}
+
+	if _, err := NewConfigFromFiles(); err == nil {
+		t.Errorf("Expected an error without any config files")
+	}
+}
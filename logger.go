@@ -0,0 +1,24 @@
+package yapper
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// logger is the logger set with SetLogger, nil for the default logger.
+var logger atomic.Pointer[slog.Logger]
+
+// SetLogger sets the logger yapper writes its warnings to, such as a strategy being abandoned for running over its
+// budget, so applications embedding yapper can capture them in their own logging pipeline. A nil logger restores the
+// default, slog.Default, which writes to stderr unless it has been changed.
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// Logger returns the logger set with SetLogger, or slog.Default if none is set.
+func Logger() *slog.Logger {
+	if l := logger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
@@ -0,0 +1,38 @@
+package yapper
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestSetLoggerCapturesWarnings(t *testing.T) {
+	var buffer bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buffer, nil)))
+	defer SetLogger(nil)
+
+	config := getConfigFromFile(t, validConfigName)
+	config.Settings.Strategies = []StrategyStep{{Strategy: StrategyPlanned, Budget: "1ns"}, {Strategy: StrategyGreedy}}
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 52)
+
+	hist := history.History{}
+	if _, err := generateForDates(config, &hist, dates); err != nil {
+		t.Fatalf("Unexpected error generating pairings: %v", err)
+	}
+
+	expected := `level=WARN msg="strategy ran over its budget, trying the next" strategy=planned budget=1ns`
+	if !strings.Contains(buffer.String(), expected) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buffer.String())
+	}
+}
+
+func TestLoggerDefaultsToSlogDefault(t *testing.T) {
+	SetLogger(nil)
+	if Logger() != slog.Default() {
+		t.Error("Expected the default logger to be slog.Default")
+	}
+}
@@ -0,0 +1,114 @@
+package yapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// pairingsJSON is the JSON form of Pairings, including what was decided about each pair when they were generated.
+type pairingsJSON struct {
+	Date    *time.Time `json:"date,omitempty"`
+	Pairs   []pairJSON `json:"pairs"`
+	Penalty float64    `json:"penalty,omitempty"`
+	Score   *PlanScore `json:"score,omitempty"`
+}
+
+type pairJSON struct {
+	People    [2]ID      `json:"people"`
+	LastMet   *time.Time `json:"lastMet,omitempty"`
+	Initiator ID         `json:"initiator,omitempty"`
+	Topic     string     `json:"topic,omitempty"`
+}
+
+// MarshalJSON writes the pairings as an object with the date of the round and each pair, along with when they last
+// met, who initiates their meeting and their topic. Unlike Export, nothing about the pairings is lost.
+func (p Pairings) MarshalJSON() ([]byte, error) {
+	out := pairingsJSON{Pairs: make([]pairJSON, 0, len(p.data)), Penalty: p.penalty, Score: p.score}
+	if !p.date.IsZero() {
+		out.Date = &p.date
+	}
+
+	for _, pair := range p.data {
+		key := pairKey(pair[0], pair[1])
+		pairOut := pairJSON{People: pair, Initiator: p.initiators[key], Topic: p.topics[key]}
+		if lastMeeting, met := p.lastMeetings[key]; met {
+			pairOut.LastMet = &lastMeeting
+		}
+		out.Pairs = append(out.Pairs, pairOut)
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads pairings written by MarshalJSON, or the list of pairs written by Export.
+func (p *Pairings) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var pairs [][2]ID
+		if err := json.Unmarshal(trimmed, &pairs); err != nil {
+			return fmt.Errorf("error decoding Pairings: %w", err)
+		}
+		*p = Pairings{data: pairs}
+		return nil
+	}
+
+	var in pairingsJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("error decoding Pairings: %w", err)
+	}
+
+	decoded := Pairings{data: make([][2]ID, 0, len(in.Pairs)), penalty: in.Penalty, score: in.Score}
+	if in.Date != nil {
+		decoded.date = *in.Date
+	}
+	for _, pair := range in.Pairs {
+		decoded.data = append(decoded.data, pair.People)
+		key := pairKey(pair.People[0], pair.People[1])
+		if pair.LastMet != nil {
+			if decoded.lastMeetings == nil {
+				decoded.lastMeetings = make(map[[2]ID]time.Time)
+			}
+			decoded.lastMeetings[key] = *pair.LastMet
+		}
+		if pair.Initiator != "" {
+			if decoded.initiators == nil {
+				decoded.initiators = make(map[[2]ID]ID)
+			}
+			decoded.initiators[key] = pair.Initiator
+		}
+		if pair.Topic != "" {
+			if decoded.topics == nil {
+				decoded.topics = make(map[[2]ID]string)
+			}
+			decoded.topics[key] = pair.Topic
+		}
+	}
+
+	*p = decoded
+	return nil
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+func (id *ID) UnmarshalText(text []byte) error {
+	*id = ID(text)
+	return nil
+}
+
+func (c Cadence) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// UnmarshalText reads a cadence, returning an error for unexpected cadences. The empty cadence is the default.
+func (c *Cadence) UnmarshalText(text []byte) error {
+	switch cadence := Cadence(text); cadence {
+	case "", CadenceOneWeek, CadenceTwoWeeks:
+		*c = cadence
+		return nil
+	default:
+		return fmt.Errorf("unexpected cadence: %s", cadence)
+	}
+}
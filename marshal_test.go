@@ -0,0 +1,63 @@
+package yapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPairingsJSONRoundTrip(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	pairings := Pairings{
+		data:         [][2]ID{{"Mario", "Luigi"}, {"Peach", "Toad"}},
+		date:         date,
+		penalty:      1.5,
+		lastMeetings: map[[2]ID]time.Time{{"Luigi", "Mario"}: date.AddDate(0, 0, -14)},
+		initiators:   map[[2]ID]ID{{"Luigi", "Mario"}: "Mario", {"Peach", "Toad"}: "Toad"},
+		topics:       map[[2]ID]string{{"Peach", "Toad"}: "karting"},
+		score:        &PlanScore{Cost: -10, Bound: -20},
+	}
+
+	data, err := json.Marshal(Round{Date: date, Pairings: pairings, Unpaired: []ID{}, Diagnostics: []Diagnostic{}})
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling round: %v", err)
+	}
+
+	var round Round
+	if err := json.Unmarshal(data, &round); err != nil {
+		t.Fatalf("Unexpected error unmarshalling round: %v", err)
+	}
+	if !reflect.DeepEqual(pairings, round.Pairings) {
+		t.Errorf("Expected:\n%+v\nGot:\n%+v", pairings, round.Pairings)
+	}
+}
+
+func TestPairingsUnmarshalJSONReadsExportedPairs(t *testing.T) {
+	var pairings Pairings
+	if err := json.Unmarshal([]byte(`[["Mario", "Luigi"]]`), &pairings); err != nil {
+		t.Fatalf("Unexpected error unmarshalling pairings: %v", err)
+	}
+
+	if expected := [][2]ID{{"Mario", "Luigi"}}; !reflect.DeepEqual(expected, pairings.data) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, pairings.data)
+	}
+}
+
+func TestCadenceUnmarshalTextReturnsErrorForUnexpectedCadence(t *testing.T) {
+	var person Person
+	if err := json.Unmarshal([]byte(`{"id": "Mario", "cadence": "daily"}`), &person); err == nil {
+		t.Errorf("Expected error due to unexpected cadence, got %+v", person)
+	}
+}
+
+func TestIDMarshalsAsMapKey(t *testing.T) {
+	data, err := json.Marshal(map[ID]Cadence{"Mario": CadenceTwoWeeks})
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling: %v", err)
+	}
+
+	if expected := `{"Mario":"two-weeks"}`; string(data) != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, string(data))
+	}
+}
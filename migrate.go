@@ -0,0 +1,118 @@
+package yapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ConfigSchemaVersion is the version of the config format written and understood by this version of yapper.
+// Config files without a version are treated as version 1, the original format.
+// Version 2 moved the program wide options into the settings section.
+const ConfigSchemaVersion = 2
+
+// configMigrations upgrade the raw config from the version of their key to the next version.
+var configMigrations = map[int]func(raw map[string]json.RawMessage) error{
+	1: migrateConfigSettings,
+}
+
+// settingsFields are the fields moved from the top level of the config into the settings in version 2.
+var settingsFields = []string{"interval", "strategy", "squadPolicy"}
+
+// migrateConfigSettings moves the program wide options of a version 1 config into the settings section.
+func migrateConfigSettings(raw map[string]json.RawMessage) error {
+	settings := map[string]json.RawMessage{}
+	if rawSettings, exists := raw["settings"]; exists {
+		if err := json.Unmarshal(rawSettings, &settings); err != nil {
+			return fmt.Errorf("error decoding settings: %w", err)
+		}
+	}
+
+	for _, field := range settingsFields {
+		value, exists := raw[field]
+		if !exists {
+			continue
+		}
+		if _, exists := settings[field]; !exists {
+			settings[field] = value
+		}
+		delete(raw, field)
+	}
+
+	if len(settings) == 0 {
+		return nil
+	}
+
+	rawSettings, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("error encoding settings: %w", err)
+	}
+	raw["settings"] = rawSettings
+	return nil
+}
+
+// decodeConfig decodes the config data, migrating it from older versions of the format.
+// Newer versions are rejected as they may contain settings which would be silently ignored.
+func decodeConfig(data []byte) (Config, error) {
+	raw, err := migrateConfig(data)
+	if err != nil {
+		return Config{}, err
+	}
+	return decodeMigratedConfig(raw)
+}
+
+// migrateConfig returns the fields of the config data, migrated from older versions of the format to the current.
+func migrateConfig(data []byte) (map[string]json.RawMessage, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error decoding Config: %w", err)
+	} else if raw == nil {
+		return nil, errors.New("error decoding Config: expected an object")
+	}
+
+	version := 1
+	if rawVersion, exists := raw["version"]; exists {
+		if err := json.Unmarshal(rawVersion, &version); err != nil {
+			return nil, fmt.Errorf("error decoding Config version: %w", err)
+		}
+	}
+
+	if version > ConfigSchemaVersion {
+		return nil, fmt.Errorf(
+			"config version %d is newer than the supported version %d, a newer version of yapper is required",
+			version,
+			ConfigSchemaVersion,
+		)
+	} else if version < 1 {
+		return nil, fmt.Errorf("invalid config version: %d", version)
+	}
+
+	for ; version < ConfigSchemaVersion; version++ {
+		migrate, exists := configMigrations[version]
+		if !exists {
+			return nil, fmt.Errorf("no migration for config version %d", version)
+		}
+
+		if err := migrate(raw); err != nil {
+			return nil, fmt.Errorf("error migrating Config from version %d: %w", version, err)
+		}
+	}
+
+	return raw, nil
+}
+
+// decodeMigratedConfig decodes the fields of a config already migrated to the current version.
+func decodeMigratedConfig(raw map[string]json.RawMessage) (Config, error) {
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return Config{}, fmt.Errorf("error encoding migrated Config: %w", err)
+	}
+
+	config := Config{}
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return Config{}, fmt.Errorf("error decoding Config: %w", err)
+	}
+	config.Version = ConfigSchemaVersion
+
+	return config, nil
+}
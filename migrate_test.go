@@ -0,0 +1,54 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeConfigReturnsErrorForFutureVersion(t *testing.T) {
+	if _, err := decodeConfig([]byte(`{"version": 99, "people": []}`)); err == nil {
+		t.Errorf("Expected error due to config version newer than %d", ConfigSchemaVersion)
+	}
+}
+
+func TestDecodeConfigReturnsErrorForInvalidVersion(t *testing.T) {
+	for _, data := range []string{`{"version": 0}`, `{"version": "one"}`} {
+		if _, err := decodeConfig([]byte(data)); err == nil {
+			t.Errorf("Expected error due to invalid config version: %s", data)
+		}
+	}
+}
+
+func TestDecodeConfigTreatsMissingVersionAsCurrent(t *testing.T) {
+	config, err := decodeConfig([]byte(`{"people": [{"id": "Mario"}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error from decodeConfig: %v", err)
+	}
+
+	if config.Version != ConfigSchemaVersion {
+		t.Errorf("Expected version %d, got %d", ConfigSchemaVersion, config.Version)
+	}
+
+	if len(config.People) != 1 || config.People[0].ID != "Mario" {
+		t.Errorf("Expected Mario in the config, got: %v", config.People)
+	}
+}
+
+func TestDecodeConfigMovesVersionOneOptionsIntoSettings(t *testing.T) {
+	config, err := decodeConfig([]byte(`{
+	"version": 1,
+	"people": [{"id": "Mario"}],
+	"interval": 14,
+	"strategy": "planned",
+	"squadPolicy": "prefer-differing",
+	"settings": {"cadence": "two-weeks"}
+}`))
+	if err != nil {
+		t.Fatalf("Unexpected error from decodeConfig: %v", err)
+	}
+
+	expected := Settings{Interval: 14, Strategy: StrategyPlanned, SquadPolicy: SquadPolicyPreferDiffering, Cadence: CadenceTwoWeeks}
+	if !reflect.DeepEqual(expected, config.Settings) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, config.Settings)
+	}
+}
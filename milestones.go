@@ -0,0 +1,74 @@
+package yapper
+
+import (
+	"fmt"
+	"time"
+)
+
+type MilestoneKind string
+
+const (
+	// MilestoneBirthday is the birthday of a person.
+	MilestoneBirthday MilestoneKind = "birthday"
+	// MilestoneWorkAnniversary is the anniversary of a person starting work.
+	MilestoneWorkAnniversary MilestoneKind = "work-anniversary"
+)
+
+// Milestone is a birthday or work anniversary of a person falling within a round.
+type Milestone struct {
+	Person ID            `json:"person"`
+	Kind   MilestoneKind `json:"kind"`
+	Date   Date          `json:"date"`
+	// Years is the number of years since the person started, for work anniversaries.
+	Years int `json:"years,omitempty"`
+}
+
+func (m Milestone) String() string {
+	switch m.Kind {
+	case MilestoneWorkAnniversary:
+		return fmt.Sprintf("%s's %d year work anniversary on %s", m.Person, m.Years, m.Date.Format(time.DateOnly))
+	default:
+		return fmt.Sprintf("%s's birthday on %s", m.Person, m.Date.Format(time.DateOnly))
+	}
+}
+
+// Milestones returns the birthdays and work anniversaries of the pair during the round starting on date, in order of
+// the people of the pair.
+func (c Config) Milestones(id1, id2 ID, date time.Time) []Milestone {
+	start := NewDate(date)
+	end := NewDate(date.AddDate(0, 0, c.RoundInterval()-1))
+
+	var milestones []Milestone
+	for _, id := range []ID{id1, id2} {
+		person, err := c.GetPerson(id)
+		if err != nil {
+			continue
+		}
+
+		if person.Birthday != nil {
+			if occurrence, found := anniversaryWithin(*person.Birthday, start, end); found {
+				milestones = append(milestones, Milestone{Person: id, Kind: MilestoneBirthday, Date: occurrence})
+			}
+		}
+
+		if person.StartDate != nil {
+			occurrence, found := anniversaryWithin(*person.StartDate, start, end)
+			if years := occurrence.Year() - person.StartDate.Year(); found && years > 0 {
+				milestones = append(milestones, Milestone{Person: id, Kind: MilestoneWorkAnniversary, Date: occurrence, Years: years})
+			}
+		}
+	}
+	return milestones
+}
+
+// anniversaryWithin returns the anniversary of the date between start and end inclusive, if there is one.
+// Anniversaries of the 29th of February fall on the 1st of March in other years.
+func anniversaryWithin(date, start, end Date) (Date, bool) {
+	for year := start.Year(); year <= end.Year(); year++ {
+		occurrence := NewDate(time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC))
+		if occurrence.Within(start, end) {
+			return occurrence, true
+		}
+	}
+	return Date{}, false
+}
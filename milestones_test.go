@@ -0,0 +1,47 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMilestonesWithinRound(t *testing.T) {
+	birthday := date(1990, time.August, 6)
+	started := date(2022, time.August, 10)
+	leapDay := date(2020, time.February, 29)
+	config := Config{People: []Person{
+		{ID: "Mario", Birthday: &birthday},
+		{ID: "Luigi", StartDate: &started},
+		{ID: "Peach", Birthday: &leapDay},
+		{ID: "Toad", StartDate: &started},
+	}}
+
+	expected := []Milestone{
+		{Person: "Mario", Kind: MilestoneBirthday, Date: date(2025, time.August, 6)},
+		{Person: "Luigi", Kind: MilestoneWorkAnniversary, Date: date(2025, time.August, 10), Years: 3},
+	}
+	if milestones := config.Milestones("Mario", "Luigi", time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)); !reflect.DeepEqual(expected, milestones) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, milestones)
+	}
+
+	if milestones := config.Milestones("Mario", "Luigi", time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC)); len(milestones) != 0 {
+		t.Errorf("Expected no milestones in the following round, got %v", milestones)
+	}
+
+	expected = []Milestone{{Person: "Peach", Kind: MilestoneBirthday, Date: date(2025, time.March, 1)}}
+	if milestones := config.Milestones("Peach", "Toad", time.Date(2025, time.February, 24, 0, 0, 0, 0, time.UTC)); !reflect.DeepEqual(expected, milestones) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, milestones)
+	}
+
+	if milestones := config.Milestones("Toad", "Mario", time.Date(2022, time.August, 8, 0, 0, 0, 0, time.UTC)); len(milestones) != 0 {
+		t.Errorf("Expected no anniversary in the week Toad started, got %v", milestones)
+	}
+}
+
+func TestMilestoneString(t *testing.T) {
+	milestone := Milestone{Person: "Luigi", Kind: MilestoneWorkAnniversary, Date: date(2025, time.August, 10), Years: 3}
+	if expected := "Luigi's 3 year work anniversary on 2025-08-10"; milestone.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, milestone.String())
+	}
+}
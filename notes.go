@@ -0,0 +1,24 @@
+package yapper
+
+import (
+	"fmt"
+	"time"
+)
+
+// Notes returns the notes of the organizers about the pair in the round starting on date, those of any pin of the
+// pair applying to the round followed by those of each person, named by their ID.
+func (c Config) Notes(id1, id2 ID, date time.Time) []string {
+	var notes []string
+	for _, pin := range c.Pins {
+		if pin.Note != "" && pairKey(pin.People[0], pin.People[1]) == pairKey(id1, id2) && pin.appliesTo(c, date) {
+			notes = append(notes, pin.Note)
+		}
+	}
+
+	for _, id := range []ID{id1, id2} {
+		if person, err := c.GetPerson(id); err == nil && person.Note != "" {
+			notes = append(notes, fmt.Sprintf("%s: %s", id, person.Note))
+		}
+	}
+	return notes
+}
@@ -0,0 +1,24 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotesOfPinsAndPeople(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	otherDate := NewDate(date.AddDate(0, 0, 14))
+	config := Config{
+		People: []Person{{ID: "Mario", Note: "joined from the Berlin office"}, {ID: "Luigi"}},
+		Pins: []Pin{
+			{People: [2]ID{"Luigi", "Mario"}, Note: "both interested in Rust"},
+			{People: [2]ID{"Mario", "Luigi"}, Date: &otherDate, Note: "planning the offsite"},
+		},
+	}
+
+	expected := []string{"both interested in Rust", "Mario: joined from the Berlin office"}
+	if notes := config.Notes("Mario", "Luigi", date); !reflect.DeepEqual(expected, notes) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, notes)
+	}
+}
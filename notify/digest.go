@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// Digest summarizes a notify run for the organizers of the program.
+type Digest struct {
+	Date  time.Time
+	Pairs int
+	// Unpaired are the people who were not paired in the round.
+	Unpaired []yapper.ID
+	// CoverageBefore and CoverageAfter are the fraction of the valid pairs who had met before the round, and who will
+	// have met after it.
+	CoverageBefore float64
+	CoverageAfter  float64
+	// Streaks are the people unpaired in too many rounds in a row, see yapper.UnpairedStreaks.
+	Streaks []yapper.Streak
+	// Failures describe the pairings which could not be notified, and the messages which failed to send.
+	Failures []string
+}
+
+// DigestMessages returns the messages sending the digest to each of the organizers of the config, routed to their
+// notify channel like the people who are paired, see Route. The organizers who cannot be reached through any of the
+// providers are returned along with the messages.
+func DigestMessages(config yapper.Config, providers []Provider, digest Digest) ([]Routed, []yapper.ID) {
+	routes := make([]Routed, 0, len(providers))
+	for _, provider := range providers {
+		routes = append(routes, Routed{Provider: provider, Messages: []Message{}})
+	}
+	unreachable := []yapper.ID{}
+
+	recipients := make([][]string, len(providers))
+	for _, organizer := range config.Organizers {
+		person := organizer.Person()
+		if person.Notify == yapper.ChannelNone {
+			continue
+		}
+
+		route := Route(person, providers)
+		if route < 0 {
+			unreachable = append(unreachable, organizer.ID)
+			continue
+		}
+		recipients[route] = append(recipients[route], providers[route].Address(person))
+	}
+
+	for i := range providers {
+		if len(recipients[i]) > 0 {
+			routes[i].Messages = append(routes[i].Messages, digestMessage(digest, recipients[i]))
+		}
+	}
+	return routes, unreachable
+}
+
+func digestMessage(digest Digest, recipients []string) Message {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "The round starting %s has %d pairs.", digest.Date.Format(time.DateOnly), digest.Pairs)
+
+	if len(digest.Unpaired) > 0 {
+		names := make([]string, 0, len(digest.Unpaired))
+		for _, id := range digest.Unpaired {
+			names = append(names, string(id))
+		}
+		fmt.Fprintf(&sb, "\n\nUnpaired: %s", strings.Join(names, ", "))
+	} else {
+		sb.WriteString("\n\nEveryone was paired.")
+	}
+
+	fmt.Fprintf(&sb, "\n\nCoverage: %.1f%% of the possible pairs have met, up from %.1f%%.", 100*digest.CoverageAfter, 100*digest.CoverageBefore)
+
+	if len(digest.Streaks) > 0 {
+		sb.WriteString("\n\nRepeatedly unpaired, which is often a sign of an over-restrictive config:")
+		for _, streak := range digest.Streaks {
+			fmt.Fprintf(&sb, "\n- %s has not been paired in %d rounds in a row: %s", streak.Person, streak.Rounds, streak.Message)
+		}
+	}
+
+	if len(digest.Failures) > 0 {
+		sb.WriteString("\n\nNotification failures:")
+		for _, failure := range digest.Failures {
+			fmt.Fprintf(&sb, "\n- %s", failure)
+		}
+	}
+
+	return Message{
+		Recipients: recipients,
+		Subject:    fmt.Sprintf("yapper digest for %s", digest.Date.Format(time.DateOnly)),
+		Text:       sb.String(),
+	}
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+func TestDigestMessagesSummarizeTheRun(t *testing.T) {
+	config := yapper.Config{Organizers: []yapper.Organizer{
+		{ID: "Peach", Email: "peach@mushroom.kingdom"},
+		{ID: "Daisy", Email: "daisy@sarasa.land"},
+		{ID: "Bowser"},
+	}}
+	digest := Digest{
+		Date:           time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+		Pairs:          3,
+		Unpaired:       []yapper.ID{"Toad"},
+		CoverageBefore: 0.4,
+		CoverageAfter:  0.55,
+		Streaks:        []yapper.Streak{{Person: "Toad", Rounds: 3, Message: "Toad cannot be paired with anyone"}},
+		Failures:       []string{"Yoshi & Birdo are missing an address for email"},
+	}
+
+	routes, unreachable := DigestMessages(config, []Provider{&recordingProvider{}}, digest)
+
+	expected := []Message{{
+		Recipients: []string{"peach@mushroom.kingdom", "daisy@sarasa.land"},
+		Subject:    "yapper digest for 2025-01-06",
+		Text:       "The round starting 2025-01-06 has 3 pairs.\n\nUnpaired: Toad\n\nCoverage: 55.0% of the possible pairs have met, up from 40.0%.\n\nRepeatedly unpaired, which is often a sign of an over-restrictive config:\n- Toad has not been paired in 3 rounds in a row: Toad cannot be paired with anyone\n\nNotification failures:\n- Yoshi & Birdo are missing an address for email",
+	}}
+	if len(routes) != 1 || !reflect.DeepEqual(expected, routes[0].Messages) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, routes)
+	}
+
+	if expectedUnreachable := []yapper.ID{"Bowser"}; !reflect.DeepEqual(expectedUnreachable, unreachable) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedUnreachable, unreachable)
+	}
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// Email sends each message as an email to its recipients through an SMTP server.
+// Each batch of messages is sent over a single connection, as SMTP servers limit the connections of an account.
+type Email struct {
+	// Addr is the host and port of the SMTP server, e.g. smtp.example.com:587.
+	Addr string
+	From string
+	// Username and Password authenticate with the SMTP server if a username is given.
+	Username string
+	Password string
+}
+
+func (e Email) Name() string {
+	return "email"
+}
+
+// Limits stay well below the hourly sending limits of common SMTP providers.
+func (e Email) Limits() Limits {
+	return Limits{Rate: 2, Burst: 20, BatchSize: 20}
+}
+
+// Address returns the email of the person.
+func (e Email) Address(person yapper.Person) string {
+	return person.Email
+}
+
+// Send delivers the messages over one connection to the SMTP server, using STARTTLS when the server supports it.
+func (e Email) Send(ctx context.Context, messages []Message) error {
+	client, err := e.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, message := range messages {
+		if err := e.send(client, message); err != nil {
+			return err
+		}
+	}
+
+	return client.Quit()
+}
+
+// Check connects and authenticates with the SMTP server, without sending an email.
+func (e Email) Check(ctx context.Context) error {
+	client, err := e.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.Quit()
+}
+
+// dial starts an SMTP session with the server, using STARTTLS when the server supports it and authenticating if a
+// username is given.
+func (e Email) dial(ctx context.Context) (*smtp.Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to SMTP server %s: %w", e.Addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, err := net.SplitHostPort(e.Addr)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error parsing SMTP server address %s: %w", e.Addr, err)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error starting SMTP session: %w", err)
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("error starting TLS: %w", err)
+		}
+	}
+
+	if e.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.Username, e.Password, host)); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("error authenticating with SMTP server: %w", err)
+		}
+	}
+
+	return client, nil
+}
+
+func (e Email) send(client *smtp.Client, message Message) error {
+	if err := client.Mail(e.From); err != nil {
+		return fmt.Errorf("error setting sender %s: %w", e.From, err)
+	}
+	for _, recipient := range message.Recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("error adding recipient %s: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error starting email: %w", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", e.From)
+	fmt.Fprintf(&sb, "To: %s\r\n", strings.Join(message.Recipients, ", "))
+	fmt.Fprintf(&sb, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&sb, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	sb.WriteString(strings.ReplaceAll(message.Text, "\n", "\r\n"))
+	sb.WriteString("\r\n")
+
+	if _, err := writer.Write([]byte(sb.String())); err != nil {
+		return fmt.Errorf("error writing email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP accepts connections, recording the data of each email and the number of connections made.
+type fakeSMTP struct {
+	listener    net.Listener
+	emails      chan string
+	connections int
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTP{listener: listener, emails: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.connections++
+			server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ready")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		switch command := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, _ := text.ReadDotLines()
+			s.emails <- strings.Join(data, "\n")
+			text.PrintfLine("250 ok")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestEmailSendsBatchOverOneConnection(t *testing.T) {
+	server := newFakeSMTP(t)
+	email := Email{Addr: server.listener.Addr().String(), From: "yapper@mushroom.kingdom"}
+
+	err := email.Send(context.Background(), []Message{
+		{Recipients: []string{"mario@mushroom.kingdom", "luigi@mushroom.kingdom"}, Subject: "Paired", Text: "Say hi"},
+		{Recipients: []string{"peach@mushroom.kingdom", "toad@mushroom.kingdom"}, Subject: "Paired", Text: "Say hi"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error sending: %v", err)
+	}
+
+	first := <-server.emails
+	if !strings.Contains(first, "To: mario@mushroom.kingdom, luigi@mushroom.kingdom") || !strings.HasSuffix(first, "Say hi") {
+		t.Errorf("Unexpected email:\n%s", first)
+	}
+	<-server.emails
+
+	if server.connections != 1 {
+		t.Errorf("Expected a single connection, got %d", server.connections)
+	}
+}
+
+func TestEmailCheckSendsNothing(t *testing.T) {
+	server := newFakeSMTP(t)
+	email := Email{Addr: server.listener.Addr().String(), From: "yapper@mushroom.kingdom"}
+
+	if err := Check(context.Background(), email); err != nil {
+		t.Fatalf("Unexpected error checking: %v", err)
+	}
+
+	if server.connections != 1 || len(server.emails) != 0 {
+		t.Errorf("Expected a single connection without emails, got %d connections and %d emails", server.connections, len(server.emails))
+	}
+}
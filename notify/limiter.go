@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// limiter is a token bucket allowing a burst of messages, refilled at the rate.
+type limiter struct {
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+	// now and sleep allow tests to control time.
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{rate: rate, burst: burst, tokens: float64(burst), now: time.Now, sleep: sleepContext}
+}
+
+// wait blocks until n messages can be sent, or the context is done.
+// A batch larger than the burst waits until the bucket is full and then empties it.
+func (l *limiter) wait(ctx context.Context, n int) error {
+	if l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	needed := min(float64(n), float64(l.burst))
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	if l.tokens < needed {
+		delay := time.Duration((needed - l.tokens) / l.rate * float64(time.Second))
+		if err := l.sleep(ctx, delay); err != nil {
+			return err
+		}
+		l.tokens = needed
+		l.last = now.Add(delay)
+	}
+
+	l.tokens -= needed
+	return nil
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock lets the limiter sleep without waiting, recording each delay.
+type fakeClock struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *fakeClock) limiter(rate float64, burst int) *limiter {
+	l := newLimiter(rate, burst)
+	l.now = func() time.Time { return c.now }
+	l.sleep = func(_ context.Context, delay time.Duration) error {
+		c.delays = append(c.delays, delay)
+		c.now = c.now.Add(delay)
+		return nil
+	}
+	return l
+}
+
+func TestLimiterWaitsForRate(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)}
+	l := clock.limiter(1, 1)
+
+	for range 3 {
+		if err := l.wait(context.Background(), 1); err != nil {
+			t.Fatalf("Unexpected error waiting: %v", err)
+		}
+	}
+
+	expected := []time.Duration{time.Second, time.Second}
+	if !reflect.DeepEqual(expected, clock.delays) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, clock.delays)
+	}
+}
+
+func TestLimiterAllowsBurst(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)}
+	l := clock.limiter(2, 4)
+
+	for range 4 {
+		l.wait(context.Background(), 1)
+	}
+	if len(clock.delays) != 0 {
+		t.Fatalf("Expected the burst not to wait, got %v", clock.delays)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	l.wait(context.Background(), 2)
+	l.wait(context.Background(), 1)
+
+	expected := []time.Duration{500 * time.Millisecond}
+	if !reflect.DeepEqual(expected, clock.delays) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, clock.delays)
+	}
+}
+
+func TestLimiterWithoutRateDoesNotWait(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)}
+	l := clock.limiter(0, 1)
+
+	for range 10 {
+		l.wait(context.Background(), 5)
+	}
+	if len(clock.delays) != 0 {
+		t.Errorf("Expected no waiting, got %v", clock.delays)
+	}
+}
+
+func TestLimiterStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l := newLimiter(1, 1)
+	l.wait(ctx, 1)
+	if err := l.wait(ctx, 1); err == nil {
+		t.Errorf("Expected an error waiting after being cancelled")
+	}
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// Pairing is a pair to introduce to each other, along with an icebreaker, who schedules the meeting, the topic of
+// the curriculum to discuss, any milestones to celebrate and the notes of the organizers, if known.
+type Pairing struct {
+	People     [2]yapper.ID
+	Icebreaker string
+	Initiator  yapper.ID
+	Topic      string
+	Milestones []yapper.Milestone
+	Notes      []string
+	// LastMet is when the pair last met before the round, zero if they had not met or it is not known.
+	LastMet time.Time
+}
+
+// PairingMessages returns one message per pairing for the round starting on the date, sent to both people together.
+// Pairings are skipped if either person has no address for the provider, and are returned along with the messages.
+// People whose notify channel is none are left out of the messages.
+func PairingMessages(config yapper.Config, provider Provider, date time.Time, pairings []Pairing) ([]Message, []Pairing, error) {
+	routes, skipped, err := RoutePairingMessages(config, []Provider{provider}, date, pairings)
+	if err != nil {
+		return nil, nil, err
+	}
+	return routes[0].Messages, skipped, nil
+}
+
+// RoutePairingMessages returns the messages for the pairings of the round starting on the date like PairingMessages,
+// sending each person's through the provider they are routed to, see Route. The people of a pairing routed to the
+// same provider are sent one message together. Pairings are skipped if either person cannot be reached through any
+// of the providers. The routes are returned in the order of the providers.
+func RoutePairingMessages(config yapper.Config, providers []Provider, date time.Time, pairings []Pairing) ([]Routed, []Pairing, error) {
+	return routeMessages(config, providers, pairings, func(pairing Pairing, recipients []string) (Message, error) {
+		return pairingMessage(config, date, pairing, recipients), nil
+	})
+}
+
+func pairingMessage(config yapper.Config, date time.Time, pairing Pairing, recipients []string) Message {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s and %s, you are paired for the round starting %s.", pairing.People[0], pairing.People[1], date.Format(time.DateOnly))
+
+	if days, err := config.CommonPreferredDays(pairing.People[0], pairing.People[1]); err == nil && len(days) > 0 {
+		names := make([]string, 0, len(days))
+		for _, day := range days {
+			names = append(names, string(day))
+		}
+		fmt.Fprintf(&sb, " You both prefer to meet on %s.", strings.Join(names, ", "))
+	}
+
+	if pairing.Initiator != "" {
+		fmt.Fprintf(&sb, " %s, you schedule this one.", pairing.Initiator)
+	}
+
+	for _, milestone := range pairing.Milestones {
+		fmt.Fprintf(&sb, " It is %s!", milestone)
+	}
+
+	if pairing.Topic != "" {
+		fmt.Fprintf(&sb, "\n\nTopic: %s", pairing.Topic)
+	}
+
+	if pairing.Icebreaker != "" {
+		fmt.Fprintf(&sb, "\n\nIcebreaker: %s", pairing.Icebreaker)
+	}
+
+	for _, note := range pairing.Notes {
+		fmt.Fprintf(&sb, "\n\nNote: %s", note)
+	}
+
+	return Message{
+		Recipients: recipients,
+		Subject:    fmt.Sprintf("yapper pairing for %s", date.Format(time.DateOnly)),
+		Text:       sb.String(),
+	}
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+func TestPairingMessagesSkipsPeopleWithoutAddress(t *testing.T) {
+	config := yapper.Config{People: []yapper.Person{
+		{ID: "Mario", Email: "mario@mushroom.kingdom", PreferredDays: []yapper.Day{"mon", "tue"}},
+		{ID: "Luigi", Email: "luigi@mushroom.kingdom", PreferredDays: []yapper.Day{"tue"}},
+		{ID: "Peach", Email: "peach@mushroom.kingdom"},
+		{ID: "Toad"},
+	}}
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	messages, skipped, err := PairingMessages(config, &recordingProvider{}, date, []Pairing{
+		{People: [2]yapper.ID{"Mario", "Luigi"}, Icebreaker: "Favourite kart?"},
+		{People: [2]yapper.ID{"Peach", "Toad"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating messages: %v", err)
+	}
+
+	expected := []Message{{
+		Recipients: []string{"mario@mushroom.kingdom", "luigi@mushroom.kingdom"},
+		Subject:    "yapper pairing for 2025-01-06",
+		Text:       "Mario and Luigi, you are paired for the round starting 2025-01-06. You both prefer to meet on tue.\n\nIcebreaker: Favourite kart?",
+	}}
+	if !reflect.DeepEqual(expected, messages) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, messages)
+	}
+
+	expectedSkipped := []Pairing{{People: [2]yapper.ID{"Peach", "Toad"}}}
+	if !reflect.DeepEqual(expectedSkipped, skipped) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedSkipped, skipped)
+	}
+}
+
+func TestPairingMessagesNameTheInitiator(t *testing.T) {
+	config := yapper.Config{People: []yapper.Person{{ID: "Mario", Email: "mario@mushroom.kingdom"}, {ID: "Luigi", Email: "luigi@mushroom.kingdom"}}}
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	messages, _, err := PairingMessages(config, &recordingProvider{}, date, []Pairing{{People: [2]yapper.ID{"Mario", "Luigi"}, Initiator: "Luigi"}})
+	if err != nil {
+		t.Fatalf("Unexpected error creating messages: %v", err)
+	}
+
+	expected := "Mario and Luigi, you are paired for the round starting 2025-01-06. Luigi, you schedule this one."
+	if len(messages) != 1 || messages[0].Text != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, messages)
+	}
+}
+
+func TestPairingMessagesIncludeTheNotes(t *testing.T) {
+	config := yapper.Config{People: []yapper.Person{{ID: "Mario", Email: "mario@mushroom.kingdom"}, {ID: "Luigi", Email: "luigi@mushroom.kingdom"}}}
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	pairing := Pairing{People: [2]yapper.ID{"Mario", "Luigi"}, Notes: []string{"both interested in Rust"}}
+
+	messages, _, err := PairingMessages(config, &recordingProvider{}, date, []Pairing{pairing})
+	if err != nil {
+		t.Fatalf("Unexpected error creating messages: %v", err)
+	}
+
+	expected := "Mario and Luigi, you are paired for the round starting 2025-01-06.\n\nNote: both interested in Rust"
+	if len(messages) != 1 || messages[0].Text != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, messages)
+	}
+}
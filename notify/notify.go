@@ -0,0 +1,115 @@
+// Package notify sends messages about pairings through providers such as Slack and email,
+// throttling and batching them to stay within the limits of each provider.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/telemetry"
+)
+
+// Message is sent to every recipient together, e.g. as one email or group message.
+type Message struct {
+	// Recipients are the addresses of the recipients for the provider, such as emails or Slack user IDs.
+	Recipients []string
+	Subject    string
+	Text       string
+}
+
+// Limits describe how quickly a provider accepts messages.
+type Limits struct {
+	// Rate is the number of messages per second which can be sent, or unlimited if zero.
+	Rate float64
+	// Burst is the number of messages which can be sent at once before the rate applies, defaulting to 1.
+	Burst int
+	// BatchSize is the number of messages given to Send at a time, defaulting to 1.
+	BatchSize int
+}
+
+// Provider delivers messages through a service such as Slack or email.
+type Provider interface {
+	// Name identifies the provider in errors.
+	Name() string
+	// Address returns the address of the person for the provider, or an empty string if they cannot be reached.
+	Address(person yapper.Person) string
+	// Limits returns the default limits of the provider.
+	Limits() Limits
+	// Send delivers a batch of at most Limits().BatchSize messages.
+	Send(ctx context.Context, messages []Message) error
+}
+
+// Checker is implemented by providers which can check their credentials without sending any messages.
+type Checker interface {
+	// Check authenticates with the service of the provider, returning an error if it cannot be reached or rejects
+	// the credentials.
+	Check(ctx context.Context) error
+}
+
+// Check checks the credentials of the provider and that its service can be reached, without sending any messages.
+// An error wrapping errors.ErrUnsupported is returned for providers which do not implement Checker.
+func Check(ctx context.Context, provider Provider) error {
+	if limited, ok := provider.(limitedProvider); ok {
+		provider = limited.Provider
+	}
+
+	checker, ok := provider.(Checker)
+	if !ok {
+		return fmt.Errorf("%s cannot be checked: %w", provider.Name(), errors.ErrUnsupported)
+	}
+	if err := checker.Check(ctx); err != nil {
+		return fmt.Errorf("error checking %s: %w", provider.Name(), err)
+	}
+	return nil
+}
+
+// WithLimits overrides the limits of the provider.
+func WithLimits(provider Provider, limits Limits) Provider {
+	return limitedProvider{Provider: provider, limits: limits}
+}
+
+type limitedProvider struct {
+	Provider
+	limits Limits
+}
+
+func (p limitedProvider) Limits() Limits {
+	return p.limits
+}
+
+// Send delivers the messages in batches through the provider, waiting between them to stay within its rate.
+// It returns the number of messages sent, which are those before the batch which failed if there is an error.
+func Send(ctx context.Context, provider Provider, messages []Message) (int, error) {
+	ctx, span := telemetry.Start(ctx, "notify.Send", telemetry.String("yapper.provider", provider.Name()), telemetry.Int("yapper.messages", len(messages)))
+	defer span.End()
+
+	sent, err := send(ctx, provider, messages)
+	span.SetAttributes(telemetry.Int("yapper.sent", sent))
+	span.RecordError(err)
+	telemetry.Add("yapper.notifications.sent", int64(sent), telemetry.String("yapper.provider", provider.Name()))
+	telemetry.Add("yapper.notifications.failed", int64(len(messages)-sent), telemetry.String("yapper.provider", provider.Name()))
+	return sent, err
+}
+
+func send(ctx context.Context, provider Provider, messages []Message) (int, error) {
+	limits := provider.Limits()
+	batchSize := max(limits.BatchSize, 1)
+	limit := newLimiter(limits.Rate, max(limits.Burst, 1))
+
+	sent := 0
+	for sent < len(messages) {
+		batch := messages[sent:min(sent+batchSize, len(messages))]
+		if err := limit.wait(ctx, len(batch)); err != nil {
+			return sent, err
+		}
+
+		if err := provider.Send(ctx, batch); err != nil {
+			return sent, fmt.Errorf("error sending through %s: %w", provider.Name(), err)
+		}
+		sent += len(batch)
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// recordingProvider records the batches it is sent, failing on the batch at failAt if it is set.
+type recordingProvider struct {
+	limits  Limits
+	batches [][]Message
+	failAt  int
+}
+
+func (p *recordingProvider) Name() string                        { return "recording" }
+func (p *recordingProvider) Limits() Limits                      { return p.limits }
+func (p *recordingProvider) Address(person yapper.Person) string { return person.Email }
+
+func (p *recordingProvider) Send(_ context.Context, messages []Message) error {
+	if p.failAt > 0 && len(p.batches)+1 == p.failAt {
+		return errors.New("rate limited")
+	}
+	p.batches = append(p.batches, messages)
+	return nil
+}
+
+func messages(texts ...string) []Message {
+	result := []Message{}
+	for _, text := range texts {
+		result = append(result, Message{Recipients: []string{"mario@mushroom.kingdom"}, Text: text})
+	}
+	return result
+}
+
+func TestSendBatchesMessages(t *testing.T) {
+	provider := &recordingProvider{limits: Limits{BatchSize: 2}}
+
+	sent, err := Send(context.Background(), provider, messages("1", "2", "3", "4", "5"))
+	if err != nil {
+		t.Fatalf("Unexpected error sending: %v", err)
+	}
+
+	if sent != 5 {
+		t.Errorf("Expected 5 messages to be sent, got %d", sent)
+	}
+
+	expected := [][]Message{messages("1", "2"), messages("3", "4"), messages("5")}
+	if !reflect.DeepEqual(expected, provider.batches) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, provider.batches)
+	}
+}
+
+func TestSendStopsAtFailedBatch(t *testing.T) {
+	provider := &recordingProvider{limits: Limits{BatchSize: 2}, failAt: 2}
+
+	sent, err := Send(context.Background(), provider, messages("1", "2", "3", "4", "5"))
+	if err == nil {
+		t.Fatalf("Expected an error sending")
+	}
+
+	if sent != 2 {
+		t.Errorf("Expected 2 messages to be sent before the error, got %d", sent)
+	}
+}
+
+func TestWithLimitsOverridesProvider(t *testing.T) {
+	provider := &recordingProvider{limits: Limits{BatchSize: 1}}
+	limited := WithLimits(provider, Limits{BatchSize: 3})
+
+	if _, err := Send(context.Background(), limited, messages("1", "2", "3")); err != nil {
+		t.Fatalf("Unexpected error sending: %v", err)
+	}
+
+	if len(provider.batches) != 1 {
+		t.Errorf("Expected a single batch, got %d", len(provider.batches))
+	}
+}
+
+func TestCheckReturnsUnsupportedForProvidersWithoutCheck(t *testing.T) {
+	err := Check(context.Background(), WithLimits(&recordingProvider{}, Limits{BatchSize: 3}))
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Expected error %v, got: %v", errors.ErrUnsupported, err)
+	}
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// Routed are the messages to send through one of the providers.
+type Routed struct {
+	Provider Provider
+	Messages []Message
+}
+
+// Route returns the index of the provider the person is notified through, which is the provider named by their
+// notify channel if it has an address for them, and otherwise the first provider which does. -1 is returned if no
+// provider can reach them, or they do not want to be notified.
+func Route(person yapper.Person, providers []Provider) int {
+	if person.Notify == yapper.ChannelNone {
+		return -1
+	}
+
+	preferred := slices.IndexFunc(providers, func(provider Provider) bool {
+		return provider.Name() == string(person.Notify) && provider.Address(person) != ""
+	})
+	if preferred >= 0 {
+		return preferred
+	}
+	return slices.IndexFunc(providers, func(provider Provider) bool { return provider.Address(person) != "" })
+}
+
+// routeMessages returns the messages rendered for the pairings whose people can all be reached, grouped by provider,
+// along with the pairings which were skipped.
+func routeMessages(config yapper.Config, providers []Provider, pairings []Pairing, render func(pairing Pairing, recipients []string) (Message, error)) ([]Routed, []Pairing, error) {
+	routes := make([]Routed, 0, len(providers))
+	for _, provider := range providers {
+		routes = append(routes, Routed{Provider: provider, Messages: []Message{}})
+	}
+	skipped := []Pairing{}
+
+	index := config.Index()
+	for _, pairing := range pairings {
+		recipients := make([][]string, len(providers))
+		reachable := true
+		for _, id := range pairing.People {
+			person, found := index[id]
+			if !found {
+				return nil, nil, fmt.Errorf("error getting person %s: no person with ID %s", id, id)
+			}
+			if person.Notify == yapper.ChannelNone {
+				continue
+			}
+
+			route := Route(person, providers)
+			if route < 0 {
+				reachable = false
+				break
+			}
+			recipients[route] = append(recipients[route], providers[route].Address(person))
+		}
+
+		if !reachable {
+			skipped = append(skipped, pairing)
+			continue
+		}
+
+		for i := range providers {
+			if len(recipients[i]) == 0 {
+				continue
+			}
+			message, err := render(pairing, recipients[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			routes[i].Messages = append(routes[i].Messages, message)
+		}
+	}
+
+	return routes, skipped, nil
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+func TestRoute(t *testing.T) {
+	providers := []Provider{Slack{}, Email{}}
+
+	tests := []struct {
+		name     string
+		person   yapper.Person
+		expected int
+	}{
+		{"first reachable", yapper.Person{ID: "Mario", Slack: "U1", Email: "mario@mushroom.kingdom"}, 0},
+		{"preferred", yapper.Person{ID: "Mario", Slack: "U1", Email: "mario@mushroom.kingdom", Notify: yapper.ChannelEmail}, 1},
+		{"preferred unreachable", yapper.Person{ID: "Mario", Slack: "U1", Notify: yapper.ChannelEmail}, 0},
+		{"unreachable", yapper.Person{ID: "Mario"}, -1},
+		{"none", yapper.Person{ID: "Mario", Slack: "U1", Notify: yapper.ChannelNone}, -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Route(test.person, providers); got != test.expected {
+				t.Errorf("Expected:\n%v\nGot:\n%v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestRoutePairingMessagesSendsThroughEachChannel(t *testing.T) {
+	config := yapper.Config{People: []yapper.Person{
+		{ID: "Mario", Slack: "U1", Email: "mario@mushroom.kingdom"},
+		{ID: "Luigi", Slack: "U2", Email: "luigi@mushroom.kingdom", Notify: yapper.ChannelEmail},
+		{ID: "Peach", Slack: "U3"},
+		{ID: "Toad", Slack: "U4", Notify: yapper.ChannelNone},
+		{ID: "Yoshi"},
+		{ID: "Daisy", Slack: "U6"},
+	}}
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	providers := []Provider{Slack{}, Email{}}
+
+	routes, skipped, err := RoutePairingMessages(config, providers, date, []Pairing{
+		{People: [2]yapper.ID{"Mario", "Luigi"}},
+		{People: [2]yapper.ID{"Peach", "Toad"}},
+		{People: [2]yapper.ID{"Yoshi", "Daisy"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating messages: %v", err)
+	}
+
+	recipients := [][][]string{}
+	for _, route := range routes {
+		routeRecipients := [][]string{}
+		for _, message := range route.Messages {
+			routeRecipients = append(routeRecipients, message.Recipients)
+		}
+		recipients = append(recipients, routeRecipients)
+	}
+
+	expected := [][][]string{{{"U1"}, {"U3"}}, {{"luigi@mushroom.kingdom"}}}
+	if !reflect.DeepEqual(expected, recipients) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, recipients)
+	}
+
+	expectedSkipped := []Pairing{{People: [2]yapper.ID{"Yoshi", "Daisy"}}}
+	if !reflect.DeepEqual(expectedSkipped, skipped) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedSkipped, skipped)
+	}
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// slackAPI is the base URL of the Slack Web API.
+const slackAPI = "https://slack.com/api"
+
+// slackRetries is the number of times a request rate limited by Slack is retried.
+const slackRetries = 3
+
+// Slack sends each message as a direct message to its recipients, using a bot token with the chat:write and im:write scopes.
+type Slack struct {
+	Token string
+	// BaseURL overrides the Slack Web API, e.g. for tests.
+	BaseURL string
+	// Client is used for requests to Slack, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+func (s Slack) Name() string {
+	return "slack"
+}
+
+// Limits follow Slack, which allows posting about one message per second to a workspace.
+func (s Slack) Limits() Limits {
+	return Limits{Rate: 1, Burst: 1, BatchSize: 1}
+}
+
+// Address returns the Slack member ID of the person.
+func (s Slack) Address(person yapper.Person) string {
+	return person.Slack
+}
+
+// Send opens a conversation with the recipients of each message and posts the message to it.
+func (s Slack) Send(ctx context.Context, messages []Message) error {
+	for _, message := range messages {
+		var conversation struct {
+			Channel struct {
+				ID string `json:"id"`
+			} `json:"channel"`
+		}
+		if err := s.call(ctx, "conversations.open", map[string]string{"users": strings.Join(message.Recipients, ",")}, &conversation); err != nil {
+			return err
+		}
+
+		text := message.Text
+		if message.Subject != "" {
+			text = "*" + message.Subject + "*\n" + text
+		}
+		if err := s.call(ctx, "chat.postMessage", map[string]string{"channel": conversation.Channel.ID, "text": text}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check calls auth.test, which only succeeds for a valid token.
+func (s Slack) Check(ctx context.Context) error {
+	return s.call(ctx, "auth.test", map[string]string{}, nil)
+}
+
+// call posts the request to the Slack method, waiting and retrying when Slack responds that it is rate limited.
+func (s Slack) call(ctx context.Context, method string, request any, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error marshalling Slack request: %w", err)
+	}
+
+	baseURL := s.BaseURL
+	if baseURL == "" {
+		baseURL = slackAPI
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/"+method, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("error creating Slack request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling Slack %s: %w", method, err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < slackRetries {
+			resp.Body.Close()
+			delay, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+			if err != nil {
+				delay = 1
+			}
+			if err := sleepContext(ctx, time.Duration(delay)*time.Second); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var result struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		var raw json.RawMessage
+		err = json.NewDecoder(resp.Body).Decode(&raw)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error decoding Slack %s response with status %d: %w", method, resp.StatusCode, err)
+		}
+
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return fmt.Errorf("error decoding Slack %s response: %w", method, err)
+		}
+		if !result.OK {
+			if result.Error == "" {
+				return fmt.Errorf("error calling Slack %s: status %d", method, resp.StatusCode)
+			}
+			return fmt.Errorf("error calling Slack %s: %w", method, errors.New(result.Error))
+		}
+
+		if response != nil {
+			if err := json.Unmarshal(raw, response); err != nil {
+				return fmt.Errorf("error decoding Slack %s response: %w", method, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// fakeSlack records the messages posted to each conversation, rate limiting the first request if limited is set.
+func fakeSlack(t *testing.T, limited bool) (*httptest.Server, *[]map[string]string) {
+	t.Helper()
+	posted := &[]map[string]string{}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /conversations.open", func(w http.ResponseWriter, r *http.Request) {
+		if limited {
+			limited = false
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		var request map[string]string
+		json.NewDecoder(r.Body).Decode(&request)
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "channel": map[string]string{"id": "D-" + request["users"]}})
+	})
+
+	mux.HandleFunc("POST /chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_auth"})
+			return
+		}
+
+		var request map[string]string
+		json.NewDecoder(r.Body).Decode(&request)
+		*posted = append(*posted, request)
+		json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	})
+
+	mux.HandleFunc("POST /auth.test", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_auth"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, posted
+}
+
+func TestSlackPostsToConversation(t *testing.T) {
+	server, posted := fakeSlack(t, true)
+	slack := Slack{Token: "xoxb-test", BaseURL: server.URL}
+
+	err := slack.Send(context.Background(), []Message{{Recipients: []string{"U1", "U2"}, Subject: "Paired", Text: "Say hi"}})
+	if err != nil {
+		t.Fatalf("Unexpected error sending: %v", err)
+	}
+
+	expected := []map[string]string{{"channel": "D-U1,U2", "text": "*Paired*\nSay hi"}}
+	if !reflect.DeepEqual(expected, *posted) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, *posted)
+	}
+}
+
+func TestSlackReportsErrors(t *testing.T) {
+	server, _ := fakeSlack(t, false)
+	slack := Slack{Token: "wrong", BaseURL: server.URL}
+
+	err := slack.Send(context.Background(), []Message{{Recipients: []string{"U1"}, Text: "Say hi"}})
+	if err == nil || err.Error() != "error calling Slack chat.postMessage: invalid_auth" {
+		t.Errorf("Expected the Slack error to be reported, got %v", err)
+	}
+}
+
+func TestSlackCheckAuthenticatesToken(t *testing.T) {
+	server, posted := fakeSlack(t, false)
+
+	if err := Check(context.Background(), Slack{Token: "xoxb-test", BaseURL: server.URL}); err != nil {
+		t.Errorf("Unexpected error checking: %v", err)
+	}
+	if err := Check(context.Background(), Slack{Token: "wrong", BaseURL: server.URL}); err == nil {
+		t.Error("Expected error checking an invalid token")
+	}
+
+	if len(*posted) != 0 {
+		t.Errorf("Expected no messages to be posted, got %v", *posted)
+	}
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// TemplateData is what the templates of a Template are executed with for each pairing.
+type TemplateData struct {
+	Pairing
+	// Date is the start of the round.
+	Date time.Time
+	// PreferredDays are the days suiting both people, nil if neither has a preference.
+	PreferredDays []yapper.Day
+}
+
+// Template renders the subject and text of pairing messages from text/template templates, in place of the built-in
+// messages. Besides the TemplateData, the templates can call these functions of the people of a pairing, which
+// describe the meetings of the pair before the round:
+//
+//	daysSinceLastMeeting .People  the days since the pair last met, 0 if they have not met
+//	timesMet .People              the number of times the pair has met
+//	firstMeeting .People          whether the pair has never met
+type Template struct {
+	subject *template.Template
+	text    *template.Template
+}
+
+// templateFuncs are the functions of the templates, answered from the history as of the round starting on date.
+// The history may already include the round, in which case the last meetings before it are taken from the pairings.
+func templateFuncs(hist history.History, date time.Time, pairings []Pairing) template.FuncMap {
+	lastMet := map[[2]yapper.ID]time.Time{}
+	for _, pairing := range pairings {
+		if !pairing.LastMet.IsZero() {
+			lastMet[sortedPeople(pairing.People)] = pairing.LastMet
+		}
+	}
+
+	timesMet := func(people [2]yapper.ID) int {
+		last, met := hist.GetPersonToLastMeetingMap(history.ID(people[0]))[history.ID(people[1])]
+		times := hist.TimesMet(history.ID(people[0]), history.ID(people[1]))
+		if met && !last.Before(date) {
+			times--
+		}
+		return times
+	}
+
+	return template.FuncMap{
+		"daysSinceLastMeeting": func(people [2]yapper.ID) int {
+			last, met := lastMet[sortedPeople(people)]
+			if !met {
+				last, met = hist.GetPersonToLastMeetingMap(history.ID(people[0]))[history.ID(people[1])]
+			}
+			if !met || !last.Before(date) {
+				return 0
+			}
+			return int(date.Sub(last).Hours() / 24)
+		},
+		"timesMet": timesMet,
+		"firstMeeting": func(people [2]yapper.ID) bool {
+			return timesMet(people) == 0
+		},
+	}
+}
+
+// NewTemplate parses the templates of the subject and text of the messages, returning an error if either cannot be
+// parsed. The subject of the built-in messages is used if the subject is empty.
+func NewTemplate(subject, text string) (*Template, error) {
+	if subject == "" {
+		subject = "yapper pairing for {{.Date.Format \"2006-01-02\"}}"
+	}
+
+	funcs := templateFuncs(history.History{}, time.Time{}, nil)
+	subjectTemplate, err := template.New("subject").Funcs(funcs).Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing subject template: %w", err)
+	}
+	textTemplate, err := template.New("text").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing text template: %w", err)
+	}
+	return &Template{subject: subjectTemplate, text: textTemplate}, nil
+}
+
+// PairingMessages returns one message per pairing like the package level PairingMessages, rendered from the
+// templates with the functions answered from the history.
+func (t *Template) PairingMessages(config yapper.Config, provider Provider, date time.Time, pairings []Pairing, hist history.History) ([]Message, []Pairing, error) {
+	routes, skipped, err := t.RoutePairingMessages(config, []Provider{provider}, date, pairings, hist)
+	if err != nil {
+		return nil, nil, err
+	}
+	return routes[0].Messages, skipped, nil
+}
+
+// RoutePairingMessages returns the messages routed to the providers like the package level RoutePairingMessages,
+// rendered from the templates with the functions answered from the history.
+func (t *Template) RoutePairingMessages(config yapper.Config, providers []Provider, date time.Time, pairings []Pairing, hist history.History) ([]Routed, []Pairing, error) {
+	funcs := templateFuncs(hist, date, pairings)
+	subjectTemplate, err := t.subject.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	textTemplate, err := t.text.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	subjectTemplate.Funcs(funcs)
+	textTemplate.Funcs(funcs)
+
+	return routeMessages(config, providers, pairings, func(pairing Pairing, recipients []string) (Message, error) {
+		data := TemplateData{Pairing: pairing, Date: date}
+		if days, err := config.CommonPreferredDays(pairing.People[0], pairing.People[1]); err == nil {
+			data.PreferredDays = days
+		}
+
+		var subject, text bytes.Buffer
+		if err := subjectTemplate.Execute(&subject, data); err != nil {
+			return Message{}, fmt.Errorf("error rendering subject of %s & %s: %w", pairing.People[0], pairing.People[1], err)
+		}
+		if err := textTemplate.Execute(&text, data); err != nil {
+			return Message{}, fmt.Errorf("error rendering text of %s & %s: %w", pairing.People[0], pairing.People[1], err)
+		}
+		return Message{Recipients: recipients, Subject: subject.String(), Text: text.String()}, nil
+	})
+}
+
+func sortedPeople(people [2]yapper.ID) [2]yapper.ID {
+	if people[1] < people[0] {
+		return [2]yapper.ID{people[1], people[0]}
+	}
+	return people
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestTemplatePairingMessagesDescribeEarlierMeetings(t *testing.T) {
+	config := yapper.Config{People: []yapper.Person{
+		{ID: "Mario", Email: "mario@mushroom.kingdom"},
+		{ID: "Luigi", Email: "luigi@mushroom.kingdom"},
+		{ID: "Peach", Email: "peach@mushroom.kingdom"},
+		{ID: "Toad", Email: "toad@mushroom.kingdom"},
+	}}
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -200))
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -94))
+
+	tmpl, err := NewTemplate("", `{{index .People 0}} and {{index .People 1}}: {{if firstMeeting .People}}first meeting{{else}}met {{timesMet .People}} times, last {{daysSinceLastMeeting .People}} days ago{{end}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing template: %v", err)
+	}
+
+	messages, _, err := tmpl.PairingMessages(config, &recordingProvider{}, date, []Pairing{
+		{People: [2]yapper.ID{"Mario", "Luigi"}},
+		{People: [2]yapper.ID{"Peach", "Toad"}},
+	}, hist)
+	if err != nil {
+		t.Fatalf("Unexpected error creating messages: %v", err)
+	}
+
+	expected := []Message{
+		{Recipients: []string{"mario@mushroom.kingdom", "luigi@mushroom.kingdom"}, Subject: "yapper pairing for 2025-01-06", Text: "Mario and Luigi: met 2 times, last 94 days ago"},
+		{Recipients: []string{"peach@mushroom.kingdom", "toad@mushroom.kingdom"}, Subject: "yapper pairing for 2025-01-06", Text: "Peach and Toad: first meeting"},
+	}
+	if !reflect.DeepEqual(expected, messages) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, messages)
+	}
+}
+
+func TestTemplateFuncsLeaveOutTheRecordedRound(t *testing.T) {
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -94))
+	hist.AddMeeting("Mario", "Luigi", date)
+	hist.AddMeeting("Peach", "Toad", date)
+	people := [2]yapper.ID{"Mario", "Luigi"}
+	funcs := templateFuncs(hist, date, []Pairing{{People: people, LastMet: date.AddDate(0, 0, -94)}})
+
+	if times := funcs["timesMet"].(func([2]yapper.ID) int)(people); times != 1 {
+		t.Errorf("Expected Mario and Luigi to have met once before the round, got %d", times)
+	}
+	if days := funcs["daysSinceLastMeeting"].(func([2]yapper.ID) int)(people); days != 94 {
+		t.Errorf("Expected Mario and Luigi to have last met 94 days before the round, got %d", days)
+	}
+	if !funcs["firstMeeting"].(func([2]yapper.ID) bool)([2]yapper.ID{"Toad", "Peach"}) {
+		t.Errorf("Expected the round to be the first meeting of Peach and Toad")
+	}
+}
+
+func TestNewTemplateReturnsErrorForInvalidTemplate(t *testing.T) {
+	if _, err := NewTemplate("", "{{if}}"); err == nil {
+		t.Errorf("Expected error due to an invalid template")
+	}
+}
@@ -0,0 +1,59 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Onboarding pairs new starters within their cohort, everyone who started in the same month, for their first weeks
+// before they graduate into the general pool.
+type Onboarding struct {
+	// Weeks is how long after their start date people are only paired within their cohort.
+	Weeks int `json:"weeks"`
+}
+
+func (o Onboarding) validate() error {
+	if o.Weeks <= 0 {
+		return fmt.Errorf("onboarding weeks must be positive: %d", o.Weeks)
+	}
+	return nil
+}
+
+// onboardingCohorts returns the cohort of each person still onboarding in the round starting on date, the month of
+// their start date. No one is onboarding without the onboarding of the config.
+func (c Config) onboardingCohorts(date time.Time) map[ID]string {
+	if c.Onboarding == nil {
+		return nil
+	}
+
+	cohorts := map[ID]string{}
+	for _, person := range c.People {
+		if person.StartDate == nil || !date.Before(person.StartDate.AddDate(0, 0, 7*c.Onboarding.Weeks)) {
+			continue
+		}
+		cohorts[person.ID] = person.StartDate.Format("2006-01")
+	}
+	return cohorts
+}
+
+// onboardingDenied reports whether the people cannot be paired as they are not in the same onboarding cohort, where
+// people who are not onboarding are in the general pool.
+func onboardingDenied(cohorts map[ID]string, id1, id2 ID) bool {
+	return cohorts[id1] != cohorts[id2]
+}
+
+// restrictToCohorts returns the valid pairings with the pairs of people in different onboarding cohorts removed.
+func restrictToCohorts(cohorts map[ID]string, idToValidPairings map[ID][]ID) map[ID][]ID {
+	if len(cohorts) == 0 {
+		return idToValidPairings
+	}
+
+	restricted := make(map[ID][]ID, len(idToValidPairings))
+	for id, validPairings := range idToValidPairings {
+		restricted[id] = slices.DeleteFunc(slices.Clone(validPairings), func(other ID) bool {
+			return onboardingDenied(cohorts, id, other)
+		})
+	}
+	return restricted
+}
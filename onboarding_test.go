@@ -0,0 +1,81 @@
+package yapper
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestOnboardingValidateReturnsErrorForNoWeeks(t *testing.T) {
+	if err := (Onboarding{}).validate(); err == nil {
+		t.Errorf("Expected error due to onboarding without any weeks")
+	}
+}
+
+func TestOnboardingCohortsGraduateAfterTheirWeeks(t *testing.T) {
+	august := NewDate(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+	lateAugust := NewDate(time.Date(2025, time.August, 25, 0, 0, 0, 0, time.UTC))
+	september := NewDate(time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC))
+	config := Config{
+		Onboarding: &Onboarding{Weeks: 4},
+		People: []Person{
+			{ID: "Mario", StartDate: &august},
+			{ID: "Luigi", StartDate: &lateAugust},
+			{ID: "Peach", StartDate: &september},
+			{ID: "Toad"},
+		},
+	}
+
+	cohorts := config.onboardingCohorts(time.Date(2025, time.September, 8, 0, 0, 0, 0, time.UTC))
+
+	expected := map[ID]string{"Luigi": "2025-08", "Peach": "2025-09"}
+	if !reflect.DeepEqual(expected, cohorts) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, cohorts)
+	}
+}
+
+func TestPairPeoplePairsOnboardingPeopleWithinTheirCohort(t *testing.T) {
+	date := time.Date(2025, time.August, 18, 0, 0, 0, 0, time.UTC)
+	start := NewDate(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+	config := Config{
+		Onboarding: &Onboarding{Weeks: 4},
+		People: []Person{
+			{ID: "Mario", StartDate: &start},
+			{ID: "Luigi"},
+			{ID: "Peach"},
+			{ID: "Toad", StartDate: &start},
+		},
+	}
+
+	for range 10 {
+		pairings := pairPeople(config, determineValidPairings(config), history.History{}, date)
+
+		expected := [][2]ID{{"Luigi", "Peach"}, {"Mario", "Toad"}}
+		if pairs := sortedPairs(pairings.data); !slices.Equal(expected, pairs) {
+			t.Fatalf("Expected:\n%v\nGot:\n%v", expected, pairs)
+		}
+	}
+}
+
+func TestValidatePairingsReportsPairsOutsideOnboardingCohort(t *testing.T) {
+	date := time.Date(2025, time.August, 18, 0, 0, 0, 0, time.UTC)
+	start := NewDate(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC))
+	config := Config{
+		Onboarding: &Onboarding{Weeks: 4},
+		People:     []Person{{ID: "Mario", StartDate: &start}, {ID: "Luigi"}},
+	}
+
+	violations := ValidatePairings(config, NewPairings(date, [][2]ID{{"Mario", "Luigi"}}))
+
+	expected := []Violation{newViolation(ViolationOnboarding, "Mario", "Luigi")}
+	if !reflect.DeepEqual(expected, violations) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, violations)
+	}
+
+	if violations := ValidatePairings(config, NewPairings(date.AddDate(0, 0, 14), [][2]ID{{"Mario", "Luigi"}})); len(violations) != 0 {
+		t.Errorf("Expected Mario to have graduated from onboarding, got %v", violations)
+	}
+}
@@ -0,0 +1,41 @@
+package yapper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Organizer is someone running the program, who is sent a digest of each notify run. Organizers do not need to be
+// among the people who are paired.
+type Organizer struct {
+	ID    ID     `json:"id"`
+	Email string `json:"email,omitempty"`
+	Slack string `json:"slack,omitempty"`
+	// Notify is the channel the digest is sent through when several are used at once.
+	Notify Channel `json:"notify,omitempty"`
+}
+
+// Person returns the organizer as a person, so they can be addressed like the people who are paired.
+func (o Organizer) Person() Person {
+	return Person{ID: o.ID, Email: o.Email, Slack: o.Slack, Notify: o.Notify}
+}
+
+func (c Config) validateOrganizers() error {
+	ids := map[ID]bool{}
+	for _, organizer := range c.Organizers {
+		if organizer.ID == "" {
+			return errors.New("organizers require an id")
+		}
+		if ids[organizer.ID] {
+			return fmt.Errorf("duplicate organizer: %s", organizer.ID)
+		}
+		ids[organizer.ID] = true
+
+		switch organizer.Notify {
+		case "", ChannelSlack, ChannelEmail, ChannelNone:
+		default:
+			return fmt.Errorf("invalid notify channel for organizer %s: %s", organizer.ID, organizer.Notify)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package yapper
+
+import "testing"
+
+func TestValidateReturnsErrorForDuplicateOrganizer(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}}, Organizers: []Organizer{{ID: "Peach"}, {ID: "Peach"}}}
+
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an error for the duplicate organizer")
+	}
+}
+
+func TestValidateReturnsErrorForOrganizerWithoutID(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}}, Organizers: []Organizer{{Email: "peach@mushroom.kingdom"}}}
+
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an error for the organizer without an id")
+	}
+}
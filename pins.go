@@ -0,0 +1,136 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Pin forces two people to be paired, either in every round or only in the round containing the date.
+// A pin is skipped when the pairing is not valid or either person is not meeting in the round.
+type Pin struct {
+	People [2]ID `json:"people"`
+	Date   *Date `json:"date,omitempty"`
+	// Note is a note of the organizers about the pair, e.g. "both interested in Rust", shown alongside the pairing and
+	// in notifications.
+	Note string `json:"note,omitempty"`
+}
+
+func (p Pin) validate(conf Config) error {
+	if p.People[0] == p.People[1] {
+		return fmt.Errorf("pin pairs %s with themselves", p.People[0])
+	}
+
+	for _, id := range p.People {
+		if _, err := conf.GetPerson(id); err != nil {
+			return fmt.Errorf("pin refers to an unknown person: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// appliesTo reports whether the pin applies to the round starting on date.
+func (p Pin) appliesTo(conf Config, date time.Time) bool {
+	if p.Date == nil {
+		return true
+	}
+
+	roundStart := NewDate(date)
+	return p.Date.Within(roundStart, NewDate(date.AddDate(0, 0, conf.RoundInterval()-1)))
+}
+
+// pinnedPairings returns the pins applying to the round which can be paired, skipping anyone already paired by an earlier pin.
+func pinnedPairings(conf Config, idToValidPairings map[ID][]ID, ineligible []ID, date time.Time) [][2]ID {
+	var pinned [][2]ID
+	paired := slices.Clone(ineligible)
+
+	for _, pin := range conf.Pins {
+		id1, id2 := pin.People[0], pin.People[1]
+		if !pin.appliesTo(conf, date) || slices.Contains(paired, id1) || slices.Contains(paired, id2) ||
+			!slices.Contains(idToValidPairings[id1], id2) {
+			continue
+		}
+
+		pinned = append(pinned, [2]ID{id1, id2})
+		paired = append(paired, id1, id2)
+	}
+
+	return pinned
+}
+
+// SkippedPin is a pin applying to a round which was not paired in it, with why it was skipped.
+type SkippedPin struct {
+	People [2]ID `json:"people"`
+	// Reason is the constraint which kept one of the people from meeting in the round, such as their cadence or an
+	// absence. It is empty if both could meet but cannot be paired together, or one was paired by an earlier pin.
+	Reason ViolationKind `json:"reason,omitempty"`
+	// Denial is the constraint which kept the people from being paired together, such as a deny list or their
+	// onboarding cohorts, when both could meet.
+	Denial  ViolationKind `json:"denial,omitempty"`
+	Message string        `json:"message"`
+}
+
+// skippedPins returns the pins applying to the round starting on date which pinnedPairings skips.
+func skippedPins(conf Config, idToValidPairings map[ID][]ID, date time.Time) []SkippedPin {
+	ineligible := getIneligiblePeople(conf, idToValidPairings, date)
+	pinned := pinnedPairings(conf, idToValidPairings, ineligible, date)
+	index := conf.Index()
+	quorum := conf.quorumOf(date)
+
+	skipped := []SkippedPin{}
+	for _, pin := range conf.Pins {
+		if !pin.appliesTo(conf, date) || slices.Contains(pinned, pin.People) {
+			continue
+		}
+		skipped = append(skipped, skipPin(conf, index, quorum, idToValidPairings, pinned, pin, date))
+	}
+	return skipped
+}
+
+// skipPin explains why the pin was skipped in the round starting on date, given the quorum and pinned pairs of the
+// round.
+func skipPin(conf Config, index map[ID]Person, quorum roundQuorum, idToValidPairings map[ID][]ID, pinned [][2]ID, pin Pin, date time.Time) SkippedPin {
+	id1, id2 := pin.People[0], pin.People[1]
+	for _, id := range pin.People {
+		person, found := index[id]
+		if !found {
+			continue
+		}
+		if kind := conf.ineligibility(person, date, quorum); kind != "" {
+			message := fmt.Sprintf("the pin of %s and %s was skipped as %s", id1, id2, newViolation(kind, id).Message)
+			return SkippedPin{People: pin.People, Reason: kind, Message: message}
+		}
+	}
+
+	if !slices.Contains(idToValidPairings[id1], id2) {
+		kind := conf.Denial(id1, id2)
+		if kind == "" {
+			kind = ViolationOnboarding
+		}
+		message := fmt.Sprintf("the pin of %s and %s was skipped as %s", id1, id2, newViolation(kind, id1, id2).Message)
+		return SkippedPin{People: pin.People, Denial: kind, Message: message}
+	}
+
+	for _, earlier := range pinned {
+		for _, id := range pin.People {
+			if partner, found := pairedIn(earlier, id); found {
+				message := fmt.Sprintf("the pin of %s and %s was skipped as %s was paired with %s by an earlier pin", id1, id2, id, partner)
+				return SkippedPin{People: pin.People, Message: message}
+			}
+		}
+	}
+	return SkippedPin{People: pin.People, Message: fmt.Sprintf("the pin of %s and %s was skipped as one of them was paired by an earlier pin", id1, id2)}
+}
+
+// pairedIn returns the partner of the person in the pair, if they are in it.
+func pairedIn(pair [2]ID, id ID) (ID, bool) {
+	switch id {
+	case pair[0]:
+		return pair[1], true
+	case pair[1]:
+		return pair[0], true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,119 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestPinValidateReturnsErrorForUnknownPerson(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}}}
+	pin := Pin{People: [2]ID{"Mario", "Bowser"}}
+
+	if err := pin.validate(config); err == nil {
+		t.Errorf("Expected error due to pin referring to an unknown person")
+	}
+}
+
+func TestPinValidateReturnsErrorForPairingWithThemselves(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}}}
+	pin := Pin{People: [2]ID{"Mario", "Mario"}}
+
+	if err := pin.validate(config); err == nil {
+		t.Errorf("Expected error due to pin pairing someone with themselves")
+	}
+}
+
+func TestPinAppliesToRoundContainingDate(t *testing.T) {
+	config := Config{}
+	roundStart := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	pinDate := NewDate(roundStart.AddDate(0, 0, 3))
+	pin := Pin{People: [2]ID{"Mario", "Luigi"}, Date: &pinDate}
+
+	if !pin.appliesTo(config, roundStart) {
+		t.Errorf("Expected pin to apply to the round containing its date")
+	}
+
+	if pin.appliesTo(config, roundStart.AddDate(0, 0, 7)) {
+		t.Errorf("Expected pin not to apply to the following round")
+	}
+}
+
+func TestPairPeoplePairsPinnedPeople(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}},
+		Pins:   []Pin{{People: [2]ID{"Mario", "Toad"}}},
+	}
+
+	for _, strategy := range Strategies {
+		config.Settings.Strategy = strategy
+		hist := history.History{}
+		hist.AddMeeting("Mario", "Toad", date.AddDate(0, 0, -7))
+
+		weeklyPairings, err := generateForDates(config, &hist, []time.Time{date})
+		if err != nil {
+			t.Fatalf("Unexpected error generating pairings: %v", err)
+		}
+
+		pinned := false
+		for id1, id2 := range weeklyPairings[0].All() {
+			if pairKey(id1, id2) == pairKey("Mario", "Toad") {
+				pinned = true
+			}
+		}
+
+		if !pinned {
+			t.Errorf("Expected the %s strategy to pair Mario and Toad, got %v", strategy, weeklyPairings[0].data)
+		}
+	}
+}
+
+func TestPairPeopleSkipsInvalidPin(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{{ID: "Mario", DenyList: []ID{"Luigi"}}, {ID: "Luigi"}},
+		Pins:   []Pin{{People: [2]ID{"Mario", "Luigi"}}},
+	}
+
+	pairings := pairPeople(config, determineValidPairings(config), history.History{}, date)
+
+	if len(pairings.data) != 0 {
+		t.Errorf("Expected the denied pin to be skipped, got %v", pairings.data)
+	}
+}
+
+func TestSkippedPinsExplainsWhyEachPinWasSkipped(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{
+			{ID: "Mario"},
+			{ID: "Luigi", Cadence: CadenceTwoWeeks},
+			{ID: "Peach", DenyList: []ID{"Toad"}},
+			{ID: "Toad"},
+			{ID: "Yoshi"},
+		},
+		Pins: []Pin{
+			{People: [2]ID{"Mario", "Yoshi"}},
+			{People: [2]ID{"Mario", "Luigi"}},
+			{People: [2]ID{"Peach", "Toad"}},
+			{People: [2]ID{"Yoshi", "Toad"}},
+		},
+	}
+	if isValidWeekForTwoWeekCadence(date, config.RoundInterval()) {
+		date = date.AddDate(0, 0, 7)
+	}
+
+	skipped := skippedPins(config, determineValidPairings(config), date)
+
+	expected := []SkippedPin{
+		{People: [2]ID{"Mario", "Luigi"}, Reason: ViolationCadence, Message: "the pin of Mario and Luigi was skipped as Luigi does not meet this round on a two week cadence"},
+		{People: [2]ID{"Peach", "Toad"}, Denial: ViolationDenyList, Message: "the pin of Peach and Toad was skipped as Peach and Toad are denied by a deny list"},
+		{People: [2]ID{"Yoshi", "Toad"}, Message: "the pin of Yoshi and Toad was skipped as Yoshi was paired with Mario by an earlier pin"},
+	}
+	if !reflect.DeepEqual(expected, skipped) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, skipped)
+	}
+}
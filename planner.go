@@ -0,0 +1,340 @@
+package yapper
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// The costs which the planned strategy minimises across all of the rounds.
+// Leaving someone unpaired is worse than a repeat, which is worse than not meeting someone new.
+const (
+	planUnpairedCost = 1000.0
+	planRepeatCost   = 100.0
+	planNewPairBonus = 10.0
+	// planMaxStalenessDays caps the bonus for pairing people who last met a long time ago.
+	planMaxStalenessDays = 365.0
+	// planMaxPasses bounds the number of passes over the plan when searching for improvements.
+	planMaxPasses = 50
+)
+
+// PlanScore compares the cost of a plan made by the planned strategy with the lowest cost any plan could have,
+// lower costs being better.
+type PlanScore struct {
+	Cost float64 `json:"cost"`
+	// Bound is a lower bound on the cost of any plan for the same rounds, which may not be achievable.
+	Bound float64 `json:"bound"`
+	// Stopped is whether the search for improvements was stopped by the max duration before it finished.
+	Stopped bool `json:"stopped,omitempty"`
+}
+
+// plan holds the pairings of every round being planned along with the people left unpaired.
+type plan struct {
+	conf       Config
+	people     map[ID]Person
+	hist       history.History
+	validPairs map[ID]map[ID]bool
+	rounds     []planRound
+}
+
+type planRound struct {
+	date     time.Time
+	pairs    [][2]ID
+	unpaired []ID
+	// pinned are the pairs which are not moved, see Config.Pins.
+	pinned map[[2]ID]bool
+	// cohorts are the onboarding cohorts of the people still onboarding in the round, see Config.Onboarding.
+	cohorts map[ID]string
+	// recent are the squads or locations the people recently met in the history, for the diversity penalty.
+	recent partnerValues
+}
+
+func (r planRound) isPinned(index int) bool {
+	return r.pinned[pairKey(r.pairs[index][0], r.pairs[index][1])]
+}
+
+// generatePlanned starts from the greedy pairings and then improves them by swapping partners within each round,
+// as long as the cost of the whole plan decreases. It stops with the error of the context once it is done.
+// Once the max duration of the config passes, the best plan found so far is returned instead.
+func generatePlanned(ctx context.Context, config Config, idToValidPairings map[ID][]ID, hist history.History, dates []time.Time) ([]Pairings, error) {
+	var deadline time.Time
+	if maxDuration := config.maxDuration(); maxDuration > 0 {
+		deadline = time.Now().Add(maxDuration)
+	}
+
+	p, err := newPlan(ctx, config, idToValidPairings, hist, dates)
+	if err != nil {
+		return nil, err
+	}
+	stopped, err := p.improve(ctx, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	score := &PlanScore{Cost: p.cost(), Bound: p.bound(), Stopped: stopped}
+	weeklyPairings := p.pairings()
+	for i := range weeklyPairings {
+		weeklyPairings[i].score = score
+	}
+	return weeklyPairings, nil
+}
+
+func newPlan(ctx context.Context, config Config, idToValidPairings map[ID][]ID, hist history.History, dates []time.Time) (*plan, error) {
+	greedy, err := generateGreedy(ctx, config, idToValidPairings, hist, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &plan{
+		conf:       config,
+		people:     config.Index(),
+		hist:       hist,
+		validPairs: make(map[ID]map[ID]bool, len(idToValidPairings)),
+	}
+
+	for id, validPairings := range idToValidPairings {
+		p.validPairs[id] = make(map[ID]bool, len(validPairings))
+		for _, other := range validPairings {
+			p.validPairs[id][other] = true
+		}
+	}
+
+	for _, pairings := range greedy {
+		round := planRound{date: pairings.Date(), pairs: slices.Clone(pairings.data), pinned: map[[2]ID]bool{}, cohorts: config.onboardingCohorts(pairings.Date())}
+		round.recent = recentPartnerValues(config, p.people, hist, round.date, config.People)
+		ineligible := getIneligiblePeople(config, idToValidPairings, round.date)
+		for _, pin := range pinnedPairings(config, idToValidPairings, ineligible, round.date) {
+			round.pinned[pairKey(pin[0], pin[1])] = true
+		}
+
+		for id := range idToValidPairings {
+			paired := slices.ContainsFunc(round.pairs, func(pair [2]ID) bool { return pair[0] == id || pair[1] == id })
+			if !paired && !slices.Contains(ineligible, id) {
+				round.unpaired = append(round.unpaired, id)
+			}
+		}
+		slices.Sort(round.unpaired)
+
+		p.rounds = append(p.rounds, round)
+	}
+
+	return p, nil
+}
+
+// improve repeatedly applies the first move found which reduces the cost of the plan without going further over the
+// squad limits, stopping with the error of the context once it is done. The plan is left as the best found so far if
+// the deadline passes, if it is not zero, which is reported by returning true.
+func (p *plan) improve(ctx context.Context, deadline time.Time) (bool, error) {
+	cost := p.cost()
+	for range planMaxPasses {
+		improved := false
+		for i := range p.rounds {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				return true, nil
+			}
+			excess := newSquadCountsOf(p.conf, p.rounds[i].pairs).excess()
+			for _, move := range p.moves(i) {
+				undo := move()
+				newExcess := newSquadCountsOf(p.conf, p.rounds[i].pairs).excess()
+				if newExcess > excess {
+					undo()
+				} else if newCost := p.cost(); newCost < cost {
+					cost, excess = newCost, newExcess
+					improved = true
+				} else {
+					undo()
+				}
+			}
+		}
+
+		if !improved {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// moves returns the possible changes to a round, each of which returns a function undoing the change.
+// A move which would create an invalid pairing does nothing, and pinned pairs are never changed.
+func (p *plan) moves(roundIndex int) []func() func() {
+	round := &p.rounds[roundIndex]
+	var moves []func() func()
+
+	for i := range round.pairs {
+		if round.isPinned(i) {
+			continue
+		}
+
+		for j := i + 1; j < len(round.pairs); j++ {
+			if round.isPinned(j) {
+				continue
+			}
+
+			for _, crossed := range []bool{false, true} {
+				moves = append(moves, func() func() {
+					a, b := round.pairs[i][0], round.pairs[i][1]
+					c, d := round.pairs[j][0], round.pairs[j][1]
+					if crossed {
+						c, d = d, c
+					}
+
+					if !p.valid(round, a, c) || !p.valid(round, b, d) {
+						return func() {}
+					}
+
+					oldI, oldJ := round.pairs[i], round.pairs[j]
+					round.pairs[i], round.pairs[j] = [2]ID{a, c}, [2]ID{b, d}
+					return func() { round.pairs[i], round.pairs[j] = oldI, oldJ }
+				})
+			}
+		}
+
+		for u := range round.unpaired {
+			for member := range 2 {
+				moves = append(moves, func() func() {
+					kept, replaced := round.pairs[i][1-member], round.pairs[i][member]
+					unpaired := round.unpaired[u]
+					if !p.valid(round, kept, unpaired) {
+						return func() {}
+					}
+
+					oldPair := round.pairs[i]
+					round.pairs[i] = [2]ID{kept, unpaired}
+					round.unpaired[u] = replaced
+					return func() {
+						round.pairs[i] = oldPair
+						round.unpaired[u] = unpaired
+					}
+				})
+			}
+		}
+	}
+
+	for u := range round.unpaired {
+		for v := u + 1; v < len(round.unpaired); v++ {
+			moves = append(moves, func() func() {
+				if u >= len(round.unpaired) || v >= len(round.unpaired) {
+					return func() {}
+				}
+
+				id1, id2 := round.unpaired[u], round.unpaired[v]
+				if !p.valid(round, id1, id2) {
+					return func() {}
+				}
+
+				oldUnpaired := round.unpaired
+				round.pairs = append(round.pairs, [2]ID{id1, id2})
+				round.unpaired = slices.DeleteFunc(slices.Clone(round.unpaired), func(id ID) bool { return id == id1 || id == id2 })
+				return func() {
+					round.pairs = round.pairs[:len(round.pairs)-1]
+					round.unpaired = oldUnpaired
+				}
+			})
+		}
+	}
+
+	return moves
+}
+
+// valid reports whether the people can be paired in the round.
+func (p *plan) valid(round *planRound, id1, id2 ID) bool {
+	return p.validPairs[id1][id2] && !onboardingDenied(round.cohorts, id1, id2)
+}
+
+// cost of the plan, taking into account the meetings earlier in the plan when considering later rounds.
+func (p *plan) cost() float64 {
+	cost := 0.0
+	plannedMeetings := map[[2]ID]time.Time{}
+
+	for _, round := range p.rounds {
+		cost += planUnpairedCost * float64(len(round.unpaired))
+
+		for _, pair := range round.pairs {
+			key := pairKey(pair[0], pair[1])
+			lastMeeting, metInPlan := plannedMeetings[key]
+			lastMeetingInHistory, metInHistory := p.hist.GetPersonToLastMeetingMap(history.ID(pair[0]))[history.ID(pair[1])]
+
+			switch {
+			case metInPlan:
+				cost += planRepeatCost
+			case metInHistory:
+				lastMeeting = lastMeetingInHistory
+				cost -= min(round.date.Sub(lastMeeting).Hours()/24, planMaxStalenessDays) / planMaxStalenessDays
+			default:
+				cost -= planNewPairBonus
+			}
+
+			person1, found1 := p.people[pair[0]]
+			person2, found2 := p.people[pair[1]]
+			if found1 && found2 {
+				cost += pairPenaltySince(p.conf, person1, person2, lastMeeting, metInPlan || metInHistory, round.date)
+				cost += diversityPenalty(p.conf, round.recent, person1, person2)
+			}
+
+			plannedMeetings[key] = round.date
+		}
+	}
+
+	return cost
+}
+
+// bound returns a lower bound on the cost of any plan for the same rounds, assuming the penalties of the soft
+// constraints are never negative. At best everyone who can meet in a round is paired, except one person when there is
+// an odd number, and each pair who have not met are new to each other once, with the rest having met long ago.
+func (p *plan) bound() float64 {
+	unmet := 0
+	for id, validPairs := range p.validPairs {
+		for other := range validPairs {
+			if id < other {
+				if _, met := p.hist.GetPersonToLastMeetingMap(history.ID(id))[history.ID(other)]; !met {
+					unmet++
+				}
+			}
+		}
+	}
+
+	bound := 0.0
+	pairs := 0
+	for _, round := range p.rounds {
+		eligible := 2*len(round.pairs) + len(round.unpaired)
+		bound += planUnpairedCost * float64(eligible%2)
+		pairs += eligible / 2
+	}
+
+	newPairs := min(pairs, unmet)
+	return bound - planNewPairBonus*float64(newPairs) - float64(pairs-newPairs)
+}
+
+// pairings converts the plan into the pairings for each round.
+func (p *plan) pairings() []Pairings {
+	weeklyPairings := make([]Pairings, 0, len(p.rounds))
+	for _, round := range p.rounds {
+		weeklyPairings = append(weeklyPairings, Pairings{data: round.pairs, date: round.date})
+	}
+	return weeklyPairings
+}
+
+// pairKey returns the same key for a pair regardless of order.
+func pairKey(id1, id2 ID) [2]ID {
+	if id2 < id1 {
+		return [2]ID{id2, id1}
+	}
+	return [2]ID{id1, id2}
+}
+
+// copyHistory returns a copy of the meetings in the history between the people in the config,
+// allowing pairings to be simulated without changing the original.
+func copyHistory(conf Config, hist history.History) history.History {
+	copied := history.History{}
+	for _, person := range conf.People {
+		for other, meetingTime := range hist.GetPersonToLastMeetingMap(history.ID(person.ID)) {
+			copied.AddMeeting(history.ID(person.ID), other, meetingTime)
+		}
+	}
+	return copied
+}
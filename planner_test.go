@@ -0,0 +1,134 @@
+package yapper
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestGeneratePlannedDoesNotCreateInvalidPairs(t *testing.T) {
+	config := getConfigFromFile(t, validConfigName)
+	validPairs := getValidPairsForConfig()
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 8)
+
+	planned, err := generatePlanned(context.Background(), config, determineValidPairings(config), history.History{}, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error planning pairings: %v", err)
+	}
+
+	for _, pairings := range planned {
+		for id1, id2 := range pairings.All() {
+			if !slices.Contains(validPairs[id1], id2) {
+				t.Errorf("%s cannot be paired with %s", id1, id2)
+			}
+			checkEligibleToMeetThisWeek(t, config, id1, pairings.Date())
+			checkEligibleToMeetThisWeek(t, config, id2, pairings.Date())
+		}
+	}
+}
+
+func TestGeneratePlannedCostIsNoWorseThanGreedy(t *testing.T) {
+	config := getConfigFromFile(t, validConfigName)
+	idToValidPairings := determineValidPairings(config)
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 12)
+
+	p, err := newPlan(context.Background(), config, idToValidPairings, history.History{}, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error planning pairings: %v", err)
+	}
+	greedyCost := p.cost()
+	if _, err := p.improve(context.Background(), time.Time{}); err != nil {
+		t.Fatalf("Unexpected error improving plan: %v", err)
+	}
+
+	if plannedCost := p.cost(); plannedCost > greedyCost {
+		t.Errorf("Planned cost %f should not be more than the greedy cost %f", plannedCost, greedyCost)
+	}
+}
+
+func TestGeneratePlannedAvoidsRepeatsWhenPossible(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}}}
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 3)
+
+	met := map[[2]ID]bool{}
+	planned, err := generatePlanned(context.Background(), config, determineValidPairings(config), history.History{}, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error planning pairings: %v", err)
+	}
+
+	for _, pairings := range planned {
+		for id1, id2 := range pairings.All() {
+			key := pairKey(id1, id2)
+			if met[key] {
+				t.Errorf("%s and %s were paired more than once", id1, id2)
+			}
+			met[key] = true
+		}
+	}
+
+	if len(met) != 6 {
+		t.Errorf("Expected everyone to meet everyone over 3 rounds, got %d pairs", len(met))
+	}
+}
+
+func TestCopyHistoryDoesNotModifyOriginal(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", date)
+
+	copied := copyHistory(config, hist)
+	copied.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, 7))
+
+	if lastMeeting := hist.GetPersonToLastMeetingMap("Mario")["Luigi"]; !lastMeeting.Equal(date) {
+		t.Errorf("Expected original history to be unchanged, got %v", lastMeeting)
+	}
+}
+
+func TestGeneratePlannedReachesBoundWhenEveryoneCanMeetSomeoneNew(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}}}
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 3)
+
+	planned, err := generatePlanned(context.Background(), config, determineValidPairings(config), history.History{}, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error planning pairings: %v", err)
+	}
+
+	expected := PlanScore{Cost: -6 * planNewPairBonus, Bound: -6 * planNewPairBonus}
+	if score, found := planned[2].PlanScore(); !found || score != expected {
+		t.Errorf("Expected:\n%+v\nGot:\n%+v", expected, score)
+	}
+}
+
+func TestGeneratePlannedReturnsBestPlanAfterMaxDuration(t *testing.T) {
+	config := getConfigFromFile(t, validConfigName)
+	config.Settings.MaxDuration = "1ns"
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 12)
+
+	planned, err := generatePlanned(context.Background(), config, determineValidPairings(config), history.History{}, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error planning pairings: %v", err)
+	}
+
+	if len(planned) != len(dates) {
+		t.Fatalf("Expected %d rounds, got %d", len(dates), len(planned))
+	}
+	score, found := planned[0].PlanScore()
+	if !found || !score.Stopped {
+		t.Errorf("Expected the search to be stopped by the max duration, got %+v", score)
+	}
+	if score.Cost < score.Bound {
+		t.Errorf("Expected the cost %f to be no lower than the bound %f", score.Cost, score.Bound)
+	}
+}
+
+func getRoundDates(start time.Time, rounds int) []time.Time {
+	dates := make([]time.Time, 0, rounds)
+	for i := range rounds {
+		dates = append(dates, start.AddDate(0, 0, 7*i))
+	}
+	return dates
+}
@@ -0,0 +1,54 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Priority decides who is matched first in a round, for when not everyone can be paired.
+type Priority string
+
+const (
+	// PriorityHigh people are matched before everyone else, e.g. executives doing skip-levels or new hires.
+	PriorityHigh Priority = "high"
+	// PriorityNormal is the default priority.
+	PriorityNormal Priority = "normal"
+	// PriorityLow people are matched after everyone else.
+	PriorityLow Priority = "low"
+)
+
+func (p Priority) validate() error {
+	switch p {
+	case "", PriorityHigh, PriorityNormal, PriorityLow:
+		return nil
+	default:
+		return fmt.Errorf("unexpected priority: %s", p)
+	}
+}
+
+// tier returns the order the people of the priority are matched in, with the lowest matched first.
+func (p Priority) tier() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// prioritiseByPriority moves the people of higher priorities to the front, otherwise keeping the existing order.
+func prioritiseByPriority(index map[ID]Person, ids []ID) []ID {
+	tiers := make(map[ID]int, len(ids))
+	for _, id := range ids {
+		if person, found := index[id]; found {
+			tiers[id] = person.Priority.tier()
+		}
+	}
+
+	slices.SortStableFunc(ids, func(a, b ID) int {
+		return tiers[a] - tiers[b]
+	})
+	return ids
+}
@@ -0,0 +1,50 @@
+package yapper
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestPriorityValidateReturnsErrorForUnexpectedPriority(t *testing.T) {
+	if err := Priority("urgent").validate(); err == nil {
+		t.Errorf("Expected error due to unexpected priority")
+	}
+}
+
+func TestPairPeopleMatchesHighPriorityPeopleFirst(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{People: []Person{
+		{ID: "Mario"},
+		{ID: "Luigi"},
+		{ID: "Peach", Priority: PriorityHigh, DenyList: []ID{"Luigi"}},
+	}}
+
+	// The people are otherwise matched in a random order, in which Luigi can take Peach's only partner.
+	for range 20 {
+		pairings := pairPeople(config, determineValidPairings(config), history.History{}, date)
+
+		if len(pairings.data) != 1 || pairKey(pairings.data[0][0], pairings.data[0][1]) != pairKey("Mario", "Peach") {
+			t.Fatalf("Expected Peach to be paired with Mario, got %v", pairings.data)
+		}
+	}
+}
+
+func TestPrioritiseByPriorityKeepsOrderWithinTiers(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario", Priority: PriorityLow},
+		{ID: "Luigi"},
+		{ID: "Peach", Priority: PriorityHigh},
+		{ID: "Toad", Priority: PriorityNormal},
+		{ID: "Yoshi", Priority: PriorityHigh},
+	}}
+
+	ids := prioritiseByPriority(config.Index(), []ID{"Mario", "Luigi", "Peach", "Toad", "Yoshi"})
+
+	expected := []ID{"Peach", "Yoshi", "Luigi", "Toad", "Mario"}
+	if !slices.Equal(expected, ids) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, ids)
+	}
+}
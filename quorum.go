@@ -0,0 +1,149 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// QuorumPolicy decides what happens to rounds in which fewer people than the quorum can meet.
+type QuorumPolicy string
+
+const (
+	// QuorumPolicySkip does not pair anyone in rounds in which fewer than the quorum can meet.
+	QuorumPolicySkip QuorumPolicy = "skip"
+	// QuorumPolicyWarn pairs rounds in which fewer than the quorum can meet as usual, with generate warning about them.
+	QuorumPolicyWarn QuorumPolicy = "warn"
+)
+
+// Quorum is the fewest people who must be able to meet for a round to be paired, so that a round in which most people
+// are away, such as a holiday week, does not pair the few who are left.
+type Quorum struct {
+	// MinPeople is the fewest people able to meet in a round, after their cadences, pauses, absences and holidays.
+	MinPeople int `json:"minPeople"`
+	// Policy decides whether rounds below the quorum are skipped, the default, or only warned about.
+	Policy QuorumPolicy `json:"policy,omitempty"`
+	// RollForward carries the rounds skipped below the quorum forward, so the people who could have met in them are
+	// matched first in the following round, with those on a two week cadence meeting in it even if it is not their week.
+	RollForward bool `json:"rollForward,omitempty"`
+}
+
+// rollForwardRounds is how many rounds before a round are looked at for the rounds skipped below the quorum which
+// are carried forward into it.
+const rollForwardRounds = 12
+
+func (q Quorum) validate() error {
+	if q.MinPeople <= 0 {
+		return fmt.Errorf("quorum min people must be positive: %d", q.MinPeople)
+	}
+
+	switch q.Policy {
+	case "", QuorumPolicySkip, QuorumPolicyWarn:
+		return nil
+	default:
+		return fmt.Errorf("unexpected quorum policy: %s", q.Policy)
+	}
+}
+
+// policy returns the configured quorum policy, defaulting to skip.
+func (q Quorum) policy() QuorumPolicy {
+	if q.Policy == "" {
+		return QuorumPolicySkip
+	}
+	return q.Policy
+}
+
+// Available returns how many of the people can meet in the round starting on date, after their cadences, pauses,
+// absences and holidays. People owed a skipped round carried forward into it are counted regardless of their cadence.
+func (c Config) Available(date time.Time) int {
+	return c.available(date, c.owed(date))
+}
+
+func (c Config) available(date time.Time, owed map[ID]bool) int {
+	available := 0
+	for _, person := range c.People {
+		if c.excusedUnavailability(person, date, owed) == "" {
+			available++
+		}
+	}
+	return available
+}
+
+// excusedUnavailability returns the unavailability of the person in the round starting on date, excusing their
+// cadence if they are owed a skipped round.
+func (c Config) excusedUnavailability(person Person, date time.Time, owed map[ID]bool) ViolationKind {
+	kind := c.unavailability(person, date)
+	if kind == ViolationCadence && owed[person.ID] {
+		return ""
+	}
+	return kind
+}
+
+// owed returns the people owed a round in the round starting on date, as they could have met in the rounds skipped
+// below the quorum just before it. It is empty unless the skipped rounds are rolled forward.
+func (c Config) owed(date time.Time) map[ID]bool {
+	if c.Quorum == nil || !c.Quorum.RollForward || c.Quorum.policy() != QuorumPolicySkip {
+		return nil
+	}
+
+	owed := map[ID]bool{}
+	for i := rollForwardRounds; i > 0; i-- {
+		round := date.AddDate(0, 0, -i*c.RoundInterval())
+		if c.available(round, owed) >= c.Quorum.MinPeople {
+			clear(owed)
+			continue
+		}
+		for _, person := range c.People {
+			if c.excusedUnavailability(person, round, owed) == "" {
+				owed[person.ID] = true
+			}
+		}
+	}
+	return owed
+}
+
+// prioritiseOwed moves the people owed a skipped round to the front, otherwise keeping the existing order.
+func prioritiseOwed(owed map[ID]bool, ids []ID) []ID {
+	if len(owed) == 0 {
+		return ids
+	}
+	slices.SortStableFunc(ids, func(a, b ID) int {
+		switch {
+		case owed[a] == owed[b]:
+			return 0
+		case owed[a]:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return ids
+}
+
+// BelowQuorum reports whether fewer people than the quorum of the config can meet in the round starting on date,
+// returning how many can. Every round meets the quorum without the quorum of the config.
+func (c Config) BelowQuorum(date time.Time) (int, bool) {
+	if c.Quorum == nil {
+		return 0, false
+	}
+	available := c.Available(date)
+	return available, available < c.Quorum.MinPeople
+}
+
+// roundQuorum is the quorum of a round, worked out once for everyone checked in the round as finding who is owed a
+// skipped round looks back over the rounds before it.
+type roundQuorum struct {
+	// owed are the people owed a skipped round carried forward into the round.
+	owed map[ID]bool
+	// skips is whether no one is paired in the round, as it is below the quorum and the quorum policy is skip.
+	skips bool
+}
+
+// quorumOf returns the quorum of the round starting on date.
+func (c Config) quorumOf(date time.Time) roundQuorum {
+	quorum := roundQuorum{owed: c.owed(date)}
+	if c.Quorum != nil && c.Quorum.policy() == QuorumPolicySkip {
+		quorum.skips = c.available(date, quorum.owed) < c.Quorum.MinPeople
+	}
+	return quorum
+}
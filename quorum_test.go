@@ -0,0 +1,146 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// quorumConfig returns a config of four people, two of whom are paused, with a quorum of three.
+func quorumConfig(policy QuorumPolicy) Config {
+	return Config{
+		People: []Person{
+			{ID: "Mario"},
+			{ID: "Luigi"},
+			{ID: "Peach", Paused: true},
+			{ID: "Toad", Paused: true},
+		},
+		Quorum: &Quorum{MinPeople: 3, Policy: policy},
+	}
+}
+
+func TestBelowQuorum(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := quorumConfig("")
+
+	if available, below := config.BelowQuorum(date); available != 2 || !below {
+		t.Errorf("Expected:\n2 true\nGot:\n%d %v", available, below)
+	}
+
+	config.People[2].Paused = false
+	if available, below := config.BelowQuorum(date); available != 3 || below {
+		t.Errorf("Expected:\n3 false\nGot:\n%d %v", available, below)
+	}
+
+	config.Quorum = nil
+	if _, below := config.BelowQuorum(date); below {
+		t.Error("Expected a round to meet the quorum without a quorum in the config")
+	}
+}
+
+func TestQuorumValidate(t *testing.T) {
+	tests := []struct {
+		quorum Quorum
+		valid  bool
+	}{
+		{Quorum{MinPeople: 2}, true},
+		{Quorum{MinPeople: 2, Policy: QuorumPolicyWarn}, true},
+		{Quorum{}, false},
+		{Quorum{MinPeople: 2, Policy: "maybe"}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.quorum.validate(); (err == nil) != test.valid {
+			t.Errorf("Expected %+v to be valid: %v, got error: %v", test.quorum, test.valid, err)
+		}
+	}
+}
+
+func TestGeneratePairingsSkipsRoundsBelowQuorum(t *testing.T) {
+	hist := history.History{}
+	result, err := GenerateResult(quorumConfig(QuorumPolicySkip), &hist, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	round := result.Rounds[0]
+	if round.Pairings.Len() != 0 {
+		t.Errorf("Expected no pairings, got %v", round.Pairings.data)
+	}
+	expected := []Diagnostic{
+		{Person: "Mario", Reason: ViolationQuorum, Message: "Mario does not meet this round as too few people can meet"},
+		{Person: "Luigi", Reason: ViolationQuorum, Message: "Luigi does not meet this round as too few people can meet"},
+		{Person: "Peach", Reason: ViolationPaused, Message: "Peach is paused"},
+		{Person: "Toad", Reason: ViolationPaused, Message: "Toad is paused"},
+	}
+	if !reflect.DeepEqual(expected, round.Diagnostics) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, round.Diagnostics)
+	}
+}
+
+func TestGeneratePairingsPairsRoundsBelowQuorumWithWarnPolicy(t *testing.T) {
+	hist := history.History{}
+	weeklyPairings, err := GeneratePairings(quorumConfig(QuorumPolicyWarn), &hist, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if partner, ok := weeklyPairings[0].PartnerOf("Mario"); !ok || partner != "Luigi" {
+		t.Errorf("Expected Mario to be paired with Luigi, got %v", weeklyPairings[0].data)
+	}
+}
+
+// rollForwardConfig returns a config with a quorum of three in which the round of 4 August is skipped, as Luigi and
+// Toad are away, leaving Mario and Peach. Peach is on a two week cadence and does not meet in the round of 11 August.
+func rollForwardConfig() Config {
+	return Config{
+		People: []Person{
+			{ID: "Mario"},
+			{ID: "Luigi"},
+			{ID: "Peach", Cadence: CadenceTwoWeeks},
+			{ID: "Toad"},
+		},
+		Absences: []Absence{
+			{Person: "Luigi", Start: date(2025, time.August, 4), End: date(2025, time.August, 8)},
+			{Person: "Toad", Start: date(2025, time.August, 4), End: date(2025, time.August, 8)},
+		},
+		Quorum: &Quorum{MinPeople: 3, RollForward: true},
+	}
+}
+
+func TestRollForwardOwesSkippedRounds(t *testing.T) {
+	config := rollForwardConfig()
+	next := time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC)
+
+	if !config.quorumOf(next.AddDate(0, 0, -7)).skips {
+		t.Fatal("Expected the round of 4 August to be skipped")
+	}
+	expected := map[ID]bool{"Mario": true, "Peach": true}
+	if owed := config.owed(next); !reflect.DeepEqual(expected, owed) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, owed)
+	}
+	if kind := config.ineligibility(config.People[2], next, config.quorumOf(next)); kind != "" {
+		t.Errorf("Expected Peach to meet in the round after the skipped round, got %s", kind)
+	}
+	if available := config.Available(next); available != 4 {
+		t.Errorf("Expected:\n4\nGot:\n%d", available)
+	}
+	if owed := config.owed(next.AddDate(0, 0, 7)); len(owed) != 0 {
+		t.Errorf("Expected nothing to be owed once the skipped round was carried forward, got %v", owed)
+	}
+
+	config.Quorum.RollForward = false
+	if kind := config.ineligibility(config.People[2], next, config.quorumOf(next)); kind != ViolationCadence {
+		t.Errorf("Expected:\n%s\nGot:\n%s", ViolationCadence, kind)
+	}
+}
+
+func TestPrioritiseOwed(t *testing.T) {
+	ids := prioritiseOwed(map[ID]bool{"Peach": true, "Mario": true}, []ID{"Luigi", "Mario", "Toad", "Peach"})
+	expected := []ID{"Mario", "Peach", "Luigi", "Toad"}
+	if !reflect.DeepEqual(expected, ids) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, ids)
+	}
+}
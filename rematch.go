@@ -0,0 +1,70 @@
+package yapper
+
+import (
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Rematch removes the declined pairs from the pairings and pairs everyone left without a partner with each other,
+// including the people of the declined pairs, respecting the usual constraints and never repeating a declined pair.
+// The new pairs are added to the pairings and returned, with their initiators and topics chosen from the history
+// but not yet recorded in it.
+func (p *Pairings) Rematch(config Config, hist history.History, declined [][2]ID) [][2]ID {
+	p.data = slices.DeleteFunc(p.data, func(pair [2]ID) bool {
+		return slices.ContainsFunc(declined, func(d [2]ID) bool { return pairKey(d[0], d[1]) == pairKey(pair[0], pair[1]) })
+	})
+
+	paired := map[ID]bool{}
+	for id1, id2 := range p.All() {
+		paired[id1], paired[id2] = true, true
+	}
+
+	available := config
+	available.People = nil
+	available.Pins = nil
+	for _, person := range config.People {
+		if paired[person.ID] {
+			continue
+		}
+
+		for _, pair := range declined {
+			if pair[0] == person.ID || pair[1] == person.ID {
+				other := pair[0]
+				if other == person.ID {
+					other = pair[1]
+				}
+				person.DenyList = append(slices.Clone(person.DenyList), other)
+			}
+		}
+		available.People = append(available.People, person)
+	}
+
+	rematched := pairPeople(available, determineValidPairings(available), hist, p.date)
+	if p.lastMeetings == nil {
+		p.lastMeetings = make(map[[2]ID]time.Time)
+	}
+	if p.initiators == nil {
+		p.initiators = make(map[[2]ID]ID)
+	}
+	if p.topics == nil {
+		p.topics = make(map[[2]ID]string)
+	}
+
+	pairs := [][2]ID{}
+	for id1, id2 := range rematched.All() {
+		p.Add(id1, id2)
+		if lastMeeting, met := hist.GetPersonToLastMeetingMap(history.ID(id1))[history.ID(id2)]; met {
+			p.lastMeetings[pairKey(id1, id2)] = lastMeeting
+		}
+		p.initiators[pairKey(id1, id2)] = Initiator(hist, id1, id2)
+		if topic := config.Topic(hist, id1, id2, p.date); topic != "" {
+			p.topics[pairKey(id1, id2)] = topic
+		}
+		pairs = append(pairs, [2]ID{id1, id2})
+	}
+	p.penalty += rematched.penalty
+
+	return pairs
+}
@@ -0,0 +1,48 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestRematchPairsDeclinedWithUnmatched(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}, {ID: "Yoshi"}}}
+	pairings := Pairings{date: date, data: [][2]ID{{"Mario", "Luigi"}, {"Peach", "Toad"}}}
+
+	rematched := pairings.Rematch(config, history.History{}, [][2]ID{{"Luigi", "Mario"}})
+
+	if len(rematched) != 1 || (rematched[0][0] != "Yoshi" && rematched[0][1] != "Yoshi") {
+		t.Fatalf("Expected one of the declined pair to be paired with Yoshi, got %v", rematched)
+	}
+
+	expected := [][2]ID{{"Peach", "Toad"}, rematched[0]}
+	if !reflect.DeepEqual(expected, pairings.data) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, pairings.data)
+	}
+}
+
+func TestRematchDoesNotRepeatDeclinedPairsOrBreakConstraints(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{People: []Person{
+		{ID: "Mario", DenyList: []ID{"Peach"}},
+		{ID: "Luigi"},
+		{ID: "Peach"},
+		{ID: "Toad", Paused: true},
+	}}
+	pairings := Pairings{date: date, data: [][2]ID{{"Mario", "Luigi"}}}
+
+	declined := [][2]ID{{"Mario", "Luigi"}}
+	rematched := pairings.Rematch(config, history.History{}, declined)
+
+	expected := [][2]ID{{"Luigi", "Peach"}}
+	if len(rematched) == 1 && rematched[0][0] == "Peach" {
+		expected = [][2]ID{{"Peach", "Luigi"}}
+	}
+	if !reflect.DeepEqual(expected, rematched) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, rematched)
+	}
+}
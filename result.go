@@ -0,0 +1,164 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Result is the rounds of pairings generated for a config, with who was left unpaired and which pins were skipped in
+// each and why.
+type Result struct {
+	Rounds []Round `json:"rounds"`
+}
+
+// Round is the pairings of one round along with the people in the config who were not paired in it.
+type Round struct {
+	Date     time.Time `json:"date"`
+	Pairings Pairings  `json:"pairings"`
+	// Unpaired are the people in the config without a partner in the round, in config order.
+	Unpaired []ID `json:"unpaired"`
+	// Diagnostics explain why each of the unpaired people was not paired.
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	// SkippedPins explain why each pin applying to the round was not paired in it.
+	SkippedPins []SkippedPin `json:"skippedPins"`
+}
+
+// Diagnostic explains why someone was not paired in a round.
+type Diagnostic struct {
+	Person ID `json:"person"`
+	// Reason is the constraint which kept the person from meeting in the round, such as being paused or absent.
+	// It is empty if nothing kept them from meeting but everyone they could be paired with was taken or ineligible.
+	Reason  ViolationKind `json:"reason,omitempty"`
+	Message string        `json:"message"`
+	// Blockers explain why the person was not paired with each of the others, grouped by the constraint. They are only
+	// given when the person could meet in the round.
+	Blockers []Blocker `json:"blockers,omitempty"`
+}
+
+// Blocker is a constraint which kept someone from being paired with some of the others in a round.
+type Blocker struct {
+	// Reason is the constraint, such as a deny list between them or the others being paused. It is empty for the
+	// others who were already paired.
+	Reason  ViolationKind `json:"reason,omitempty"`
+	People  []ID          `json:"people"`
+	Message string        `json:"message"`
+}
+
+// blockerDescriptions describe the others kept from being paired with someone by each constraint.
+var blockerDescriptions = map[ViolationKind]string{
+	"":                  "already paired",
+	ViolationDenyList:   "denied by a deny list",
+	ViolationSameSquad:  "in the same squad",
+	ViolationTagRule:    "sharing a tag denied by a tag rule",
+	ViolationRule:       "denied by a rule",
+	ViolationSkipLevel:  "outside of the management chain under the skip-level preset",
+	ViolationGuest:      "guests, or not allowing external chats",
+	ViolationPaused:     "paused",
+	ViolationAbsent:     "absent",
+	ViolationHoliday:    "on holiday for most of the round",
+	ViolationCadence:    "not meeting this round on a two week cadence",
+	ViolationOnboarding: "in another onboarding cohort",
+	ViolationSquadLimit: "in a squad whose squad limit was reached",
+}
+
+// GenerateResult generates the given number of rounds of pairings like GeneratePairings, describing each round.
+func GenerateResult(config Config, hist *history.History, rounds int) (Result, error) {
+	weeklyPairings, err := GeneratePairings(config, hist, rounds)
+	if err != nil {
+		return Result{}, err
+	}
+	return NewResult(config, weeklyPairings), nil
+}
+
+// NewResult describes the rounds of pairings generated for the config, such as those returned by GeneratePairings.
+func NewResult(config Config, weeklyPairings []Pairings) Result {
+	allValidPairings := determineValidPairings(config)
+	result := Result{Rounds: make([]Round, 0, len(weeklyPairings))}
+
+	for _, pairings := range weeklyPairings {
+		idToValidPairings := restrictToCohorts(config.onboardingCohorts(pairings.Date()), allValidPairings)
+		round := Round{Date: pairings.Date(), Pairings: pairings, Unpaired: []ID{}, Diagnostics: []Diagnostic{}}
+		paired := map[ID]bool{}
+		quorum := config.quorumOf(pairings.Date())
+		for id1, id2 := range pairings.All() {
+			paired[id1], paired[id2] = true, true
+		}
+
+		for _, person := range config.People {
+			if paired[person.ID] || slices.Contains(round.Unpaired, person.ID) {
+				continue
+			}
+			round.Unpaired = append(round.Unpaired, person.ID)
+			round.Diagnostics = append(round.Diagnostics, diagnose(config, idToValidPairings, quorum, person, pairings))
+		}
+
+		round.SkippedPins = skippedPins(config, idToValidPairings, pairings.Date())
+		result.Rounds = append(result.Rounds, round)
+	}
+
+	return result
+}
+
+// diagnose explains why the person was not paired in the pairings of a round, given the quorum of the round.
+func diagnose(config Config, idToValidPairings map[ID][]ID, quorum roundQuorum, person Person, pairings Pairings) Diagnostic {
+	date := pairings.Date()
+	if kind := config.ineligibility(person, date, quorum); kind != "" {
+		return Diagnostic{Person: person.ID, Reason: kind, Message: newViolation(kind, person.ID).Message}
+	}
+
+	diagnostic := Diagnostic{Person: person.ID, Blockers: blockers(config, quorum, person, pairings)}
+	if len(idToValidPairings[person.ID]) == 0 {
+		diagnostic.Message = fmt.Sprintf("%s cannot be paired with anyone", person.ID)
+	} else {
+		diagnostic.Message = fmt.Sprintf("everyone %s can be paired with was already paired or cannot meet", person.ID)
+	}
+	return diagnostic
+}
+
+// blockers returns the constraints which kept the person from being paired with each of the others in the pairings,
+// in the order each constraint first applies to someone in the config. The first constraint applying to each of the
+// others is given, with those preventing the pair entirely before those keeping the other from the round. Others who
+// were left unpaired even though nothing kept them from the person are not included.
+func blockers(config Config, quorum roundQuorum, person Person, pairings Pairings) []Blocker {
+	date := pairings.Date()
+	cohorts := config.onboardingCohorts(date)
+	squadCounts := newSquadCountsOf(config, pairings.data)
+	check := newPairCheck(config)
+
+	var grouped []Blocker
+	for _, other := range config.People {
+		if other.ID == person.ID {
+			continue
+		}
+
+		kind := check.denial(person, other)
+		if kind == "" && onboardingDenied(cohorts, person.ID, other.ID) {
+			kind = ViolationOnboarding
+		}
+		if kind == "" {
+			kind = config.ineligibility(other, date, quorum)
+		}
+		_, paired := pairings.PartnerOf(other.ID)
+		if kind == "" && !paired && !squadCounts.allows(person.ID, other.ID) {
+			kind = ViolationSquadLimit
+		}
+		if kind == "" && !paired {
+			continue
+		}
+
+		index := slices.IndexFunc(grouped, func(b Blocker) bool { return b.Reason == kind })
+		if index == -1 {
+			grouped = append(grouped, Blocker{Reason: kind})
+			index = len(grouped) - 1
+		}
+		grouped[index].People = append(grouped[index].People, other.ID)
+	}
+
+	for i, blocker := range grouped {
+		grouped[i].Message = fmt.Sprintf("%s: %s", blockerDescriptions[blocker.Reason], joinIDs(blocker.People))
+	}
+	return grouped
+}
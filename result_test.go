@@ -0,0 +1,63 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestNewResultDiagnosesUnpairedPeople(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario"},
+		{ID: "Luigi"},
+		{ID: "Peach", Paused: true},
+		{ID: "Toad"},
+		{ID: "Bowser", DenyList: []ID{"Mario", "Luigi", "Peach", "Toad"}},
+	}}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	pairings := NewPairings(date, [][2]ID{{"Mario", "Luigi"}})
+
+	result := NewResult(config, []Pairings{pairings})
+
+	if len(result.Rounds) != 1 || !result.Rounds[0].Date.Equal(date) {
+		t.Fatalf("Expected one round starting on %v, got %+v", date, result.Rounds)
+	}
+	round := result.Rounds[0]
+	if expected := []ID{"Peach", "Toad", "Bowser"}; !reflect.DeepEqual(expected, round.Unpaired) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, round.Unpaired)
+	}
+
+	expected := []Diagnostic{
+		{Person: "Peach", Reason: ViolationPaused, Message: "Peach is paused"},
+		{Person: "Toad", Message: "everyone Toad can be paired with was already paired or cannot meet", Blockers: []Blocker{
+			{People: []ID{"Mario", "Luigi"}, Message: "already paired: Mario, Luigi"},
+			{Reason: ViolationPaused, People: []ID{"Peach"}, Message: "paused: Peach"},
+			{Reason: ViolationDenyList, People: []ID{"Bowser"}, Message: "denied by a deny list: Bowser"},
+		}},
+		{Person: "Bowser", Message: "Bowser cannot be paired with anyone", Blockers: []Blocker{
+			{Reason: ViolationDenyList, People: []ID{"Mario", "Luigi", "Peach", "Toad"}, Message: "denied by a deny list: Mario, Luigi, Peach, Toad"},
+		}},
+	}
+	if !reflect.DeepEqual(expected, round.Diagnostics) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, round.Diagnostics)
+	}
+}
+
+func TestGenerateResultRecordsPairings(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+	hist := history.History{}
+
+	result, err := GenerateResult(config, &hist, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error generating pairings: %v", err)
+	}
+
+	if len(result.Rounds) != 2 || len(result.Rounds[1].Unpaired) != 0 || !result.Rounds[1].Date.After(result.Rounds[0].Date) {
+		t.Errorf("Expected Mario and Luigi to meet in 2 consecutive rounds, got %+v", result.Rounds)
+	}
+	if _, met := hist.GetPersonToLastMeetingMap("Mario")["Luigi"]; !met {
+		t.Error("Expected the pairings to be recorded in the history")
+	}
+}
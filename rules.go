@@ -0,0 +1,418 @@
+package yapper
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Rule is a pairing constraint written as an expression, e.g. `person.level == "intern" && other.level == "vp"`.
+//
+// Expressions refer to the two people being considered as person and other. Rules are checked in both directions,
+// so it does not matter which person is which. The fields id, squad and cadence are available, along with any
+// attribute of the person, e.g. person.level. Supported are:
+//   - comparisons with == and != against other fields or double quoted strings
+//   - combining with &&, || and !, grouping with parentheses
+//   - hasTag(person, "pattern") which reports whether a person has a tag matching the pattern
+type Rule struct {
+	// Deny is an expression which prevents the pairing when it is true.
+	Deny string `json:"deny"`
+}
+
+func (r Rule) compile() (condition, error) {
+	tokens, err := tokenize(r.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", r.Deny, err)
+	}
+
+	p := &ruleParser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", r.Deny, err)
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("invalid rule %q: unexpected %q", r.Deny, p.peek().text)
+	}
+
+	return cond, nil
+}
+
+// compileRules compiles each of the rules, returning the conditions of those which are valid along with the errors of
+// those which are not.
+func compileRules(rules []Rule) ([]condition, error) {
+	conditions := make([]condition, 0, len(rules))
+	var errs []error
+	for _, rule := range rules {
+		cond, err := rule.compile()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, errors.Join(errs...)
+}
+
+// deniedByRules reports whether any of the compiled rules deny pairing the two people.
+func deniedByRules(rules []condition, person1, person2 Person) bool {
+	for _, cond := range rules {
+		if cond.evaluate(person1, person2) || cond.evaluate(person2, person1) {
+			return true
+		}
+	}
+	return false
+}
+
+// condition is a compiled boolean expression evaluated for a pair of people.
+type condition interface {
+	evaluate(person, other Person) bool
+}
+
+// operand is a compiled expression which results in a string.
+type operand interface {
+	value(person, other Person) string
+}
+
+type andCondition struct{ left, right condition }
+
+func (c andCondition) evaluate(person, other Person) bool {
+	return c.left.evaluate(person, other) && c.right.evaluate(person, other)
+}
+
+type orCondition struct{ left, right condition }
+
+func (c orCondition) evaluate(person, other Person) bool {
+	return c.left.evaluate(person, other) || c.right.evaluate(person, other)
+}
+
+type notCondition struct{ inner condition }
+
+func (c notCondition) evaluate(person, other Person) bool {
+	return !c.inner.evaluate(person, other)
+}
+
+type comparison struct {
+	left, right operand
+	equal       bool
+}
+
+func (c comparison) evaluate(person, other Person) bool {
+	return (c.left.value(person, other) == c.right.value(person, other)) == c.equal
+}
+
+type hasTagCondition struct {
+	usePerson bool
+	pattern   string
+}
+
+func (c hasTagCondition) evaluate(person, other Person) bool {
+	if c.usePerson {
+		return person.HasTag(c.pattern)
+	}
+	return other.HasTag(c.pattern)
+}
+
+type literal string
+
+func (l literal) value(Person, Person) string {
+	return string(l)
+}
+
+type field struct {
+	usePerson bool
+	name      string
+}
+
+func (f field) value(person, other Person) string {
+	subject := other
+	if f.usePerson {
+		subject = person
+	}
+
+	switch f.name {
+	case "id":
+		return string(subject.ID)
+	case "squad":
+		return subject.Squad
+	case "cadence":
+		return string(subject.Cadence)
+	default:
+		return subject.Attributes[f.name]
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenIdentifier tokenKind = iota
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, errors.New("unterminated string")
+			}
+
+			text, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s: %w", string(runes[i:end+1]), err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[i:end])})
+			i = end
+		default:
+			operator := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "!", "(", ")", ","} {
+				if hasPrefix(runes[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator})
+			i += len(operator)
+		}
+	}
+
+	return tokens, nil
+}
+
+// hasPrefix reports whether the runes begin with the prefix, without converting all of them back to a string.
+func hasPrefix(runes []rune, prefix string) bool {
+	for _, r := range prefix {
+		if len(runes) == 0 || runes[0] != r {
+			return false
+		}
+		runes = runes[1:]
+	}
+	return true
+}
+
+// maxRuleDepth is how deeply the negations and parentheses of a rule can be nested, so malformed rules are rejected
+// rather than exhausting the stack.
+const maxRuleDepth = 100
+
+type ruleParser struct {
+	tokens []token
+	pos    int
+	// depth is how many negations and parentheses enclose the expression being parsed.
+	depth int
+}
+
+func (p *ruleParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *ruleParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() (token, error) {
+	if p.done() {
+		return token{}, errors.New("unexpected end of rule")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *ruleParser) acceptOperator(operator string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == operator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ruleParser) expectOperator(operator string) error {
+	if !p.acceptOperator(operator) {
+		return fmt.Errorf("expected %q", operator)
+	}
+	return nil
+}
+
+func (p *ruleParser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptOperator("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCondition{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (condition, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptOperator("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andCondition{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *ruleParser) parseUnary() (condition, error) {
+	if t := p.peek(); t.kind == tokenOperator && (t.text == "!" || t.text == "(") {
+		if p.depth == maxRuleDepth {
+			return nil, fmt.Errorf("nested more than %d levels deep", maxRuleDepth)
+		}
+		p.depth++
+		defer func() { p.depth-- }()
+	}
+
+	if p.acceptOperator("!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{inner: inner}, nil
+	}
+
+	if p.acceptOperator("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	if t := p.peek(); t.kind == tokenIdentifier && t.text == "hasTag" {
+		p.pos++
+		return p.parseHasTag()
+	}
+
+	return p.parseComparison()
+}
+
+func (p *ruleParser) parseHasTag() (condition, error) {
+	if err := p.expectOperator("("); err != nil {
+		return nil, err
+	}
+
+	subject, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if subject.kind != tokenIdentifier || (subject.text != "person" && subject.text != "other") {
+		return nil, fmt.Errorf("hasTag expects person or other, got %q", subject.text)
+	}
+
+	if err := p.expectOperator(","); err != nil {
+		return nil, err
+	}
+
+	pattern, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if pattern.kind != tokenString {
+		return nil, fmt.Errorf("hasTag expects a string pattern, got %q", pattern.text)
+	}
+	if _, err := path.Match(pattern.text, ""); err != nil {
+		return nil, fmt.Errorf("invalid tag pattern %q: %w", pattern.text, err)
+	}
+
+	if err := p.expectOperator(")"); err != nil {
+		return nil, err
+	}
+
+	return hasTagCondition{usePerson: subject.text == "person", pattern: pattern.text}, nil
+}
+
+func (p *ruleParser) parseComparison() (condition, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	var equal bool
+	switch {
+	case p.acceptOperator("=="):
+		equal = true
+	case p.acceptOperator("!="):
+		equal = false
+	default:
+		return nil, errors.New("expected == or != after a value")
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return comparison{left: left, right: right, equal: equal}, nil
+}
+
+func (p *ruleParser) parseOperand() (operand, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.kind {
+	case tokenString:
+		return literal(t.text), nil
+	case tokenIdentifier:
+		subject, name, found := strings.Cut(t.text, ".")
+		if !found || name == "" || strings.Contains(name, ".") || (subject != "person" && subject != "other") {
+			return nil, fmt.Errorf("expected person.<field> or other.<field>, got %q", t.text)
+		}
+		return field{usePerson: subject == "person", name: name}, nil
+	default:
+		return nil, fmt.Errorf("expected a value, got %q", t.text)
+	}
+}
@@ -0,0 +1,93 @@
+package yapper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestRuleCompileReturnsErrorForInvalidExpressions(t *testing.T) {
+	invalid := []string{
+		"",
+		`person.level ==`,
+		`person.level == "intern" &&`,
+		`level == "intern"`,
+		`person.level = "intern"`,
+		`(person.level == "intern"`,
+		`person.level == "intern`,
+		`hasTag(someone, "guild:*")`,
+		`hasTag(person, "guild:[")`,
+		`person.level == "intern" other.level == "vp"`,
+		strings.Repeat("(", 1_000_000) + `person.level == "intern"` + strings.Repeat(")", 1_000_000),
+		strings.Repeat("!", 1_000_000) + `person.level == "intern"`,
+	}
+
+	for _, expression := range invalid {
+		if _, err := (Rule{Deny: expression}).compile(); err == nil {
+			t.Errorf("Expected error for invalid rule: %s", expression)
+		}
+	}
+}
+
+func TestRuleEvaluatesExpressions(t *testing.T) {
+	intern := Person{ID: "Toad", Squad: "mushrooms", Attributes: map[string]string{"level": "intern"}}
+	vp := Person{ID: "Peach", Tags: []string{"royalty"}, Attributes: map[string]string{"level": "vp"}}
+	engineer := Person{ID: "Mario", Squad: "bros", Attributes: map[string]string{"level": "senior"}}
+
+	tests := []struct {
+		expression       string
+		person1, person2 Person
+		expected         bool
+	}{
+		{`person.level == "intern" && other.level == "vp"`, intern, vp, true},
+		{`person.level == "intern" && other.level == "vp"`, vp, intern, true},
+		{`person.level == "intern" && other.level == "vp"`, intern, engineer, false},
+		{`person.level == other.level`, vp, engineer, false},
+		{`!(person.squad == "bros") && hasTag(other, "roy*")`, intern, vp, true},
+		{`person.id == "Mario" || other.id == "Mario"`, engineer, vp, true},
+		{`person.missing != ""`, intern, vp, false},
+		{strings.Repeat("!(", 25) + `person.id == "Toad"` + strings.Repeat(")", 25), intern, vp, true},
+	}
+
+	for _, test := range tests {
+		rules, err := compileRules([]Rule{{Deny: test.expression}})
+		if err != nil {
+			t.Fatalf("Expected %s to compile, got %v", test.expression, err)
+		}
+		if denied := deniedByRules(rules, test.person1, test.person2); denied != test.expected {
+			t.Errorf("For %s with %s and %s expected %t, got %t", test.expression, test.person1.ID, test.person2.ID, test.expected, denied)
+		}
+	}
+}
+
+func TestDetermineValidPairingsExcludesPairsDeniedByRules(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Toad", Attributes: map[string]string{"level": "intern"}},
+			{ID: "Peach", Attributes: map[string]string{"level": "vp"}},
+			{ID: "Mario", Attributes: map[string]string{"level": "senior"}},
+		},
+		Rules: []Rule{{Deny: `person.level == "intern" && other.level == "vp"`}},
+	}
+
+	expected := map[ID][]ID{
+		"Toad":  {"Mario"},
+		"Peach": {"Mario"},
+		"Mario": {"Toad", "Peach"},
+	}
+	diffPairings(t, determineValidPairings(config), expected)
+}
+
+func TestGenerateForDatesRejectsInvalidRules(t *testing.T) {
+	config := Config{
+		People: []Person{{ID: "Mario"}, {ID: "Luigi"}},
+		Rules:  []Rule{{Deny: `person.squad ==`}},
+	}
+	hist := history.History{}
+	dates := []time.Time{time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)}
+	if _, err := generateForDates(config, &hist, dates); err == nil {
+		t.Errorf("Expected the invalid rule to be reported")
+	}
+}
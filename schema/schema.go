@@ -0,0 +1,150 @@
+// Package schema generates JSON Schemas from Go types and validates JSON documents against them,
+// reporting the line and field of any problems.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema used to describe the yapper file formats.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"-"`
+	// ClosedProperties disallows any properties other than those listed, catching misspelt fields.
+	ClosedProperties bool    `json:"-"`
+	Items            *Schema `json:"items,omitempty"`
+	MinItems         *int    `json:"minItems,omitempty"`
+	MaxItems         *int    `json:"maxItems,omitempty"`
+}
+
+// MarshalJSON writes additionalProperties as false for closed objects, or as the schema of the additional properties.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	out := struct {
+		*plain
+		AdditionalProperties any `json:"additionalProperties,omitempty"`
+	}{plain: (*plain)(s)}
+
+	if s.ClosedProperties {
+		out.AdditionalProperties = false
+	} else if s.AdditionalProperties != nil {
+		out.AdditionalProperties = s.AdditionalProperties
+	}
+
+	return json.Marshal(out)
+}
+
+// Enumerated is implemented by string types with a fixed set of values.
+type Enumerated interface {
+	SchemaEnum() []string
+}
+
+// Formatted is implemented by types encoded as strings with a JSON Schema format, such as "date".
+type Formatted interface {
+	SchemaFormat() string
+}
+
+// Required is implemented by structs with fields which must be present.
+type Required interface {
+	SchemaRequired() []string
+}
+
+var (
+	enumeratedType     = reflect.TypeFor[Enumerated]()
+	formattedType      = reflect.TypeFor[Formatted]()
+	requiredType       = reflect.TypeFor[Required]()
+	textMarshalerType  = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType           = reflect.TypeFor[time.Time]()
+	jsonRawMessageType = reflect.TypeFor[json.RawMessage]()
+	anyType            = reflect.TypeFor[any]()
+)
+
+// Generate returns the schema of the JSON encoding of the type of v.
+func Generate(title string, v any) *Schema {
+	s := forType(reflect.TypeOf(v))
+	s.Schema = Draft
+	s.Title = title
+	return s
+}
+
+func forType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == jsonRawMessageType || t == anyType:
+		return &Schema{}
+	case t.Implements(formattedType):
+		return &Schema{Type: "string", Format: reflect.Zero(t).Interface().(Formatted).SchemaFormat()}
+	case t.Implements(enumeratedType):
+		return &Schema{Type: "string", Enum: reflect.Zero(t).Interface().(Enumerated).SchemaEnum()}
+	case t.Implements(textMarshalerType) && t.Kind() != reflect.Struct:
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: forType(t.Elem())}
+	case reflect.Array:
+		length := t.Len()
+		return &Schema{Type: "array", Items: forType(t.Elem()), MinItems: &length, MaxItems: &length}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: forType(t.Elem())}
+	case reflect.Struct:
+		return forStruct(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func forStruct(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, ClosedProperties: true}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		s.Properties[name] = forType(field.Type)
+	}
+
+	if t.Implements(requiredType) {
+		s.Required = reflect.Zero(t).Interface().(Required).SchemaRequired()
+	}
+
+	return s
+}
@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type level string
+
+func (level) SchemaEnum() []string {
+	return []string{"junior", "senior"}
+}
+
+type example struct {
+	Name    string            `json:"name"`
+	Level   level             `json:"level"`
+	Friends []string          `json:"friends"`
+	Pair    [2]string         `json:"pair"`
+	Labels  map[string]string `json:"labels"`
+	Count   int               `json:"count"`
+	Ignored string            `json:"-"`
+	hidden  string
+}
+
+func (example) SchemaRequired() []string {
+	return []string{"name"}
+}
+
+func TestGenerateDescribesStructFields(t *testing.T) {
+	s := Generate("example", example{})
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling schema: %v", err)
+	}
+
+	for _, expected := range []string{
+		`"$schema":"` + Draft + `"`,
+		`"title":"example"`,
+		`"additionalProperties":false`,
+		`"level":{"type":"string","enum":["junior","senior"]}`,
+		`"pair":{"type":"array","items":{"type":"string"},"minItems":2,"maxItems":2}`,
+		`"labels":{"type":"object","additionalProperties":{"type":"string"}}`,
+		`"count":{"type":"integer"}`,
+		`"required":["name"]`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected schema to contain %s, got:\n%s", expected, data)
+		}
+	}
+
+	if _, exists := s.Properties["Ignored"]; exists {
+		t.Errorf("Expected ignored field to be excluded from the schema")
+	}
+
+	if _, exists := s.Properties["hidden"]; exists {
+		t.Errorf("Expected unexported field to be excluded from the schema")
+	}
+}
@@ -0,0 +1,224 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Error is a problem with a JSON document, along with where it was found.
+type Error struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("line %d, column %d, %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// Validate checks the JSON document against the schema, returning every problem found.
+// Invalid JSON results in a single error at the position the document could not be parsed.
+func Validate(s *Schema, data []byte) []Error {
+	v := &validator{data: data, decoder: json.NewDecoder(bytes.NewReader(data))}
+	v.decoder.UseNumber()
+
+	if err := v.value(s, "$"); err != nil {
+		return append(v.errors, v.errorAt(v.decoder.InputOffset(), "$", err.Error()))
+	}
+
+	if _, err := v.decoder.Token(); !errors.Is(err, io.EOF) {
+		return append(v.errors, v.errorAt(v.decoder.InputOffset(), "$", "unexpected data after the document"))
+	}
+
+	return v.errors
+}
+
+type validator struct {
+	data    []byte
+	decoder *json.Decoder
+	errors  []Error
+}
+
+// value validates the next value in the document, which a nil schema allows to be anything.
+// Only errors in the document's syntax are returned, problems with its contents are collected.
+func (v *validator) value(s *Schema, path string) error {
+	offset := v.decoder.InputOffset()
+	token, err := v.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	report := func(format string, args ...any) {
+		v.errors = append(v.errors, v.errorAt(offset, path, fmt.Sprintf(format, args...)))
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			return v.object(s, path, report)
+		}
+		return v.array(s, path, report)
+	case nil:
+		return nil
+	case bool:
+		if s != nil && s.Type != "" && s.Type != "boolean" {
+			report("expected %s, got boolean", s.Type)
+		}
+	case json.Number:
+		if s == nil || s.Type == "" {
+			return nil
+		}
+		if s.Type != "number" && s.Type != "integer" {
+			report("expected %s, got number %s", s.Type, t)
+		} else if _, err := t.Int64(); s.Type == "integer" && err != nil {
+			report("expected integer, got %s", t)
+		}
+	case string:
+		if s == nil || s.Type == "" {
+			return nil
+		}
+		if s.Type != "string" {
+			report("expected %s, got string %q", s.Type, t)
+		} else if len(s.Enum) > 0 && !slices.Contains(s.Enum, t) {
+			report("expected one of %s, got %q", strings.Join(s.Enum, ", "), t)
+		} else if err := checkFormat(s.Format, t); err != nil {
+			report("%v", err)
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) object(s *Schema, path string, report func(string, ...any)) error {
+	if s != nil && s.Type != "" && s.Type != "object" {
+		report("expected %s, got object", s.Type)
+		s = nil
+	}
+
+	seen := map[string]bool{}
+	for v.decoder.More() {
+		keyOffset := v.decoder.InputOffset()
+		token, err := v.decoder.Token()
+		if err != nil {
+			return err
+		}
+		key := token.(string)
+		seen[key] = true
+		childPath := path + "." + key
+
+		var child *Schema
+		if s != nil {
+			if property, exists := s.Properties[key]; exists {
+				child = property
+			} else if s.ClosedProperties {
+				v.errors = append(v.errors, v.errorAt(keyOffset, childPath, "unknown field"))
+			} else {
+				child = s.AdditionalProperties
+			}
+		}
+
+		if err := v.value(child, childPath); err != nil {
+			return err
+		}
+	}
+
+	if _, err := v.decoder.Token(); err != nil {
+		return err
+	}
+
+	if s != nil {
+		for _, required := range s.Required {
+			if !seen[required] {
+				report("missing required field %q", required)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) array(s *Schema, path string, report func(string, ...any)) error {
+	if s != nil && s.Type != "" && s.Type != "array" {
+		report("expected %s, got array", s.Type)
+		s = nil
+	}
+
+	var items *Schema
+	if s != nil {
+		items = s.Items
+	}
+
+	count := 0
+	for v.decoder.More() {
+		if err := v.value(items, fmt.Sprintf("%s[%d]", path, count)); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if _, err := v.decoder.Token(); err != nil {
+		return err
+	}
+
+	if s != nil && s.MinItems != nil && count < *s.MinItems {
+		report("expected at least %d items, got %d", *s.MinItems, count)
+	}
+	if s != nil && s.MaxItems != nil && count > *s.MaxItems {
+		report("expected at most %d items, got %d", *s.MaxItems, count)
+	}
+
+	return nil
+}
+
+func checkFormat(format, value string) error {
+	switch format {
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return fmt.Errorf("expected a date as YYYY-MM-DD, got %q", value)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("expected an RFC 3339 date and time, got %q", value)
+		}
+	}
+	return nil
+}
+
+// errorAt creates an error for the value following the offset, skipping any whitespace and separators before it.
+func (v *validator) errorAt(offset int64, path, message string) Error {
+	for offset < int64(len(v.data)) && strings.ContainsRune(" \t\r\n,:", rune(v.data[offset])) {
+		offset++
+	}
+
+	line, column := 1, 1
+	for _, b := range v.data[:min(offset, int64(len(v.data)))] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return Error{Line: line, Column: column, Path: path, Message: message}
+}
+
+// Join combines the errors into one, or returns nil if there are none.
+func Join(errs []Error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	joined := make([]error, 0, len(errs))
+	for _, err := range errs {
+		joined = append(joined, err)
+	}
+	return errors.Join(joined...)
+}
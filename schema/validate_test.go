@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestValidateAcceptsValidDocument(t *testing.T) {
+	data := []byte(`{"name": "Mario", "level": "senior", "pair": ["a", "b"], "labels": {"x": "y"}, "count": 3}`)
+	if errs := Validate(Generate("example", example{}), data); len(errs) != 0 {
+		t.Errorf("Expected no errors, got: %v", errs)
+	}
+}
+
+func TestValidateReportsLocationOfProblems(t *testing.T) {
+	data := []byte(`{
+  "name": "Mario",
+  "levle": "senior",
+  "level": "boss",
+  "pair": ["a"],
+  "count": 1.5
+}`)
+
+	expected := []Error{
+		{Line: 3, Column: 3, Path: "$.levle", Message: "unknown field"},
+		{Line: 4, Column: 12, Path: "$.level", Message: `expected one of junior, senior, got "boss"`},
+		{Line: 5, Column: 11, Path: "$.pair", Message: "expected at least 2 items, got 1"},
+		{Line: 6, Column: 12, Path: "$.count", Message: "expected integer, got 1.5"},
+	}
+
+	errs := Validate(Generate("example", example{}), data)
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got: %v", len(expected), errs)
+	}
+
+	for i := range expected {
+		if errs[i] != expected[i] {
+			t.Errorf("Expected:\n%v\ngot:\n%v", expected[i], errs[i])
+		}
+	}
+}
+
+func TestValidateReportsMissingRequiredFields(t *testing.T) {
+	errs := Validate(Generate("example", example{}), []byte(`{"count": 1}`))
+	if len(errs) != 1 || errs[0].Message != `missing required field "name"` {
+		t.Errorf("Expected missing name error, got: %v", errs)
+	}
+}
+
+func TestValidateReportsInvalidJSON(t *testing.T) {
+	errs := Validate(Generate("example", example{}), []byte(`{"name": "Mario",}`))
+	if len(errs) != 1 {
+		t.Errorf("Expected a single syntax error, got: %v", errs)
+	}
+}
@@ -0,0 +1,169 @@
+package yapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper/schema"
+)
+
+// ConfigSchema returns the JSON Schema of the config file format.
+func ConfigSchema() *schema.Schema {
+	return schema.Generate("yapper config", Config{})
+}
+
+// PairingsSchema returns the JSON Schema of the pairings format written by Pairings.MarshalJSON.
+func PairingsSchema() *schema.Schema {
+	return schema.Generate("yapper pairings", pairingsJSON{})
+}
+
+// exportedPairingsSchema returns the JSON Schema of the list of pairs written by Pairings.Export.
+func exportedPairingsSchema() *schema.Schema {
+	return schema.Generate("yapper exported pairings", [][2]ID{})
+}
+
+// ValidateConfigSchema checks the config data against the schema, reporting the line and field of any problems.
+// The schema describes the current version, but configs of an older version may still have the options moved into
+// the settings since at the top level, as they are migrated when the config is read.
+func ValidateConfigSchema(data []byte) error {
+	errs := schema.Validate(ConfigSchema(), data)
+	if configVersionOf(data) < ConfigSchemaVersion {
+		errs = slices.DeleteFunc(errs, func(err schema.Error) bool {
+			field, found := strings.CutPrefix(err.Path, "$.")
+			return found && err.Message == "unknown field" && slices.Contains(settingsFields, field)
+		})
+	}
+	return schema.Join(errs)
+}
+
+// configVersionOf returns the version of the config data, 1 without a version. Data which cannot be decoded is
+// treated as the current version, as its problems are reported by the schema.
+func configVersionOf(data []byte) int {
+	var versioned struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &versioned); err != nil {
+		return ConfigSchemaVersion
+	} else if versioned.Version == nil {
+		return 1
+	}
+	return *versioned.Version
+}
+
+// ValidatePairingsSchema checks the pairings data against the schema, reporting the line and field of any problems.
+// Like Pairings.UnmarshalJSON, a list is checked as the pairs written by Pairings.Export instead.
+func ValidatePairingsSchema(data []byte) error {
+	s := PairingsSchema()
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		s = exportedPairingsSchema()
+	}
+	return schema.Join(schema.Validate(s, data))
+}
+
+// The values of the enumerated and formatted types, used when generating the schemas.
+
+func (Cadence) SchemaEnum() []string {
+	return []string{string(CadenceOneWeek), string(CadenceTwoWeeks)}
+}
+
+func (Channel) SchemaEnum() []string {
+	return []string{string(ChannelSlack), string(ChannelEmail), string(ChannelNone)}
+}
+
+func (Priority) SchemaEnum() []string {
+	return []string{string(PriorityHigh), string(PriorityNormal), string(PriorityLow)}
+}
+
+func (Preset) SchemaEnum() []string {
+	return []string{string(PresetSkipLevel)}
+}
+
+func (Day) SchemaEnum() []string {
+	values := make([]string, 0, len(weekDays))
+	for _, day := range weekDays {
+		values = append(values, string(day))
+	}
+	return values
+}
+
+func (Strategy) SchemaEnum() []string {
+	values := make([]string, 0, len(Strategies))
+	for _, strategy := range Strategies {
+		values = append(values, string(strategy))
+	}
+	return values
+}
+
+func (SquadPolicy) SchemaEnum() []string {
+	return []string{string(SquadPolicyDeny), string(SquadPolicyPreferDiffering)}
+}
+
+func (HolidayPolicy) SchemaEnum() []string {
+	return []string{string(HolidayPolicySkip), string(HolidayPolicyDeprioritize)}
+}
+
+func (StrangerOrder) SchemaEnum() []string {
+	values := make([]string, 0, len(StrangerOrders))
+	for _, order := range StrangerOrders {
+		values = append(values, string(order))
+	}
+	return values
+}
+
+func (QuorumPolicy) SchemaEnum() []string {
+	return []string{string(QuorumPolicySkip), string(QuorumPolicyWarn)}
+}
+
+func (DiversityAttribute) SchemaEnum() []string {
+	return []string{string(DiversityBySquad), string(DiversityByLocation)}
+}
+
+func (TagRuleKind) SchemaEnum() []string {
+	return []string{string(TagRuleDenyShared), string(TagRulePreferDiffering)}
+}
+
+func (Date) SchemaFormat() string {
+	return "date"
+}
+
+func (Person) SchemaRequired() []string {
+	return []string{"id"}
+}
+
+func (Organizer) SchemaRequired() []string {
+	return []string{"id"}
+}
+
+func (Campaign) SchemaRequired() []string {
+	return []string{"name", "start", "end", "cohorts"}
+}
+
+func (Pin) SchemaRequired() []string {
+	return []string{"people"}
+}
+
+func (Absence) SchemaRequired() []string {
+	return []string{"person", "start", "end"}
+}
+
+func (Curriculum) SchemaRequired() []string {
+	return []string{"start", "topics"}
+}
+
+func (Rule) SchemaRequired() []string {
+	return []string{"deny"}
+}
+
+func (pairingsJSON) SchemaRequired() []string {
+	return []string{"pairs"}
+}
+
+func (pairJSON) SchemaRequired() []string {
+	return []string{"people"}
+}
+
+func (StrategyStep) SchemaRequired() []string {
+	return []string{"strategy"}
+}
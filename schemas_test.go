@@ -0,0 +1,97 @@
+package yapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateConfigSchemaAcceptsValidConfig(t *testing.T) {
+	data, err := os.ReadFile(getPathToConfig(t, validConfigName))
+	if err != nil {
+		t.Fatalf("Error reading config: %v", err)
+	}
+
+	if err := ValidateConfigSchema(data); err != nil {
+		t.Errorf("Unexpected error from ValidateConfigSchema: %v", err)
+	}
+}
+
+func TestValidateConfigSchemaReportsMisspeltField(t *testing.T) {
+	data := []byte("{\n  \"people\": [\n    {\"id\": \"Mario\", \"cadense\": \"two-weeks\"}\n  ]\n}")
+
+	err := ValidateConfigSchema(data)
+	if err == nil {
+		t.Fatalf("Expected error due to misspelt field")
+	}
+
+	if expected := "line 3, column 21, $.people[0].cadense: unknown field"; err.Error() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, err)
+	}
+}
+
+func TestValidatePairingsSchemaAcceptsExportedPairings(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "expectedPairings.json"))
+	if err != nil {
+		t.Fatalf("Error reading pairings: %v", err)
+	}
+
+	if err := ValidatePairingsSchema(data); err != nil {
+		t.Errorf("Unexpected error from ValidatePairingsSchema: %v", err)
+	}
+
+	if err := ValidatePairingsSchema([]byte(`[["id1", "id2", "id3"]]`)); err == nil || !strings.Contains(err.Error(), "at most 2") {
+		t.Errorf("Expected error due to pairing of three people, got: %v", err)
+	}
+}
+
+func TestValidateConfigSchemaAcceptsOptionsOutsideOfSettingsInVersionOne(t *testing.T) {
+	for _, data := range []string{
+		`{"interval": 14, "strategy": "planned", "squadPolicy": "deny"}`,
+		`{"version": 1, "interval": 14}`,
+	} {
+		if err := ValidateConfigSchema([]byte(data)); err != nil {
+			t.Errorf("Unexpected error from ValidateConfigSchema for %s: %v", data, err)
+		}
+		if _, err := decodeConfig([]byte(data)); err != nil {
+			t.Errorf("Unexpected error reading %s: %v", data, err)
+		}
+	}
+
+	if err := ValidateConfigSchema([]byte(`{"interval": 14, "colour": "red"}`)); err == nil {
+		t.Errorf("Expected error due to unknown field in a version 1 config")
+	}
+}
+
+func TestValidatePairingsSchemaAcceptsMarshalledPairings(t *testing.T) {
+	pairings := Pairings{date: time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), penalty: 1.5, score: &PlanScore{Cost: 2, Bound: 1}}
+	pairings.Add("Mario", "Luigi")
+	pairings.Add("Peach", "Toad")
+	lastMet := time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC)
+	pairings.lastMeetings = map[[2]ID]time.Time{pairKey("Mario", "Luigi"): lastMet}
+	pairings.initiators = map[[2]ID]ID{pairKey("Mario", "Luigi"): "Mario"}
+	pairings.topics = map[[2]ID]string{pairKey("Peach", "Toad"): "mushrooms"}
+
+	data, err := json.Marshal(pairings)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling pairings: %v", err)
+	}
+	if err := ValidatePairingsSchema(data); err != nil {
+		t.Errorf("Unexpected error from ValidatePairingsSchema for %s: %v", data, err)
+	}
+
+	if err := ValidatePairingsSchema([]byte(`{"pairs": [{"people": ["Mario"]}]}`)); err == nil {
+		t.Errorf("Expected error due to pair of one person")
+	}
+}
+
+func TestValidateConfigSchemaRejectsOptionsOutsideOfSettings(t *testing.T) {
+	data := fmt.Sprintf(`{"version": %d, "strategy": "planned"}`, ConfigSchemaVersion)
+	if err := ValidateConfigSchema([]byte(data)); err == nil {
+		t.Errorf("Expected error due to strategy outside of the settings: %s", data)
+	}
+}
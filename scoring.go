@@ -0,0 +1,198 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+type SquadPolicy string
+
+const (
+	// SquadPolicyDeny never pairs people from the same squad.
+	SquadPolicyDeny SquadPolicy = "deny"
+	// SquadPolicyPreferDiffering allows pairing people from the same squad, with the same squad soft constraint as the penalty.
+	SquadPolicyPreferDiffering SquadPolicy = "prefer-differing"
+)
+
+// defaultSoftConstraintWeight is used for soft constraints that are enabled without a weight.
+const defaultSoftConstraintWeight = 1.0
+
+// SoftConstraints are the weights of the penalties for undesirable, but allowed, pairings.
+// Hard constraints such as deny lists remove pairings entirely, whereas soft constraints only make a pairing less preferred.
+// Possible pairings with a lower total penalty are preferred over others.
+type SoftConstraints struct {
+	// SameSquad is the penalty for pairing people of the same squad, when the squad policy is prefer-differing.
+	SameSquad float64 `json:"sameSquad"`
+	// SameLocation is the penalty for pairing people of the same location.
+	SameLocation float64 `json:"sameLocation"`
+	// RecentlyMet is the penalty for pairing people who met within RecentlyMetDays,
+	// reducing linearly the longer ago they met.
+	RecentlyMet     float64 `json:"recentlyMet"`
+	RecentlyMetDays int     `json:"recentlyMetDays"`
+	// Holiday is the penalty for each person of a pair whose round is mostly public holidays, when the holiday
+	// policy is deprioritize, defaulting to 1.
+	Holiday float64 `json:"holiday"`
+	// Diversity is the penalty for pairing someone whose partners of the last DiversityWeeks span fewer than
+	// DiversityMin distinct squads, or locations with DiversityBy, with someone of one of those squads again.
+	Diversity      float64            `json:"diversity"`
+	DiversityWeeks int                `json:"diversityWeeks"`
+	DiversityMin   int                `json:"diversityMin"`
+	DiversityBy    DiversityAttribute `json:"diversityBy,omitempty"`
+}
+
+func (s SoftConstraints) validate() error {
+	if s.SameSquad < 0 || s.SameLocation < 0 || s.RecentlyMet < 0 || s.Holiday < 0 || s.Diversity < 0 {
+		return fmt.Errorf("soft constraint weights must not be negative")
+	}
+
+	if s.RecentlyMetDays < 0 {
+		return fmt.Errorf("recently met days must not be negative: %d", s.RecentlyMetDays)
+	}
+
+	if s.DiversityWeeks < 0 || s.DiversityMin < 0 {
+		return fmt.Errorf("diversity weeks and minimum must not be negative")
+	}
+	if err := s.DiversityBy.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// squadPolicy returns the configured squad policy, defaulting to deny.
+func (c Config) squadPolicy() SquadPolicy {
+	if c.Settings.SquadPolicy == "" {
+		return SquadPolicyDeny
+	}
+	return c.Settings.SquadPolicy
+}
+
+// HasSoftConstraints reports whether any soft constraint can result in a penalty.
+func (c Config) HasSoftConstraints() bool {
+	return c.squadPolicy() == SquadPolicyPreferDiffering ||
+		c.SoftConstraints.SameLocation > 0 ||
+		(c.SoftConstraints.RecentlyMet > 0 && c.SoftConstraints.RecentlyMetDays > 0) ||
+		(c.holidayPolicy() == HolidayPolicyDeprioritize && len(c.Holidays) > 0) ||
+		c.SoftConstraints.diversityEnabled() ||
+		slices.ContainsFunc(c.TagRules, func(r TagRule) bool { return r.Kind == TagRulePreferDiffering })
+}
+
+// PairPenalty returns the total penalty of the soft constraints for pairing the two people in the round starting on date.
+func PairPenalty(conf Config, hist history.History, person1, person2 Person, date time.Time) float64 {
+	recent := recentPartnerValues(conf, conf.Index(), hist, date, []Person{person1, person2})
+	return pairPenalty(conf, hist, recent, person1, person2, date)
+}
+
+// pairPenalty returns the penalty of pairing the two people like PairPenalty, given the recent partner values of at
+// least the two of them.
+func pairPenalty(conf Config, hist history.History, recent partnerValues, person1, person2 Person, date time.Time) float64 {
+	lastMeeting, met := hist.GetPersonToLastMeetingMap(history.ID(person1.ID))[history.ID(person2.ID)]
+	return pairPenaltySince(conf, person1, person2, lastMeeting, met, date) + diversityPenalty(conf, recent, person1, person2)
+}
+
+// roundScorer scores the pairs of a round, with the people indexed and their recent partner values worked out once
+// for all of the pairs scored.
+type roundScorer struct {
+	conf   Config
+	hist   history.History
+	date   time.Time
+	index  map[ID]Person
+	recent partnerValues
+}
+
+func newRoundScorer(conf Config, hist history.History, date time.Time) roundScorer {
+	index := conf.Index()
+	return roundScorer{conf: conf, hist: hist, date: date, index: index, recent: recentPartnerValues(conf, index, hist, date, conf.People)}
+}
+
+// penalty returns the penalty of pairing the people with the IDs, or no penalty if either is not in the config.
+func (s roundScorer) penalty(id1, id2 ID) float64 {
+	person1, found1 := s.index[id1]
+	person2, found2 := s.index[id2]
+	if !found1 || !found2 {
+		return 0
+	}
+	return pairPenalty(s.conf, s.hist, s.recent, person1, person2, s.date)
+}
+
+// pairPenaltySince returns the total penalty for pairing the two people, given when they last met, if they have.
+func pairPenaltySince(conf Config, person1, person2 Person, lastMeeting time.Time, met bool, date time.Time) float64 {
+	penalty := 0.0
+	weights := conf.SoftConstraints
+
+	if conf.squadPolicy() == SquadPolicyPreferDiffering && person1.Squad != "" && person1.Squad == person2.Squad {
+		penalty += weightOrDefault(weights.SameSquad)
+	}
+
+	if person1.Location != "" && person1.Location == person2.Location {
+		penalty += weights.SameLocation
+	}
+
+	if met && weights.RecentlyMet > 0 && weights.RecentlyMetDays > 0 {
+		daysSince := date.Sub(lastMeeting).Hours() / 24
+		if window := float64(weights.RecentlyMetDays); daysSince < window {
+			penalty += weights.RecentlyMet * (1 - max(daysSince, 0)/window)
+		}
+	}
+
+	if conf.holidayPolicy() == HolidayPolicyDeprioritize {
+		for _, person := range []Person{person1, person2} {
+			if conf.onHoliday(person, date) {
+				penalty += weightOrDefault(weights.Holiday)
+			}
+		}
+	}
+
+	for _, rule := range conf.TagRules {
+		if rule.Kind == TagRulePreferDiffering && rule.matchedBy(person1, person2) {
+			penalty += weightOrDefault(rule.Weight)
+		}
+	}
+
+	return penalty
+}
+
+// Penalty returns the total penalty of the soft constraints for all of the pairings,
+// using the history from before the pairings were recorded.
+func Penalty(conf Config, hist history.History, pairings Pairings) float64 {
+	scorer := newRoundScorer(conf, hist, pairings.Date())
+	total := 0.0
+	for id1, id2 := range pairings.All() {
+		total += scorer.penalty(id1, id2)
+	}
+	return total
+}
+
+// prioritiseLowestPenalty orders the possible pairings by their penalty, lowest first, otherwise keeping the existing order.
+func prioritiseLowestPenalty(scorer roundScorer, id ID, possiblePairings []ID) []ID {
+	if !scorer.conf.HasSoftConstraints() {
+		return possiblePairings
+	}
+
+	penalties := make(map[ID]float64, len(possiblePairings))
+	for _, other := range possiblePairings {
+		penalties[other] = scorer.penalty(id, other)
+	}
+
+	slices.SortStableFunc(possiblePairings, func(a, b ID) int {
+		switch {
+		case penalties[a] < penalties[b]:
+			return -1
+		case penalties[a] > penalties[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return possiblePairings
+}
+
+func weightOrDefault(weight float64) float64 {
+	if weight == 0 {
+		return defaultSoftConstraintWeight
+	}
+	return weight
+}
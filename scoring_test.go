@@ -0,0 +1,104 @@
+package yapper
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestPrioritiseLowestPenaltyMovesSharedTagsToTheBack(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Tags: []string{"location:berlin"}},
+			{ID: "Luigi", Tags: []string{"location:berlin"}},
+			{ID: "Peach", Tags: []string{"location:paris"}},
+			{ID: "Toad"},
+		},
+		TagRules: []TagRule{{Tag: "location:*", Kind: TagRulePreferDiffering}},
+	}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	ordered := prioritiseLowestPenalty(newRoundScorer(config, history.History{}, date), "Mario", []ID{"Luigi", "Peach", "Toad"})
+	expected := []ID{"Peach", "Toad", "Luigi"}
+
+	if !slices.Equal(ordered, expected) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, ordered)
+	}
+}
+
+func TestPairPenaltyCombinesSoftConstraints(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		Settings: Settings{SquadPolicy: SquadPolicyPreferDiffering},
+		SoftConstraints: SoftConstraints{
+			SameSquad:       2,
+			SameLocation:    0.5,
+			RecentlyMet:     4,
+			RecentlyMetDays: 28,
+		},
+	}
+	mario := Person{ID: "Mario", Squad: "bros", Location: "kingdom"}
+	luigi := Person{ID: "Luigi", Squad: "bros", Location: "kingdom"}
+
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -7))
+
+	// Same squad (2) + same location (0.5) + met a quarter of the window ago (4 * 0.75)
+	expected := 5.5
+	if penalty := PairPenalty(config, hist, mario, luigi, date); math.Abs(penalty-expected) > 1e-9 {
+		t.Errorf("Expected penalty %f, got %f", expected, penalty)
+	}
+
+	hist.AddMeeting("Mario", "Luigi", date.AddDate(0, 0, -28))
+	expected = 2.5
+	if penalty := PairPenalty(config, hist, mario, luigi, date); math.Abs(penalty-expected) > 1e-9 {
+		t.Errorf("Expected penalty %f once outside the recently met window, got %f", expected, penalty)
+	}
+}
+
+func TestDetermineValidPairingsAllowsSameSquadWithPreferDifferingPolicy(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Squad: "bros"},
+			{ID: "Luigi", Squad: "bros"},
+		},
+		Settings: Settings{SquadPolicy: SquadPolicyPreferDiffering},
+	}
+
+	expected := map[ID][]ID{
+		"Mario": {"Luigi"},
+		"Luigi": {"Mario"},
+	}
+	diffPairings(t, determineValidPairings(config), expected)
+}
+
+func TestPairPeopleReportsPenaltyOfPairings(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Squad: "bros"},
+			{ID: "Luigi", Squad: "bros"},
+		},
+		Settings:        Settings{SquadPolicy: SquadPolicyPreferDiffering},
+		SoftConstraints: SoftConstraints{SameSquad: 3},
+	}
+
+	pairings := pairPeople(config, determineValidPairings(config), history.History{}, date)
+	if penalty := pairings.Penalty(); penalty != 3 {
+		t.Errorf("Expected penalty of 3, got %f", penalty)
+	}
+
+	if penalty := Penalty(config, history.History{}, pairings); penalty != 3 {
+		t.Errorf("Expected penalty of 3 from Penalty, got %f", penalty)
+	}
+}
+
+func TestConfigValidateReturnsErrorForUnexpectedSquadPolicy(t *testing.T) {
+	config := Config{Settings: Settings{SquadPolicy: "sometimes"}}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to unexpected squad policy")
+	}
+}
@@ -0,0 +1,408 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// pairingsResponse is the proposed pairings of the current round.
+type pairingsResponse struct {
+	Date     string            `json:"date"`
+	Pairings []pairingResponse `json:"pairings"`
+	// Unpaired are the people without a partner this round.
+	Unpaired []yapper.ID `json:"unpaired"`
+	// Confirmed is whether the pairings have been recorded in the history.
+	Confirmed bool `json:"confirmed"`
+}
+
+type pairingResponse struct {
+	People           [2]yapper.ID `json:"people"`
+	LastMet          string       `json:"lastMet,omitempty"`
+	DaysSinceLastMet *int         `json:"daysSinceLastMet,omitempty"`
+	Pinned           bool         `json:"pinned"`
+	// Status is the progress of the pair in meeting, if reported by a webhook.
+	Status MeetingStatus `json:"status,omitempty"`
+	// Initiator is the person responsible for scheduling the meeting.
+	Initiator yapper.ID `json:"initiator,omitempty"`
+	// Topic is the topic of the curriculum for the pair to discuss.
+	Topic string `json:"topic,omitempty"`
+	// Milestones are the birthdays and work anniversaries of the pair during the round.
+	Milestones []yapper.Milestone `json:"milestones,omitempty"`
+	// Notes are the notes of the organizers about the pair and each of the people.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// recordMeetingRequest records that two people met, on the given date or otherwise today.
+type recordMeetingRequest struct {
+	People [2]yapper.ID `json:"people"`
+	Date   string       `json:"date"`
+}
+
+// pinRequest pins a pair in the proposed pairings.
+type pinRequest struct {
+	People [2]yapper.ID `json:"people"`
+}
+
+// coverageResponse is how long ago each pair of people last met, as of the start of the current round.
+// DaysSinceLastMet[i][j] is nil if People[i] and People[j] have never met.
+type coverageResponse struct {
+	Date             string      `json:"date"`
+	People           []yapper.ID `json:"people"`
+	DaysSinceLastMet [][]*int    `json:"daysSinceLastMet"`
+}
+
+type personResponse struct {
+	ID       yapper.ID         `json:"id"`
+	Squad    string            `json:"squad,omitempty"`
+	Meetings []meetingResponse `json:"meetings"`
+}
+
+type meetingResponse struct {
+	Person yapper.ID `json:"person"`
+	Date   string    `json:"date"`
+}
+
+func (s *Server) handleGetPairings(w http.ResponseWriter, r *http.Request) {
+	s.withProposal(r.Context(), w, func(config yapper.Config, p *proposal) error { return nil })
+}
+
+// handleReroll replaces the pairings which are not pinned, avoiding them for the rest of the round.
+func (s *Server) handleReroll(w http.ResponseWriter, r *http.Request) {
+	s.withProposal(r.Context(), w, func(config yapper.Config, p *proposal) error {
+		if p.confirmed {
+			return errConfirmed
+		}
+
+		for id1, id2 := range p.pairings.All() {
+			if !containsPair(p.pins, id1, id2) && !containsPair(p.rerolled, id1, id2) {
+				p.rerolled = append(p.rerolled, [2]yapper.ID{id1, id2})
+			}
+		}
+		return s.regenerate(r.Context(), config)
+	})
+}
+
+func (s *Server) handleAddPin(w http.ResponseWriter, r *http.Request) {
+	var request pinRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding pin: %w", err))
+		return
+	}
+
+	s.withProposal(r.Context(), w, func(config yapper.Config, p *proposal) error {
+		if p.confirmed {
+			return errConfirmed
+		}
+
+		id1, id2 := request.People[0], request.People[1]
+		if id1 == id2 {
+			return badRequest{fmt.Errorf("cannot pin %s with themselves", id1)}
+		}
+		for _, id := range request.People {
+			if _, err := config.GetPerson(id); err != nil {
+				return badRequest{err}
+			}
+		}
+
+		p.rerolled = slices.DeleteFunc(p.rerolled, func(pair [2]yapper.ID) bool { return containsPair([][2]yapper.ID{pair}, id1, id2) })
+		p.pins = slices.DeleteFunc(p.pins, func(pair [2]yapper.ID) bool {
+			_, found1 := partnerIn(pair, id1)
+			_, found2 := partnerIn(pair, id2)
+			return found1 || found2
+		})
+		p.pins = append(p.pins, request.People)
+		return s.regenerate(r.Context(), config)
+	})
+}
+
+func (s *Server) handleRemovePin(w http.ResponseWriter, r *http.Request) {
+	id1, id2 := yapper.ID(r.PathValue("person1")), yapper.ID(r.PathValue("person2"))
+
+	s.withProposal(r.Context(), w, func(config yapper.Config, p *proposal) error {
+		if p.confirmed {
+			return errConfirmed
+		}
+
+		if !containsPair(p.pins, id1, id2) {
+			return notFound{fmt.Errorf("no pin for %s and %s", id1, id2)}
+		}
+
+		p.pins = slices.DeleteFunc(p.pins, func(pair [2]yapper.ID) bool { return containsPair([][2]yapper.ID{pair}, id1, id2) })
+		return s.regenerate(r.Context(), config)
+	})
+}
+
+// handleConfirm records the proposed pairings in the history, after which they can no longer be changed.
+func (s *Server) handleConfirm(w http.ResponseWriter, r *http.Request) {
+	s.withProposal(r.Context(), w, func(config yapper.Config, p *proposal) error {
+		if p.confirmed {
+			return errConfirmed
+		}
+
+		hist, err := s.loadHistory(r.Context())
+		if err != nil {
+			return err
+		}
+		program := hist.Namespace(config.Namespace)
+
+		// Completed meetings are already recorded on the day they took place, and skipped ones did not take place.
+		for id1, id2 := range p.pairings.All() {
+			switch p.statuses[sortedPair(id1, id2)] {
+			case MeetingCompleted, MeetingSkipped:
+			default:
+				program.AddMeeting(history.ID(id1), history.ID(id2), p.pairings.Date())
+				if initiator, designated := p.pairings.Initiator(id1, id2); designated {
+					program.AddInitiator(history.ID(initiator))
+				}
+				if topic := p.pairings.Topic(id1, id2); topic != "" {
+					program.AddTopic(history.ID(id1), history.ID(id2), topic)
+				}
+			}
+		}
+
+		p.confirmed = true
+		recordProposal(config, program, p)
+		program.SetLastRun(history.Run{Time: time.Now(), Command: "serve"})
+		if err := s.saveHistory(r.Context(), hist); err != nil {
+			p.confirmed = false
+			return err
+		}
+		return nil
+	})
+}
+
+// handleRecordMeeting records a meeting between two people in the history, on the given date or otherwise today.
+func (s *Server) handleRecordMeeting(w http.ResponseWriter, r *http.Request) {
+	var request recordMeetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding meeting: %w", err))
+		return
+	}
+
+	date, err := parseMeetingDate(request.Date)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if status, err := s.recordMeeting(r.Context(), request.People[0], request.People[1], date); err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// recordMeeting adds the meeting to the history, returning the status to respond with if it cannot be recorded.
+// The caller must hold the lock.
+func (s *Server) recordMeeting(ctx context.Context, id1, id2 yapper.ID, date time.Time) (int, error) {
+	config, err := s.loadConfig()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	_, err1 := config.GetPerson(id1)
+	_, err2 := config.GetPerson(id2)
+	if err1 != nil || err2 != nil || id1 == id2 {
+		return http.StatusBadRequest, fmt.Errorf("cannot record a meeting of %s with %s", id1, id2)
+	}
+
+	hist, err := s.loadHistory(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	hist.Namespace(config.Namespace).AddMeeting(history.ID(id1), history.ID(id2), date)
+	if err := s.saveHistory(ctx, hist); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// parseMeetingDate parses a date as YYYY-MM-DD, defaulting to now if it is empty.
+func parseMeetingDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Now(), nil
+	}
+
+	parsed, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD: %w", date, err)
+	}
+	return parsed, nil
+}
+
+// withProposal applies the change to the proposal of the current round and responds with the resulting pairings.
+func (s *Server) withProposal(ctx context.Context, w http.ResponseWriter, change func(yapper.Config, *proposal) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, err := s.loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	p, err := s.currentProposal(ctx, config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := change(config, p); err != nil {
+		var conflictErr conflict
+		var badRequestErr badRequest
+		var notFoundErr notFound
+		switch {
+		case errors.As(err, &badRequestErr):
+			writeError(w, http.StatusBadRequest, err)
+		case errors.As(err, &notFoundErr):
+			writeError(w, http.StatusNotFound, err)
+		case errors.As(err, &conflictErr):
+			writeError(w, http.StatusConflict, err)
+		default:
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if err := s.saveProposal(ctx, config, p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPairingsResponse(config, s.proposal))
+}
+
+func newPairingsResponse(config yapper.Config, p *proposal) pairingsResponse {
+	response := pairingsResponse{
+		Date:      p.pairings.Date().Format(time.DateOnly),
+		Pairings:  []pairingResponse{},
+		Unpaired:  []yapper.ID{},
+		Confirmed: p.confirmed,
+	}
+
+	paired := map[yapper.ID]bool{}
+	for id1, id2 := range p.pairings.All() {
+		pairing := pairingResponse{
+			People: [2]yapper.ID{id1, id2},
+			Pinned: containsPair(p.pins, id1, id2),
+			Status: p.statuses[sortedPair(id1, id2)],
+		}
+		if initiator, designated := p.pairings.Initiator(id1, id2); designated {
+			pairing.Initiator = initiator
+		}
+		pairing.Topic = p.pairings.Topic(id1, id2)
+		pairing.Milestones = config.Milestones(id1, id2, p.pairings.Date())
+		pairing.Notes = config.Notes(id1, id2, p.pairings.Date())
+		if lastMet, met := p.pairings.LastMet(id1, id2); met {
+			days := int(p.pairings.Date().Sub(lastMet).Hours() / 24)
+			pairing.LastMet = lastMet.Format(time.DateOnly)
+			pairing.DaysSinceLastMet = &days
+		}
+
+		response.Pairings = append(response.Pairings, pairing)
+		paired[id1], paired[id2] = true, true
+	}
+
+	for _, person := range config.People {
+		if !paired[person.ID] {
+			response.Unpaired = append(response.Unpaired, person.ID)
+		}
+	}
+
+	return response
+}
+
+func (s *Server) handleCoverage(w http.ResponseWriter, r *http.Request) {
+	config, hist, ok := s.loadState(r.Context(), w)
+	if !ok {
+		return
+	}
+
+	roundStart := s.roundStart(config)
+	response := coverageResponse{Date: roundStart.Format(time.DateOnly), People: config.IDs(), DaysSinceLastMet: [][]*int{}}
+
+	for _, id1 := range response.People {
+		lastMeetings := hist.GetPersonToLastMeetingMap(history.ID(id1))
+		row := make([]*int, len(response.People))
+		for j, id2 := range response.People {
+			if lastMeeting, met := lastMeetings[history.ID(id2)]; met {
+				days := int(roundStart.Sub(lastMeeting).Hours() / 24)
+				row[j] = &days
+			}
+		}
+		response.DaysSinceLastMet = append(response.DaysSinceLastMet, row)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetPerson responds with when the person last met each of the people they have met, most recent first.
+func (s *Server) handleGetPerson(w http.ResponseWriter, r *http.Request) {
+	config, hist, ok := s.loadState(r.Context(), w)
+	if !ok {
+		return
+	}
+
+	person, err := config.GetPerson(yapper.ID(r.PathValue("id")))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	response := personResponse{ID: person.ID, Squad: person.Squad, Meetings: []meetingResponse{}}
+	met := history.GetPeopleMetSortedByLastMeeting(hist, history.ID(person.ID))
+	lastMeetings := hist.GetPersonToLastMeetingMap(history.ID(person.ID))
+	for _, other := range slices.Backward(met) {
+		response.Meetings = append(response.Meetings, meetingResponse{
+			Person: yapper.ID(other),
+			Date:   lastMeetings[other].Format(time.DateOnly),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// loadState reads the config and the history of its namespace, responding with an error if either cannot be read.
+func (s *Server) loadState(ctx context.Context, w http.ResponseWriter) (yapper.Config, history.History, bool) {
+	config, err := s.loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return yapper.Config{}, history.History{}, false
+	}
+
+	hist, err := s.loadHistory(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return yapper.Config{}, history.History{}, false
+	}
+
+	return config, *hist.Namespace(config.Namespace), true
+}
+
+// badRequest is an error caused by the request rather than the state of the server.
+type badRequest struct{ error }
+
+// notFound is an error caused by the request referring to something which does not exist.
+type notFound struct{ error }
+
+// conflict is an error caused by the request not being possible in the current state, such as changing confirmed pairings.
+type conflict struct{ error }
+
+var errConfirmed = conflict{errors.New("the pairings of the current round have already been confirmed")}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// DefaultLeaseTTL is how long the lease of the leader lasts unless LeaderElection.TTL is set.
+const DefaultLeaseTTL = 15 * time.Second
+
+// LeaderElection lets several replicas of the server share a history, such as the pods of a Kubernetes deployment,
+// with only the leader serving the dashboard and REST API so the replicas do not each propose and record pairings.
+// The leader holds a lease on the history file, see history.AcquireLease.
+type LeaderElection struct {
+	// Holder identifies the replica, such as the name of its pod.
+	Holder string
+	// TTL is how long the lease lasts without being renewed, after which another replica takes over.
+	TTL time.Duration
+}
+
+// WithLeaderElection only serves requests while the replica is the leader, responding to everything but the health
+// checks with 503 Service Unavailable otherwise. The replica only becomes the leader once Elect is running.
+func WithLeaderElection(election LeaderElection) Option {
+	return func(s *Server) {
+		if election.TTL <= 0 {
+			election.TTL = DefaultLeaseTTL
+		}
+		s.election = &election
+	}
+}
+
+// Leader reports whether the replica is the leader, which it always is without leader election.
+func (s *Server) Leader() bool {
+	return s.election == nil || s.leader.Load()
+}
+
+// Elect takes the lease on the history whenever it is free and renews it while it is held, until the context is
+// cancelled, after which the lease is released so another replica can take over straight away. It returns at once
+// without leader election.
+func (s *Server) Elect(ctx context.Context) error {
+	if s.election == nil {
+		return nil
+	}
+	if s.election.Holder == "" {
+		return errors.New("leader election requires a holder")
+	}
+
+	ticker := time.NewTicker(s.election.TTL / 3)
+	defer ticker.Stop()
+	for {
+		lease, acquired, err := history.AcquireLease(s.historyPath, s.election.Holder, s.election.TTL, time.Now())
+		if err != nil {
+			yapper.Logger().Error("error acquiring the lease of the leader", "error", err)
+		}
+		if wasLeader := s.leader.Swap(acquired); wasLeader != acquired {
+			yapper.Logger().Info("leadership changed", "leader", acquired, "holder", s.election.Holder, "current", lease.Holder)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.leader.Store(false)
+			return history.ReleaseLease(s.historyPath, s.election.Holder)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown waits for the notifications sent in the background to finish, or for the context to be done. It is
+// called after the HTTP server has shut down, so no new notifications are started.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopping.Store(true)
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleHealth reports that the server is running, for liveness probes.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady reports whether the server should receive requests, which it should unless it is shutting down or
+// another replica is the leader, for readiness probes.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case s.stopping.Load():
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting down"})
+	case !s.Leader():
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not the leader"})
+	default:
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// requireLeader responds with 503 Service Unavailable to the requests a replica which is not the leader receives.
+func (s *Server) requireLeader(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.Leader() {
+			writeError(w, http.StatusServiceUnavailable, errors.New("this replica is not the leader"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
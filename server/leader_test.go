@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestOnlyTheLeaderServesRequests(t *testing.T) {
+	s, historyPath := newTestServer(t)
+	WithLeaderElection(LeaderElection{Holder: "replica-2", TTL: time.Minute})(s)
+	if _, _, err := history.AcquireLease(historyPath, "replica-1", time.Minute, time.Now()); err != nil {
+		t.Fatalf("Unexpected error acquiring lease: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	elected := make(chan error, 1)
+	go func() { elected <- s.Elect(ctx) }()
+
+	request(t, s, http.MethodGet, "/healthz", nil, http.StatusOK, nil)
+	request(t, s, http.MethodGet, "/readyz", nil, http.StatusServiceUnavailable, nil)
+	request(t, s, http.MethodGet, "/api/pairings", nil, http.StatusServiceUnavailable, nil)
+
+	cancel()
+	if err := <-elected; err != nil {
+		t.Fatalf("Unexpected error electing: %v", err)
+	}
+	if err := history.ReleaseLease(historyPath, "replica-1"); err != nil {
+		t.Fatalf("Unexpected error releasing lease: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() { elected <- s.Elect(ctx) }()
+	for deadline := time.Now().Add(time.Second); !s.Leader() && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	request(t, s, http.MethodGet, "/readyz", nil, http.StatusOK, nil)
+	request(t, s, http.MethodGet, "/api/pairings", nil, http.StatusOK, nil)
+
+	cancel()
+	if err := <-elected; err != nil {
+		t.Fatalf("Unexpected error electing: %v", err)
+	}
+	if _, acquired, _ := history.AcquireLease(historyPath, "replica-1", time.Minute, time.Now()); !acquired {
+		t.Errorf("Expected the lease to be released once the election stops")
+	}
+}
+
+func TestServerIsNotReadyOnceShuttingDown(t *testing.T) {
+	s, _ := newTestServer(t)
+	request(t, s, http.MethodGet, "/readyz", nil, http.StatusOK, nil)
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error shutting down: %v", err)
+	}
+	request(t, s, http.MethodGet, "/readyz", nil, http.StatusServiceUnavailable, nil)
+}
@@ -0,0 +1,482 @@
+package server
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookie = "yapper_session"
+	// loginCookie holds the state and nonce of a login in progress.
+	loginCookie     = "yapper_login"
+	sessionDuration = 12 * time.Hour
+	loginDuration   = 10 * time.Minute
+)
+
+// Role decides which parts of the server a signed in user can access.
+type Role string
+
+const (
+	// RoleOrganizer can use the dashboard and every endpoint of the REST API.
+	RoleOrganizer Role = "organizer"
+	// RoleParticipant can only use the participant portal, as the person in the config with the same email.
+	RoleParticipant Role = "participant"
+)
+
+// OIDCConfig configures signing in with an OpenID Connect provider, such as a corporate SSO.
+type OIDCConfig struct {
+	// Issuer is the URL of the provider, from which its configuration is discovered.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the URL of /auth/callback on this server, as registered with the provider.
+	RedirectURL string
+	// Organizers are the emails of the users with the organizer role. Everyone else is a participant.
+	Organizers []string
+	// SessionSecret signs the session cookies.
+	SessionSecret []byte
+	// TrustUnverifiedEmails accepts ID tokens whose email is not verified by the email_verified claim, for providers
+	// which leave the claim out as they only issue emails they control. Otherwise the claim must be true.
+	TrustUnverifiedEmails bool
+	// Client makes the requests to the provider, defaulting to http.DefaultClient.
+	Client *http.Client
+}
+
+// WithOIDC requires organisers and participants to sign in with the OpenID Connect provider.
+// Without it the dashboard and REST API are open to anyone who can reach the server.
+func WithOIDC(config OIDCConfig) Option {
+	return func(s *Server) {
+		if config.Client == nil {
+			config.Client = http.DefaultClient
+		}
+		s.oidc = &oidcProvider{config: config}
+	}
+}
+
+// CheckOIDC discovers the configuration and signing keys of the OpenID Connect provider, returning an error if
+// either cannot be fetched, so that a misconfigured provider is found before anyone tries to sign in.
+func CheckOIDC(config OIDCConfig) error {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	provider := &oidcProvider{config: config}
+
+	discovery, err := provider.getDiscovery()
+	if err != nil {
+		return err
+	}
+
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := provider.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return fmt.Errorf("error getting provider keys: %w", err)
+	}
+	if len(jwks.Keys) == 0 {
+		return errors.New("the provider has no signing keys")
+	}
+	return nil
+}
+
+// oidcProvider discovers the endpoints and keys of the provider on first use.
+type oidcProvider struct {
+	config OIDCConfig
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// session is the signed in user, stored in a signed cookie.
+type session struct {
+	Email   string `json:"email"`
+	Expires int64  `json:"exp"`
+}
+
+type loginState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Redirect string `json:"redirect"`
+	Expires  int64  `json:"exp"`
+}
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Audience      audience `json:"aud"`
+	Expires       int64    `json:"exp"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified *bool    `json:"email_verified"`
+}
+
+// audience is the aud claim, which is either a single string or an array of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("invalid audience: %w", err)
+	}
+	*a = multiple
+	return nil
+}
+
+func (p *oidcProvider) getDiscovery() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := p.getJSON(strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("error discovering OpenID Connect provider: %w", err)
+	}
+
+	if discovery.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("provider issuer %s does not match %s", discovery.Issuer, p.config.Issuer)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// key returns the signing key with the ID, refreshing the keys of the provider if it is unknown as they may have been rotated.
+func (p *oidcProvider) key(discovery *oidcDiscovery, id string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, exists := p.keys[id]; exists {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []struct {
+			ID      string `json:"kid"`
+			Type    string `json:"kty"`
+			Modulus string `json:"n"`
+			Expo    string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("error getting provider keys: %w", err)
+	}
+
+	p.keys = map[string]*rsa.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Type != "RSA" {
+			continue
+		}
+
+		modulus, err1 := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+		exponent, err2 := base64.RawURLEncoding.DecodeString(jwk.Expo)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid provider key %s", jwk.ID)
+		}
+
+		p.keys[jwk.ID] = &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(new(big.Int).SetBytes(exponent).Int64())}
+	}
+
+	key, exists := p.keys[id]
+	if !exists {
+		return nil, fmt.Errorf("unknown provider key %s", id)
+	}
+	return key, nil
+}
+
+func (p *oidcProvider) getJSON(url string, v any) error {
+	response, err := p.config.Client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from %s: %s", url, response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+// exchange redeems the authorization code for an ID token and returns its verified claims.
+func (p *oidcProvider) exchange(code, nonce string) (idTokenClaims, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return idTokenClaims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"client_secret": {p.config.ClientSecret},
+	}
+	response, err := p.config.Client.PostForm(discovery.TokenEndpoint, form)
+	if err != nil {
+		return idTokenClaims{}, fmt.Errorf("error exchanging authorization code: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return idTokenClaims{}, fmt.Errorf("unexpected status exchanging authorization code: %s", response.Status)
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&tokens); err != nil {
+		return idTokenClaims{}, fmt.Errorf("error decoding tokens: %w", err)
+	}
+
+	return p.verify(discovery, tokens.IDToken, nonce, time.Now())
+}
+
+// verify checks the signature and claims of the ID token.
+func (p *oidcProvider) verify(discovery *oidcDiscovery, token, nonce string, now time.Time) (idTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return idTokenClaims{}, errors.New("malformed ID token")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return idTokenClaims{}, fmt.Errorf("malformed ID token header: %w", err)
+	}
+
+	if header.Algorithm != "RS256" {
+		return idTokenClaims{}, fmt.Errorf("unsupported ID token algorithm: %s", header.Algorithm)
+	}
+
+	key, err := p.key(discovery, header.KeyID)
+	if err != nil {
+		return idTokenClaims{}, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return idTokenClaims{}, fmt.Errorf("malformed ID token signature: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return idTokenClaims{}, errors.New("invalid ID token signature")
+	}
+
+	var claims idTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return idTokenClaims{}, fmt.Errorf("malformed ID token claims: %w", err)
+	}
+
+	switch {
+	case claims.Issuer != discovery.Issuer:
+		return idTokenClaims{}, fmt.Errorf("unexpected ID token issuer: %s", claims.Issuer)
+	case !slices.Contains(claims.Audience, p.config.ClientID):
+		return idTokenClaims{}, errors.New("ID token is not intended for this server")
+	case now.Unix() >= claims.Expires:
+		return idTokenClaims{}, errors.New("ID token has expired")
+	case !hmac.Equal([]byte(claims.Nonce), []byte(nonce)):
+		return idTokenClaims{}, errors.New("unexpected ID token nonce")
+	case claims.Email == "" || (!p.config.TrustUnverifiedEmails && (claims.EmailVerified == nil || !*claims.EmailVerified)):
+		return idTokenClaims{}, errors.New("ID token does not include a verified email")
+	}
+
+	return claims, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// handleLogin redirects to the provider to sign in, returning to the redirect parameter afterwards.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	discovery, err := s.oidc.getDiscovery()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	redirect := r.URL.Query().Get("redirect")
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") {
+		redirect = "/"
+	}
+
+	login := loginState{State: randomString(), Nonce: randomString(), Redirect: redirect, Expires: time.Now().Add(loginDuration).Unix()}
+	s.setSignedCookie(w, loginCookie, login, loginDuration)
+
+	authorization, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("invalid authorization endpoint: %w", err))
+		return
+	}
+
+	query := authorization.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", s.oidc.config.ClientID)
+	query.Set("redirect_uri", s.oidc.config.RedirectURL)
+	query.Set("scope", "openid email")
+	query.Set("state", login.State)
+	query.Set("nonce", login.Nonce)
+	authorization.RawQuery = query.Encode()
+
+	http.Redirect(w, r, authorization.String(), http.StatusFound)
+}
+
+// handleCallback completes signing in after the provider redirects back with an authorization code.
+func (s *Server) handleCallback(w http.ResponseWriter, r *http.Request) {
+	var login loginState
+	if err := s.signedCookie(r, loginCookie, &login); err != nil || login.Expires < time.Now().Unix() {
+		writeError(w, http.StatusBadRequest, errors.New("no login in progress"))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: loginCookie, Path: "/", MaxAge: -1})
+
+	if !hmac.Equal([]byte(r.URL.Query().Get("state")), []byte(login.State)) {
+		writeError(w, http.StatusBadRequest, errors.New("unexpected login state"))
+		return
+	}
+
+	if message := r.URL.Query().Get("error"); message != "" {
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("sign in failed: %s", message))
+		return
+	}
+
+	claims, err := s.oidc.exchange(r.URL.Query().Get("code"), login.Nonce)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	s.setSignedCookie(w, sessionCookie, session{Email: claims.Email, Expires: time.Now().Add(sessionDuration).Unix()}, sessionDuration)
+	http.Redirect(w, r, login.Redirect, http.StatusFound)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// currentSession returns the signed in user, if any.
+func (s *Server) currentSession(r *http.Request) (session, bool) {
+	var current session
+	if err := s.signedCookie(r, sessionCookie, &current); err != nil || current.Expires < time.Now().Unix() {
+		return session{}, false
+	}
+	return current, true
+}
+
+// role returns the role of the signed in user.
+func (s *Server) role(current session) Role {
+	if slices.ContainsFunc(s.oidc.config.Organizers, func(email string) bool { return strings.EqualFold(email, current.Email) }) {
+		return RoleOrganizer
+	}
+	return RoleParticipant
+}
+
+// organizer requires the user to be signed in as an organiser before calling the handler, if signing in is enabled.
+func (s *Server) organizer(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.oidc == nil {
+			handler(w, r)
+			return
+		}
+
+		current, signedIn := s.currentSession(r)
+		if !signedIn {
+			writeError(w, http.StatusUnauthorized, errors.New("sign in required"))
+			return
+		}
+
+		if s.role(current) != RoleOrganizer {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%s is not an organizer", current.Email))
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (s *Server) setSignedCookie(w http.ResponseWriter, name string, v any, duration time.Duration) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling cookie %s: %v", name, err))
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    encoded + "." + base64.RawURLEncoding.EncodeToString(s.signCookie(name, encoded)),
+		Path:     "/",
+		MaxAge:   int(duration.Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(s.oidc.config.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (s *Server) signedCookie(r *http.Request, name string, v any) error {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return err
+	}
+
+	encoded, encodedSignature, found := strings.Cut(cookie.Value, ".")
+	if !found {
+		return errors.New("malformed cookie")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.signCookie(name, encoded)) {
+		return errors.New("invalid cookie signature")
+	}
+
+	return decodeSegment(encoded, v)
+}
+
+func (s *Server) signCookie(name, encoded string) []byte {
+	mac := hmac.New(sha256.New, s.oidc.config.SessionSecret)
+	mac.Write([]byte(name + ":" + encoded))
+	return mac.Sum(nil)
+}
+
+func randomString() string {
+	data := make([]byte, 24)
+	if _, err := rand.Read(data); err != nil {
+		panic(fmt.Sprintf("error generating random string: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testConfigWithEmails = `{
+	"version": 1,
+	"people": [
+		{"id": "Mario", "email": "mario@mushroom.kingdom"},
+		{"id": "Luigi", "email": "luigi@mushroom.kingdom"}
+	]
+}`
+
+// fakeProvider is an OpenID Connect provider issuing ID tokens for the email it is given.
+type fakeProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	email  string
+	// nonces are the nonces of each authorization code.
+	nonces map[string]string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unexpected error generating key: %v", err)
+	}
+
+	provider := &fakeProvider{key: key, nonces: map[string]string{}}
+	mux := http.NewServeMux()
+	provider.server = httptest.NewServer(mux)
+	t.Cleanup(provider.server.Close)
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                provider.server.URL,
+			AuthorizationEndpoint: provider.server.URL + "/authorize",
+			TokenEndpoint:         provider.server.URL + "/token",
+			JWKSURI:               provider.server.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": "test",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		nonce, exists := provider.nonces[r.FormValue("code")]
+		if !exists || r.FormValue("client_id") != "yapper" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"id_token": provider.idToken(t, map[string]any{
+			"iss":            provider.server.URL,
+			"aud":            "yapper",
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"nonce":          nonce,
+			"email":          provider.email,
+			"email_verified": true,
+		})})
+	})
+
+	return provider
+}
+
+func (p *fakeProvider) idToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Unexpected error signing ID token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newOIDCServer(t *testing.T, provider *fakeProvider) *Server {
+	t.Helper()
+	s, _ := newTestServer(t)
+	if err := os.WriteFile(s.configPath, []byte(testConfigWithEmails), 0o644); err != nil {
+		t.Fatalf("Unexpected error writing config: %v", err)
+	}
+
+	WithOIDC(OIDCConfig{
+		Issuer:        provider.server.URL,
+		ClientID:      "yapper",
+		ClientSecret:  "secret",
+		RedirectURL:   "http://localhost/auth/callback",
+		Organizers:    []string{"peach@mushroom.kingdom"},
+		SessionSecret: []byte("session secret"),
+	})(s)
+	return s
+}
+
+// signIn goes through the login flow as the email, returning the session cookie.
+func signIn(t *testing.T, s *Server, provider *fakeProvider, email string) *http.Cookie {
+	t.Helper()
+	handler := s.Handler()
+
+	login := httptest.NewRecorder()
+	handler.ServeHTTP(login, httptest.NewRequest(http.MethodGet, "/auth/login?redirect=/portal.html", nil))
+	if login.Code != http.StatusFound {
+		t.Fatalf("Expected a redirect to the provider, got %d: %s", login.Code, login.Body.String())
+	}
+
+	authorization, err := url.Parse(login.Header().Get("Location"))
+	if err != nil || !strings.HasPrefix(authorization.String(), provider.server.URL+"/authorize") {
+		t.Fatalf("Expected a redirect to the authorization endpoint, got %s", login.Header().Get("Location"))
+	}
+
+	provider.email = email
+	provider.nonces["code"] = authorization.Query().Get("nonce")
+
+	callback := httptest.NewRequest(http.MethodGet, "/auth/callback?code=code&state="+url.QueryEscape(authorization.Query().Get("state")), nil)
+	for _, cookie := range login.Result().Cookies() {
+		callback.AddCookie(cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, callback)
+	if recorder.Code != http.StatusFound || recorder.Header().Get("Location") != "/portal.html" {
+		t.Fatalf("Expected a redirect back to the portal, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == sessionCookie {
+			return cookie
+		}
+	}
+	t.Fatalf("Expected a session cookie")
+	return nil
+}
+
+func statusWithCookie(s *Server, method, path string, cookie *http.Cookie) int {
+	req := httptest.NewRequest(method, path, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestOIDCRequiresSignIn(t *testing.T) {
+	s := newOIDCServer(t, newFakeProvider(t))
+
+	for _, path := range []string{"/api/pairings", "/api/me"} {
+		if status := statusWithCookie(s, http.MethodGet, path, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected %s to require signing in, got %d", path, status)
+		}
+	}
+}
+
+func TestOIDCOrganizerCanUseDashboard(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := newOIDCServer(t, provider)
+	cookie := signIn(t, s, provider, "peach@mushroom.kingdom")
+
+	if status := statusWithCookie(s, http.MethodGet, "/api/pairings", cookie); status != http.StatusOK {
+		t.Errorf("Expected the organizer to see the pairings, got %d", status)
+	}
+}
+
+func TestOIDCParticipantCanOnlyUsePortal(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := newOIDCServer(t, provider)
+	cookie := signIn(t, s, provider, "Mario@mushroom.kingdom")
+
+	if status := statusWithCookie(s, http.MethodGet, "/api/pairings", cookie); status != http.StatusForbidden {
+		t.Errorf("Expected the participant not to see the dashboard, got %d", status)
+	}
+
+	if status := statusWithCookie(s, http.MethodGet, "/api/me", cookie); status != http.StatusOK {
+		t.Errorf("Expected the participant to see the portal, got %d", status)
+	}
+}
+
+func TestOIDCRejectsUnknownParticipant(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := newOIDCServer(t, provider)
+	cookie := signIn(t, s, provider, "bowser@koopa.kingdom")
+
+	if status := statusWithCookie(s, http.MethodGet, "/api/me", cookie); status != http.StatusForbidden {
+		t.Errorf("Expected an unknown participant to be forbidden, got %d", status)
+	}
+}
+
+func TestOIDCRejectsTamperedSession(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := newOIDCServer(t, provider)
+	cookie := signIn(t, s, provider, "mario@mushroom.kingdom")
+
+	encoded, signature, _ := strings.Cut(cookie.Value, ".")
+	data, _ := base64.RawURLEncoding.DecodeString(encoded)
+	tampered := strings.Replace(string(data), "mario", "peach", 1)
+	cookie.Value = base64.RawURLEncoding.EncodeToString([]byte(tampered)) + "." + signature
+
+	if status := statusWithCookie(s, http.MethodGet, "/api/pairings", cookie); status != http.StatusUnauthorized {
+		t.Errorf("Expected a tampered session to be rejected, got %d", status)
+	}
+}
+
+func TestVerifyRejectsInvalidIDTokens(t *testing.T) {
+	provider := newFakeProvider(t)
+	s := newOIDCServer(t, provider)
+	discovery, err := s.oidc.getDiscovery()
+	if err != nil {
+		t.Fatalf("Unexpected error discovering provider: %v", err)
+	}
+
+	valid := map[string]any{
+		"iss":            provider.server.URL,
+		"aud":            []string{"other", "yapper"},
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"nonce":          "nonce",
+		"email":          "mario@mushroom.kingdom",
+		"email_verified": true,
+	}
+	if _, err := s.oidc.verify(discovery, provider.idToken(t, valid), "nonce", time.Now()); err != nil {
+		t.Fatalf("Unexpected error verifying valid ID token: %v", err)
+	}
+
+	unverified := map[string]any{}
+	for k, v := range valid {
+		unverified[k] = v
+	}
+	delete(unverified, "email_verified")
+	if _, err := s.oidc.verify(discovery, provider.idToken(t, unverified), "nonce", time.Now()); err == nil {
+		t.Errorf("Expected an error verifying an ID token without email_verified")
+	}
+	s.oidc.config.TrustUnverifiedEmails = true
+	if _, err := s.oidc.verify(discovery, provider.idToken(t, unverified), "nonce", time.Now()); err != nil {
+		t.Errorf("Unexpected error verifying an ID token without email_verified while trusting unverified emails: %v", err)
+	}
+	s.oidc.config.TrustUnverifiedEmails = false
+
+	changes := map[string]map[string]any{
+		"issuer":     {"iss": "https://evil.example"},
+		"audience":   {"aud": "other"},
+		"expired":    {"exp": time.Now().Add(-time.Hour).Unix()},
+		"nonce":      {"nonce": "replayed"},
+		"unverified": {"email_verified": false},
+	}
+	for name, change := range changes {
+		claims := map[string]any{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		for k, v := range change {
+			claims[k] = v
+		}
+
+		if _, err := s.oidc.verify(discovery, provider.idToken(t, claims), "nonce", time.Now()); err == nil {
+			t.Errorf("Expected an error verifying an ID token with an invalid %s", name)
+		}
+	}
+
+	token := provider.idToken(t, valid)
+	tampered := token[:len(token)-4] + "AAAA"
+	if _, err := s.oidc.verify(discovery, tampered, "nonce", time.Now()); err == nil {
+		t.Errorf("Expected an error verifying an ID token with an invalid signature")
+	}
+}
+
+func TestCheckOIDCDiscoversProvider(t *testing.T) {
+	provider := newFakeProvider(t)
+
+	if err := CheckOIDC(OIDCConfig{Issuer: provider.server.URL}); err != nil {
+		t.Errorf("Unexpected error checking provider: %v", err)
+	}
+	if err := CheckOIDC(OIDCConfig{Issuer: provider.server.URL + "/other"}); err == nil {
+		t.Error("Expected error checking a provider which cannot be discovered")
+	}
+}
@@ -0,0 +1,326 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// meResponse is what a participant sees of themselves in the portal.
+type meResponse struct {
+	ID            yapper.ID    `json:"id"`
+	Paused        bool         `json:"paused"`
+	PreferredDays []yapper.Day `json:"preferredDays"`
+	Interests     []string     `json:"interests"`
+	// Partner is who the participant is paired with in the current round, if anyone.
+	Partner *partnerResponse `json:"partner,omitempty"`
+}
+
+type partnerResponse struct {
+	ID   yapper.ID `json:"id"`
+	Date string    `json:"date"`
+	// Met is whether the participant has confirmed meeting their partner since the round started.
+	Met bool `json:"met"`
+}
+
+// updateMeRequest changes the preferences of a participant, leaving any omitted field unchanged.
+type updateMeRequest struct {
+	Paused        *bool         `json:"paused"`
+	PreferredDays *[]yapper.Day `json:"preferredDays"`
+	Interests     *[]string     `json:"interests"`
+}
+
+// meetingRequest confirms that a participant met someone, on the given date or otherwise today.
+type meetingRequest struct {
+	Person yapper.ID `json:"person"`
+	Date   string    `json:"date"`
+}
+
+type portalLinkResponse struct {
+	URL string `json:"url"`
+}
+
+// portalLinkDuration is how long the token of a portal link is accepted for after it is issued.
+const portalLinkDuration = 30 * 24 * time.Hour
+
+// ParticipantToken returns the token a participant uses to sign in to the portal, which expires after 30 days.
+func (s *Server) ParticipantToken(id yapper.ID) string {
+	return s.participantToken(id, time.Now().Add(portalLinkDuration))
+}
+
+func (s *Server) participantToken(id yapper.ID, expires time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(id)) + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+}
+
+func (s *Server) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.portalSecret)
+	mac.Write([]byte("participant:" + payload))
+	return mac.Sum(nil)
+}
+
+// participantFromToken returns the ID of the participant the token was issued to, as long as it has not expired.
+func (s *Server) participantFromToken(token string) (yapper.ID, error) {
+	cut := strings.LastIndex(token, ".")
+	if cut < 0 {
+		return "", errors.New("malformed token")
+	}
+	payload, encodedSignature := token[:cut], token[cut+1:]
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(payload)) {
+		return "", errors.New("invalid token")
+	}
+
+	encodedID, encodedExpires, found := strings.Cut(payload, ".")
+	if !found {
+		return "", errors.New("malformed token")
+	}
+	id, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil {
+		return "", errors.New("malformed token")
+	}
+	expires, err := strconv.ParseInt(encodedExpires, 10, 64)
+	if err != nil {
+		return "", errors.New("malformed token")
+	}
+	if expires < time.Now().Unix() {
+		return "", errors.New("token has expired")
+	}
+
+	return yapper.ID(id), nil
+}
+
+// participant authenticates the request as a participant before calling the handler, either with the bearer token
+// from their portal link or by signing in as the person in the config with the same email.
+func (s *Server) participant(handler func(http.ResponseWriter, *http.Request, yapper.ID)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.portalSecret) == 0 && s.oidc == nil {
+			writeError(w, http.StatusNotFound, errors.New("the participant portal is not enabled"))
+			return
+		}
+
+		if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found && len(s.portalSecret) > 0 {
+			id, err := s.participantFromToken(token)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, err)
+				return
+			}
+
+			handler(w, r, id)
+			return
+		}
+
+		if s.oidc != nil {
+			if current, signedIn := s.currentSession(r); signedIn {
+				id, err := s.participantFromSession(current)
+				if err != nil {
+					writeError(w, http.StatusForbidden, err)
+					return
+				}
+
+				handler(w, r, id)
+				return
+			}
+		}
+
+		writeError(w, http.StatusUnauthorized, errors.New("sign in required"))
+	}
+}
+
+// participantFromSession returns the ID of the person in the config with the email of the signed in user.
+func (s *Server) participantFromSession(current session) (yapper.ID, error) {
+	config, err := s.loadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	index := slices.IndexFunc(config.People, func(p yapper.Person) bool {
+		return p.Email != "" && strings.EqualFold(p.Email, current.Email)
+	})
+	if index == -1 {
+		return "", fmt.Errorf("no person with the email %s", current.Email)
+	}
+
+	return config.People[index].ID, nil
+}
+
+// handlePortalLink responds with the link a participant uses to open the portal, for organisers to share.
+func (s *Server) handlePortalLink(w http.ResponseWriter, r *http.Request) {
+	if len(s.portalSecret) == 0 {
+		writeError(w, http.StatusNotFound, errors.New("the participant portal is not enabled"))
+		return
+	}
+
+	config, err := s.loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	person, err := config.GetPerson(yapper.ID(r.PathValue("id")))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	link := url.URL{Path: s.pathPrefix + "/portal.html", Fragment: "token=" + s.ParticipantToken(person.ID)}
+	writeJSON(w, http.StatusOK, portalLinkResponse{URL: link.String()})
+}
+
+func (s *Server) handleGetMe(w http.ResponseWriter, r *http.Request, id yapper.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, err := s.loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.writeMe(r.Context(), w, config, id)
+}
+
+// handleUpdateMe changes the preferences of the participant in the config, regenerating the proposed pairings
+// if they have not been confirmed yet so that the changes apply to the current round.
+func (s *Server) handleUpdateMe(w http.ResponseWriter, r *http.Request, id yapper.ID) {
+	var request updateMeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding preferences: %w", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, err := s.loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	index := slices.IndexFunc(config.People, func(p yapper.Person) bool { return p.ID == id })
+	if index == -1 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no person with ID %s", id))
+		return
+	}
+
+	config.People = slices.Clone(config.People)
+	person := &config.People[index]
+	if request.Paused != nil {
+		person.Paused = *request.Paused
+	}
+	if request.PreferredDays != nil {
+		person.PreferredDays = *request.PreferredDays
+	}
+	if request.Interests != nil {
+		person.Interests = *request.Interests
+	}
+
+	if err := config.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.saveConfig(config); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if s.proposal != nil && !s.proposal.confirmed {
+		if err := s.regenerate(r.Context(), config); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if err := s.saveProposal(r.Context(), config, s.proposal); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	s.writeMe(r.Context(), w, config, id)
+}
+
+// handleConfirmMeeting records in the history that the participant met someone.
+func (s *Server) handleConfirmMeeting(w http.ResponseWriter, r *http.Request, id yapper.ID) {
+	var request meetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding meeting: %w", err))
+		return
+	}
+
+	date, err := parseMeetingDate(request.Date)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if status, err := s.recordMeeting(r.Context(), id, request.Person, date); err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	config, err := s.loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.writeMe(r.Context(), w, config, id)
+}
+
+// writeMe responds with the participant, including their partner in the current round.
+// The caller must hold the lock.
+func (s *Server) writeMe(ctx context.Context, w http.ResponseWriter, config yapper.Config, id yapper.ID) {
+	person, err := config.GetPerson(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	response := meResponse{
+		ID:            person.ID,
+		Paused:        person.Paused,
+		PreferredDays: append([]yapper.Day{}, person.PreferredDays...),
+		Interests:     append([]string{}, person.Interests...),
+	}
+
+	p, err := s.currentProposal(ctx, config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	hist, err := s.loadHistory(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if partner, found := p.pairings.PartnerOf(id); found {
+		lastMeeting, met := hist.Namespace(config.Namespace).GetPersonToLastMeetingMap(history.ID(id))[history.ID(partner)]
+		response.Partner = &partnerResponse{
+			ID:   partner,
+			Date: p.pairings.Date().Format(time.DateOnly),
+			Met:  met && lastMeeting.After(p.pairings.Date()),
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
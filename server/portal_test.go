@@ -0,0 +1,145 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+func participantRequest(t *testing.T, s *Server, token, method, path string, body any, wantStatus int, response any) {
+	t.Helper()
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatalf("Unexpected error encoding request: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, &reader)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, req)
+
+	if recorder.Code != wantStatus {
+		t.Fatalf("Expected status %d from %s %s, got %d: %s", wantStatus, method, path, recorder.Code, recorder.Body.String())
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Fatalf("Unexpected error decoding response: %v", err)
+		}
+	}
+}
+
+func newPortalServer(t *testing.T) *Server {
+	t.Helper()
+	s, _ := newTestServer(t)
+	WithPortalSecret([]byte("secret"))(s)
+	return s
+}
+
+func TestPortalIsDisabledWithoutSecret(t *testing.T) {
+	s, _ := newTestServer(t)
+	participantRequest(t, s, s.ParticipantToken("Mario"), http.MethodGet, "/api/me", nil, http.StatusNotFound, nil)
+}
+
+func TestPortalRejectsInvalidTokens(t *testing.T) {
+	s := newPortalServer(t)
+	other := New("", "", WithPortalSecret([]byte("other secret")))
+
+	for _, token := range []string{"", "Mario", other.ParticipantToken("Mario")} {
+		participantRequest(t, s, token, http.MethodGet, "/api/me", nil, http.StatusUnauthorized, nil)
+	}
+}
+
+func TestPortalLinksRequireAuthentication(t *testing.T) {
+	s := newPortalServer(t)
+	request(t, s, http.MethodGet, "/api/people/Mario/portal-link", nil, http.StatusForbidden, nil)
+}
+
+func TestPortalRejectsExpiredTokens(t *testing.T) {
+	s := newPortalServer(t)
+	token := s.participantToken("Mario", time.Now().Add(-time.Minute))
+	participantRequest(t, s, token, http.MethodGet, "/api/me", nil, http.StatusUnauthorized, nil)
+}
+
+func TestPortalLinkSignsInParticipant(t *testing.T) {
+	s := newPortalServer(t)
+	issuer, issuerToken, err := NewAPIToken("issuer", []Scope{ScopeIssuePortalLinks})
+	if err != nil {
+		t.Fatalf("Unexpected error creating API token: %v", err)
+	}
+	WithAPITokens([]APIToken{issuerToken})(s)
+
+	var link portalLinkResponse
+	participantRequest(t, s, issuer, http.MethodGet, "/api/people/Mario/portal-link", nil, http.StatusOK, &link)
+	token := link.URL[len("/portal.html#token="):]
+
+	var me meResponse
+	participantRequest(t, s, token, http.MethodGet, "/api/me", nil, http.StatusOK, &me)
+	if me.ID != "Mario" {
+		t.Errorf("Expected to be signed in as Mario, got %s", me.ID)
+	}
+}
+
+func TestPausingRemovesParticipantFromProposal(t *testing.T) {
+	s := newPortalServer(t)
+	token := s.ParticipantToken("Mario")
+
+	paused := true
+	var me meResponse
+	participantRequest(t, s, token, http.MethodPut, "/api/me", updateMeRequest{Paused: &paused}, http.StatusOK, &me)
+	if !me.Paused || me.Partner != nil {
+		t.Errorf("Expected Mario to be paused without a partner, got %+v", me)
+	}
+
+	config, err := s.loadConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error loading config: %v", err)
+	}
+	if person, _ := config.GetPerson("Mario"); !person.Paused {
+		t.Errorf("Expected Mario to be paused in the config")
+	}
+
+	var round pairingsResponse
+	request(t, s, http.MethodGet, "/api/pairings", nil, http.StatusOK, &round)
+	for _, pairing := range round.Pairings {
+		if pairing.People[0] == "Mario" || pairing.People[1] == "Mario" {
+			t.Errorf("Expected paused Mario not to be paired, got %+v", round)
+		}
+	}
+}
+
+func TestUpdateMeRejectsInvalidPreferredDays(t *testing.T) {
+	s := newPortalServer(t)
+
+	days := []yapper.Day{"someday"}
+	participantRequest(t, s, s.ParticipantToken("Mario"), http.MethodPut, "/api/me", updateMeRequest{PreferredDays: &days}, http.StatusBadRequest, nil)
+}
+
+func TestConfirmingMeetingRecordsItInHistory(t *testing.T) {
+	s := newPortalServer(t)
+	token := s.ParticipantToken("Mario")
+
+	var round pairingsResponse
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Peach"}}, http.StatusOK, &round)
+
+	var me meResponse
+	participantRequest(t, s, token, http.MethodGet, "/api/me", nil, http.StatusOK, &me)
+	if me.Partner == nil || me.Partner.ID != "Peach" || me.Partner.Met {
+		t.Fatalf("Expected Mario to be paired with Peach who they have not met yet, got %+v", me)
+	}
+
+	partner := me.Partner.ID
+	participantRequest(t, s, token, http.MethodPost, "/api/me/meetings", meetingRequest{Person: partner}, http.StatusOK, &me)
+	if me.Partner == nil || me.Partner.ID != partner || !me.Partner.Met {
+		t.Errorf("Expected Mario to have met %s, got %+v", partner, me)
+	}
+
+	participantRequest(t, s, token, http.MethodPost, "/api/me/meetings", meetingRequest{Person: "Mario"}, http.StatusBadRequest, nil)
+}
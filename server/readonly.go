@@ -0,0 +1,33 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// WithReadOnly serves the dashboard, API and portal without ever changing the config or history, so they can be served
+// from production with credentials which cannot write. Requests which would change them are rejected with 403
+// Forbidden, and proposals are only kept in memory.
+func WithReadOnly() Option {
+	return func(s *Server) {
+		s.readOnly = true
+	}
+}
+
+// rejectChanges responds with 403 Forbidden to the requests which would change the config or history of a read only
+// server, which are all but those reading and signing in or out.
+func (s *Server) rejectChanges(handler http.Handler) http.Handler {
+	if !s.readOnly {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && !strings.HasPrefix(r.URL.Path, "/auth/") {
+			writeError(w, http.StatusForbidden, fmt.Errorf("the server is read only: %w", history.ErrReadOnly))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestReadOnlyServerNeverWritesHistory(t *testing.T) {
+	s, historyPath := newTestServer(t)
+	WithReadOnly()(s)
+
+	var response pairingsResponse
+	request(t, s, http.MethodGet, "/api/pairings", nil, http.StatusOK, &response)
+	if len(response.Pairings) == 0 {
+		t.Errorf("Expected pairings to be proposed")
+	}
+	request(t, s, http.MethodPost, "/api/pairings/reroll", map[string]any{}, http.StatusForbidden, nil)
+	request(t, s, http.MethodPost, "/api/pairings/confirm", nil, http.StatusForbidden, nil)
+
+	if _, err := os.Stat(historyPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", os.ErrNotExist, err)
+	}
+}
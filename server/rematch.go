@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/notify"
+)
+
+// WithNotifier notifies the new pairs through the provider when people are re-matched after declining.
+func WithNotifier(provider notify.Provider) Option {
+	return func(s *Server) {
+		s.notifier = provider
+	}
+}
+
+// rematch removes the declined pair from the proposal and re-matches them with anyone else without a partner.
+// The declined pair is avoided if the proposal is regenerated, and the new pairs are recorded in the history if the
+// proposal was already confirmed. The caller must hold the lock.
+func (s *Server) rematch(ctx context.Context, config yapper.Config, p *proposal, pair [2]yapper.ID) error {
+	hist, err := s.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	program := hist.Namespace(config.Namespace)
+
+	if !containsPair(p.rerolled, pair[0], pair[1]) {
+		p.rerolled = append(p.rerolled, pair)
+	}
+	p.pins = slices.DeleteFunc(p.pins, func(pin [2]yapper.ID) bool { return containsPair([][2]yapper.ID{pin}, pair[0], pair[1]) })
+	if p.statuses == nil {
+		p.statuses = map[[2]yapper.ID]MeetingStatus{}
+	}
+	p.statuses[sortedPair(pair[0], pair[1])] = MeetingDeclined
+
+	var declined [][2]yapper.ID
+	for declinedPair, status := range p.statuses {
+		if status == MeetingDeclined {
+			declined = append(declined, declinedPair)
+		}
+	}
+
+	rematched := p.pairings.Rematch(config, *program, declined)
+
+	if p.confirmed && len(rematched) > 0 {
+		for _, newPair := range rematched {
+			program.AddMeeting(history.ID(newPair[0]), history.ID(newPair[1]), p.pairings.Date())
+			if initiator, designated := p.pairings.Initiator(newPair[0], newPair[1]); designated {
+				program.AddInitiator(history.ID(initiator))
+			}
+			if topic := p.pairings.Topic(newPair[0], newPair[1]); topic != "" {
+				program.AddTopic(history.ID(newPair[0]), history.ID(newPair[1]), topic)
+			}
+		}
+		if err := s.saveHistory(ctx, hist); err != nil {
+			return err
+		}
+	}
+
+	if s.notifier != nil && len(rematched) > 0 {
+		date := p.pairings.Date()
+		pairings := make([]notify.Pairing, 0, len(rematched))
+		for _, pair := range rematched {
+			initiator, _ := p.pairings.Initiator(pair[0], pair[1])
+			lastMet, _ := p.pairings.LastMet(pair[0], pair[1])
+			pairings = append(pairings, notify.Pairing{
+				People:     pair,
+				Icebreaker: config.Icebreaker(pair[0], pair[1], date),
+				Initiator:  initiator,
+				Topic:      p.pairings.Topic(pair[0], pair[1]),
+				Milestones: config.Milestones(pair[0], pair[1], date),
+				Notes:      config.Notes(pair[0], pair[1], date),
+				LastMet:    lastMet,
+			})
+		}
+		s.background.Add(1)
+		go func() {
+			defer s.background.Done()
+			s.notifyPairs(context.WithoutCancel(ctx), config, date, pairings)
+		}()
+	}
+	return nil
+}
+
+// notifyPairs introduces the people of each pairing to each other, logging any failure with yapper.Logger.
+func (s *Server) notifyPairs(ctx context.Context, config yapper.Config, date time.Time, pairings []notify.Pairing) {
+	messages, skipped, err := notify.PairingMessages(config, s.notifier, date, pairings)
+	if err != nil {
+		yapper.Logger().Error("error creating messages for re-matched pairs", "error", err)
+		return
+	}
+	for _, pairing := range skipped {
+		yapper.Logger().Warn("not notifying re-matched pair missing an address", "person1", pairing.People[0], "person2", pairing.People[1], "provider", s.notifier.Name())
+	}
+
+	if allowed := s.notified.reserve(s.quota, date, len(messages)); allowed < len(messages) {
+		yapper.Logger().Warn("not notifying re-matched pairs beyond the notification quota", "skipped", len(messages)-allowed, "quota", s.quota.MaxNotifications)
+		messages = messages[:allowed]
+	}
+	if _, err := notify.Send(ctx, s.notifier, messages); err != nil {
+		yapper.Logger().Error("error notifying re-matched pairs", "error", err)
+	}
+}
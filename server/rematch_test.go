@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/notify"
+)
+
+// channelProvider sends the messages it is given to a channel, addressing everyone by their ID.
+type channelProvider struct {
+	messages chan notify.Message
+}
+
+func (p channelProvider) Name() string                        { return "channel" }
+func (p channelProvider) Limits() notify.Limits               { return notify.Limits{} }
+func (p channelProvider) Address(person yapper.Person) string { return string(person.ID) }
+
+func (p channelProvider) Send(_ context.Context, messages []notify.Message) error {
+	for _, message := range messages {
+		p.messages <- message
+	}
+	return nil
+}
+
+func TestDeclinedPairIsRematchedAndNotified(t *testing.T) {
+	s, _ := newWebhookServer(t)
+	provider := channelProvider{messages: make(chan notify.Message, 1)}
+	WithNotifier(provider)(s)
+
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Peach"}}, http.StatusOK, nil)
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Luigi", "Toad"}}, http.StatusOK, nil)
+
+	var round pairingsResponse
+	webhookRequest(t, s, "webhook secret", meetingStatusRequest{People: [2]yapper.ID{"Mario", "Peach"}, Status: MeetingDeclined}, http.StatusOK, &round)
+
+	if _, found := findPairing(round, "Mario", "Peach"); found {
+		t.Errorf("Expected the declined pair to be removed, got %+v", round)
+	}
+
+	_, withMario := findPairing(round, "Mario", "Yoshi")
+	_, withPeach := findPairing(round, "Peach", "Yoshi")
+	if !withMario && !withPeach {
+		t.Fatalf("Expected Yoshi to be re-matched with Mario or Peach, got %+v", round)
+	}
+
+	select {
+	case message := <-provider.messages:
+		if len(message.Recipients) != 2 || (message.Recipients[0] != "Yoshi" && message.Recipients[1] != "Yoshi") {
+			t.Errorf("Expected the new pair with Yoshi to be notified, got %+v", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the new pair to be notified")
+	}
+
+	var rerolled pairingsResponse
+	request(t, s, http.MethodPost, "/api/pairings/reroll", nil, http.StatusOK, &rerolled)
+	if _, found := findPairing(rerolled, "Mario", "Peach"); found {
+		t.Errorf("Expected the declined pair to stay avoided after re-rolling, got %+v", rerolled)
+	}
+}
@@ -0,0 +1,461 @@
+// Package server serves a REST API and web dashboard for organisers to review, adjust and confirm pairings.
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/notify"
+	"github.com/AleksaSvitlica/yapper/signing"
+	"github.com/AleksaSvitlica/yapper/telemetry"
+)
+
+//go:embed static
+var static embed.FS
+
+// Server proposes the pairings of the current round, which organisers can re-roll or pin before confirming them.
+// The config and history are read from their files on each request, so changes to them are picked up without a restart.
+type Server struct {
+	configPath  string
+	historyPath string
+	// portalSecret signs the tokens of the participant portal, which is disabled if there is no secret.
+	portalSecret []byte
+	// oidc signs in organisers and participants, without which the dashboard and REST API are not protected.
+	oidc *oidcProvider
+	// apiTokens grant integrations access to the REST API.
+	apiTokens []APIToken
+	// webhookSecret and slackSigningSecret verify the requests of the meeting status webhooks, which are disabled without them.
+	webhookSecret      []byte
+	slackSigningSecret []byte
+	// notifier notifies pairs who are re-matched, who are not notified without it.
+	notifier notify.Provider
+	// maxRequestSize is the most bytes read from the body of a request, see WithMaxRequestSize.
+	maxRequestSize int64
+	// quota limits the people and notifications of the server, see WithQuota.
+	quota    Quota
+	notified notificationCount
+	// pathPrefix is the path the server is served under, such as that of its tenant, see NewTenants.
+	pathPrefix string
+	// election lets one of several replicas serve requests, see WithLeaderElection.
+	election *LeaderElection
+	leader   atomic.Bool
+	// stopping is set once the server is shutting down, see Shutdown.
+	stopping atomic.Bool
+	// background are the notifications being sent, which Shutdown waits for.
+	background sync.WaitGroup
+	// readOnly is set if the config and history must not be changed, see WithReadOnly.
+	readOnly bool
+	// signingKey signs the history file each time it is written, see WithSigningKey.
+	signingKey ed25519.PrivateKey
+
+	mu       sync.Mutex
+	proposal *proposal
+}
+
+// proposal is the pairings of the current round which have not been confirmed yet.
+type proposal struct {
+	pairings yapper.Pairings
+	// pins are the pairs pinned from the dashboard.
+	pins [][2]yapper.ID
+	// rerolled are the pairs which were re-rolled and so are avoided for the rest of the round.
+	rerolled [][2]yapper.ID
+	// statuses are the progress of each pair reported by the webhooks, keyed by the sorted pair.
+	statuses map[[2]yapper.ID]MeetingStatus
+	// confirmed is whether the pairings have been recorded in the history.
+	confirmed bool
+	// saved is the proposal as last kept in the state of the history, see saveProposal.
+	saved savedProposal
+}
+
+// savedProposal is the proposal as it is kept in the state of the history, as the pending round until it is
+// confirmed and as the last round after.
+type savedProposal struct {
+	pending   history.Pending
+	confirmed bool
+}
+
+// Option configures optional features of the server.
+type Option func(*Server)
+
+// DefaultMaxRequestSize is the most bytes read from the body of a request unless WithMaxRequestSize is given.
+const DefaultMaxRequestSize = 1 << 20
+
+// WithMaxRequestSize limits the bodies of requests to the given number of bytes, rejecting larger requests with
+// 413 Request Entity Too Large before they are decoded. The limit protects the server from untrusted clients and
+// webhooks sending huge bodies.
+func WithMaxRequestSize(bytes int64) Option {
+	return func(s *Server) {
+		s.maxRequestSize = bytes
+	}
+}
+
+// WithSigningKey signs the history file with the key each time it is written, like the commands do while
+// YAPPER_SIGNING_KEY is set, so its signature stays valid. Histories in a store are not signed.
+func WithSigningKey(key ed25519.PrivateKey) Option {
+	return func(s *Server) {
+		s.signingKey = key
+	}
+}
+
+// WithPortalSecret enables the participant portal, signing the tokens of participants with the secret.
+func WithPortalSecret(secret []byte) Option {
+	return func(s *Server) {
+		s.portalSecret = secret
+	}
+}
+
+// New returns a server for the program with the config and history at the given paths.
+// The history file is created when the first pairings are confirmed if it does not exist.
+func New(configPath, historyPath string, options ...Option) *Server {
+	s := &Server{configPath: configPath, historyPath: historyPath, maxRequestSize: DefaultMaxRequestSize}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Handler returns the handler of the REST API, under /api/, the dashboard and the health checks, /healthz and /readyz
+// for liveness and readiness probes. Each request is traced if telemetry is enabled.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/pairings", s.authorize(ScopeReadPairings, s.handleGetPairings))
+	mux.HandleFunc("POST /api/pairings/reroll", s.authorize(ScopeGeneratePairings, s.handleReroll))
+	mux.HandleFunc("POST /api/pairings/confirm", s.authorize(ScopeRecordMeetings, s.handleConfirm))
+	mux.HandleFunc("POST /api/pins", s.authorize(ScopeGeneratePairings, s.handleAddPin))
+	mux.HandleFunc("DELETE /api/pins/{person1}/{person2}", s.authorize(ScopeGeneratePairings, s.handleRemovePin))
+	mux.HandleFunc("GET /api/coverage", s.authorize(ScopeReadPairings, s.handleCoverage))
+	mux.HandleFunc("GET /api/people/{id}", s.authorize(ScopeReadPairings, s.handleGetPerson))
+	mux.HandleFunc("POST /api/meetings", s.authorize(ScopeRecordMeetings, s.handleRecordMeeting))
+	mux.HandleFunc("GET /api/people/{id}/portal-link", s.authorizeAlways(ScopeIssuePortalLinks, s.handlePortalLink))
+
+	mux.HandleFunc("GET /api/me", s.participant(s.handleGetMe))
+	mux.HandleFunc("PUT /api/me", s.participant(s.handleUpdateMe))
+	mux.HandleFunc("POST /api/me/meetings", s.participant(s.handleConfirmMeeting))
+
+	if s.webhookSecret != nil {
+		mux.HandleFunc("POST /webhooks/meetings", s.handleMeetingWebhook)
+	}
+	if s.slackSigningSecret != nil {
+		mux.HandleFunc("POST /webhooks/slack", s.handleSlackWebhook)
+	}
+
+	if s.oidc != nil {
+		mux.HandleFunc("GET /auth/login", s.handleLogin)
+		mux.HandleFunc("GET /auth/callback", s.handleCallback)
+		mux.HandleFunc("POST /auth/logout", s.handleLogout)
+	}
+
+	dashboard, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(fmt.Sprintf("error loading embedded dashboard: %v", err))
+	}
+	mux.Handle("GET /", http.FileServerFS(dashboard))
+
+	root := http.NewServeMux()
+	root.HandleFunc("GET /healthz", s.handleHealth)
+	root.HandleFunc("GET /readyz", s.handleReady)
+	root.Handle("/", s.requireLeader(s.rejectChanges(mux)))
+	return telemetry.Handler(http.MaxBytesHandler(root, s.maxRequestSize))
+}
+
+func (s *Server) loadConfig() (yapper.Config, error) {
+	config, err := yapper.NewConfigFromFile(s.configPath)
+	if err != nil {
+		return yapper.Config{}, err
+	}
+	if err := s.quota.checkPeople(config); err != nil {
+		return yapper.Config{}, err
+	}
+	return config, nil
+}
+
+// loadHistory reads the history from its file and journal, or its store if the history path is a store URL, returning
+// an empty history if none exists yet. The meetings of the config's program are in the namespace of the config, see
+// yapper.Config.Namespace.
+func (s *Server) loadHistory(ctx context.Context) (history.History, error) {
+	ctx, span := telemetry.Start(ctx, "history.read", telemetry.String("yapper.history", s.historyPath))
+	defer span.End()
+
+	var hist history.History
+	var err error
+	if history.IsStoreURL(s.historyPath) {
+		var store history.Store
+		if store, err = history.OpenStore(s.historyPath); err == nil {
+			hist, err = store.Load(ctx)
+		}
+	} else {
+		hist, err = history.ReadFile(s.historyPath)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return history.History{}, nil
+	} else if err != nil {
+		span.RecordError(err)
+		return history.History{}, fmt.Errorf("error reading history file %s: %w", s.historyPath, err)
+	}
+	return hist, nil
+}
+
+func (s *Server) saveConfig(config yapper.Config) error {
+	if s.readOnly {
+		return fmt.Errorf("config file %s cannot be changed: %w", s.configPath, history.ErrReadOnly)
+	}
+	if config.Interpolated() {
+		return fmt.Errorf("config file %s refers to environment variables and cannot be changed", s.configPath)
+	}
+
+	file, err := os.Create(s.configPath)
+	if err != nil {
+		return fmt.Errorf("error creating config file %s: %w", s.configPath, err)
+	}
+
+	if err := config.Export(file); err != nil {
+		file.Close()
+		return fmt.Errorf("error exporting config to file %s: %w", s.configPath, err)
+	}
+
+	return file.Close()
+}
+
+// saveHistory writes the history while holding the lock on its file, failing rather than overwriting the changes of a
+// command run at the same time. Histories in a store are saved only if they were not changed since they were loaded.
+func (s *Server) saveHistory(ctx context.Context, hist history.History) error {
+	ctx, span := telemetry.Start(ctx, "history.write", telemetry.String("yapper.history", s.historyPath))
+	defer span.End()
+
+	if s.readOnly {
+		return fmt.Errorf("history %s cannot be changed: %w", s.historyPath, history.ErrReadOnly)
+	}
+
+	if history.IsStoreURL(s.historyPath) {
+		store, err := history.OpenStore(s.historyPath)
+		if err == nil {
+			err = store.Save(ctx, &hist)
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	unlock, err := history.Lock(s.historyPath)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer unlock()
+
+	if err := hist.WriteFile(s.historyPath); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("error writing history file %s: %w", s.historyPath, err)
+	}
+	if s.signingKey != nil {
+		if err := signing.SignFile(s.signingKey, s.historyPath); err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("error signing history file %s: %w", s.historyPath, err)
+		}
+	}
+	return nil
+}
+
+// currentProposal returns the proposal for the current round, generating one if there is none.
+// The caller must hold the lock.
+func (s *Server) currentProposal(ctx context.Context, config yapper.Config) (*proposal, error) {
+	if s.proposal != nil && s.proposal.pairings.Date().Equal(s.roundStart(config)) {
+		return s.proposal, nil
+	}
+
+	hist, err := s.loadHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if restored, found := restoreProposal(*hist.Namespace(config.Namespace), s.roundStart(config)); found {
+		s.proposal = restored
+		return s.proposal, nil
+	}
+
+	s.proposal = &proposal{}
+	if err := s.regenerate(ctx, config); err != nil {
+		s.proposal = nil
+		return nil, err
+	}
+	if err := s.saveProposal(ctx, config, s.proposal); err != nil {
+		return nil, err
+	}
+	return s.proposal, nil
+}
+
+// restoreProposal returns the proposal of the round starting on the date from the state of the history, so the
+// pins, re-rolls and statuses of the round survive the server restarting. False is returned if the state has no
+// proposal for the round.
+func restoreProposal(hist history.History, roundStart time.Time) (*proposal, bool) {
+	var saved savedProposal
+	if pending, found := hist.Pending(); found && pending.Round.Date.Equal(roundStart) {
+		saved = savedProposal{pending: pending}
+	} else if round, found := hist.LastRound(); found && round.Date.Equal(roundStart) {
+		saved = savedProposal{pending: history.Pending{Round: round}, confirmed: true}
+	} else {
+		return nil, false
+	}
+
+	p := &proposal{pairings: yapper.NewPairingsFromRound(saved.pending.Round), confirmed: saved.confirmed, saved: saved}
+	for _, pair := range saved.pending.Pins {
+		p.pins = append(p.pins, [2]yapper.ID{yapper.ID(pair[0]), yapper.ID(pair[1])})
+	}
+	for _, pair := range saved.pending.Rerolled {
+		p.rerolled = append(p.rerolled, [2]yapper.ID{yapper.ID(pair[0]), yapper.ID(pair[1])})
+	}
+	for _, pair := range saved.pending.Round.Pairs {
+		if pair.Status == "" {
+			continue
+		}
+		if p.statuses == nil {
+			p.statuses = map[[2]yapper.ID]MeetingStatus{}
+		}
+		p.statuses[sortedPair(yapper.ID(pair.People[0]), yapper.ID(pair.People[1]))] = MeetingStatus(pair.Status)
+	}
+	return p, true
+}
+
+// newSavedProposal returns the proposal as it is kept in the state of the history.
+func newSavedProposal(config yapper.Config, p *proposal) savedProposal {
+	round := yapper.NewHistoryRound(config, p.pairings)
+	for i, pair := range round.Pairs {
+		round.Pairs[i].Status = string(p.statuses[sortedPair(yapper.ID(pair.People[0]), yapper.ID(pair.People[1]))])
+	}
+
+	saved := savedProposal{pending: history.Pending{Round: round}, confirmed: p.confirmed}
+	for _, pair := range p.pins {
+		saved.pending.Pins = append(saved.pending.Pins, [2]history.ID{history.ID(pair[0]), history.ID(pair[1])})
+	}
+	for _, pair := range p.rerolled {
+		saved.pending.Rerolled = append(saved.pending.Rerolled, [2]history.ID{history.ID(pair[0]), history.ID(pair[1])})
+	}
+	return saved
+}
+
+// recordProposal keeps the proposal in the state of the history, as the pending round until it is confirmed and as
+// the last round after.
+func recordProposal(config yapper.Config, hist *history.History, p *proposal) {
+	saved := newSavedProposal(config, p)
+	if saved.confirmed {
+		hist.ClearPending()
+		hist.SetLastRound(saved.pending.Round)
+	} else {
+		hist.SetPending(saved.pending)
+	}
+	p.saved = saved
+}
+
+// saveProposal keeps the proposal in the state of the history file unless it is unchanged since it was last kept, or
+// the server is read only.
+// The caller must hold the lock.
+func (s *Server) saveProposal(ctx context.Context, config yapper.Config, p *proposal) error {
+	if s.readOnly || reflect.DeepEqual(newSavedProposal(config, p), p.saved) {
+		return nil
+	}
+
+	hist, err := s.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	recordProposal(config, hist.Namespace(config.Namespace), p)
+	return s.saveHistory(ctx, hist)
+}
+
+// regenerate replaces the pairings of the proposal, keeping the pinned pairs and avoiding the re-rolled ones.
+// The caller must hold the lock.
+func (s *Server) regenerate(ctx context.Context, config yapper.Config) error {
+	hist, err := s.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	roundStart := yapper.NewDate(s.roundStart(config))
+	pins := make([]yapper.Pin, 0, len(s.proposal.pins)+len(config.Pins))
+	for _, pin := range s.proposal.pins {
+		pins = append(pins, yapper.Pin{People: pin, Date: &roundStart})
+	}
+	config.Pins = append(pins, config.Pins...)
+
+	people := slices.Clone(config.People)
+	for i := range people {
+		for _, pair := range s.proposal.rerolled {
+			if other, found := partnerIn(pair, people[i].ID); found {
+				people[i].DenyList = append(slices.Clone(people[i].DenyList), other)
+			}
+		}
+	}
+	config.People = people
+
+	weeklyPairings, err := yapper.GeneratePairingsContext(ctx, config, hist.Namespace(config.Namespace), 1)
+	if err != nil {
+		return err
+	}
+
+	s.proposal.pairings = weeklyPairings[0]
+	return nil
+}
+
+// roundStart returns the start of the current round in the timezone of the config.
+func (s *Server) roundStart(config yapper.Config) time.Time {
+	location, err := config.Location()
+	if err != nil {
+		location = time.UTC
+	}
+	return config.RoundStart(time.Now().In(location))
+}
+
+// partnerIn returns the other person of the pair if the person is one of them.
+func partnerIn(pair [2]yapper.ID, id yapper.ID) (yapper.ID, bool) {
+	switch id {
+	case pair[0]:
+		return pair[1], true
+	case pair[1]:
+		return pair[0], true
+	default:
+		return "", false
+	}
+}
+
+func containsPair(pairs [][2]yapper.ID, id1, id2 yapper.ID) bool {
+	return slices.ContainsFunc(pairs, func(pair [2]yapper.ID) bool {
+		other, found := partnerIn(pair, id1)
+		return found && other == id2
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		yapper.Logger().Error("error writing response", "error", err)
+	}
+}
+
+// writeError writes the error with the status, or with 413 Request Entity Too Large if the error is from reading a
+// body larger than the limit of the server.
+func writeError(w http.ResponseWriter, status int, err error) {
+	if maxBytesError := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesError) {
+		status = http.StatusRequestEntityTooLarge
+	} else if quotaErr := (quotaExceeded{}); errors.As(err, &quotaErr) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, history.ErrConflict) {
+		status = http.StatusConflict
+	} else if errors.Is(err, history.ErrReadOnly) {
+		status = http.StatusForbidden
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/signing"
+)
+
+const testConfig = `{
+	"version": 1,
+	"people": [
+		{"id": "Mario", "squad": "bros"},
+		{"id": "Luigi", "squad": "bros"},
+		{"id": "Peach", "squad": "royals"},
+		{"id": "Toad", "squad": "royals"},
+		{"id": "Yoshi"}
+	]
+}`
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, []byte(testConfig), 0o644); err != nil {
+		t.Fatalf("Unexpected error writing config: %v", err)
+	}
+
+	historyPath := filepath.Join(dir, "history.json")
+	return New(configPath, historyPath), historyPath
+}
+
+func request(t *testing.T, s *Server, method, path string, body any, wantStatus int, response any) {
+	t.Helper()
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatalf("Unexpected error encoding request: %v", err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest(method, path, &reader))
+
+	if recorder.Code != wantStatus {
+		t.Fatalf("Expected status %d from %s %s, got %d: %s", wantStatus, method, path, recorder.Code, recorder.Body.String())
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Fatalf("Unexpected error decoding response: %v", err)
+		}
+	}
+}
+
+func findPairing(round pairingsResponse, id1, id2 yapper.ID) (pairingResponse, bool) {
+	for _, pairing := range round.Pairings {
+		if pairing.People == [2]yapper.ID{id1, id2} || pairing.People == [2]yapper.ID{id2, id1} {
+			return pairing, true
+		}
+	}
+	return pairingResponse{}, false
+}
+
+func TestGetPairingsProposesPairingsForEveryone(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	var round pairingsResponse
+	request(t, s, http.MethodGet, "/api/pairings", nil, http.StatusOK, &round)
+
+	if len(round.Pairings) != 2 || len(round.Unpaired) != 1 {
+		t.Errorf("Expected 2 pairings and 1 unpaired person, got %+v", round)
+	}
+
+	var again pairingsResponse
+	request(t, s, http.MethodGet, "/api/pairings", nil, http.StatusOK, &again)
+	if !bytes.Equal(mustMarshal(t, round), mustMarshal(t, again)) {
+		t.Errorf("Expected the proposal to be kept between requests.\nExpected:\n%+v\nGot:\n%+v", round, again)
+	}
+}
+
+func TestPinnedPairSurvivesReroll(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	var round pairingsResponse
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Yoshi"}}, http.StatusOK, &round)
+	if pairing, found := findPairing(round, "Mario", "Yoshi"); !found || !pairing.Pinned {
+		t.Fatalf("Expected Mario and Yoshi to be pinned, got %+v", round)
+	}
+
+	var rerolled pairingsResponse
+	request(t, s, http.MethodPost, "/api/pairings/reroll", nil, http.StatusOK, &rerolled)
+	if _, found := findPairing(rerolled, "Mario", "Yoshi"); !found {
+		t.Errorf("Expected Mario and Yoshi to still be paired after re-rolling, got %+v", rerolled)
+	}
+
+	for _, pairing := range round.Pairings {
+		if pairing.Pinned {
+			continue
+		}
+		if _, found := findPairing(rerolled, pairing.People[0], pairing.People[1]); found {
+			t.Errorf("Expected re-rolled pair %v to be replaced, got %+v", pairing.People, rerolled)
+		}
+	}
+}
+
+func TestProposalSurvivesRestart(t *testing.T) {
+	s, historyPath := newTestServer(t)
+
+	var round pairingsResponse
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Yoshi"}}, http.StatusOK, &round)
+
+	restarted := New(s.configPath, historyPath)
+	var restored pairingsResponse
+	request(t, restarted, http.MethodGet, "/api/pairings", nil, http.StatusOK, &restored)
+	if !bytes.Equal(mustMarshal(t, round), mustMarshal(t, restored)) {
+		t.Errorf("Expected the proposal to be kept in the history.\nExpected:\n%+v\nGot:\n%+v", round, restored)
+	}
+
+	request(t, restarted, http.MethodPost, "/api/pairings/confirm", nil, http.StatusOK, nil)
+	hist, err := history.ReadFile(historyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	if _, pending := hist.Pending(); pending {
+		t.Errorf("Expected no pending round once confirmed")
+	}
+
+	var confirmed pairingsResponse
+	request(t, New(s.configPath, historyPath), http.MethodGet, "/api/pairings", nil, http.StatusOK, &confirmed)
+	if !confirmed.Confirmed {
+		t.Errorf("Expected the pairings to still be confirmed after restarting, got %+v", confirmed)
+	}
+}
+
+func TestAddPinReturnsBadRequestForUnknownPerson(t *testing.T) {
+	s, _ := newTestServer(t)
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Bowser"}}, http.StatusBadRequest, nil)
+}
+
+func TestRequestsLargerThanTheLimitAreRejected(t *testing.T) {
+	s, _ := newTestServer(t)
+	WithMaxRequestSize(64)(s)
+
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Peach"}}, http.StatusOK, nil)
+	request(t, s, http.MethodPost, "/api/pins", map[string]string{"padding": strings.Repeat("a", 64)}, http.StatusRequestEntityTooLarge, nil)
+}
+
+func TestRemovePinReturnsNotFoundWithoutPin(t *testing.T) {
+	s, _ := newTestServer(t)
+	request(t, s, http.MethodDelete, "/api/pins/Mario/Luigi", nil, http.StatusNotFound, nil)
+}
+
+func TestConfirmRecordsPairingsInHistory(t *testing.T) {
+	s, historyPath := newTestServer(t)
+
+	var round pairingsResponse
+	request(t, s, http.MethodPost, "/api/pairings/confirm", nil, http.StatusOK, &round)
+	if !round.Confirmed {
+		t.Errorf("Expected the pairings to be confirmed")
+	}
+
+	file, err := os.Open(historyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error opening history: %v", err)
+	}
+	defer file.Close()
+
+	hist, err := history.NewHistoryFromFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+
+	for _, pairing := range round.Pairings {
+		if _, met := hist.GetPersonToLastMeetingMap(history.ID(pairing.People[0]))[history.ID(pairing.People[1])]; !met {
+			t.Errorf("Expected %v to have been recorded in the history", pairing.People)
+		}
+	}
+
+	request(t, s, http.MethodPost, "/api/pairings/reroll", nil, http.StatusConflict, nil)
+}
+
+func TestConfirmSignsHistoryWithSigningKey(t *testing.T) {
+	s, historyPath := newTestServer(t)
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating key: %v", err)
+	}
+	WithSigningKey(private)(s)
+
+	request(t, s, http.MethodPost, "/api/pairings/confirm", nil, http.StatusOK, nil)
+	if err := signing.VerifyFile(public, historyPath); err != nil {
+		t.Errorf("Expected the history to be signed, got %v", err)
+	}
+}
+
+func TestCoverageAndPersonHistoryReflectConfirmedPairings(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	var round pairingsResponse
+	request(t, s, http.MethodPost, "/api/pairings/confirm", nil, http.StatusOK, &round)
+	id1, id2 := round.Pairings[0].People[0], round.Pairings[0].People[1]
+
+	var coverage coverageResponse
+	request(t, s, http.MethodGet, "/api/coverage", nil, http.StatusOK, &coverage)
+	if len(coverage.People) != 5 || len(coverage.DaysSinceLastMet) != 5 {
+		t.Fatalf("Expected coverage of 5 people, got %+v", coverage)
+	}
+
+	met := 0
+	for _, row := range coverage.DaysSinceLastMet {
+		for _, days := range row {
+			if days != nil {
+				met++
+			}
+		}
+	}
+	if met != 2*len(round.Pairings) {
+		t.Errorf("Expected each confirmed pairing to appear twice in the coverage, got %d cells", met)
+	}
+
+	var person personResponse
+	request(t, s, http.MethodGet, "/api/people/"+string(id1), nil, http.StatusOK, &person)
+	if len(person.Meetings) != 1 || person.Meetings[0].Person != id2 || person.Meetings[0].Date != round.Date {
+		t.Errorf("Expected %s to have met %s on %s, got %+v", id1, id2, round.Date, person)
+	}
+
+	request(t, s, http.MethodGet, "/api/people/Bowser", nil, http.StatusNotFound, nil)
+}
+
+func TestDashboardIsServed(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if recorder.Code != http.StatusOK || !bytes.Contains(recorder.Body.Bytes(), []byte("<title>yapper</title>")) {
+		t.Errorf("Expected the dashboard to be served, got %d", recorder.Code)
+	}
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling: %v", err)
+	}
+	return data
+}
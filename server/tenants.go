@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// Tenant is an organisation or department hosted by a multi-tenant server, with a config, history and API tokens of
+// its own. The API tokens of one tenant cannot be used with any other.
+type Tenant struct {
+	// Name is used in the path of the tenant's dashboard and REST API, /t/<name>/.
+	Name    string `json:"name"`
+	Config  string `json:"config"`
+	History string `json:"history"`
+	// Tokens is the path to the API tokens file of the tenant, see LoadAPITokens.
+	Tokens string `json:"tokens,omitempty"`
+	Quota  Quota  `json:"quota,omitempty"`
+}
+
+// Quota limits the use a tenant makes of a shared server. Zero values are unlimited.
+type Quota struct {
+	// MaxPeople is the most people the config may have, beyond which no pairings are proposed.
+	MaxPeople int `json:"maxPeople,omitempty"`
+	// MaxNotifications is the most messages sent to the pairs of each round, after which re-matched pairs are not
+	// notified.
+	MaxNotifications int `json:"maxNotifications,omitempty"`
+}
+
+// quotaExceeded is an error caused by a request exceeding the quota of the server.
+type quotaExceeded struct{ error }
+
+// notificationCount counts the messages sent to the pairs of a round, see Quota.MaxNotifications.
+type notificationCount struct {
+	mu    sync.Mutex
+	round time.Time
+	sent  int
+}
+
+// WithQuota limits the people and notifications of the server.
+func WithQuota(quota Quota) Option {
+	return func(s *Server) {
+		s.quota = quota
+	}
+}
+
+// checkPeople returns an error if the config has more people than the quota allows.
+func (q Quota) checkPeople(config yapper.Config) error {
+	if q.MaxPeople > 0 && len(config.People) > q.MaxPeople {
+		return quotaExceeded{fmt.Errorf("the config has %d people, more than the quota of %d", len(config.People), q.MaxPeople)}
+	}
+	return nil
+}
+
+// reserve returns how many of the messages can be sent to the pairs of the round, counting them as sent.
+func (c *notificationCount) reserve(quota Quota, round time.Time, messages int) int {
+	if quota.MaxNotifications <= 0 {
+		return messages
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.round.Equal(round) {
+		c.round, c.sent = round, 0
+	}
+	allowed := min(messages, max(quota.MaxNotifications-c.sent, 0))
+	c.sent += allowed
+	return allowed
+}
+
+// tenantName is the pattern of the names of tenants, which are used in paths.
+var tenantName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// LoadTenants reads the tenants of a multi-tenant server from the JSON file at the path.
+func LoadTenants(path string) ([]Tenant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tenants file %s: %w", path, err)
+	}
+
+	var tenants []Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tenants file %s: %w", path, err)
+	}
+	return tenants, nil
+}
+
+// Tenants serves each of several tenants under /t/<name>/ with a server of its own.
+type Tenants struct {
+	mux     *http.ServeMux
+	servers []*Server
+}
+
+// NewTenants returns a handler hosting each of the tenants under /t/<name>/, each with the options along with its own
+// API tokens and quota. Each tenant has its own proposal and the portal links of participants are signed separately
+// for each tenant, so nothing is shared between them. Signing in with OpenID Connect is not supported, as the
+// provider would redirect every tenant to the same callback.
+func NewTenants(tenants []Tenant, options ...Option) (*Tenants, error) {
+	if len(tenants) == 0 {
+		return nil, errors.New("at least one tenant is required")
+	}
+
+	t := &Tenants{mux: http.NewServeMux()}
+	seen := map[string]bool{}
+	for _, tenant := range tenants {
+		if !tenantName.MatchString(tenant.Name) {
+			return nil, fmt.Errorf("tenant names must be lowercase letters, digits and dashes: %q", tenant.Name)
+		}
+		if seen[tenant.Name] {
+			return nil, fmt.Errorf("duplicate tenant: %s", tenant.Name)
+		}
+		seen[tenant.Name] = true
+
+		if tenant.Config == "" || tenant.History == "" {
+			return nil, fmt.Errorf("tenant %s is missing a config or history path", tenant.Name)
+		}
+
+		var tokens []APIToken
+		if tenant.Tokens != "" {
+			var err error
+			if tokens, err = LoadAPITokens(tenant.Tokens); err != nil {
+				return nil, fmt.Errorf("error loading the API tokens of tenant %s: %w", tenant.Name, err)
+			}
+		}
+
+		s := New(tenant.Config, tenant.History, slices.Concat(options, []Option{WithAPITokens(tokens), WithQuota(tenant.Quota)})...)
+		if s.oidc != nil {
+			return nil, errors.New("signing in with OpenID Connect is not supported with tenants")
+		}
+		if len(s.portalSecret) > 0 {
+			mac := hmac.New(sha256.New, s.portalSecret)
+			mac.Write([]byte(tenant.Name))
+			s.portalSecret = mac.Sum(nil)
+		}
+
+		prefix := "/t/" + tenant.Name
+		s.pathPrefix = prefix
+		t.mux.Handle(prefix+"/", http.StripPrefix(prefix, s.Handler()))
+		t.servers = append(t.servers, s)
+	}
+	return t, nil
+}
+
+func (t *Tenants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.mux.ServeHTTP(w, r)
+}
+
+// Elect runs the leader election of each tenant until the context is cancelled, see Server.Elect.
+func (t *Tenants) Elect(ctx context.Context) error {
+	errs := make(chan error, len(t.servers))
+	for _, s := range t.servers {
+		go func() { errs <- s.Elect(ctx) }()
+	}
+
+	var err error
+	for range t.servers {
+		err = errors.Join(err, <-errs)
+	}
+	return err
+}
+
+// Shutdown waits for the notifications of each tenant, see Server.Shutdown.
+func (t *Tenants) Shutdown(ctx context.Context) error {
+	var err error
+	for _, s := range t.servers {
+		err = errors.Join(err, s.Shutdown(ctx))
+	}
+	return err
+}
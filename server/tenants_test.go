@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTenant(t *testing.T, name string, quota Quota) (Tenant, string) {
+	t.Helper()
+	dir := t.TempDir()
+	tenant := Tenant{Name: name, Config: filepath.Join(dir, "config.json"), History: filepath.Join(dir, "history.json"), Tokens: filepath.Join(dir, "tokens.json"), Quota: quota}
+	if err := os.WriteFile(tenant.Config, []byte(testConfig), 0o644); err != nil {
+		t.Fatalf("Unexpected error writing config: %v", err)
+	}
+
+	token, apiToken, err := NewAPIToken(name, Scopes)
+	if err != nil {
+		t.Fatalf("Unexpected error creating API token: %v", err)
+	}
+	if err := SaveAPITokens(tenant.Tokens, []APIToken{apiToken}); err != nil {
+		t.Fatalf("Unexpected error saving API tokens: %v", err)
+	}
+	return tenant, token
+}
+
+func tenantStatus(handler http.Handler, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestTenantsAreIsolated(t *testing.T) {
+	mushroom, mushroomToken := newTestTenant(t, "mushroom-kingdom", Quota{})
+	koopa, koopaToken := newTestTenant(t, "koopa", Quota{})
+	handler, err := NewTenants([]Tenant{mushroom, koopa})
+	if err != nil {
+		t.Fatalf("Unexpected error creating tenants: %v", err)
+	}
+
+	tests := []struct {
+		method, path, token string
+		expected            int
+	}{
+		{http.MethodGet, "/t/mushroom-kingdom/api/pairings", mushroomToken, http.StatusOK},
+		{http.MethodGet, "/t/koopa/api/pairings", koopaToken, http.StatusOK},
+		{http.MethodGet, "/t/koopa/api/pairings", mushroomToken, http.StatusUnauthorized},
+		{http.MethodPost, "/t/mushroom-kingdom/api/pairings/confirm", koopaToken, http.StatusUnauthorized},
+		{http.MethodPost, "/t/mushroom-kingdom/api/pairings/confirm", mushroomToken, http.StatusOK},
+		{http.MethodGet, "/api/pairings", mushroomToken, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		if status := tenantStatus(handler, test.method, test.path, test.token); status != test.expected {
+			t.Errorf("Expected status %d from %s %s, got %d", test.expected, test.method, test.path, status)
+		}
+	}
+
+	if _, err := os.Stat(mushroom.History); err != nil {
+		t.Errorf("Expected the confirmed pairings in the history of the tenant: %v", err)
+	}
+	if data, err := os.ReadFile(koopa.History); err == nil && strings.Contains(string(data), `"lastRound"`) {
+		t.Errorf("Expected the other tenant's history to be unaffected, got %s", data)
+	}
+}
+
+func TestTenantsRejectInvalidNames(t *testing.T) {
+	tenant, _ := newTestTenant(t, "Mushroom Kingdom", Quota{})
+	if _, err := NewTenants([]Tenant{tenant}); err == nil {
+		t.Errorf("Expected error due to invalid tenant name")
+	}
+
+	tenant.Name = "koopa"
+	if _, err := NewTenants([]Tenant{tenant, tenant}); err == nil {
+		t.Errorf("Expected error due to duplicate tenant")
+	}
+}
+
+func TestPeopleQuotaIsEnforced(t *testing.T) {
+	tenant, token := newTestTenant(t, "koopa", Quota{MaxPeople: 4})
+	handler, err := NewTenants([]Tenant{tenant})
+	if err != nil {
+		t.Fatalf("Unexpected error creating tenants: %v", err)
+	}
+
+	if status := tenantStatus(handler, http.MethodGet, "/t/koopa/api/pairings", token); status != http.StatusForbidden {
+		t.Errorf("Expected status %d for a config over the quota, got %d", http.StatusForbidden, status)
+	}
+}
+
+func TestNotificationQuotaIsCountedPerRound(t *testing.T) {
+	quota := Quota{MaxNotifications: 3}
+	round := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	var count notificationCount
+
+	if allowed := count.reserve(quota, round, 2); allowed != 2 {
+		t.Errorf("Expected 2 messages to be allowed, got %d", allowed)
+	}
+	if allowed := count.reserve(quota, round, 2); allowed != 1 {
+		t.Errorf("Expected 1 message to be allowed, got %d", allowed)
+	}
+	if allowed := count.reserve(quota, round, 1); allowed != 0 {
+		t.Errorf("Expected no messages to be allowed, got %d", allowed)
+	}
+	if allowed := count.reserve(quota, round.AddDate(0, 0, 7), 2); allowed != 2 {
+		t.Errorf("Expected 2 messages to be allowed in the next round, got %d", allowed)
+	}
+}
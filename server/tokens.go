@@ -0,0 +1,158 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Scope is a permission granted to an API token.
+type Scope string
+
+const (
+	// ScopeReadPairings allows reading the proposed pairings, coverage and the history of each person.
+	ScopeReadPairings Scope = "pairings:read"
+	// ScopeGeneratePairings allows re-rolling and pinning the proposed pairings.
+	ScopeGeneratePairings Scope = "pairings:generate"
+	// ScopeRecordMeetings allows confirming the proposed pairings and recording meetings in the history.
+	ScopeRecordMeetings Scope = "meetings:write"
+	// ScopeIssuePortalLinks allows getting the portal link of any participant, which signs in as them.
+	ScopeIssuePortalLinks Scope = "portal:links"
+)
+
+// Scopes lists the supported scopes.
+var Scopes = []Scope{ScopeReadPairings, ScopeGeneratePairings, ScopeRecordMeetings, ScopeIssuePortalLinks}
+
+// tokenPrefix identifies yapper API tokens, e.g. for secret scanners.
+const tokenPrefix = "yap_"
+
+// APIToken grants an integration access to the REST API with only the given scopes.
+// Only the hash of the token is kept, so it cannot be recovered from the tokens file.
+type APIToken struct {
+	Name   string  `json:"name"`
+	Hash   string  `json:"hash"`
+	Scopes []Scope `json:"scopes"`
+}
+
+// NewAPIToken returns a new random token along with the APIToken to give to the server.
+func NewAPIToken(name string, scopes []Scope) (string, APIToken, error) {
+	if name == "" {
+		return "", APIToken{}, errors.New("API token is missing a name")
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return "", APIToken{}, fmt.Errorf("unexpected scope: %s", scope)
+		}
+	}
+
+	token := tokenPrefix + randomString()
+	return token, APIToken{Name: name, Hash: hashToken(token), Scopes: scopes}, nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// LoadAPITokens reads the API tokens from the file at the path, returning no tokens if the file does not exist.
+func LoadAPITokens(path string) ([]APIToken, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading API tokens file %s: %w", path, err)
+	}
+
+	var tokens []APIToken
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("error unmarshalling API tokens file %s: %w", path, err)
+	}
+	return tokens, nil
+}
+
+// SaveAPITokens writes the API tokens to the file at the path, readable only by the current user.
+func SaveAPITokens(path string, tokens []APIToken) error {
+	data, err := json.MarshalIndent(tokens, "", "\t")
+	if err != nil {
+		return fmt.Errorf("error marshalling API tokens: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("error writing API tokens file %s: %w", path, err)
+	}
+	return nil
+}
+
+// WithAPITokens allows the API tokens to use the REST API within their scopes.
+// Once any tokens are given, the REST API is no longer open to anyone without a token or signing in.
+func WithAPITokens(tokens []APIToken) Option {
+	return func(s *Server) {
+		s.apiTokens = tokens
+	}
+}
+
+// apiToken returns the API token matching the presented token, if any.
+func (s *Server) apiToken(token string) (APIToken, bool) {
+	hash := []byte(hashToken(token))
+	for _, apiToken := range s.apiTokens {
+		if subtle.ConstantTimeCompare(hash, []byte(apiToken.Hash)) == 1 {
+			return apiToken, true
+		}
+	}
+	return APIToken{}, false
+}
+
+// authorize requires an API token with the scope, or an organiser to be signed in, before calling the handler.
+// An empty scope can only be used by organisers. Without API tokens or signing in the REST API is open to anyone.
+func (s *Server) authorize(scope Scope, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found && len(s.apiTokens) > 0 {
+			apiToken, exists := s.apiToken(token)
+			if !exists {
+				writeError(w, http.StatusUnauthorized, errors.New("invalid API token"))
+				return
+			}
+
+			if scope == "" || !slices.Contains(apiToken.Scopes, scope) {
+				writeError(w, http.StatusForbidden, fmt.Errorf("API token %s does not have the scope required", apiToken.Name))
+				return
+			}
+
+			handler(w, r)
+			return
+		}
+
+		if s.oidc != nil {
+			s.organizer(handler)(w, r)
+			return
+		}
+
+		if len(s.apiTokens) > 0 {
+			writeError(w, http.StatusUnauthorized, errors.New("API token required"))
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// authorizeAlways requires an API token with the scope, or an organiser to be signed in, like authorize, but is closed
+// to everyone rather than open without API tokens or signing in.
+func (s *Server) authorizeAlways(scope Scope, handler http.HandlerFunc) http.HandlerFunc {
+	authorized := s.authorize(scope, handler)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.oidc == nil && len(s.apiTokens) == 0 {
+			writeError(w, http.StatusForbidden, errors.New("API tokens or signing in are required"))
+			return
+		}
+		authorized(w, r)
+	}
+}
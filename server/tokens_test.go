@@ -0,0 +1,90 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func statusWithToken(s *Server, method, path, body, token string) int {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestNewAPITokenReturnsErrorForUnexpectedScope(t *testing.T) {
+	if _, _, err := NewAPIToken("ci", []Scope{"everything"}); err == nil {
+		t.Errorf("Expected error due to unexpected scope")
+	}
+}
+
+func TestAPITokensAreLimitedToTheirScopes(t *testing.T) {
+	s, _ := newTestServer(t)
+	reader, readerToken, err := NewAPIToken("reader", []Scope{ScopeReadPairings})
+	if err != nil {
+		t.Fatalf("Unexpected error creating API token: %v", err)
+	}
+	recorder, recorderToken, err := NewAPIToken("recorder", []Scope{ScopeRecordMeetings})
+	if err != nil {
+		t.Fatalf("Unexpected error creating API token: %v", err)
+	}
+	WithAPITokens([]APIToken{readerToken, recorderToken})(s)
+
+	meeting := `{"people": ["Mario", "Luigi"], "date": "2025-08-04"}`
+	tests := []struct {
+		method, path, body, token string
+		expected                  int
+	}{
+		{http.MethodGet, "/api/pairings", "", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/pairings", "", "yap_invalid", http.StatusUnauthorized},
+		{http.MethodGet, "/api/pairings", "", reader, http.StatusOK},
+		{http.MethodGet, "/api/coverage", "", reader, http.StatusOK},
+		{http.MethodPost, "/api/pairings/reroll", "", reader, http.StatusForbidden},
+		{http.MethodPost, "/api/meetings", meeting, reader, http.StatusForbidden},
+		{http.MethodPost, "/api/meetings", meeting, recorder, http.StatusNoContent},
+		{http.MethodGet, "/api/pairings", "", recorder, http.StatusForbidden},
+		{http.MethodGet, "/api/people/Mario/portal-link", "", reader, http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		if status := statusWithToken(s, test.method, test.path, test.body, test.token); status != test.expected {
+			t.Errorf("Expected status %d from %s %s, got %d", test.expected, test.method, test.path, status)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/people/Mario", nil)
+	req.Header.Set("Authorization", "Bearer "+reader)
+	response := httptest.NewRecorder()
+	s.Handler().ServeHTTP(response, req)
+	if !strings.Contains(response.Body.String(), `"person":"Luigi"`) {
+		t.Errorf("Expected the recorded meeting with Luigi, got %s", response.Body.String())
+	}
+}
+
+func TestAPITokensRoundTripThroughFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	_, token, err := NewAPIToken("ci", []Scope{ScopeReadPairings, ScopeGeneratePairings})
+	if err != nil {
+		t.Fatalf("Unexpected error creating API token: %v", err)
+	}
+
+	if err := SaveAPITokens(path, []APIToken{token}); err != nil {
+		t.Fatalf("Unexpected error saving API tokens: %v", err)
+	}
+
+	loaded, err := LoadAPITokens(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading API tokens: %v", err)
+	}
+
+	if expected := []APIToken{token}; !reflect.DeepEqual(loaded, expected) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, loaded)
+	}
+}
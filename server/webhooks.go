@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+)
+
+// MeetingStatus is the progress of a pair in meeting during the current round.
+type MeetingStatus string
+
+const (
+	// MeetingScheduled is a meeting the pair has arranged but not yet had.
+	MeetingScheduled MeetingStatus = "scheduled"
+	// MeetingCompleted is a meeting which took place, which is recorded in the history.
+	MeetingCompleted MeetingStatus = "completed"
+	// MeetingSkipped is a meeting which will not take place this round, which is not recorded when confirming.
+	MeetingSkipped MeetingStatus = "skipped"
+	// MeetingDeclined is a pair which declined to meet, who are re-matched with anyone else without a partner.
+	MeetingDeclined MeetingStatus = "declined"
+)
+
+// MeetingStatuses lists the supported meeting statuses.
+var MeetingStatuses = []MeetingStatus{MeetingScheduled, MeetingCompleted, MeetingSkipped, MeetingDeclined}
+
+// slackTimestampTolerance is how old a request from Slack can be, to prevent replaying it.
+const slackTimestampTolerance = 5 * time.Minute
+
+// meetingStatusRequest marks the meeting of a pair in the current round, which for completed meetings is on the
+// given date or otherwise today.
+type meetingStatusRequest struct {
+	People [2]yapper.ID  `json:"people"`
+	Status MeetingStatus `json:"status"`
+	Date   string        `json:"date"`
+}
+
+// WithWebhookSecret enables the meeting status webhook, which requires requests to be signed with the secret.
+func WithWebhookSecret(secret []byte) Option {
+	return func(s *Server) {
+		s.webhookSecret = secret
+	}
+}
+
+// WithSlackSigningSecret enables the Slack interactivity webhook, verifying requests with the signing secret of the Slack app.
+func WithSlackSigningSecret(secret []byte) Option {
+	return func(s *Server) {
+		s.slackSigningSecret = secret
+	}
+}
+
+// handleMeetingWebhook marks the status of a meeting, for requests signed with the webhook secret in the
+// X-Yapper-Signature header as "sha256=" followed by the hex encoded HMAC-SHA256 of the body.
+func (s *Server) handleMeetingWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error reading webhook: %w", err))
+		return
+	}
+
+	mac := hmac.New(sha256.New, s.webhookSecret)
+	mac.Write(body)
+	signature, found := strings.CutPrefix(r.Header.Get("X-Yapper-Signature"), "sha256=")
+	decoded, err := hex.DecodeString(signature)
+	if !found || err != nil || !hmac.Equal(decoded, mac.Sum(nil)) {
+		writeError(w, http.StatusUnauthorized, errors.New("invalid webhook signature"))
+		return
+	}
+
+	var request meetingStatusRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding meeting status: %w", err))
+		return
+	}
+
+	s.withProposal(r.Context(), w, func(config yapper.Config, p *proposal) error {
+		return s.setMeetingStatus(r.Context(), config, p, request)
+	})
+}
+
+// handleSlackWebhook marks the status of a meeting from a button in a Slack message, whose value is a meeting
+// status request as JSON. Requests are verified with the signing secret as described by Slack.
+func (s *Server) handleSlackWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error reading webhook: %w", err))
+		return
+	}
+
+	if err := s.verifySlack(r.Header, body, time.Now()); err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding Slack payload: %w", err))
+		return
+	}
+
+	var payload struct {
+		Actions []struct {
+			Value string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil || len(payload.Actions) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("expected a Slack payload with an action"))
+		return
+	}
+
+	var request meetingStatusRequest
+	if err := json.Unmarshal([]byte(payload.Actions[0].Value), &request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding meeting status: %w", err))
+		return
+	}
+
+	s.withProposal(r.Context(), w, func(config yapper.Config, p *proposal) error {
+		return s.setMeetingStatus(r.Context(), config, p, request)
+	})
+}
+
+// verifySlack checks the signature of a request from Slack, which must have been sent recently.
+func (s *Server) verifySlack(header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("missing Slack request timestamp")
+	}
+
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackTimestampTolerance || age < -slackTimestampTolerance {
+		return errors.New("Slack request is too old")
+	}
+
+	mac := hmac.New(sha256.New, s.slackSigningSecret)
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	signature, found := strings.CutPrefix(header.Get("X-Slack-Signature"), "v0=")
+	decoded, err := hex.DecodeString(signature)
+	if !found || err != nil || !hmac.Equal(decoded, mac.Sum(nil)) {
+		return errors.New("invalid Slack signature")
+	}
+	return nil
+}
+
+// setMeetingStatus marks the meeting of a pair in the proposal, recording completed meetings in the history.
+// The caller must hold the lock.
+func (s *Server) setMeetingStatus(ctx context.Context, config yapper.Config, p *proposal, request meetingStatusRequest) error {
+	if !slices.Contains(MeetingStatuses, request.Status) {
+		return badRequest{fmt.Errorf("unexpected meeting status: %s", request.Status)}
+	}
+
+	id1, id2 := request.People[0], request.People[1]
+	if !p.pairings.Contains(id1, id2) {
+		return notFound{fmt.Errorf("%s and %s are not paired this round", id1, id2)}
+	}
+
+	if request.Status == MeetingDeclined {
+		return s.rematch(ctx, config, p, [2]yapper.ID{id1, id2})
+	}
+
+	if request.Status == MeetingCompleted {
+		date, err := parseMeetingDate(request.Date)
+		if err != nil {
+			return badRequest{err}
+		}
+
+		if status, err := s.recordMeeting(ctx, id1, id2, date); err != nil {
+			if status == http.StatusBadRequest {
+				return badRequest{err}
+			}
+			return err
+		}
+	}
+
+	if p.statuses == nil {
+		p.statuses = map[[2]yapper.ID]MeetingStatus{}
+	}
+	p.statuses[sortedPair(id1, id2)] = request.Status
+	return nil
+}
+
+// sortedPair returns the pair in a consistent order, regardless of the order of the people.
+func sortedPair(id1, id2 yapper.ID) [2]yapper.ID {
+	if id2 < id1 {
+		return [2]yapper.ID{id2, id1}
+	}
+	return [2]yapper.ID{id1, id2}
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper"
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func newWebhookServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	s, historyPath := newTestServer(t)
+	WithWebhookSecret([]byte("webhook secret"))(s)
+	WithSlackSigningSecret([]byte("slack secret"))(s)
+	return s, historyPath
+}
+
+func webhookRequest(t *testing.T, s *Server, secret string, body any, wantStatus int, response any) {
+	t.Helper()
+	data := mustMarshal(t, body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/meetings", bytes.NewReader(data))
+	req.Header.Set("X-Yapper-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, req)
+
+	if recorder.Code != wantStatus {
+		t.Fatalf("Expected status %d from the webhook, got %d: %s", wantStatus, recorder.Code, recorder.Body.String())
+	}
+	if response != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Fatalf("Unexpected error decoding response: %v", err)
+		}
+	}
+}
+
+func slackRequest(t *testing.T, s *Server, secret string, request meetingStatusRequest, sent time.Time) int {
+	t.Helper()
+	value := string(mustMarshal(t, request))
+	payload := `{"type":"block_actions","actions":[{"value":` + strconv.Quote(value) + `}]}`
+	body := "payload=" + url.QueryEscape(payload)
+	timestamp := strconv.FormatInt(sent.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/slack", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func readHistory(t *testing.T, historyPath string) history.History {
+	t.Helper()
+	file, err := os.Open(historyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error opening history: %v", err)
+	}
+	defer file.Close()
+
+	hist, err := history.NewHistoryFromFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error reading history: %v", err)
+	}
+	return hist
+}
+
+func TestWebhooksAreDisabledWithoutSecrets(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	for _, path := range []string{"/webhooks/meetings", "/webhooks/slack"} {
+		if status := statusWithCookie(s, http.MethodPost, path, nil); status == http.StatusOK {
+			t.Errorf("Expected %s to be disabled", path)
+		}
+	}
+}
+
+func TestMeetingWebhookRejectsInvalidSignature(t *testing.T) {
+	s, _ := newWebhookServer(t)
+	body := meetingStatusRequest{People: [2]yapper.ID{"Mario", "Peach"}, Status: MeetingScheduled}
+	webhookRequest(t, s, "wrong secret", body, http.StatusUnauthorized, nil)
+}
+
+func TestMeetingWebhookUpdatesStatus(t *testing.T) {
+	s, historyPath := newWebhookServer(t)
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Peach"}}, http.StatusOK, nil)
+
+	var round pairingsResponse
+	webhookRequest(t, s, "webhook secret", meetingStatusRequest{People: [2]yapper.ID{"Peach", "Mario"}, Status: MeetingScheduled}, http.StatusOK, &round)
+	if pairing, _ := findPairing(round, "Mario", "Peach"); pairing.Status != MeetingScheduled {
+		t.Errorf("Expected Mario and Peach to be scheduled, got %+v", round)
+	}
+
+	webhookRequest(t, s, "webhook secret", meetingStatusRequest{People: [2]yapper.ID{"Mario", "Peach"}, Status: MeetingCompleted, Date: "2025-01-08"}, http.StatusOK, &round)
+	if pairing, _ := findPairing(round, "Mario", "Peach"); pairing.Status != MeetingCompleted {
+		t.Errorf("Expected Mario and Peach to be completed, got %+v", round)
+	}
+
+	request(t, s, http.MethodPost, "/api/pairings/confirm", nil, http.StatusOK, nil)
+	hist := readHistory(t, historyPath)
+	expected := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	if met := hist.GetPersonToLastMeetingMap("Mario")["Peach"]; !met.Equal(expected) {
+		t.Errorf("Expected Mario and Peach to have met on %v, got %v", expected, met)
+	}
+}
+
+func TestConfirmSkipsSkippedMeetings(t *testing.T) {
+	s, historyPath := newWebhookServer(t)
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Peach"}}, http.StatusOK, nil)
+	webhookRequest(t, s, "webhook secret", meetingStatusRequest{People: [2]yapper.ID{"Mario", "Peach"}, Status: MeetingSkipped}, http.StatusOK, nil)
+
+	request(t, s, http.MethodPost, "/api/pairings/confirm", nil, http.StatusOK, nil)
+	hist := readHistory(t, historyPath)
+	if _, met := hist.GetPersonToLastMeetingMap("Mario")["Peach"]; met {
+		t.Errorf("Expected the skipped meeting not to be recorded")
+	}
+}
+
+func TestMeetingWebhookRejectsUnknownPairsAndStatuses(t *testing.T) {
+	s, _ := newWebhookServer(t)
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Peach"}}, http.StatusOK, nil)
+
+	webhookRequest(t, s, "webhook secret", meetingStatusRequest{People: [2]yapper.ID{"Mario", "Luigi"}, Status: MeetingScheduled}, http.StatusNotFound, nil)
+	webhookRequest(t, s, "webhook secret", meetingStatusRequest{People: [2]yapper.ID{"Mario", "Peach"}, Status: "postponed"}, http.StatusBadRequest, nil)
+}
+
+func TestSlackWebhookVerifiesRequests(t *testing.T) {
+	s, _ := newWebhookServer(t)
+	request(t, s, http.MethodPost, "/api/pins", pinRequest{People: [2]yapper.ID{"Mario", "Peach"}}, http.StatusOK, nil)
+	body := meetingStatusRequest{People: [2]yapper.ID{"Mario", "Peach"}, Status: MeetingScheduled}
+
+	if status := slackRequest(t, s, "wrong secret", body, time.Now()); status != http.StatusUnauthorized {
+		t.Errorf("Expected an invalid signature to be rejected, got %d", status)
+	}
+
+	if status := slackRequest(t, s, "slack secret", body, time.Now().Add(-time.Hour)); status != http.StatusUnauthorized {
+		t.Errorf("Expected an old request to be rejected, got %d", status)
+	}
+
+	if status := slackRequest(t, s, "slack secret", body, time.Now()); status != http.StatusOK {
+		t.Errorf("Expected a valid request to be accepted, got %d", status)
+	}
+
+	var round pairingsResponse
+	request(t, s, http.MethodGet, "/api/pairings", nil, http.StatusOK, &round)
+	if pairing, _ := findPairing(round, "Mario", "Peach"); pairing.Status != MeetingScheduled {
+		t.Errorf("Expected Mario and Peach to be scheduled, got %+v", round)
+	}
+}
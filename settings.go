@@ -0,0 +1,106 @@
+package yapper
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Settings are the program wide options of the config, which apply to everyone unless overridden per person.
+type Settings struct {
+	// Interval is the number of days between rounds of pairings, defaulting to weekly.
+	Interval int `json:"interval,omitempty"`
+	// Strategy decides how pairings are chosen, defaulting to greedy.
+	Strategy Strategy `json:"strategy,omitempty"`
+	// Strategies are tried in turn instead of the strategy, until one completes within its budget.
+	Strategies []StrategyStep `json:"strategies,omitempty"`
+	// MaxDuration is the longest the planned strategy searches for improvements, such as 10s, after which the best plan
+	// found so far is used.
+	MaxDuration string `json:"maxDuration,omitempty"`
+	// Improvement swaps partners within each round paired by the greedy strategy to lower its penalty.
+	Improvement *Improvement `json:"improvement,omitempty"`
+	// SquadPolicy decides whether people of the same squad are never paired, or only less preferred.
+	SquadPolicy SquadPolicy `json:"squadPolicy,omitempty"`
+	// Cadence is the cadence of people who do not set their own, defaulting to one week.
+	Cadence Cadence `json:"cadence,omitempty"`
+	// GroupSize is the number of people seated at each table of events, defaulting to pairs.
+	GroupSize int `json:"groupSize,omitempty"`
+}
+
+func (s Settings) validate() error {
+	if s.Interval < 0 {
+		return fmt.Errorf("interval must not be negative: %d", s.Interval)
+	}
+
+	if err := s.Strategy.validate(); err != nil {
+		return err
+	}
+	if s.Strategy != "" && len(s.Strategies) > 0 {
+		return errors.New("only one of strategy and strategies can be set")
+	}
+	for _, step := range s.Strategies {
+		if err := step.validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.MaxDuration != "" {
+		if maxDuration, err := time.ParseDuration(s.MaxDuration); err != nil {
+			return fmt.Errorf("error parsing max duration: %w", err)
+		} else if maxDuration <= 0 {
+			return fmt.Errorf("max duration must be positive: %s", s.MaxDuration)
+		}
+	}
+
+	if s.Improvement != nil {
+		if err := s.Improvement.validate(); err != nil {
+			return err
+		}
+	}
+
+	switch s.SquadPolicy {
+	case "", SquadPolicyDeny, SquadPolicyPreferDiffering:
+	default:
+		return fmt.Errorf("unexpected squad policy: %s", s.SquadPolicy)
+	}
+
+	switch s.Cadence {
+	case "", CadenceOneWeek, CadenceTwoWeeks:
+	default:
+		return fmt.Errorf("unexpected cadence: %s", s.Cadence)
+	}
+
+	if s.GroupSize < 0 || s.GroupSize == 1 {
+		return fmt.Errorf("groups must have at least two people: %d", s.GroupSize)
+	}
+
+	return nil
+}
+
+// maxDuration returns the longest the planned strategy searches for improvements, or zero if it is not limited.
+func (c Config) maxDuration() time.Duration {
+	maxDuration, _ := time.ParseDuration(c.Settings.MaxDuration)
+	return maxDuration
+}
+
+// cadence returns the cadence of the person, defaulting to the cadence of the settings.
+func (c Config) cadence(person Person) Cadence {
+	if person.Cadence == "" {
+		return c.Settings.Cadence
+	}
+	return person.Cadence
+}
+
+// withCadence returns the person with their cadence defaulted from the settings, for rules referring to it.
+func (c Config) withCadence(person Person) Person {
+	person.Cadence = c.cadence(person)
+	return person
+}
+
+// GroupSize returns the number of people seated at each table of events, with 2 meaning they are paired instead.
+func (c Config) GroupSize() int {
+	if c.Settings.GroupSize == 0 {
+		return 2
+	}
+	return c.Settings.GroupSize
+}
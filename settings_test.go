@@ -0,0 +1,61 @@
+package yapper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSettingsCadenceAppliesToPeopleWithoutTheirOwn(t *testing.T) {
+	config := Config{
+		People:   []Person{{ID: "Mario"}, {ID: "Luigi", Cadence: CadenceOneWeek}},
+		Settings: Settings{Cadence: CadenceTwoWeeks},
+	}
+
+	for _, round := range []time.Time{time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)} {
+		twoWeekValid := isValidWeekForTwoWeekCadence(round, config.RoundInterval())
+
+		mario, _ := config.GetPerson("Mario")
+		if kind := config.ineligibility(mario, round, config.quorumOf(round)); (kind == ViolationCadence) == twoWeekValid {
+			t.Errorf("Expected Mario to follow the default two week cadence on %s, got %q", round.Format(time.DateOnly), kind)
+		}
+
+		luigi, _ := config.GetPerson("Luigi")
+		if kind := config.ineligibility(luigi, round, config.quorumOf(round)); kind != "" {
+			t.Errorf("Expected Luigi to keep his own cadence on %s, got %q", round.Format(time.DateOnly), kind)
+		}
+	}
+}
+
+func TestSettingsCadenceIsVisibleToRules(t *testing.T) {
+	config := Config{
+		People:   []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach", Cadence: CadenceOneWeek}},
+		Settings: Settings{Cadence: CadenceTwoWeeks},
+		Rules:    []Rule{{Deny: `person.cadence == "two-weeks" && other.cadence == "two-weeks"`}},
+	}
+
+	expected := map[ID][]ID{
+		"Mario": {"Peach"},
+		"Luigi": {"Peach"},
+		"Peach": {"Mario", "Luigi"},
+	}
+	diffPairings(t, determineValidPairings(config), expected)
+}
+
+func TestConfigValidateReturnsErrorForInvalidSettings(t *testing.T) {
+	for _, settings := range []Settings{
+		{Interval: -7},
+		{Strategy: "random"},
+		{SquadPolicy: "sometimes"},
+		{Cadence: "daily"},
+		{GroupSize: 1},
+		{Strategy: StrategyGreedy, Strategies: []StrategyStep{{Strategy: StrategyPlanned}}},
+		{Strategies: []StrategyStep{{Budget: "1s"}}},
+		{Strategies: []StrategyStep{{Strategy: StrategyPlanned, Budget: "soon"}}},
+		{Strategies: []StrategyStep{{Strategy: StrategyPlanned, Budget: "-1s"}}},
+		{MaxDuration: "soon"},
+	} {
+		if err := (Config{Settings: settings}).Validate(); err == nil {
+			t.Errorf("Expected error due to invalid settings: %+v", settings)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package signing signs exported pairings, plans and history files with Ed25519 and verifies them on load, so
+// pipelines keeping them in version control can detect the meeting ledger being tampered with by hand. Signatures are
+// kept in a file next to the signed file, with the Suffix appended to its path.
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Suffix is appended to the path of a signed file for the path of its signature.
+const Suffix = ".sig"
+
+// ErrInvalid is wrapped by the errors of verifying a file which is not signed, or whose signature does not match the
+// file, with the key.
+var ErrInvalid = errors.New("signature is not valid")
+
+// NewKeys returns a new private key for signing and its public key for verifying, each PEM encoded.
+func NewKeys() (privatePEM, publicPEM []byte, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating key: %w", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding private key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), nil
+}
+
+// ReadPrivateKey reads the PEM encoded Ed25519 private key in the file at path, such as one created by NewKeys.
+func ReadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key %s: %w", path, err)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an Ed25519 key", path)
+	}
+	return private, nil
+}
+
+// ReadPublicKey reads the PEM encoded Ed25519 public key in the file at path, such as one created by NewKeys.
+func ReadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key %s: %w", path, err)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return public, nil
+}
+
+func readPEM(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s does not have a PEM encoded %s", path, blockType)
+	}
+	return block.Bytes, nil
+}
+
+// SignFile signs the file at path, and the journal of a history file if it has one, writing the signature to the
+// path with the Suffix.
+func SignFile(key ed25519.PrivateKey, path string) error {
+	message, err := signedMessage(path)
+	if err != nil {
+		return err
+	}
+
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, message))
+	if err := os.WriteFile(path+Suffix, []byte(signature+"\n"), 0o644); err != nil {
+		return fmt.Errorf("error writing signature: %w", err)
+	}
+	return nil
+}
+
+// VerifyFile verifies the signature of the file at path, and the journal of a history file if it has one, returning an
+// error wrapping ErrInvalid if it is not signed or the signature does not match.
+func VerifyFile(key ed25519.PublicKey, path string) error {
+	message, err := signedMessage(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path + Suffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s is not signed, %w", path, ErrInvalid)
+	} else if err != nil {
+		return fmt.Errorf("error reading signature: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || !ed25519.Verify(key, message, signature) {
+		return fmt.Errorf("%s does not match its signature, it may have been changed by hand, %w", path, ErrInvalid)
+	}
+	return nil
+}
+
+// signedMessage returns the contents of the file at path and its journal, each preceded by its length so data cannot
+// be moved between them without changing the message. A missing journal is signed as an empty one.
+func signedMessage(path string) ([]byte, error) {
+	var message []byte
+	for i, file := range []string{path, path + history.JournalSuffix} {
+		data, err := os.ReadFile(file)
+		if i > 0 && errors.Is(err, os.ErrNotExist) {
+			data, err = nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading signed file: %w", err)
+		}
+		message = binary.BigEndian.AppendUint64(message, uint64(len(data)))
+		message = append(message, data...)
+	}
+	return message, nil
+}
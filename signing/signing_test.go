@@ -0,0 +1,90 @@
+package signing
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKeys writes a new private and public key to files, returning their paths.
+func writeKeys(t *testing.T) (string, string) {
+	t.Helper()
+	privatePEM, publicPEM, err := NewKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	privatePath, publicPath := filepath.Join(dir, "signing.pem"), filepath.Join(dir, "verify.pem")
+	if err := os.WriteFile(privatePath, privatePEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, publicPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func TestVerifyFileDetectsTampering(t *testing.T) {
+	privatePath, publicPath := writeKeys(t)
+	private, err := ReadPrivateKey(privatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := ReadPublicKey(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "history.json")
+	os.WriteFile(path, []byte(`{"version":"2"}`), 0o644)
+	if err := VerifyFile(public, path); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected an unsigned file to be invalid, got %v", err)
+	}
+
+	if err := SignFile(private, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(public, path); err != nil {
+		t.Errorf("Expected the signature to be valid, got %v", err)
+	}
+
+	os.WriteFile(path+".journal", []byte(`{"meeting":{}}`+"\n"), 0o644)
+	if err := VerifyFile(public, path); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected a changed journal to be detected, got %v", err)
+	}
+	if err := SignFile(private, path); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(path, []byte(`{"version":"3"}`), 0o644)
+	if err := VerifyFile(public, path); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected a changed file to be detected, got %v", err)
+	}
+}
+
+func TestReadKeysRejectsOtherKeys(t *testing.T) {
+	privatePath, publicPath := writeKeys(t)
+
+	if _, err := ReadPublicKey(privatePath); err == nil {
+		t.Errorf("Expected an error reading a private key as a public key")
+	}
+	if _, err := ReadPrivateKey(publicPath); err == nil {
+		t.Errorf("Expected an error reading a public key as a private key")
+	}
+}
+
+func TestVerifyFileRejectsOtherKeys(t *testing.T) {
+	privatePath, _ := writeKeys(t)
+	_, otherPublicPath := writeKeys(t)
+	private, _ := ReadPrivateKey(privatePath)
+	otherPublic, _ := ReadPublicKey(otherPublicPath)
+
+	path := filepath.Join(t.TempDir(), "pairings.json")
+	os.WriteFile(path, []byte(`{}`), 0o644)
+	if err := SignFile(private, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(otherPublic, path); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", ErrInvalid, err)
+	}
+}
@@ -0,0 +1,85 @@
+package yapper
+
+import (
+	"fmt"
+)
+
+// Preset is a built-in kind of program, adding the constraints typical of it to those of the config.
+type Preset string
+
+const (
+	// PresetSkipLevel only pairs people tagged manager with people who are not, outside of their management chain, for
+	// skip-level meetings. The chains are followed through the manager of each person.
+	PresetSkipLevel Preset = "skip-level"
+)
+
+// ManagerTag is the tag of the managers of the skip-level preset.
+const ManagerTag = "manager"
+
+func (p Preset) validate() error {
+	switch p {
+	case "", PresetSkipLevel:
+		return nil
+	default:
+		return fmt.Errorf("unexpected preset: %s", p)
+	}
+}
+
+// validateManagers returns an error if the manager of someone is not in the config, or the management chain above
+// someone loops back on itself.
+func (c Config) validateManagers() error {
+	index := c.Index()
+	for _, person := range c.People {
+		if person.Manager == "" {
+			continue
+		}
+		if _, found := index[person.Manager]; !found {
+			return fmt.Errorf("unknown manager of %s: %s", person.ID, person.Manager)
+		}
+
+		seen := map[ID]bool{person.ID: true}
+		for manager := person.Manager; manager != ""; manager = index[manager].Manager {
+			if seen[manager] {
+				return fmt.Errorf("management chain of %s loops back to %s", person.ID, manager)
+			}
+			seen[manager] = true
+		}
+	}
+	return nil
+}
+
+// skipLevelDenied reports whether the skip-level preset denies pairing the people, which it does unless exactly one
+// of them is a manager and the other is outside of their chain.
+func skipLevelDenied(conf Config, index map[ID]Person, person, other Person) bool {
+	if conf.Preset != PresetSkipLevel {
+		return false
+	}
+
+	manager, report := person, other
+	if !manager.HasTag(ManagerTag) {
+		manager, report = other, person
+	}
+	if !manager.HasTag(ManagerTag) || report.HasTag(ManagerTag) {
+		return true
+	}
+	return manages(index, manager.ID, report) || manages(index, report.ID, manager)
+}
+
+// manages reports whether the person with the ID is above the person in their management chain, looking the managers
+// up in the index of the config.
+func manages(index map[ID]Person, id ID, person Person) bool {
+	seen := map[ID]bool{person.ID: true}
+	for manager := person.Manager; manager != "" && !seen[manager]; {
+		if manager == id {
+			return true
+		}
+		seen[manager] = true
+
+		next, found := index[manager]
+		if !found {
+			return false
+		}
+		manager = next.Manager
+	}
+	return false
+}
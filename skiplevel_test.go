@@ -0,0 +1,66 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateReturnsErrorForUnknownManager(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario", Manager: "Bowser"}}}
+
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to a manager who is not in the config")
+	}
+}
+
+func TestValidateReturnsErrorForManagementChainLoop(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario", Manager: "Luigi"},
+		{ID: "Luigi", Manager: "Peach"},
+		{ID: "Peach", Manager: "Mario"},
+	}}
+
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to a management chain looping back on itself")
+	}
+}
+
+func TestValidateReturnsErrorForUnexpectedPreset(t *testing.T) {
+	config := Config{Preset: "speed-dating"}
+
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to an unexpected preset")
+	}
+}
+
+func TestSkipLevelPresetPairsManagersWithPeopleOutsideTheirChain(t *testing.T) {
+	config := Config{
+		Preset: PresetSkipLevel,
+		People: []Person{
+			{ID: "Peach", Tags: []string{ManagerTag}},
+			{ID: "Mario", Tags: []string{ManagerTag}, Manager: "Peach"},
+			{ID: "Luigi", Manager: "Mario"},
+			{ID: "Bowser", Tags: []string{ManagerTag}},
+			{ID: "Koopa", Manager: "Bowser"},
+		},
+	}
+
+	expected := map[ID][]ID{
+		"Peach":  {"Koopa"},
+		"Mario":  {"Koopa"},
+		"Luigi":  {"Bowser"},
+		"Bowser": {"Luigi"},
+		"Koopa":  {"Peach", "Mario"},
+	}
+	if validPairings := computeValidPairings(config); !reflect.DeepEqual(expected, validPairings) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, validPairings)
+	}
+}
+
+func TestSkipLevelDeniedIsFalseWithoutPreset(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi", Manager: "Mario"}}}
+
+	if skipLevelDenied(config, config.Index(), config.People[0], config.People[1]) {
+		t.Errorf("Expected pairings not to be denied without the skip-level preset")
+	}
+}
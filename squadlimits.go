@@ -0,0 +1,103 @@
+package yapper
+
+import "fmt"
+
+// SquadLimit caps how many pairs between two squads meet in each round, so a small squad is not monopolised by a
+// larger one, e.g. at most 2 pairs of sales and engineering. The squads can be the same to cap the pairs within a
+// squad when the squad policy is prefer-differing.
+type SquadLimit struct {
+	Squads   [2]string `json:"squads"`
+	MaxPairs int       `json:"maxPairs"`
+}
+
+func (l SquadLimit) validate() error {
+	if l.Squads[0] == "" || l.Squads[1] == "" {
+		return fmt.Errorf("squad limits must name two squads: %v", l.Squads)
+	}
+	if l.MaxPairs < 0 {
+		return fmt.Errorf("max pairs of the squad limit of %s and %s must not be negative: %d", l.Squads[0], l.Squads[1], l.MaxPairs)
+	}
+	return nil
+}
+
+// appliesTo reports whether the limit is of the pairs between the squads.
+func (l SquadLimit) appliesTo(squad1, squad2 string) bool {
+	return (l.Squads[0] == squad1 && l.Squads[1] == squad2) || (l.Squads[0] == squad2 && l.Squads[1] == squad1)
+}
+
+// squadCounts counts the pairs of a round between the squads of each squad limit. A nil squadCounts, for a config
+// without squad limits, allows every pair.
+type squadCounts struct {
+	limits []SquadLimit
+	squads map[ID]string
+	counts []int
+}
+
+// newSquadCounts returns the counts of the squad limits of the config, or nil if it has none.
+func newSquadCounts(conf Config) *squadCounts {
+	if len(conf.SquadLimits) == 0 {
+		return nil
+	}
+
+	squads := make(map[ID]string, len(conf.People))
+	for _, person := range conf.People {
+		squads[person.ID] = person.Squad
+	}
+	return &squadCounts{limits: conf.SquadLimits, squads: squads, counts: make([]int, len(conf.SquadLimits))}
+}
+
+// newSquadCountsOf returns the counts of the squad limits of the config for the pairs.
+func newSquadCountsOf(conf Config, pairs [][2]ID) *squadCounts {
+	counts := newSquadCounts(conf)
+	for _, pair := range pairs {
+		counts.add(pair[0], pair[1])
+	}
+	return counts
+}
+
+// allows reports whether pairing the people keeps within every squad limit.
+func (c *squadCounts) allows(id1, id2 ID) bool {
+	if c == nil {
+		return true
+	}
+	for i, limit := range c.limits {
+		if limit.appliesTo(c.squads[id1], c.squads[id2]) && c.counts[i] >= limit.MaxPairs {
+			return false
+		}
+	}
+	return true
+}
+
+// excess returns how many more pairs the squad limits have than they allow in total, which only pins can cause
+// outside of pairings edited by hand.
+func (c *squadCounts) excess() int {
+	if c == nil {
+		return 0
+	}
+	excess := 0
+	for i, limit := range c.limits {
+		excess += max(c.counts[i]-limit.MaxPairs, 0)
+	}
+	return excess
+}
+
+// add counts the pair towards the squad limits applying to it.
+func (c *squadCounts) add(id1, id2 ID) {
+	c.change(id1, id2, 1)
+}
+
+// remove stops counting the pair towards the squad limits applying to it.
+func (c *squadCounts) remove(id1, id2 ID) {
+	c.change(id1, id2, -1)
+}
+
+func (c *squadCounts) change(id1, id2 ID, by int) {
+	if c == nil {
+		return
+	}
+	for i, limit := range c.limits {
+		if limit.appliesTo(c.squads[id1], c.squads[id2]) {
+			c.counts[i] += by
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package yapper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// squadLimitConfig returns a config with a sales squad of two and a larger engineering squad, where everyone has met
+// except the people of sales and engineering, with at most one pair of them in each round.
+func squadLimitConfig() (Config, history.History) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Squad: "sales"},
+			{ID: "Luigi", Squad: "sales"},
+			{ID: "Peach", Squad: "eng"},
+			{ID: "Daisy", Squad: "eng"},
+			{ID: "Toad", Squad: "eng"},
+			{ID: "Yoshi", Squad: "eng"},
+		},
+		Settings:    Settings{SquadPolicy: SquadPolicyPreferDiffering},
+		SquadLimits: []SquadLimit{{Squads: [2]string{"sales", "eng"}, MaxPairs: 1}},
+	}
+	hist := history.History{}
+	date := time.Date(2025, time.July, 28, 0, 0, 0, 0, time.UTC)
+	hist.AddMeeting("Mario", "Luigi", date)
+	for _, id1 := range []history.ID{"Peach", "Daisy", "Toad", "Yoshi"} {
+		for _, id2 := range []history.ID{"Peach", "Daisy", "Toad", "Yoshi"} {
+			if id1 < id2 {
+				hist.AddMeeting(id1, id2, date)
+			}
+		}
+	}
+	return config, hist
+}
+
+// salesAndEngPairs counts the pairs between sales and engineering.
+func salesAndEngPairs(config Config, pairings Pairings) int {
+	counts := newSquadCountsOf(config, pairings.data)
+	return counts.counts[0]
+}
+
+func TestStrategiesKeepToSquadLimits(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	for _, strategy := range Strategies {
+		config, hist := squadLimitConfig()
+		config.Settings.Strategy = strategy
+		config.Settings.Improvement = &Improvement{}
+		config.SoftConstraints.RecentlyMet, config.SoftConstraints.RecentlyMetDays = 1, 28
+
+		weeklyPairings, err := chooseWithStrategies(context.Background(), config, determineValidPairings(config), hist, []time.Time{date, date.AddDate(0, 0, 7)})
+		if err != nil {
+			t.Fatalf("Unexpected error with the %s strategy: %v", strategy, err)
+		}
+		for _, pairings := range weeklyPairings {
+			if pairs := salesAndEngPairs(config, pairings); pairs > 1 {
+				t.Errorf("Expected at most one pair of sales and engineering with the %s strategy, got %v", strategy, pairings.data)
+			}
+			// Only pairing sales together pairs everyone, which the exact strategy finds.
+			if strategy == StrategyExact && pairings.Len() != 3 {
+				t.Errorf("Expected everyone to be paired with the exact strategy, got %v", pairings.data)
+			}
+		}
+	}
+}
+
+func TestValidatePairingsReportsSquadLimits(t *testing.T) {
+	config, _ := squadLimitConfig()
+	pairings := NewPairings(time.Time{}, [][2]ID{{"Mario", "Peach"}, {"Luigi", "Daisy"}, {"Toad", "Yoshi"}})
+
+	expected := []Violation{newViolation(ViolationSquadLimit, "Luigi", "Daisy")}
+	if got := ValidatePairings(config, pairings); !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+	}
+}
+
+func TestNewResultExplainsSquadLimits(t *testing.T) {
+	config, _ := squadLimitConfig()
+	config.People = config.People[:4]
+	config.People[1].DenyList = []ID{"Mario"}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	round := NewResult(config, []Pairings{NewPairings(date, [][2]ID{{"Mario", "Peach"}})}).Rounds[0]
+	expected := []Blocker{
+		{Reason: ViolationDenyList, People: []ID{"Mario"}, Message: "denied by a deny list: Mario"},
+		{People: []ID{"Peach"}, Message: "already paired: Peach"},
+		{Reason: ViolationSquadLimit, People: []ID{"Daisy"}, Message: "in a squad whose squad limit was reached: Daisy"},
+	}
+	if got := round.Diagnostics[0].Blockers; !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+	}
+}
+
+func TestValidateRejectsInvalidSquadLimits(t *testing.T) {
+	for _, limit := range []SquadLimit{{Squads: [2]string{"sales", ""}, MaxPairs: 1}, {Squads: [2]string{"sales", "eng"}, MaxPairs: -1}} {
+		config := Config{SquadLimits: []SquadLimit{limit}}
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected an error validating %+v", limit)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package yapper
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// StrangerOrder decides the order in which people who have never met are considered for a pairing.
+// As everyone a person has not met ranks equally on time since last meeting, the order decides who meets first.
+type StrangerOrder string
+
+const (
+	// StrangerOrderConfig considers people in the order they are listed in the config.
+	StrangerOrderConfig StrangerOrder = "config-order"
+	// StrangerOrderRandom considers people in a random order, which is stable for a person in a round.
+	StrangerOrderRandom StrangerOrder = "random"
+	// StrangerOrderFewestPartners considers the people who have met the fewest people first, then in config order.
+	StrangerOrderFewestPartners StrangerOrder = "fewest-partners-first"
+)
+
+// StrangerOrders lists the supported stranger orders.
+var StrangerOrders = []StrangerOrder{StrangerOrderConfig, StrangerOrderRandom, StrangerOrderFewestPartners}
+
+func (o StrangerOrder) validate() error {
+	if o != "" && !slices.Contains(StrangerOrders, o) {
+		return fmt.Errorf("unexpected stranger order: %s", o)
+	}
+	return nil
+}
+
+// strangerOrder returns the configured stranger order, defaulting to config order.
+func (c Config) strangerOrder() StrangerOrder {
+	if c.StrangerOrder == "" {
+		return StrangerOrderConfig
+	}
+	return c.StrangerOrder
+}
+
+// orderStrangers orders the people the person has never met according to the stranger order of the config.
+func orderStrangers(conf Config, hist history.History, id ID, strangers []ID, date time.Time) []ID {
+	switch conf.strangerOrder() {
+	case StrangerOrderRandom:
+		hash := fnv.New64a()
+		hash.Write([]byte(id))
+		hash.Write([]byte{0})
+		hash.Write([]byte(date.Format(time.DateOnly)))
+		random := rand.New(rand.NewPCG(hash.Sum64(), 0))
+		random.Shuffle(len(strangers), func(i, j int) { strangers[i], strangers[j] = strangers[j], strangers[i] })
+	case StrangerOrderFewestPartners:
+		slices.SortStableFunc(strangers, func(a, b ID) int {
+			return len(hist.GetPersonToLastMeetingMap(history.ID(a))) - len(hist.GetPersonToLastMeetingMap(history.ID(b)))
+		})
+	}
+	return strangers
+}
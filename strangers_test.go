@@ -0,0 +1,60 @@
+package yapper
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestOrderStrangers(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Luigi", "Bowser", date.AddDate(0, 0, -7))
+	hist.AddMeeting("Luigi", "Wario", date.AddDate(0, 0, -14))
+	hist.AddMeeting("Peach", "Bowser", date.AddDate(0, 0, -7))
+
+	strangers := []ID{"Luigi", "Peach", "Toad"}
+	tests := map[StrangerOrder][]ID{
+		"":                          {"Luigi", "Peach", "Toad"},
+		StrangerOrderConfig:         {"Luigi", "Peach", "Toad"},
+		StrangerOrderFewestPartners: {"Toad", "Peach", "Luigi"},
+	}
+
+	for order, expected := range tests {
+		got := orderStrangers(Config{StrangerOrder: order}, hist, "Mario", slices.Clone(strangers), date)
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("Expected %q order:\n%v\nGot:\n%v", order, expected, got)
+		}
+	}
+}
+
+func TestOrderStrangersRandomIsStableWithinRound(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{StrangerOrder: StrangerOrderRandom}
+	strangers := []ID{"A", "B", "C", "D", "E", "F", "G", "H"}
+
+	first := orderStrangers(config, history.History{}, "Mario", slices.Clone(strangers), date)
+	second := orderStrangers(config, history.History{}, "Mario", slices.Clone(strangers), date)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same order within a round, got %v and %v", first, second)
+	}
+
+	varies := false
+	for week := 1; week <= 10 && !varies; week++ {
+		other := orderStrangers(config, history.History{}, "Mario", slices.Clone(strangers), date.AddDate(0, 0, 7*week))
+		varies = !reflect.DeepEqual(first, other)
+	}
+	if !varies {
+		t.Errorf("Expected the order to vary between rounds")
+	}
+}
+
+func TestValidateRejectsUnexpectedStrangerOrder(t *testing.T) {
+	config := Config{StrangerOrder: "alphabetical"}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected an error validating an unexpected stranger order")
+	}
+}
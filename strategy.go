@@ -0,0 +1,171 @@
+package yapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Strategy decides how the pairings for the requested rounds are chosen.
+type Strategy string
+
+const (
+	// StrategyGreedy pairs each round in turn, preferring the people who have not met for the longest.
+	StrategyGreedy Strategy = "greedy"
+	// StrategyPlanned optimises all of the requested rounds together, minimising repeats and maximising coverage.
+	StrategyPlanned Strategy = "planned"
+	// StrategyExact pairs each round in turn, choosing the best pairings of the round by solving an integer program.
+	StrategyExact Strategy = "exact"
+)
+
+// Strategies lists the supported strategies.
+var Strategies = []Strategy{StrategyGreedy, StrategyPlanned, StrategyExact}
+
+func (s Strategy) validate() error {
+	switch s {
+	case "", StrategyGreedy, StrategyPlanned, StrategyExact:
+		return nil
+	default:
+		return fmt.Errorf("unexpected strategy: %s", s)
+	}
+}
+
+// StrategyStep is one of the strategies tried in turn, which is abandoned for the next if it runs over its budget.
+type StrategyStep struct {
+	Strategy Strategy `json:"strategy"`
+	// Budget is the longest the strategy may take, such as 10s, without which it always runs to completion.
+	Budget string `json:"budget,omitempty"`
+}
+
+func (s StrategyStep) validate() error {
+	if s.Strategy == "" {
+		return errors.New("each strategy step must have a strategy")
+	}
+	if err := s.Strategy.validate(); err != nil {
+		return err
+	}
+
+	if s.Budget == "" {
+		return nil
+	}
+	if budget, err := time.ParseDuration(s.Budget); err != nil {
+		return fmt.Errorf("error parsing budget of %s strategy: %w", s.Strategy, err)
+	} else if budget <= 0 {
+		return fmt.Errorf("budget of %s strategy must be positive: %s", s.Strategy, s.Budget)
+	}
+	return nil
+}
+
+// budget returns the budget of the step, or zero if it has none.
+func (s StrategyStep) budget() time.Duration {
+	budget, _ := time.ParseDuration(s.Budget)
+	return budget
+}
+
+// strategy returns the configured strategy, defaulting to greedy.
+func (c Config) strategy() Strategy {
+	if c.Settings.Strategy == "" {
+		return StrategyGreedy
+	}
+	return c.Settings.Strategy
+}
+
+// strategySteps returns the strategies to try in turn, defaulting to the configured strategy without a budget.
+func (c Config) strategySteps() []StrategyStep {
+	if len(c.Settings.Strategies) == 0 {
+		return []StrategyStep{{Strategy: c.strategy()}}
+	}
+	return c.Settings.Strategies
+}
+
+// chooseWithStrategies tries each of the strategies of the config in turn until one completes within its budget,
+// returning the pairings it chose without changing the history. It stops with the error of the context once it is done.
+func chooseWithStrategies(ctx context.Context, config Config, idToValidPairings map[ID][]ID, hist history.History, dates []time.Time) ([]Pairings, error) {
+	var err error
+	for _, step := range config.strategySteps() {
+		var weeklyPairings []Pairings
+		weeklyPairings, err = generateWithStrategy(ctx, config, idToValidPairings, hist, dates, step)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			Logger().Warn("strategy ran over its budget, trying the next", "strategy", step.Strategy, "budget", step.Budget)
+			continue
+		}
+		return weeklyPairings, err
+	}
+
+	return nil, fmt.Errorf("no strategy completed within its budget: %w", err)
+}
+
+// generateWithStrategy generates the rounds with the strategy of the step without changing the history,
+// returning context.DeadlineExceeded if the strategy runs over its budget.
+func generateWithStrategy(ctx context.Context, config Config, idToValidPairings map[ID][]ID, hist history.History, dates []time.Time, step StrategyStep) ([]Pairings, error) {
+	if budget := step.budget(); budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	switch step.Strategy {
+	case StrategyGreedy:
+		return generateGreedy(ctx, config, idToValidPairings, hist, dates)
+	case StrategyPlanned:
+		weeklyPairings, err := generatePlanned(ctx, config, idToValidPairings, hist, dates)
+		if err != nil {
+			return nil, err
+		}
+		for i := range weeklyPairings {
+			weeklyPairings[i].penalty = Penalty(config, hist, weeklyPairings[i])
+		}
+		return weeklyPairings, nil
+	case StrategyExact:
+		return generateExact(ctx, config, idToValidPairings, hist, dates)
+	default:
+		return nil, fmt.Errorf("unexpected strategy: %s", step.Strategy)
+	}
+}
+
+// generateGreedy pairs each round in turn, improving it with the improvement of the config and simulating its meetings
+// in a copy of the history before moving to the next round. It stops with the error of the context once it is done.
+func generateGreedy(ctx context.Context, config Config, idToValidPairings map[ID][]ID, hist history.History, dates []time.Time) ([]Pairings, error) {
+	weeklyPairings := make([]Pairings, 0, len(dates))
+	simulated := copyHistory(config, hist)
+
+	for _, date := range dates {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pairings := improveRound(ctx, config, idToValidPairings, simulated, pairPeople(config, idToValidPairings, simulated, date))
+		for id1, id2 := range pairings.All() {
+			simulated.AddMeeting(history.ID(id1), history.ID(id2), date)
+		}
+		weeklyPairings = append(weeklyPairings, pairings)
+	}
+
+	return weeklyPairings, nil
+}
+
+// recordPairings adds a meeting to the history for each of the pairings,
+// first noting when each pair previously met, and designates who initiates each meeting and what they discuss.
+func recordPairings(conf Config, hist *history.History, pairings *Pairings) {
+	pairings.lastMeetings = make(map[[2]ID]time.Time)
+	for id1, id2 := range pairings.All() {
+		if lastMeeting, met := hist.GetPersonToLastMeetingMap(history.ID(id1))[history.ID(id2)]; met {
+			pairings.lastMeetings[pairKey(id1, id2)] = lastMeeting
+		}
+	}
+
+	for id1, id2 := range pairings.All() {
+		pairings.designateInitiator(hist, id1, id2)
+		pairings.assignTopic(conf, hist, id1, id2)
+		hist.AddMeeting(
+			history.ID(id1),
+			history.ID(id2),
+			pairings.Date(),
+		)
+	}
+}
@@ -0,0 +1,62 @@
+package yapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestGenerateForDatesFallsBackToNextStrategy(t *testing.T) {
+	config := getConfigFromFile(t, validConfigName)
+	config.Settings.Strategies = []StrategyStep{{Strategy: StrategyPlanned, Budget: "1ns"}, {Strategy: StrategyGreedy}}
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 52)
+
+	hist := history.History{}
+	weeklyPairings, err := generateForDates(config, &hist, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error generating pairings: %v", err)
+	}
+
+	if len(weeklyPairings) != len(dates) {
+		t.Fatalf("Expected %d rounds, got %d", len(dates), len(weeklyPairings))
+	}
+	if len(hist.GetPersonToLastMeetingMap(history.ID(weeklyPairings[0].data[0][0]))) == 0 {
+		t.Error("Expected the pairings of the greedy strategy to be recorded in the history")
+	}
+}
+
+func TestGenerateForDatesReturnsErrorWhenEveryStrategyRunsOutOfBudget(t *testing.T) {
+	config := getConfigFromFile(t, validConfigName)
+	config.Settings.Strategies = []StrategyStep{{Strategy: StrategyPlanned, Budget: "1ns"}}
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 52)
+
+	hist := history.History{}
+	if _, err := generateForDates(config, &hist, dates); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the strategy to run out of budget, got %v", err)
+	}
+
+	for pair := range hist.All() {
+		t.Errorf("Expected the history to be unchanged, got a meeting of %v", pair)
+	}
+}
+
+func TestGenerateGreedyDoesNotChangeHistory(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+	dates := getRoundDates(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), 2)
+
+	hist := history.History{}
+	weeklyPairings, err := generateGreedy(context.Background(), config, determineValidPairings(config), hist, dates)
+	if err != nil {
+		t.Fatalf("Unexpected error generating pairings: %v", err)
+	}
+
+	if len(weeklyPairings) != 2 || len(weeklyPairings[1].data) != 1 {
+		t.Errorf("Expected Mario and Luigi to meet in both rounds, got %v", weeklyPairings)
+	}
+	for pair := range hist.All() {
+		t.Errorf("Expected the history to be unchanged, got a meeting of %v", pair)
+	}
+}
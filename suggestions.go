@@ -0,0 +1,143 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// SuggestionKind is a kind of change to the config suggested by SuggestConfigChanges.
+type SuggestionKind string
+
+const (
+	// SuggestionUnpairable is someone who cannot be paired with anyone under the hard constraints.
+	SuggestionUnpairable SuggestionKind = "unpairable"
+	// SuggestionRedundantDeny is an entry of a deny list for someone who is never paired with its person anyway, such
+	// as because they are in the same squad.
+	SuggestionRedundantDeny SuggestionKind = "redundant-deny"
+	// SuggestionSmallSquad is a squad so small its members met far less often than everyone else in the history.
+	SuggestionSmallSquad SuggestionKind = "small-squad"
+)
+
+// Suggestion is a change to the config which would likely improve the pairings or simplify the config.
+type Suggestion struct {
+	Kind    SuggestionKind `json:"kind"`
+	People  []ID           `json:"people,omitempty"`
+	Squads  []string       `json:"squads,omitempty"`
+	Message string         `json:"message"`
+}
+
+// SuggestionOptions are the thresholds of the suggestions.
+type SuggestionOptions struct {
+	// SmallSquad is the most members a squad can have to be suggested as too small.
+	SmallSquad int
+	// RareShare is the share of the average meetings of everyone the members of a small squad must have met less than
+	// on average for the squad to be suggested, e.g. 0.5.
+	RareShare float64
+}
+
+// SuggestConfigChanges returns the changes to the config suggested by its constraints and the history: people who
+// cannot be paired with anyone, deny list entries for people who are never paired with the person anyway, and small
+// squads whose members met far less often than everyone else.
+func SuggestConfigChanges(config Config, hist history.History, options SuggestionOptions) []Suggestion {
+	suggestions := []Suggestion{}
+	idToValidPairings := determineValidPairings(config)
+
+	for _, person := range config.People {
+		if len(idToValidPairings[person.ID]) == 0 {
+			message := fmt.Sprintf("%s cannot be paired with anyone, so their deny lists, squad, tags or the rules applying to them should be relaxed", person.ID)
+			suggestions = append(suggestions, Suggestion{Kind: SuggestionUnpairable, People: []ID{person.ID}, Message: message})
+		}
+	}
+
+	suggestions = append(suggestions, redundantDenyEntries(config)...)
+	suggestions = append(suggestions, smallSquads(config, hist, options)...)
+	return suggestions
+}
+
+// redundantDenyEntries returns the entries of the deny lists for people who would not be paired with the person even
+// without any deny lists or deny groups.
+func redundantDenyEntries(config Config) []Suggestion {
+	suggestions := []Suggestion{}
+	check := newPairCheck(config)
+	for _, person := range config.People {
+		for _, id := range person.DenyList {
+			other, found := check.index[id]
+			if !found || id == person.ID {
+				continue
+			}
+
+			undenied := person
+			undenied.DenyList, undenied.DenyGroups = nil, nil
+			other.DenyList, other.DenyGroups = nil, nil
+			kind := check.denial(undenied, other)
+			if kind == "" {
+				continue
+			}
+
+			message := fmt.Sprintf("%s can be removed from the deny list of %s, as %s", id, person.ID, newViolation(kind, person.ID, id).Message)
+			suggestions = append(suggestions, Suggestion{Kind: SuggestionRedundantDeny, People: []ID{person.ID, id}, Message: message})
+		}
+	}
+	return suggestions
+}
+
+// smallSquads returns the squads of at most the small squad size whose members met less than the rare share of the
+// average meetings of everyone in the config. People who are paused are left out of both.
+func smallSquads(config Config, hist history.History, options SuggestionOptions) []Suggestion {
+	suggestions := []Suggestion{}
+	if options.SmallSquad <= 0 {
+		return suggestions
+	}
+
+	index := config.Index()
+	meetings := map[ID]int{}
+	total, counted := 0, 0
+	members := map[string][]ID{}
+	var squads []string
+	for _, person := range config.People {
+		if person.Paused {
+			continue
+		}
+		for other := range hist.PartnersOf(history.ID(person.ID)) {
+			if _, found := index[ID(other)]; found {
+				meetings[person.ID] += hist.TimesMet(history.ID(person.ID), other)
+			}
+		}
+		total += meetings[person.ID]
+		counted++
+
+		if person.Squad == "" {
+			continue
+		}
+		if _, found := members[person.Squad]; !found {
+			squads = append(squads, person.Squad)
+		}
+		members[person.Squad] = append(members[person.Squad], person.ID)
+	}
+	if total == 0 {
+		return suggestions
+	}
+	average := float64(total) / float64(counted)
+	slices.Sort(squads)
+
+	for _, squad := range squads {
+		if len(members[squad]) > options.SmallSquad {
+			continue
+		}
+
+		squadTotal := 0
+		for _, id := range members[squad] {
+			squadTotal += meetings[id]
+		}
+		squadAverage := float64(squadTotal) / float64(len(members[squad]))
+		if squadAverage >= options.RareShare*average {
+			continue
+		}
+
+		message := fmt.Sprintf("the members of squad %s met %.1f times on average, against %.1f for everyone, so it could be merged into another squad", squad, squadAverage, average)
+		suggestions = append(suggestions, Suggestion{Kind: SuggestionSmallSquad, People: members[squad], Squads: []string{squad}, Message: message})
+	}
+	return suggestions
+}
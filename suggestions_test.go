@@ -0,0 +1,51 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestSuggestConfigChanges(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Squad: "plumbers", DenyList: []ID{"Luigi", "Peach"}},
+			{ID: "Luigi", Squad: "plumbers"},
+			{ID: "Peach", Squad: "royals"},
+			{ID: "Daisy", Squad: "royals"},
+			{ID: "Toad", Squad: "royals"},
+			{ID: "Yoshi", Squad: "dinosaurs"},
+			{ID: "Bowser", Guest: true},
+		},
+		Settings: Settings{SquadPolicy: SquadPolicyDeny},
+	}
+	hist := history.History{}
+	date := time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC)
+	for week := range 3 {
+		hist.AddMeeting("Mario", "Daisy", date.AddDate(0, 0, 7*week))
+		hist.AddMeeting("Luigi", "Toad", date.AddDate(0, 0, 7*week))
+		hist.AddMeeting("Peach", "Luigi", date.AddDate(0, 0, 7*week))
+	}
+
+	suggestions := SuggestConfigChanges(config, hist, SuggestionOptions{SmallSquad: 1, RareShare: 0.5})
+	expected := []Suggestion{
+		{Kind: SuggestionUnpairable, People: []ID{"Bowser"}, Message: "Bowser cannot be paired with anyone, so their deny lists, squad, tags or the rules applying to them should be relaxed"},
+		{Kind: SuggestionRedundantDeny, People: []ID{"Mario", "Luigi"}, Message: "Luigi can be removed from the deny list of Mario, as Mario and Luigi are in the same squad"},
+		{Kind: SuggestionSmallSquad, People: []ID{"Yoshi"}, Squads: []string{"dinosaurs"}, Message: "the members of squad dinosaurs met 0.0 times on average, against 2.6 for everyone, so it could be merged into another squad"},
+	}
+	if !reflect.DeepEqual(expected, suggestions) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, suggestions)
+	}
+}
+
+func TestSuggestConfigChangesWithoutSmallSquads(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario", Squad: "plumbers"}, {ID: "Luigi", Squad: "royals"}}}
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", time.Date(2025, time.July, 7, 0, 0, 0, 0, time.UTC))
+
+	if suggestions := SuggestConfigChanges(config, hist, SuggestionOptions{}); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions, got %v", suggestions)
+	}
+}
@@ -0,0 +1,163 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// The costs of seating two people at the same table, which table assignment minimises.
+// Seating people who cannot be paired is worse than seating people together again, which is worse than seating
+// people who met before the event.
+const (
+	tableDeniedCost    = 1000.0
+	tableRepeatCost    = 100.0
+	tableMetBeforeCost = 1.0
+)
+
+// Table is the people seated together for a round of an event, who all meet each other.
+type Table []ID
+
+// GenerateTables seats everyone who can attend an event on the date at tables of at most tableSize people, for the
+// given number of rounds, recording the meetings of each table in the history.
+// People are moved between tables to maximise the distinct people each person meets across the rounds,
+// avoiding seating people who cannot be paired together where possible. See GenerateEvent for who can attend.
+func GenerateTables(config Config, hist *history.History, date time.Time, tableSize, rounds int) ([][]Table, error) {
+	if tableSize < 2 {
+		return nil, fmt.Errorf("tables must seat at least two people: %d", tableSize)
+	}
+	if rounds < 1 {
+		return nil, fmt.Errorf("an event needs at least one round: %d", rounds)
+	}
+
+	event := eventConfig(config)
+	year, month, day := date.Date()
+	date = time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+
+	idToValidPairings := determineValidPairings(event)
+	ineligible := getIneligiblePeople(event, idToValidPairings, date)
+	var attendees []ID
+	for _, person := range event.People {
+		if !slices.Contains(ineligible, person.ID) {
+			attendees = append(attendees, person.ID)
+		}
+	}
+
+	s := seating{
+		hist:       copyHistory(event, *hist),
+		validPairs: map[[2]ID]bool{},
+		seated:     map[[2]ID]int{},
+	}
+	for id, validPairings := range idToValidPairings {
+		for _, other := range validPairings {
+			s.validPairs[pairKey(id, other)] = true
+		}
+	}
+
+	eventRounds := make([][]Table, 0, rounds)
+	for range rounds {
+		tables := s.seat(attendees, tableSize)
+		s.improve(tables)
+
+		for _, table := range tables {
+			for i, id1 := range table {
+				for _, id2 := range table[i+1:] {
+					s.seated[pairKey(id1, id2)]++
+					hist.AddMeeting(history.ID(id1), history.ID(id2), date)
+				}
+			}
+		}
+		eventRounds = append(eventRounds, tables)
+	}
+
+	return eventRounds, nil
+}
+
+// seating holds who has already been seated together during an event.
+type seating struct {
+	// hist is the history from before the event.
+	hist       history.History
+	validPairs map[[2]ID]bool
+	seated     map[[2]ID]int
+}
+
+// seat assigns the attendees in order to the table with the lowest cost which has a seat left, balancing the number
+// of people at each table.
+func (s *seating) seat(attendees []ID, tableSize int) []Table {
+	if len(attendees) == 0 {
+		return []Table{}
+	}
+
+	count := (len(attendees) + tableSize - 1) / tableSize
+	tables := make([]Table, count)
+	seats := make([]int, count)
+	for i := range seats {
+		seats[i] = len(attendees) / count
+		if i < len(attendees)%count {
+			seats[i]++
+		}
+	}
+
+	for _, id := range attendees {
+		best := -1
+		for i, table := range tables {
+			if len(table) == seats[i] {
+				continue
+			}
+			if best == -1 || s.cost(id, table, "") < s.cost(id, tables[best], "") {
+				best = i
+			}
+		}
+		tables[best] = append(tables[best], id)
+	}
+
+	return tables
+}
+
+// improve repeatedly swaps people between tables while doing so reduces the cost of the round.
+func (s *seating) improve(tables []Table) {
+	for range planMaxPasses {
+		improved := false
+		for i := range tables {
+			for j := i + 1; j < len(tables); j++ {
+				for a, id1 := range tables[i] {
+					for b, id2 := range tables[j] {
+						before := s.cost(id1, tables[i], id1) + s.cost(id2, tables[j], id2)
+						after := s.cost(id1, tables[j], id2) + s.cost(id2, tables[i], id1)
+						if after < before {
+							tables[i][a], tables[j][b] = id2, id1
+							id1 = id2
+							improved = true
+						}
+					}
+				}
+			}
+		}
+
+		if !improved {
+			return
+		}
+	}
+}
+
+// cost of seating the person with the people at the table, other than the person leaving it.
+func (s *seating) cost(id ID, table Table, leaving ID) float64 {
+	cost := 0.0
+	for _, other := range table {
+		if other == leaving || other == id {
+			continue
+		}
+
+		key := pairKey(id, other)
+		if !s.validPairs[key] {
+			cost += tableDeniedCost
+		}
+		cost += tableRepeatCost * float64(s.seated[key])
+		if _, met := s.hist.GetPersonToLastMeetingMap(history.ID(id))[history.ID(other)]; met {
+			cost += tableMetBeforeCost
+		}
+	}
+	return cost
+}
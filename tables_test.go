@@ -0,0 +1,85 @@
+package yapper
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+var tablesDate = time.Date(2025, time.August, 7, 0, 0, 0, 0, time.UTC)
+
+func TestGenerateTablesMaximisesEncounters(t *testing.T) {
+	var people []Person
+	for i := range 16 {
+		people = append(people, Person{ID: ID(fmt.Sprintf("Goomba %d", i))})
+	}
+	hist := history.History{}
+
+	rounds, err := GenerateTables(Config{People: people}, &hist, tablesDate, 4, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seated := map[[2]ID]bool{}
+	for _, tables := range rounds {
+		seen := 0
+		for _, table := range tables {
+			if len(table) != 4 {
+				t.Errorf("Expected tables of 4, got %v", table)
+			}
+			seen += len(table)
+
+			for i, id1 := range table {
+				for _, id2 := range table[i+1:] {
+					if seated[pairKey(id1, id2)] {
+						t.Errorf("Expected %s and %s to only be seated together once", id1, id2)
+					}
+					seated[pairKey(id1, id2)] = true
+				}
+			}
+		}
+
+		if seen != 16 {
+			t.Errorf("Expected everyone to be seated once per round, got %d seats", seen)
+		}
+	}
+
+	if _, met := hist.GetPersonToLastMeetingMap(history.ID(rounds[0][0][0]))[history.ID(rounds[0][0][1])]; !met {
+		t.Errorf("Expected the tables to be recorded in the history")
+	}
+}
+
+func TestGenerateTablesBalancesTablesAndAvoidsDeniedPairs(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario", DenyList: []ID{"Bowser"}}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}, {ID: "Bowser"}, {ID: "Yoshi", Paused: true},
+	}}
+
+	rounds, err := GenerateTables(config, &history.History{}, tablesDate, 3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, tables := range rounds {
+		if len(tables) != 2 || len(tables[0]) != 3 || len(tables[1]) != 2 {
+			t.Errorf("Expected tables of 3 and 2, got %v", tables)
+		}
+
+		for _, table := range tables {
+			if slices.Contains(table, "Yoshi") {
+				t.Errorf("Expected Yoshi not to be seated while paused, got %v", table)
+			}
+			if slices.Contains(table, "Mario") && slices.Contains(table, "Bowser") {
+				t.Errorf("Expected Mario and Bowser not to be seated together, got %v", table)
+			}
+		}
+	}
+}
+
+func TestGenerateTablesRequiresSeats(t *testing.T) {
+	if _, err := GenerateTables(Config{}, &history.History{}, tablesDate, 1, 1); err == nil {
+		t.Errorf("Expected an error for tables of one")
+	}
+}
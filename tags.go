@@ -0,0 +1,88 @@
+package yapper
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+type TagRuleKind string
+
+const (
+	// TagRuleDenyShared prevents pairing people who share a tag matching the rule.
+	TagRuleDenyShared TagRuleKind = "deny-shared"
+	// TagRulePreferDiffering is a soft constraint preferring to pair people who do not share a tag matching the rule.
+	TagRulePreferDiffering TagRuleKind = "prefer-differing"
+)
+
+// TagRule applies to the tags matching its pattern, e.g. "guild:*" for every guild or "location:berlin".
+// Patterns use the syntax of path.Match.
+type TagRule struct {
+	Tag  string      `json:"tag"`
+	Kind TagRuleKind `json:"kind"`
+	// Weight is the penalty of a prefer-differing rule, defaulting to 1.
+	Weight float64 `json:"weight"`
+}
+
+func (r TagRule) validate() error {
+	if _, err := path.Match(r.Tag, ""); err != nil {
+		return fmt.Errorf("invalid tag pattern %q: %w", r.Tag, err)
+	}
+
+	if r.Weight < 0 {
+		return fmt.Errorf("tag rule weight must not be negative for %q: %f", r.Tag, r.Weight)
+	}
+
+	switch r.Kind {
+	case TagRuleDenyShared, TagRulePreferDiffering:
+		return nil
+	default:
+		return fmt.Errorf("unexpected tag rule kind for %q: %s", r.Tag, r.Kind)
+	}
+}
+
+// matchedBy reports whether both people share a tag matching the rule.
+func (r TagRule) matchedBy(person1, person2 Person) bool {
+	tags2 := person2.AllTags()
+	for _, tag := range person1.AllTags() {
+		if matched, _ := path.Match(r.Tag, tag); matched && slices.Contains(tags2, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllTags returns the tags of the person, including their squad as a "squad:" tag, location as a "location:" tag
+// and interests as "interest:" tags.
+func (p Person) AllTags() []string {
+	if p.Squad == "" && p.Location == "" && len(p.Interests) == 0 {
+		return p.Tags
+	}
+
+	tags := slices.Clone(p.Tags)
+	if p.Squad != "" {
+		tags = append(tags, "squad:"+p.Squad)
+	}
+	if p.Location != "" {
+		tags = append(tags, "location:"+p.Location)
+	}
+	for _, interest := range p.Interests {
+		tags = append(tags, "interest:"+interest)
+	}
+	return tags
+}
+
+// HasTag reports whether the person has a tag, including their squad and location, matching the pattern.
+func (p Person) HasTag(pattern string) bool {
+	return slices.ContainsFunc(p.AllTags(), func(tag string) bool {
+		matched, _ := path.Match(pattern, tag)
+		return matched
+	})
+}
+
+// sharesTag reports whether the people share a tag matching any rule of the given kind.
+func sharesTag(conf Config, kind TagRuleKind, person1, person2 Person) bool {
+	return slices.ContainsFunc(conf.TagRules, func(r TagRule) bool {
+		return r.Kind == kind && r.matchedBy(person1, person2)
+	})
+}
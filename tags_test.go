@@ -0,0 +1,56 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagRuleValidateReturnsErrorForUnexpectedKind(t *testing.T) {
+	rule := TagRule{Tag: "guild:*", Kind: "sometimes"}
+	if err := rule.validate(); err == nil {
+		t.Errorf("Expected error due to unexpected tag rule kind")
+	}
+}
+
+func TestTagRuleValidateReturnsErrorForInvalidPattern(t *testing.T) {
+	rule := TagRule{Tag: "guild:[", Kind: TagRuleDenyShared}
+	if err := rule.validate(); err == nil {
+		t.Errorf("Expected error due to invalid tag pattern")
+	}
+}
+
+func TestPersonAllTagsIncludesSquad(t *testing.T) {
+	person := Person{ID: "Mario", Squad: "bros", Tags: []string{"location:mushroom-kingdom"}}
+	expected := []string{"location:mushroom-kingdom", "squad:bros"}
+
+	if tags := person.AllTags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, tags)
+	}
+}
+
+func TestPersonAllTagsIncludesInterests(t *testing.T) {
+	person := Person{ID: "Mario", Interests: []string{"karting", "plumbing"}}
+	expected := []string{"interest:karting", "interest:plumbing"}
+
+	if tags := person.AllTags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, tags)
+	}
+}
+
+func TestDetermineValidPairingsExcludesPeopleSharingDenyTag(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", Tags: []string{"guild:plumbing"}},
+			{ID: "Luigi", Tags: []string{"guild:plumbing"}},
+			{ID: "Peach", Tags: []string{"guild:royalty"}},
+		},
+		TagRules: []TagRule{{Tag: "guild:*", Kind: TagRuleDenyShared}},
+	}
+
+	expected := map[ID][]ID{
+		"Mario": {"Peach"},
+		"Luigi": {"Peach"},
+		"Peach": {"Mario", "Luigi"},
+	}
+	diffPairings(t, determineValidPairings(config), expected)
+}
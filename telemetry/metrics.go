@@ -0,0 +1,123 @@
+package telemetry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// counter is the cumulative sum of a counter for one set of attributes.
+type counter struct {
+	name       string
+	attributes []Attribute
+	value      int64
+}
+
+// histogram is the cumulative count, sum and range of the values recorded for one set of attributes.
+type histogram struct {
+	name       string
+	attributes []Attribute
+	count      uint64
+	sum        float64
+	min        float64
+	max        float64
+}
+
+// Add adds the value to the counter with the name and attributes, such as the number of messages sent.
+func Add(name string, value int64, attributes ...Attribute) {
+	e := current.Load()
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := metricKey(name, attributes)
+	if e.counters[key] == nil {
+		e.counters[key] = &counter{name: name, attributes: attributes}
+	}
+	e.counters[key].value += value
+}
+
+// Record records the value in the histogram with the name and attributes, such as the duration of a run in seconds.
+func Record(name string, value float64, attributes ...Attribute) {
+	e := current.Load()
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := metricKey(name, attributes)
+	h := e.histograms[key]
+	if h == nil {
+		h = &histogram{name: name, attributes: attributes, min: value, max: value}
+		e.histograms[key] = h
+	}
+	h.count++
+	h.sum += value
+	h.min = min(h.min, value)
+	h.max = max(h.max, value)
+}
+
+// metricKey identifies the series of a metric, which is the same whatever order the attributes are given in.
+func metricKey(name string, attributes []Attribute) string {
+	parts := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		parts = append(parts, fmt.Sprintf("%s=%v", attribute.Key, attribute.Value))
+	}
+	slices.Sort(parts)
+	return name + "\x00" + strings.Join(parts, "\x00")
+}
+
+// metrics returns the current values of the metrics, grouping the series of each metric. The caller must hold the
+// lock.
+func (e *exporter) metrics(now time.Time) []otlpMetric {
+	byName := map[string]*otlpMetric{}
+	var names []string
+	metric := func(name string) *otlpMetric {
+		if byName[name] == nil {
+			byName[name] = &otlpMetric{Name: name}
+			names = append(names, name)
+		}
+		return byName[name]
+	}
+
+	for _, c := range e.counters {
+		m := metric(c.name)
+		if m.Sum == nil {
+			m.Sum = &otlpSum{AggregationTemporality: 2, IsMonotonic: true}
+		}
+		m.Sum.DataPoints = append(m.Sum.DataPoints, otlpNumberDataPoint{
+			Attributes:        otlpAttributes(c.attributes),
+			StartTimeUnixNano: unixNano(e.start),
+			TimeUnixNano:      unixNano(now),
+			AsInt:             fmt.Sprint(c.value),
+		})
+	}
+	for _, h := range e.histograms {
+		m := metric(h.name)
+		if m.Histogram == nil {
+			m.Histogram = &otlpHistogram{AggregationTemporality: 2}
+		}
+		m.Histogram.DataPoints = append(m.Histogram.DataPoints, otlpHistogramDataPoint{
+			Attributes:        otlpAttributes(h.attributes),
+			StartTimeUnixNano: unixNano(e.start),
+			TimeUnixNano:      unixNano(now),
+			Count:             fmt.Sprint(h.count),
+			Sum:               h.sum,
+			Min:               h.min,
+			Max:               h.max,
+			BucketCounts:      []string{fmt.Sprint(h.count)},
+			ExplicitBounds:    []float64{},
+		})
+	}
+
+	slices.Sort(names)
+	metrics := make([]otlpMetric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, *byName[name])
+	}
+	return metrics
+}
@@ -0,0 +1,174 @@
+// Package telemetry traces and measures yapper with OpenTelemetry, exporting the spans and metrics as OTLP JSON over
+// HTTP so no SDK is needed. Nothing is recorded until it is enabled, and spans started before then are no-ops.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// scopeName is the instrumentation scope of everything recorded.
+const scopeName = "github.com/AleksaSvitlica/yapper"
+
+const defaultInterval = 10 * time.Second
+
+// Config describes where the spans and metrics are exported to.
+type Config struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver, such as http://localhost:4318, which the spans and metrics
+	// are posted to under /v1/traces and /v1/metrics.
+	Endpoint string
+	// ServiceName is the service.name of the resource, defaulting to yapper.
+	ServiceName string
+	// Interval is how often the recorded spans and metrics are exported, defaulting to 10 seconds.
+	Interval time.Duration
+	// Client sends the requests, defaulting to a client with a 10 second timeout.
+	Client *http.Client
+	// OnError is called with the errors of the periodic exports, which are otherwise dropped.
+	OnError func(err error)
+}
+
+// ConfigFromEnv returns the config described by the standard OpenTelemetry environment variables,
+// OTEL_EXPORTER_OTLP_ENDPOINT and OTEL_SERVICE_NAME, and whether telemetry should be enabled. It is not enabled
+// without an endpoint, or if OTEL_SDK_DISABLED is true.
+func ConfigFromEnv() (Config, bool) {
+	config := Config{Endpoint: os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), ServiceName: os.Getenv("OTEL_SERVICE_NAME")}
+	disabled := strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true")
+	return config, config.Endpoint != "" && !disabled
+}
+
+// exporter buffers the ended spans and aggregates the metrics until they are exported.
+type exporter struct {
+	config Config
+	start  time.Time
+
+	mu         sync.Mutex
+	spans      []*Span
+	counters   map[string]*counter
+	histograms map[string]*histogram
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// current is the enabled exporter, nil when telemetry is disabled.
+var current atomic.Pointer[exporter]
+
+// Enable starts exporting the spans and metrics recorded from now on, returning a function which exports what is
+// left and stops. An error is returned if telemetry is already enabled.
+func Enable(config Config) (func(ctx context.Context) error, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("an endpoint is required")
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = "yapper"
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	e := &exporter{
+		config:     config,
+		start:      time.Now(),
+		counters:   map[string]*counter{},
+		histograms: map[string]*histogram{},
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if !current.CompareAndSwap(nil, e) {
+		return nil, errors.New("telemetry is already enabled")
+	}
+
+	go e.run()
+
+	return func(ctx context.Context) error {
+		current.CompareAndSwap(e, nil)
+		close(e.stop)
+		<-e.done
+		return e.export(ctx)
+	}, nil
+}
+
+// run exports periodically until stopped.
+func (e *exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), e.config.Interval)
+			if err := e.export(ctx); err != nil && e.config.OnError != nil {
+				e.config.OnError(err)
+			}
+			cancel()
+		}
+	}
+}
+
+// export posts the spans ended since the last export and the current values of the metrics.
+func (e *exporter) export(ctx context.Context) error {
+	e.mu.Lock()
+	spans := e.spans
+	e.spans = nil
+	metrics := e.metrics(time.Now())
+	e.mu.Unlock()
+
+	resource := otlpResource{Attributes: otlpAttributes([]Attribute{String("service.name", e.config.ServiceName)})}
+	scope := otlpScope{Name: scopeName}
+
+	var errs []error
+	if len(spans) > 0 {
+		data := make([]otlpSpan, 0, len(spans))
+		for _, span := range spans {
+			data = append(data, span.otlp())
+		}
+		body := otlpTraces{ResourceSpans: []otlpResourceSpans{{Resource: resource, ScopeSpans: []otlpScopeSpans{{Scope: scope, Spans: data}}}}}
+		errs = append(errs, e.post(ctx, "/v1/traces", body))
+	}
+	if len(metrics) > 0 {
+		body := otlpMetrics{ResourceMetrics: []otlpResourceMetrics{{Resource: resource, ScopeMetrics: []otlpScopeMetrics{{Scope: scope, Metrics: metrics}}}}}
+		errs = append(errs, e.post(ctx, "/v1/metrics", body))
+	}
+	return errors.Join(errs...)
+}
+
+func (e *exporter) post(ctx context.Context, path string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", path, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(e.config.Endpoint, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := e.config.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error exporting %s: %w", path, err)
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("error exporting %s: unexpected status %s", path, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// receiver records the bodies posted to each path of an OTLP/HTTP receiver.
+type receiver struct {
+	mu     sync.Mutex
+	bodies map[string][][]byte
+}
+
+func newReceiver(t *testing.T) (*receiver, *httptest.Server) {
+	r := &receiver{bodies: map[string][][]byte{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.bodies[req.URL.Path] = append(r.bodies[req.URL.Path], body)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func enable(t *testing.T, endpoint string) func() {
+	shutdown, err := Enable(Config{Endpoint: endpoint})
+	if err != nil {
+		t.Fatalf("Unexpected error enabling telemetry: %v", err)
+	}
+	return func() {
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("Unexpected error shutting down telemetry: %v", err)
+		}
+	}
+}
+
+func TestSpansAndMetricsAreExported(t *testing.T) {
+	received, server := newReceiver(t)
+	shutdown := enable(t, server.URL)
+
+	ctx, parent := Start(context.Background(), "generate", Int("people", 4))
+	_, child := Start(ctx, "history.write")
+	child.End()
+	parent.End()
+	Add("yapper.notifications.sent", 2, String("provider", "slack"))
+	Add("yapper.notifications.sent", 1, String("provider", "slack"))
+	Record("yapper.generate.duration", 0.5)
+	shutdown()
+
+	if len(received.bodies["/v1/traces"]) != 1 || len(received.bodies["/v1/metrics"]) != 1 {
+		t.Fatalf("Expected one export of each, got %v", received.bodies)
+	}
+
+	var traces otlpTraces
+	if err := json.Unmarshal(received.bodies["/v1/traces"][0], &traces); err != nil {
+		t.Fatalf("Unexpected error decoding traces: %v", err)
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 || spans[0].Name != "history.write" || spans[1].Name != "generate" {
+		t.Fatalf("Expected the child and parent spans, got %v", spans)
+	}
+	if spans[0].TraceID != spans[1].TraceID || spans[0].ParentSpanID != spans[1].SpanID || spans[1].ParentSpanID != "" {
+		t.Errorf("Expected the child span to be in the trace of the parent, got %v", spans)
+	}
+
+	var metrics otlpMetrics
+	if err := json.Unmarshal(received.bodies["/v1/metrics"][0], &metrics); err != nil {
+		t.Fatalf("Unexpected error decoding metrics: %v", err)
+	}
+	exported := metrics.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(exported) != 2 || exported[0].Histogram == nil || exported[0].Histogram.DataPoints[0].Count != "1" {
+		t.Fatalf("Expected the histogram and counter, got %v", exported)
+	}
+	if exported[1].Sum == nil || exported[1].Sum.DataPoints[0].AsInt != "3" {
+		t.Errorf("Expected the counter to be 3, got %v", exported[1])
+	}
+}
+
+func TestSpansAreNoOpsWhenDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "generate")
+	span.SetAttributes(String("strategy", "greedy"))
+	span.End()
+
+	if span != nil || ctx.Value(spanContextKey{}) != nil {
+		t.Errorf("Expected no span, got %v", span)
+	}
+}
+
+func TestHandlerContinuesTraceparent(t *testing.T) {
+	received, server := newReceiver(t)
+	shutdown := enable(t, server.URL)
+
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/api/pairings", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	shutdown()
+
+	var traces otlpTraces
+	if err := json.Unmarshal(received.bodies["/v1/traces"][0], &traces); err != nil {
+		t.Fatalf("Unexpected error decoding traces: %v", err)
+	}
+	span := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || span.ParentSpanID != "00f067aa0ba902b7" || span.Name != "GET /api/pairings" {
+		t.Errorf("Expected the span to continue the trace, got %v", span)
+	}
+}
+
+func TestParseTraceparentRejectsInvalidHeaders(t *testing.T) {
+	headers := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+	}
+	for _, header := range headers {
+		if _, ok := parseTraceparent(header); ok {
+			t.Errorf("Expected %q to be rejected", header)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Attribute is a key and value describing a span or metric. The value is a string, int, int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attribute    { return Attribute{key, value} }
+func Int(key string, value int) Attribute   { return Attribute{key, value} }
+func Bool(key string, value bool) Attribute { return Attribute{key, value} }
+
+// spanKind is the kind of a span in OTLP.
+type spanKind int
+
+const (
+	spanKindInternal spanKind = 1
+	spanKindServer   spanKind = 2
+)
+
+// spanContext identifies a span, which may be a remote parent propagated in a traceparent header.
+type spanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+}
+
+type spanContextKey struct{}
+
+// Span is an operation being traced. A nil span, which is started while telemetry is disabled, records nothing.
+type Span struct {
+	exporter *exporter
+	context  spanContext
+	parentID [8]byte
+	name     string
+	kind     spanKind
+	start    time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attributes []Attribute
+	err        error
+}
+
+// Start starts a span with the name, as a child of the span of the context if it has one, returning the context
+// of the new span. The span must be ended.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	return start(ctx, name, spanKindInternal, attributes)
+}
+
+func start(ctx context.Context, name string, kind spanKind, attributes []Attribute) (context.Context, *Span) {
+	e := current.Load()
+	if e == nil {
+		return ctx, nil
+	}
+
+	span := &Span{exporter: e, name: name, kind: kind, start: time.Now(), attributes: attributes}
+	if parent, found := ctx.Value(spanContextKey{}).(spanContext); found {
+		span.context.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		rand.Read(span.context.traceID[:])
+	}
+	rand.Read(span.context.spanID[:])
+
+	return context.WithValue(ctx, spanContextKey{}, span.context), span
+}
+
+// SetAttributes adds attributes describing the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes = append(s.attributes, attributes...)
+}
+
+// RecordError marks the span as failed with the error, if it is not nil.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// End ends the span, which is exported with the next export. Ending a span again has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	s.exporter.mu.Lock()
+	defer s.exporter.mu.Unlock()
+	s.exporter.spans = append(s.exporter.spans, s)
+}
+
+func (s *Span) otlp() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	span := otlpSpan{
+		TraceID:           hex.EncodeToString(s.context.traceID[:]),
+		SpanID:            hex.EncodeToString(s.context.spanID[:]),
+		Name:              s.name,
+		Kind:              int(s.kind),
+		StartTimeUnixNano: unixNano(s.start),
+		EndTimeUnixNano:   unixNano(s.end),
+		Attributes:        otlpAttributes(s.attributes),
+	}
+	if s.parentID != [8]byte{} {
+		span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	if s.err != nil {
+		span.Status = &otlpStatus{Code: 2, Message: s.err.Error()}
+	}
+	return span
+}
+
+// Handler traces each request to the handler in a server span, continuing the trace of a W3C traceparent header.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if parent, ok := parseTraceparent(r.Header.Get("traceparent")); ok {
+			ctx = context.WithValue(ctx, spanContextKey{}, parent)
+		}
+
+		ctx, span := start(ctx, r.Method+" "+r.URL.Path, spanKindServer, []Attribute{String("http.request.method", r.Method), String("url.path", r.URL.Path)})
+		if span == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(Int("http.response.status_code", recorder.status))
+		if recorder.status >= 500 {
+			span.RecordError(fmt.Errorf("%d %s", recorder.status, http.StatusText(recorder.status)))
+		}
+	})
+}
+
+// statusRecorder records the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// parseTraceparent returns the remote parent of a W3C traceparent header, such as
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func parseTraceparent(header string) (spanContext, bool) {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return spanContext{}, false
+	}
+
+	var parent spanContext
+	if n, err := hex.Decode(parent.traceID[:], []byte(parts[1])); err != nil || n != 16 || len(parts[1]) != 32 {
+		return spanContext{}, false
+	}
+	if n, err := hex.Decode(parent.spanID[:], []byte(parts[2])); err != nil || n != 8 || len(parts[2]) != 16 {
+		return spanContext{}, false
+	}
+	if parent.traceID == [16]byte{} || parent.spanID == [8]byte{} {
+		return spanContext{}, false
+	}
+	return parent, true
+}
@@ -0,0 +1,67 @@
+package yapper
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// TieBreak shuffles the people a person last met on the same date, who otherwise rank in the order of their IDs. Such
+// ties are common after importing a history with a single backfill date, and a fixed order would pair the same people
+// again and again.
+type TieBreak struct {
+	// Seed varies the shuffles, which are otherwise the same for a person in a round so that runs are repeatable. It
+	// also varies the order people are matched in by the greedy strategy.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// seededRandom returns a source of random numbers which is the same for each run with the seed of the tie break of the
+// config, or no seed without one, and the same keys.
+func seededRandom(conf Config, keys ...string) *rand.Rand {
+	var seed int64
+	if conf.TieBreak != nil {
+		seed = conf.TieBreak.Seed
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(strconv.FormatInt(seed, 10)))
+	for _, key := range keys {
+		hash.Write([]byte{0})
+		hash.Write([]byte(key))
+	}
+	return rand.New(rand.NewPCG(hash.Sum64(), 0))
+}
+
+// shufflePeople shuffles the IDs into the order the people are matched in the round starting on date, before they are
+// sorted by priority. The order changes from round to round, so a fixed order does not keep pairing the same people,
+// but is the same for the round each time so that runs are repeatable.
+func shufflePeople(conf Config, ids []ID, date time.Time) []ID {
+	random := seededRandom(conf, date.Format(time.DateOnly))
+	random.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	return ids
+}
+
+// breakTies shuffles each run of people in the meetings, ordered by when the person last met them, who were last met
+// on the same date. The meetings are left as they are without the tie break of the config.
+func breakTies(conf Config, hist history.History, id ID, meetings []history.ID, date time.Time) []history.ID {
+	if conf.TieBreak == nil || len(meetings) < 2 {
+		return meetings
+	}
+
+	random := seededRandom(conf, string(id), date.Format(time.DateOnly))
+
+	lastMeetings := hist.GetPersonToLastMeetingMap(history.ID(id))
+	for start := 0; start < len(meetings); {
+		end := start + 1
+		for end < len(meetings) && lastMeetings[meetings[end]].Equal(lastMeetings[meetings[start]]) {
+			end++
+		}
+		tied := meetings[start:end]
+		random.Shuffle(len(tied), func(i, j int) { tied[i], tied[j] = tied[j], tied[i] })
+		start = end
+	}
+	return meetings
+}
@@ -0,0 +1,107 @@
+package yapper
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+func TestBreakTiesKeepsOrderWithoutTieBreak(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	for _, other := range []history.ID{"A", "B", "C", "D"} {
+		hist.AddMeeting("Mario", other, date.AddDate(0, 0, -7))
+	}
+
+	expected := []history.ID{"A", "B", "C", "D"}
+	got := breakTies(Config{}, hist, "Mario", history.GetPeopleMetSortedByLastMeeting(hist, "Mario"), date)
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, got)
+	}
+}
+
+func TestBreakTiesOnlyShufflesPeopleMetOnTheSameDate(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Oldest", date.AddDate(0, 0, -28))
+	for _, other := range []history.ID{"A", "B", "C", "D", "E", "F"} {
+		hist.AddMeeting("Mario", other, date.AddDate(0, 0, -14))
+	}
+	hist.AddMeeting("Mario", "Newest", date.AddDate(0, 0, -7))
+	config := Config{TieBreak: &TieBreak{}}
+
+	varies := false
+	for week := 0; week < 10; week++ {
+		round := date.AddDate(0, 0, 7*week)
+		got := breakTies(config, hist, "Mario", history.GetPeopleMetSortedByLastMeeting(hist, "Mario"), round)
+		if got[0] != "Oldest" || got[len(got)-1] != "Newest" {
+			t.Fatalf("Expected the people met on other dates to keep their order, got %v", got)
+		}
+		tied := slices.Clone(got[1 : len(got)-1])
+		varies = varies || !slices.IsSorted(tied)
+		slices.Sort(tied)
+		if !reflect.DeepEqual([]history.ID{"A", "B", "C", "D", "E", "F"}, tied) {
+			t.Errorf("Expected the tied people to be shuffled, got %v", got)
+		}
+	}
+	if !varies {
+		t.Errorf("Expected the tied people to be shuffled in some round")
+	}
+}
+
+func TestBreakTiesIsStableForASeed(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	for _, other := range []history.ID{"A", "B", "C", "D", "E", "F", "G", "H"} {
+		hist.AddMeeting("Mario", other, date.AddDate(0, 0, -7))
+	}
+	order := func(seed int64) []history.ID {
+		config := Config{TieBreak: &TieBreak{Seed: seed}}
+		return breakTies(config, hist, "Mario", history.GetPeopleMetSortedByLastMeeting(hist, "Mario"), date)
+	}
+
+	if first, second := order(1), order(1); !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same order for the same seed, got %v and %v", first, second)
+	}
+
+	varies := false
+	for seed := int64(2); seed <= 10 && !varies; seed++ {
+		varies = !reflect.DeepEqual(order(1), order(seed))
+	}
+	if !varies {
+		t.Errorf("Expected the order to vary between seeds")
+	}
+}
+
+func TestPairPeopleIsRepeatableForASeed(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{TieBreak: &TieBreak{Seed: 1}}
+	for _, id := range []ID{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L"} {
+		config.People = append(config.People, Person{ID: id})
+	}
+	hist := history.History{}
+	for i, person := range config.People {
+		for _, other := range config.People[i+1:] {
+			hist.AddMeeting(history.ID(person.ID), history.ID(other.ID), date.AddDate(0, 0, -7))
+		}
+	}
+
+	expected := pairPeople(config, computeValidPairings(config), hist, date)
+	for range 20 {
+		if got := pairPeople(config, computeValidPairings(config), hist, date); !reflect.DeepEqual(expected.data, got.data) {
+			t.Fatalf("Expected:\n%v\nGot:\n%v", expected.data, got.data)
+		}
+	}
+
+	changed := false
+	for seed := int64(2); seed < 10 && !changed; seed++ {
+		config.TieBreak = &TieBreak{Seed: seed}
+		changed = !reflect.DeepEqual(expected.data, pairPeople(config, computeValidPairings(config), hist, date).data)
+	}
+	if !changed {
+		t.Error("Expected a different seed to change the pairings")
+	}
+}
@@ -0,0 +1,91 @@
+package yapper
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+// Curriculum is a series of conversation topics, one for each round of the program.
+type Curriculum struct {
+	// Start is the date in the first round of the curriculum, which discusses the first topic.
+	Start Date `json:"start"`
+	// Topics are discussed in order, starting again from the first after the last.
+	Topics []string `json:"topics"`
+}
+
+func (c Curriculum) validate() error {
+	if len(c.Topics) == 0 {
+		return errors.New("curriculum has no topics")
+	}
+
+	if slices.Contains(c.Topics, "") {
+		return errors.New("curriculum has an empty topic")
+	}
+
+	return nil
+}
+
+// roundIndex returns the number of the round starting on date within the curriculum, if it has started.
+func (c Config) roundIndex(date time.Time) (int, bool) {
+	start := NewDate(c.RoundStart(c.Curriculum.Start.Time))
+	days := int(NewDate(date).Sub(start.Time).Hours() / 24)
+	if days < 0 {
+		return 0, false
+	}
+	return days / c.RoundInterval(), true
+}
+
+// RoundTopic returns the topic of the curriculum for the round starting on date,
+// or an empty string if there is no curriculum or it has not started.
+func (c Config) RoundTopic(date time.Time) string {
+	if c.Curriculum == nil {
+		return ""
+	}
+
+	index, started := c.roundIndex(date)
+	if !started {
+		return ""
+	}
+	return c.Curriculum.Topics[index%len(c.Curriculum.Topics)]
+}
+
+// Topic returns the topic for the pair to discuss in the round starting on date. Pairs who already discussed the
+// topic of the round advance to the next topic they have not discussed, starting again once they have discussed all.
+func (c Config) Topic(hist history.History, id1, id2 ID, date time.Time) string {
+	roundTopic := c.RoundTopic(date)
+	if roundTopic == "" {
+		return ""
+	}
+
+	discussed := hist.Topics(history.ID(id1), history.ID(id2))
+	topics := c.Curriculum.Topics
+	start := slices.Index(topics, roundTopic)
+	for i := range topics {
+		if topic := topics[(start+i)%len(topics)]; !slices.Contains(discussed, topic) {
+			return topic
+		}
+	}
+	return roundTopic
+}
+
+// Topic returns the topic of the curriculum the pair is to discuss, as chosen when the pairings were recorded.
+func (p *Pairings) Topic(id1, id2 ID) string {
+	return p.topics[pairKey(id1, id2)]
+}
+
+// assignTopic chooses the topic of the pair from the history and records it in the history, if there is a curriculum.
+func (p *Pairings) assignTopic(conf Config, hist *history.History, id1, id2 ID) {
+	topic := conf.Topic(*hist, id1, id2, p.date)
+	if topic == "" {
+		return
+	}
+
+	if p.topics == nil {
+		p.topics = make(map[[2]ID]string)
+	}
+	p.topics[pairKey(id1, id2)] = topic
+	hist.AddTopic(history.ID(id1), history.ID(id2), topic)
+}
@@ -0,0 +1,71 @@
+package yapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AleksaSvitlica/yapper/history"
+)
+
+var curriculumConfig = Config{
+	People:     []Person{{ID: "Mario"}, {ID: "Luigi"}},
+	Curriculum: &Curriculum{Start: date(2025, time.August, 6), Topics: []string{"Career goals", "Feedback", "Hobbies"}},
+}
+
+func TestRoundTopicFollowsCurriculum(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected string
+	}{
+		{time.Date(2025, time.July, 28, 0, 0, 0, 0, time.UTC), ""},
+		{time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), "Career goals"},
+		{time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC), "Feedback"},
+		{time.Date(2025, time.August, 25, 0, 0, 0, 0, time.UTC), "Career goals"},
+	}
+
+	for _, test := range tests {
+		if topic := curriculumConfig.RoundTopic(test.date); topic != test.expected {
+			t.Errorf("Expected the topic of %v to be %q, got %q", test.date, test.expected, topic)
+		}
+	}
+}
+
+func TestTopicAdvancesForRepeatPairs(t *testing.T) {
+	hist := history.History{}
+	hist.AddTopic("Mario", "Luigi", "Feedback")
+	hist.AddTopic("Mario", "Luigi", "Hobbies")
+
+	if topic := curriculumConfig.Topic(hist, "Luigi", "Mario", time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC)); topic != "Career goals" {
+		t.Errorf("Expected the pair to advance to Career goals, got %q", topic)
+	}
+
+	hist.AddTopic("Mario", "Luigi", "Career goals")
+	if topic := curriculumConfig.Topic(hist, "Luigi", "Mario", time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC)); topic != "Feedback" {
+		t.Errorf("Expected the pair to start again from the topic of the round, got %q", topic)
+	}
+}
+
+func TestGeneratedPairingsAreAssignedTopics(t *testing.T) {
+	hist := history.History{}
+	rounds, err := generateForDates(curriculumConfig, &hist, []time.Time{
+		time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if topic := rounds[0].Topic("Mario", "Luigi"); topic != "Career goals" {
+		t.Errorf("Expected Career goals in the first round, got %q", topic)
+	}
+	if topic := rounds[1].Topic("Mario", "Luigi"); topic != "Feedback" {
+		t.Errorf("Expected the pair to advance to Feedback when meeting again in the same round, got %q", topic)
+	}
+}
+
+func TestCurriculumRequiresTopics(t *testing.T) {
+	config := Config{Curriculum: &Curriculum{Start: date(2025, time.August, 4)}}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected an error for a curriculum without topics")
+	}
+}
@@ -0,0 +1,65 @@
+package yapper
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// validPairingsCacheSize bounds the number of configs whose valid pairings are cached. The cache is emptied when it is
+// full, which only happens to servers used for many programs or configs changing often.
+const validPairingsCacheSize = 16
+
+// defaultValidPairingsCache is shared by everything generating or checking pairings in the process.
+var defaultValidPairingsCache = newValidPairingsCache(validPairingsCacheSize)
+
+// validPairingsCache remembers the valid pairings of configs by a hash of their content, sparing long running servers,
+// which reload the config for every request, from checking every pair of people each time. A reloaded config with
+// different content has a different hash, so the valid pairings of the old content are never used for it.
+type validPairingsCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[[sha256.Size]byte]map[ID][]ID
+}
+
+func newValidPairingsCache(size int) *validPairingsCache {
+	return &validPairingsCache{size: size, entries: map[[sha256.Size]byte]map[ID][]ID{}}
+}
+
+// get returns a copy of the valid pairings of the config, computing them if the config has not been seen.
+func (c *validPairingsCache) get(config Config) map[ID][]ID {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return computeValidPairings(config)
+	}
+	key := sha256.Sum256(data)
+
+	c.mu.Lock()
+	pairings, found := c.entries[key]
+	c.mu.Unlock()
+	if found {
+		return cloneValidPairings(pairings)
+	}
+
+	pairings = computeValidPairings(config)
+
+	c.mu.Lock()
+	if len(c.entries) >= c.size {
+		clear(c.entries)
+	}
+	c.entries[key] = pairings
+	c.mu.Unlock()
+
+	return cloneValidPairings(pairings)
+}
+
+// cloneValidPairings copies the valid pairings, so callers removing pairs from them do not change the cache.
+func cloneValidPairings(pairings map[ID][]ID) map[ID][]ID {
+	cloned := maps.Clone(pairings)
+	for id, validPairings := range cloned {
+		cloned[id] = slices.Clone(validPairings)
+	}
+	return cloned
+}
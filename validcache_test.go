@@ -0,0 +1,67 @@
+package yapper
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestValidPairingsCacheReturnsCopies(t *testing.T) {
+	cache := newValidPairingsCache(validPairingsCacheSize)
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}}}
+
+	pairings := cache.get(config)
+	pairings["Mario"] = pairings["Mario"][:1]
+	delete(pairings, "Luigi")
+
+	if cached := cache.get(config); !reflect.DeepEqual(computeValidPairings(config), cached) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", computeValidPairings(config), cached)
+	}
+}
+
+func TestValidPairingsCacheRecomputesChangedConfig(t *testing.T) {
+	cache := newValidPairingsCache(validPairingsCacheSize)
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}, {ID: "Peach"}}}
+	cache.get(config)
+
+	config.People = []Person{{ID: "Mario", DenyList: []ID{"Luigi"}}, {ID: "Luigi"}, {ID: "Peach"}}
+	expected := map[ID][]ID{
+		"Mario": {"Peach"},
+		"Luigi": {"Peach"},
+		"Peach": {"Mario", "Luigi"},
+	}
+	diffPairings(t, cache.get(config), expected)
+}
+
+func TestValidPairingsCacheIsBounded(t *testing.T) {
+	cache := newValidPairingsCache(2)
+	for i := range 5 {
+		cache.get(Config{People: []Person{{ID: ID(fmt.Sprint(i))}, {ID: "Mario"}}})
+	}
+
+	if len(cache.entries) > 2 {
+		t.Errorf("Expected at most 2 cached configs, got %d", len(cache.entries))
+	}
+}
+
+func BenchmarkValidPairings(b *testing.B) {
+	config := Config{Rules: []Rule{{Deny: `person.squad == other.squad`}}}
+	for i := range 500 {
+		config.People = append(config.People, Person{ID: ID(fmt.Sprintf("person-%d", i)), Squad: fmt.Sprintf("squad-%d", i%20)})
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		for range b.N {
+			computeValidPairings(config)
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		cache := newValidPairingsCache(validPairingsCacheSize)
+		cache.get(config)
+		b.ResetTimer()
+		for range b.N {
+			cache.get(config)
+		}
+	})
+}
@@ -0,0 +1,287 @@
+package yapper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ViolationKind is the constraint broken by a pairing.
+type ViolationKind string
+
+const (
+	// ViolationUnknownPerson is a pairing of someone who is not in the config.
+	ViolationUnknownPerson ViolationKind = "unknown-person"
+	// ViolationPairedWithSelf is a pairing of someone with themselves.
+	ViolationPairedWithSelf ViolationKind = "paired-with-self"
+	// ViolationPairedTwice is someone paired more than once in a round.
+	ViolationPairedTwice ViolationKind = "paired-twice"
+	// ViolationDenyList is a pairing of people where one is on the deny list of the other.
+	ViolationDenyList ViolationKind = "deny-list"
+	// ViolationSameSquad is a pairing of people of the same squad when the squad policy is deny.
+	ViolationSameSquad ViolationKind = "same-squad"
+	// ViolationTagRule is a pairing of people sharing a tag matching a deny-shared tag rule.
+	ViolationTagRule ViolationKind = "tag-rule"
+	// ViolationRule is a pairing denied by a rule expression.
+	ViolationRule ViolationKind = "rule"
+	// ViolationSkipLevel is a pairing other than a manager with someone outside their chain under the skip-level preset.
+	ViolationSkipLevel ViolationKind = "skip-level"
+	// ViolationGuest is a pairing of a guest with someone who does not allow external chats, or with another guest.
+	ViolationGuest ViolationKind = "guest"
+	// ViolationPaused is a pairing of someone who is paused.
+	ViolationPaused ViolationKind = "paused"
+	// ViolationAbsent is a pairing of someone who is absent in the round.
+	ViolationAbsent ViolationKind = "absent"
+	// ViolationHoliday is a pairing of someone whose round is mostly public holidays when the holiday policy is skip.
+	ViolationHoliday ViolationKind = "holiday"
+	// ViolationCadence is a pairing of someone on a two week cadence in a round they do not meet.
+	ViolationCadence ViolationKind = "cadence"
+	// ViolationOnboarding is a pairing of someone still onboarding with someone outside of their cohort.
+	ViolationOnboarding ViolationKind = "onboarding"
+	// ViolationPin is a pin applying to the round whose people are not paired together.
+	ViolationPin ViolationKind = "pin"
+	// ViolationSquadLimit is a pairing beyond the squad limit of the squads of its people.
+	ViolationSquadLimit ViolationKind = "squad-limit"
+	// ViolationQuorum is a pairing in a round skipped as fewer people than the quorum can meet in it.
+	ViolationQuorum ViolationKind = "quorum"
+)
+
+// Violation is a constraint broken by a set of pairings, naming the people involved.
+type Violation struct {
+	Kind    ViolationKind `json:"kind"`
+	People  []ID          `json:"people"`
+	Message string        `json:"message"`
+}
+
+// ViolationsError is returned for pairings which violate constraints of the config, listing each violation.
+type ViolationsError struct {
+	Violations []Violation
+}
+
+func (e ViolationsError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return fmt.Sprintf("the pairings violate the config: %s", strings.Join(messages, ", "))
+}
+
+func newViolation(kind ViolationKind, people ...ID) Violation {
+	var message string
+	switch kind {
+	case ViolationUnknownPerson:
+		message = fmt.Sprintf("no person with ID %s", people[0])
+	case ViolationPairedWithSelf:
+		message = fmt.Sprintf("%s is paired with themselves", people[0])
+	case ViolationPairedTwice:
+		message = fmt.Sprintf("%s is paired more than once", people[0])
+	case ViolationDenyList:
+		message = fmt.Sprintf("%s and %s are denied by a deny list", people[0], people[1])
+	case ViolationSameSquad:
+		message = fmt.Sprintf("%s and %s are in the same squad", people[0], people[1])
+	case ViolationTagRule:
+		message = fmt.Sprintf("%s and %s share a tag denied by a tag rule", people[0], people[1])
+	case ViolationRule:
+		message = fmt.Sprintf("%s and %s are denied by a rule", people[0], people[1])
+	case ViolationSkipLevel:
+		message = fmt.Sprintf("%s and %s are not a manager and someone outside their chain", people[0], people[1])
+	case ViolationGuest:
+		message = fmt.Sprintf("%s and %s are a guest and someone who does not allow external chats", people[0], people[1])
+	case ViolationPaused:
+		message = fmt.Sprintf("%s is paused", people[0])
+	case ViolationAbsent:
+		message = fmt.Sprintf("%s is absent", people[0])
+	case ViolationHoliday:
+		message = fmt.Sprintf("%s is on holiday for most of the round", people[0])
+	case ViolationCadence:
+		message = fmt.Sprintf("%s does not meet this round on a two week cadence", people[0])
+	case ViolationOnboarding:
+		message = fmt.Sprintf("%s and %s are not in the same onboarding cohort", people[0], people[1])
+	case ViolationPin:
+		message = fmt.Sprintf("%s and %s are pinned but not paired", people[0], people[1])
+	case ViolationSquadLimit:
+		message = fmt.Sprintf("%s and %s are paired beyond the squad limit of their squads", people[0], people[1])
+	case ViolationQuorum:
+		message = fmt.Sprintf("%s does not meet this round as too few people can meet", people[0])
+	}
+	return Violation{Kind: kind, People: people, Message: message}
+}
+
+// ValidatePairings checks the pairings, whether generated or edited by hand, against the hard constraints of the config.
+// The cadences, absences, holidays and pins of the round are only checked when the pairings have a date.
+// An empty slice is returned if no constraint is violated.
+func ValidatePairings(config Config, pairings Pairings) []Violation {
+	violations := []Violation{}
+	hasDate := !pairings.date.IsZero()
+	paired := map[ID]bool{}
+	check := newPairCheck(config)
+	index := check.index
+	cohorts := config.onboardingCohorts(pairings.date)
+	squadCounts := newSquadCounts(config)
+	var quorum roundQuorum
+	if hasDate {
+		quorum = config.quorumOf(pairings.date)
+	}
+
+	for id1, id2 := range pairings.All() {
+		if id1 == id2 {
+			violations = append(violations, newViolation(ViolationPairedWithSelf, id1))
+			continue
+		}
+
+		var people []Person
+		for _, id := range []ID{id1, id2} {
+			if paired[id] {
+				violations = append(violations, newViolation(ViolationPairedTwice, id))
+			}
+			paired[id] = true
+
+			person, found := index[id]
+			if !found {
+				violations = append(violations, newViolation(ViolationUnknownPerson, id))
+				continue
+			}
+			people = append(people, person)
+
+			if !hasDate {
+				continue
+			}
+			if kind := config.ineligibility(person, pairings.date, quorum); kind != "" {
+				violations = append(violations, newViolation(kind, id))
+			}
+		}
+
+		if len(people) != 2 {
+			continue
+		}
+		if kind := check.denial(people[0], people[1]); kind != "" {
+			violations = append(violations, newViolation(kind, id1, id2))
+		}
+		if hasDate && onboardingDenied(cohorts, id1, id2) {
+			violations = append(violations, newViolation(ViolationOnboarding, id1, id2))
+		}
+		if !squadCounts.allows(id1, id2) {
+			violations = append(violations, newViolation(ViolationSquadLimit, id1, id2))
+		}
+		squadCounts.add(id1, id2)
+	}
+
+	if !hasDate {
+		return violations
+	}
+
+	idToValidPairings := restrictToCohorts(cohorts, determineValidPairings(config))
+	for _, pin := range pinnedPairings(config, idToValidPairings, getIneligiblePeople(config, idToValidPairings, pairings.date), pairings.date) {
+		if !pairings.Contains(pin[0], pin[1]) {
+			violations = append(violations, newViolation(ViolationPin, pin[0], pin[1]))
+		}
+	}
+
+	return violations
+}
+
+// Denial returns the hard constraint which prevents pairing the people with the IDs, or an empty kind if they can be
+// paired.
+func (c Config) Denial(id1, id2 ID) ViolationKind {
+	person1, err1 := c.GetPerson(id1)
+	person2, err2 := c.GetPerson(id2)
+	if err1 != nil || err2 != nil {
+		return ViolationUnknownPerson
+	}
+	return newPairCheck(c).denial(person1, person2)
+}
+
+// pairCheck checks pairs of people against the hard constraints of a config, with the people indexed and the rules
+// compiled once for all of the pairs checked.
+type pairCheck struct {
+	config Config
+	index  map[ID]Person
+	rules  []condition
+}
+
+// newPairCheck returns the check of the config. Invalid rules are rejected by Config.Validate, so they are only
+// logged and left out here.
+func newPairCheck(config Config) pairCheck {
+	rules, err := compileRules(config.Rules)
+	if err != nil {
+		Logger().Warn("leaving out invalid rules", "error", err)
+	}
+	return pairCheck{config: config, index: config.Index(), rules: rules}
+}
+
+// denial returns the hard constraint which prevents pairing the two people, or an empty kind if they can be paired.
+func (check pairCheck) denial(person, other Person) ViolationKind {
+	config := check.config
+	switch {
+	case person.ID == other.ID:
+		return ViolationPairedWithSelf
+	case config.denies(person, other) || config.denies(other, person):
+		return ViolationDenyList
+	case config.squadPolicy() == SquadPolicyDeny && person.Squad != "" && person.Squad == other.Squad:
+		return ViolationSameSquad
+	case sharesTag(config, TagRuleDenyShared, person, other):
+		return ViolationTagRule
+	case deniedByRules(check.rules, config.withCadence(person), config.withCadence(other)):
+		return ViolationRule
+	case skipLevelDenied(config, check.index, person, other):
+		return ViolationSkipLevel
+	case guestDenied(person, other):
+		return ViolationGuest
+	default:
+		return ""
+	}
+}
+
+// guestDenied reports whether the people cannot be paired as one is a guest, which is only allowed when the other is not
+// a guest and allows external chats.
+func guestDenied(person, other Person) bool {
+	switch {
+	case person.Guest && other.Guest:
+		return true
+	case person.Guest:
+		return !other.AllowExternal
+	case other.Guest:
+		return !person.AllowExternal
+	default:
+		return false
+	}
+}
+
+// ineligibility returns why the person cannot meet in the round starting on date, given the quorum of the round, or an
+// empty kind if they can.
+func (c Config) ineligibility(person Person, date time.Time, quorum roundQuorum) ViolationKind {
+	if kind := c.excusedUnavailability(person, date, quorum.owed); kind != "" {
+		return kind
+	}
+	if quorum.skips {
+		return ViolationQuorum
+	}
+	return ""
+}
+
+// unavailability returns the reason the person cannot meet in the round starting on date on their own account, such as
+// being paused, leaving out the quorum of the round.
+func (c Config) unavailability(person Person, date time.Time) ViolationKind {
+	switch {
+	case person.Paused:
+		return ViolationPaused
+	case c.absent(person.ID, date):
+		return ViolationAbsent
+	case c.holidayPolicy() == HolidayPolicySkip && c.onHoliday(person, date):
+		return ViolationHoliday
+	}
+
+	switch cadence := c.cadence(person); cadence {
+	case CadenceOneWeek, "":
+		return ""
+	case CadenceTwoWeeks:
+		if !isValidWeekForTwoWeekCadence(date, c.RoundInterval()) {
+			return ViolationCadence
+		}
+		return ""
+	default:
+		// Config.Validate rejects unexpected cadences, so someone with one is only skipped here.
+		Logger().Warn("skipping person with unexpected cadence", "person", person.ID, "cadence", cadence)
+		return ViolationCadence
+	}
+}
@@ -0,0 +1,130 @@
+package yapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidatePairings(t *testing.T) {
+	config := Config{
+		People: []Person{
+			{ID: "Mario", DenyList: []ID{"Bowser"}},
+			{ID: "Luigi", Squad: "plumbers"},
+			{ID: "Peach", Squad: "plumbers"},
+			{ID: "Toad", Paused: true},
+			{ID: "Yoshi"},
+			{ID: "Bowser"},
+			{ID: "Daisy", Attributes: map[string]string{"level": "intern"}},
+			{ID: "Wario", Attributes: map[string]string{"level": "vp"}},
+		},
+		Rules: []Rule{{Deny: `person.level == "intern" && other.level == "vp"`}},
+		Pins:  []Pin{{People: [2]ID{"Yoshi", "Luigi"}}},
+	}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	pairings := NewPairings(date, [][2]ID{
+		{"Mario", "Bowser"}, {"Luigi", "Peach"}, {"Toad", "Yoshi"}, {"Wario", "Daisy"}, {"Yoshi", "Koopa"}, {"Mario", "Mario"},
+	})
+
+	expected := []Violation{
+		newViolation(ViolationDenyList, "Mario", "Bowser"),
+		newViolation(ViolationSameSquad, "Luigi", "Peach"),
+		newViolation(ViolationPaused, "Toad"),
+		newViolation(ViolationRule, "Wario", "Daisy"),
+		newViolation(ViolationPairedTwice, "Yoshi"),
+		newViolation(ViolationUnknownPerson, "Koopa"),
+		newViolation(ViolationPairedWithSelf, "Mario"),
+		newViolation(ViolationPin, "Yoshi", "Luigi"),
+	}
+	if violations := ValidatePairings(config, pairings); !reflect.DeepEqual(expected, violations) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, violations)
+	}
+}
+
+func TestValidatePairingsWithoutDateSkipsRoundConstraints(t *testing.T) {
+	config := Config{
+		People: []Person{{ID: "Mario", Paused: true}, {ID: "Luigi"}, {ID: "Peach"}, {ID: "Toad"}},
+		Pins:   []Pin{{People: [2]ID{"Mario", "Peach"}}},
+	}
+
+	pairings := Pairings{data: [][2]ID{{"Mario", "Luigi"}, {"Peach", "Toad"}}}
+	if violations := ValidatePairings(config, pairings); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %v", violations)
+	}
+}
+
+func TestValidatePairingsAcceptsGeneratedPairings(t *testing.T) {
+	config, err := NewConfigFromFile("testdata/validConfig.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	first := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	rounds, err := Replay(config, StrategyGreedy, []time.Time{first, first.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, pairings := range rounds {
+		if violations := ValidatePairings(config, pairings); len(violations) != 0 {
+			t.Errorf("Expected no violations in the round starting %v, got %v", pairings.Date(), violations)
+		}
+	}
+}
+
+func TestConfigDenialReturnsTheConstraintDenyingAPair(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario", Squad: "bros", DenyList: []ID{"Wario"}},
+		{ID: "Luigi", Squad: "bros"},
+		{ID: "Wario"},
+	}}
+
+	tests := []struct {
+		id1, id2 ID
+		expected ViolationKind
+	}{
+		{"Mario", "Luigi", ViolationSameSquad},
+		{"Wario", "Mario", ViolationDenyList},
+		{"Luigi", "Wario", ""},
+		{"Luigi", "Bowser", ViolationUnknownPerson},
+	}
+	for _, test := range tests {
+		if kind := config.Denial(test.id1, test.id2); kind != test.expected {
+			t.Errorf("Expected %s and %s to be denied by %q, got %q", test.id1, test.id2, test.expected, kind)
+		}
+	}
+}
+
+func TestConfigDenialOnlyPairsGuestsWithPeopleAllowingExternalChats(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario", AllowExternal: true},
+		{ID: "Luigi"},
+		{ID: "Wario", Guest: true},
+		{ID: "Waluigi", Guest: true, AllowExternal: true},
+	}}
+
+	tests := []struct {
+		id1, id2 ID
+		expected ViolationKind
+	}{
+		{"Wario", "Mario", ""},
+		{"Mario", "Waluigi", ""},
+		{"Luigi", "Wario", ViolationGuest},
+		{"Wario", "Waluigi", ViolationGuest},
+		{"Mario", "Luigi", ""},
+	}
+	for _, test := range tests {
+		if kind := config.Denial(test.id1, test.id2); kind != test.expected {
+			t.Errorf("Expected %s and %s to be denied by %q, got %q", test.id1, test.id2, test.expected, kind)
+		}
+	}
+}
+
+func TestUnavailabilitySkipsPeopleWithUnexpectedCadences(t *testing.T) {
+	config := Config{}
+	person := Person{ID: "Mario", Cadence: "daily"}
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	if got := config.unavailability(person, date); got != ViolationCadence {
+		t.Errorf("Expected:\n%v\nGot:\n%v", ViolationCadence, got)
+	}
+}
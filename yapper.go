@@ -1,16 +1,19 @@
 package yapper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
-	"log"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/AleksaSvitlica/yapper/history"
+	"github.com/AleksaSvitlica/yapper/telemetry"
 )
 
 type Cadence string
@@ -22,8 +25,128 @@ const (
 
 type ID string
 
+// Channel is how a person would like to be notified of their pairings, named after the provider.
+type Channel string
+
+const (
+	ChannelSlack Channel = "slack"
+	ChannelEmail Channel = "email"
+	// ChannelNone people are not notified of their pairings.
+	ChannelNone Channel = "none"
+)
+
+// Day is an abbreviated weekday used for scheduling preferences.
+type Day string
+
+const (
+	DayMonday    Day = "mon"
+	DayTuesday   Day = "tue"
+	DayWednesday Day = "wed"
+	DayThursday  Day = "thu"
+	DayFriday    Day = "fri"
+	DaySaturday  Day = "sat"
+	DaySunday    Day = "sun"
+)
+
+// weekDays lists the valid days in the order they occur in a week.
+var weekDays = []Day{DayMonday, DayTuesday, DayWednesday, DayThursday, DayFriday, DaySaturday, DaySunday}
+
+// Weekday converts the day to a time.Weekday.
+func (d Day) Weekday() time.Weekday {
+	return time.Weekday((slices.Index(weekDays, d) + 1) % 7)
+}
+
+// defaultInterval is the number of days between rounds of pairings when no interval is configured.
+const defaultInterval = 7
+
 type Config struct {
-	People []Person `json:"people"`
+	// Version of the config format, see ConfigSchemaVersion.
+	Version int      `json:"version"`
+	People  []Person `json:"people"`
+	// Settings are the program wide options, such as the interval between rounds and the default cadence.
+	Settings Settings `json:"settings"`
+	// WeekStart is the day of the week rounds start on, defaulting to Monday.
+	WeekStart Day `json:"weekStart,omitempty"`
+	// Timezone is the IANA name of the timezone used for week and day boundaries, defaulting to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Campaigns temporarily prefer pairing people from specific cohorts.
+	Campaigns []Campaign `json:"campaigns,omitempty"`
+	// TagRules constrain or prefer pairings based on the tags people share.
+	TagRules []TagRule `json:"tagRules,omitempty"`
+	// SquadLimits cap the pairs between two squads in each round.
+	SquadLimits []SquadLimit `json:"squadLimits,omitempty"`
+	// Rules are expressions denying pairings for policies not covered by the other constraints.
+	Rules []Rule `json:"rules,omitempty"`
+	// SoftConstraints are the weights of the penalties for undesirable pairings.
+	SoftConstraints SoftConstraints `json:"softConstraints"`
+	// Icebreakers are conversation starters, one of which is suggested for each pairing.
+	Icebreakers []string `json:"icebreakers,omitempty"`
+	// Pins force pairs of people to be paired.
+	Pins []Pin `json:"pins,omitempty"`
+	// Absences are periods people are away, who are not paired in the rounds overlapping them.
+	Absences []Absence `json:"absences,omitempty"`
+	// DenyGroups are named lists of people, which people can refer to instead of repeating them in their deny lists.
+	DenyGroups map[string][]ID `json:"denyGroups,omitempty"`
+	// Holidays are the public holidays of each location, matching the location of people.
+	Holidays map[string][]Date `json:"holidays,omitempty"`
+	// HolidayPolicy decides whether people whose round is mostly holidays are skipped, or only paired last.
+	HolidayPolicy HolidayPolicy `json:"holidayPolicy,omitempty"`
+	// Quorum skips, or warns about, rounds in which too few people can meet.
+	Quorum *Quorum `json:"quorum,omitempty"`
+	// StrangerOrder decides who is paired first among people who have never met, defaulting to config order.
+	StrangerOrder StrangerOrder `json:"strangerOrder,omitempty"`
+	// TieBreak shuffles the people last met on the same date, who are otherwise considered in the order of their IDs.
+	TieBreak *TieBreak `json:"tieBreak,omitempty"`
+	// Curriculum is a series of conversation topics for the rounds, which pairs work through in order.
+	Curriculum *Curriculum `json:"curriculum,omitempty"`
+	// Onboarding pairs new starters within the cohort of people who started in the same month for their first weeks.
+	Onboarding *Onboarding `json:"onboarding,omitempty"`
+	// Preset adds the constraints of a built-in kind of program, such as skip-level meetings.
+	Preset Preset `json:"preset,omitempty"`
+	// Organizers are sent a digest summarizing each notify run.
+	Organizers []Organizer `json:"organizers,omitempty"`
+	// Escalation alerts the organizers in the digest when someone is unpaired in several rounds in a row.
+	Escalation *Escalation `json:"escalation,omitempty"`
+	// Namespace is the namespace of the history the meetings of the program are kept in, so that several programs can
+	// share a history file without affecting each other's pairings. The meetings outside of any namespace are used
+	// by default.
+	Namespace string `json:"namespace,omitempty"`
+	// Environment lists the environment variables which string values can refer to as ${NAME}, expanded when the
+	// config is read so the same config can be used in each deployment. $$ is written for a literal dollar sign.
+	Environment []string `json:"environment,omitempty"`
+
+	// interpolated is whether any environment variables were expanded, in which case the config is not written back.
+	interpolated bool
+}
+
+// Location returns the timezone used for week and day boundaries.
+func (c Config) Location() (*time.Location, error) {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %s: %w", c.Timezone, err)
+	}
+	return location, nil
+}
+
+// RoundInterval returns the number of days between rounds of pairings.
+func (c Config) RoundInterval() int {
+	if c.Settings.Interval == 0 {
+		return defaultInterval
+	}
+	return c.Settings.Interval
+}
+
+// RoundStart returns the start of the round containing date, which is midnight on the most recent week start day.
+func (c Config) RoundStart(date time.Time) time.Time {
+	weekStart := c.WeekStart
+	if weekStart == "" {
+		weekStart = DayMonday
+	}
+
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	daysSinceWeekStart := (int(start.Weekday()) - int(weekStart.Weekday()) + 7) % 7
+	return start.AddDate(0, 0, -daysSinceWeekStart)
 }
 
 func (c Config) GetPerson(id ID) (Person, error) {
@@ -38,7 +161,137 @@ func (c Config) GetPerson(id ID) (Person, error) {
 	return c.People[index], nil
 }
 
-func (c Config) validate() error {
+// Index returns the people of the config by their ID, for looking up many people at once.
+func (c Config) Index() map[ID]Person {
+	index := make(map[ID]Person, len(c.People))
+	for _, person := range c.People {
+		if _, exists := index[person.ID]; !exists {
+			index[person.ID] = person
+		}
+	}
+	return index
+}
+
+// IDs returns the IDs of the people in config order.
+func (c Config) IDs() []ID {
+	ids := make([]ID, 0, len(c.People))
+	for _, person := range c.People {
+		ids = append(ids, person.ID)
+	}
+	return ids
+}
+
+// BySquad returns the IDs of the people in each squad in config order. People without a squad are left out.
+func (c Config) BySquad() map[string][]ID {
+	squads := map[string][]ID{}
+	for _, person := range c.People {
+		if person.Squad != "" {
+			squads[person.Squad] = append(squads[person.Squad], person.ID)
+		}
+	}
+	return squads
+}
+
+// Validate checks the config is consistent, e.g. that IDs are unique and every setting has a supported value.
+func (c Config) Validate() error {
+	if err := c.Settings.validate(); err != nil {
+		return err
+	}
+
+	if c.WeekStart != "" && !slices.Contains(weekDays, c.WeekStart) {
+		return fmt.Errorf("invalid week start: %s", c.WeekStart)
+	}
+
+	if _, err := c.Location(); err != nil {
+		return err
+	}
+
+	for _, campaign := range c.Campaigns {
+		if err := campaign.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, limit := range c.SquadLimits {
+		if err := limit.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range c.TagRules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := compileRules(c.Rules); err != nil {
+		return err
+	}
+
+	if err := c.SoftConstraints.validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateDenyGroups(); err != nil {
+		return err
+	}
+
+	if err := c.Preset.validate(); err != nil {
+		return err
+	}
+
+	if c.Onboarding != nil {
+		if err := c.Onboarding.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.validateManagers(); err != nil {
+		return err
+	}
+
+	if err := c.validateOrganizers(); err != nil {
+		return err
+	}
+
+	if c.Escalation != nil {
+		if err := c.Escalation.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, pin := range c.Pins {
+		if err := pin.validate(c); err != nil {
+			return err
+		}
+	}
+
+	if err := c.HolidayPolicy.validate(); err != nil {
+		return err
+	}
+
+	if c.Quorum != nil {
+		if err := c.Quorum.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.StrangerOrder.validate(); err != nil {
+		return err
+	}
+
+	for _, absence := range c.Absences {
+		if err := absence.validate(c); err != nil {
+			return err
+		}
+	}
+
+	if c.Curriculum != nil {
+		if err := c.Curriculum.validate(); err != nil {
+			return err
+		}
+	}
+
 	ids := make(map[ID]struct{})
 	for _, person := range c.People {
 		_, exists := ids[person.ID]
@@ -46,63 +299,191 @@ func (c Config) validate() error {
 			return fmt.Errorf("ID is not unique: %s", person.ID)
 		}
 		ids[person.ID] = struct{}{}
+
+		for _, day := range person.PreferredDays {
+			if !slices.Contains(weekDays, day) {
+				return fmt.Errorf("invalid preferred day for %s: %s", person.ID, day)
+			}
+		}
+
+		switch person.Notify {
+		case "", ChannelSlack, ChannelEmail, ChannelNone:
+		default:
+			return fmt.Errorf("invalid notify channel for %s: %s", person.ID, person.Notify)
+		}
+
+		if err := person.Priority.validate(); err != nil {
+			return fmt.Errorf("invalid priority for %s: %w", person.ID, err)
+		}
 	}
 	return nil
 }
 
-func NewConfigFromFile(path string) (Config, error) {
-	file, err := os.Open(path)
+// CommonPreferredDays returns the days that suit both people in week order.
+// A person without any preferred days is treated as available on any day.
+// Nil is returned if neither person has a preference, an empty slice if their preferences do not overlap.
+func (c Config) CommonPreferredDays(id1, id2 ID) ([]Day, error) {
+	person1, err := c.GetPerson(id1)
 	if err != nil {
-		return Config{}, fmt.Errorf("error opening file %s: %w", path, err)
+		return nil, err
 	}
 
-	config := new(Config)
-	err = json.NewDecoder(file).Decode(config)
+	person2, err := c.GetPerson(id2)
 	if err != nil {
-		return Config{}, fmt.Errorf("error decoding Config: %w", err)
+		return nil, err
 	}
 
-	if err := config.validate(); err != nil {
-		return Config{}, err
+	if len(person1.PreferredDays) == 0 && len(person2.PreferredDays) == 0 {
+		return nil, nil
 	}
 
-	if err := file.Close(); err != nil {
-		return Config{}, err
+	common := []Day{}
+	for _, day := range weekDays {
+		if prefersDay(person1, day) && prefersDay(person2, day) {
+			common = append(common, day)
+		}
 	}
 
-	return *config, nil
+	return common, nil
+}
+
+func prefersDay(person Person, day Day) bool {
+	return len(person.PreferredDays) == 0 || slices.Contains(person.PreferredDays, day)
+}
+
+// NewConfigFromFile reads and validates the config file, migrating it from older versions of the format.
+func NewConfigFromFile(path string) (Config, error) {
+	return NewConfigFromFiles(path)
+}
+
+// Interpolated reports whether environment variables were expanded when reading the config. Such configs cannot be
+// exported, as the values of the variables would replace the references to them.
+func (c Config) Interpolated() bool {
+	return c.interpolated
+}
+
+// Export writes the config to the given writer in the current version, typically a file.
+func (c Config) Export(writer io.Writer) error {
+	if c.interpolated {
+		return errors.New("config refers to environment variables and cannot be written back")
+	}
+
+	c.Version = ConfigSchemaVersion
+	data, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return fmt.Errorf("error marshalling Config: %w", err)
+	}
+
+	if _, err = writer.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing Config: %w", err)
+	}
+	return nil
 }
 
 type Person struct {
 	ID       ID      `json:"id"`
-	DenyList []ID    `json:"denyList"`
-	Cadence  Cadence `json:"cadence"`
-	Squad    string  `json:"squad"`
+	DenyList []ID    `json:"denyList,omitempty"`
+	Cadence  Cadence `json:"cadence,omitempty"`
+	Squad    string  `json:"squad,omitempty"`
+	// DenyGroups are the names of deny groups of the config, whose members the person is not paired with.
+	DenyGroups []string `json:"denyGroups,omitempty"`
+	// PreferredDays are the days of the week the person would like to meet on.
+	PreferredDays []Day `json:"preferredDays,omitempty"`
+	// Tags describe the groups a person belongs to, e.g. "guild:frontend" or "location:berlin".
+	Tags []string `json:"tags,omitempty"`
+	// Attributes are arbitrary values which rules can refer to, e.g. a level or department.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Location is where the person works, e.g. an office or region.
+	Location string `json:"location,omitempty"`
+	// Interests are topics the person would like to talk about, also available as "interest:" tags.
+	Interests []string `json:"interests,omitempty"`
+	// Email identifies the person when signing in to the participant portal, and is where they are emailed their pairings.
+	Email string `json:"email,omitempty"`
+	// Slack is the Slack member ID of the person, used to message them their pairings.
+	Slack string `json:"slack,omitempty"`
+	// Notify is the channel the person is notified of their pairings through when several are used at once, or none
+	// to not be notified. People without one are notified through the first channel which can reach them.
+	Notify Channel `json:"notify,omitempty"`
+	// Paused people are not paired until they are unpaused, e.g. while on leave.
+	Paused bool `json:"paused,omitempty"`
+	// Birthday is flagged when it falls within a round the person is paired in. Only the month and day are used.
+	Birthday *Date `json:"birthday,omitempty"`
+	// StartDate is when the person started, with the anniversaries flagged when they fall within a round.
+	StartDate *Date `json:"startDate,omitempty"`
+	// Priority decides whether the person is matched before or after others, defaulting to normal.
+	Priority Priority `json:"priority,omitempty"`
+	// Manager is the ID of the person they report to, which the skip-level preset follows to find management chains.
+	Manager ID `json:"manager,omitempty"`
+	// Guest people are external participants, such as partners or contractors, who are only paired with people who
+	// allow external chats.
+	Guest bool `json:"guest,omitempty"`
+	// AllowExternal people opt into being paired with guests.
+	AllowExternal bool `json:"allowExternal,omitempty"`
+	// Note is a note of the organizers about the person, shown alongside their pairings and in notifications.
+	Note string `json:"note,omitempty"`
 }
 
 type Pairings struct {
-	data [][2]ID
+	data    [][2]ID
+	date    time.Time
+	penalty float64
+	// lastMeetings are when each pair previously met, before these pairings were recorded.
+	lastMeetings map[[2]ID]time.Time
+	// initiators are who is responsible for scheduling the meeting of each pair.
+	initiators map[[2]ID]ID
+	// topics are the topics of the curriculum each pair is to discuss.
+	topics map[[2]ID]string
+	// score is the score of the plan the pairings are part of, when generated by the planned strategy.
+	score *PlanScore
 }
 
-// NewPairingsFromFile constructs and returns Pairings.
-func NewPairingsFromFile(path string) (Pairings, error) {
+// NewPairings returns the pairs as the pairings of the round starting on the date, e.g. to rematch a saved plan.
+func NewPairings(date time.Time, pairs [][2]ID) Pairings {
+	return Pairings{data: slices.Clone(pairs), date: date}
+}
+
+// NewPairingsFromFile reads the pairings file at path, either a list of pairs as written by Export or the pairings of
+// a round with their date, initiators and topics as written by MarshalJSON. If a config is given the pairings are checked
+// against it with ValidatePairings, returning a ViolationsError listing the violations along with the pairings if
+// any constraint is violated. The constraints of the round are only checked if the file has a date.
+func NewPairingsFromFile(path string, config ...Config) (Pairings, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return Pairings{}, fmt.Errorf("error opening file %s: %w", path, err)
 	}
+	defer file.Close()
 
-	data := new([][2]ID)
-	err = json.NewDecoder(file).Decode(data)
-	if err != nil {
+	var pairings Pairings
+	if err := json.NewDecoder(file).Decode(&pairings); err != nil {
 		return Pairings{}, fmt.Errorf("error decoding Pairings: %w", err)
 	}
-
-	return Pairings{data: *data}, nil
+	var violations []Violation
+	for _, c := range config {
+		violations = append(violations, ValidatePairings(c, pairings)...)
+	}
+	if len(violations) > 0 {
+		return pairings, ViolationsError{Violations: violations}
+	}
+	return pairings, nil
 }
 
 // Export writes the pairings to the given writer, typically a file.
+// The people of each pair and the pairs themselves are sorted, so exporting the same pairings always writes the same
+// data regardless of the order they were generated in.
 func (p *Pairings) Export(writer io.Writer) error {
-	data, err := json.Marshal(p.data)
+	return p.ExportIndent(writer, "")
+}
+
+// ExportIndent writes the pairings like Export, with each pair on its own line indented by indent. Nothing is
+// indented if indent is empty.
+func (p *Pairings) ExportIndent(writer io.Writer, indent string) error {
+	var data []byte
+	var err error
+	if indent == "" {
+		data, err = json.Marshal(sortedPairs(p.data))
+	} else {
+		data, err = json.MarshalIndent(sortedPairs(p.data), "", indent)
+	}
 	if err != nil {
 		return fmt.Errorf("error marshalling Pairings: %w", err)
 	}
@@ -113,6 +494,53 @@ func (p *Pairings) Export(writer io.Writer) error {
 	return nil
 }
 
+// Date returns the start of the round the pairings were generated for.
+func (p *Pairings) Date() time.Time {
+	return p.date
+}
+
+// Penalty returns the total penalty of the soft constraints for the generated pairings.
+func (p *Pairings) Penalty() float64 {
+	return p.penalty
+}
+
+// PlanScore returns the score of the plan the pairings are part of, if they were generated by the planned strategy.
+func (p *Pairings) PlanScore() (PlanScore, bool) {
+	if p.score == nil {
+		return PlanScore{}, false
+	}
+	return *p.score, true
+}
+
+// LastMet returns when the pair previously met, if they have, as of when the pairings were generated.
+func (p *Pairings) LastMet(id1, id2 ID) (time.Time, bool) {
+	lastMeeting, met := p.lastMeetings[pairKey(id1, id2)]
+	return lastMeeting, met
+}
+
+// Len returns the number of pairs.
+func (p *Pairings) Len() int {
+	return len(p.data)
+}
+
+// Contains reports whether the two people are paired together, in either order.
+func (p *Pairings) Contains(id1, id2 ID) bool {
+	return slices.ContainsFunc(p.data, func(pair [2]ID) bool { return pairKey(pair[0], pair[1]) == pairKey(id1, id2) })
+}
+
+// PartnerOf returns who the person is paired with, if they are paired.
+func (p *Pairings) PartnerOf(id ID) (ID, bool) {
+	for _, pair := range p.data {
+		switch id {
+		case pair[0]:
+			return pair[1], true
+		case pair[1]:
+			return pair[0], true
+		}
+	}
+	return "", false
+}
+
 func (p *Pairings) Add(id1, id2 ID) {
 	p.data = append(p.data, [2]ID{id1, id2})
 }
@@ -127,41 +555,84 @@ func (p *Pairings) All() iter.Seq2[ID, ID] {
 	}
 }
 
-func GeneratePairings(config Config, hist *history.History, weeks int) ([]Pairings, error) {
-	date := time.Now()
-	var weeklyPairings []Pairings
-	idToValidPairings := determineValidPairings(config)
+// GeneratePairings generates the given number of rounds of pairings, recording each in the history.
+// The first round starts at the beginning of the current week in the timezone of the config,
+// and rounds are spaced by the interval of the config. The strategy of the config decides how pairings are chosen.
+func GeneratePairings(config Config, hist *history.History, rounds int) ([]Pairings, error) {
+	return GeneratePairingsContext(context.Background(), config, hist, rounds)
+}
+
+// GeneratePairingsContext generates pairings like GeneratePairings, stopping with the error of the context once it is
+// done. The generation is traced as a span of the context and its duration measured when telemetry is enabled.
+func GeneratePairingsContext(ctx context.Context, config Config, hist *history.History, rounds int) ([]Pairings, error) {
+	location, err := config.Location()
+	if err != nil {
+		return nil, err
+	}
 
-	for range weeks {
-		pairings := pairPeople(config, idToValidPairings, *hist, date)
+	ctx, span := telemetry.Start(ctx, "yapper.GeneratePairings", telemetry.Int("yapper.people", len(config.People)), telemetry.Int("yapper.rounds", rounds))
+	defer span.End()
+	start := time.Now()
 
-		for id1, id2 := range pairings.All() {
-			hist.AddMeeting(
-				history.ID(id1),
-				history.ID(id2),
-				date,
-			)
-		}
+	weeklyPairings, err := generateForDatesContext(ctx, config, hist, roundDates(config, time.Now().In(location), rounds))
+	span.RecordError(err)
+	telemetry.Record("yapper.generate.duration", time.Since(start).Seconds(), telemetry.Bool("yapper.error", err != nil))
+	return weeklyPairings, err
+}
 
-		weeklyPairings = append(weeklyPairings, pairings)
-		date = date.AddDate(0, 0, 7)
+// roundDates returns the starts of the given number of rounds, the first being the round containing now.
+func roundDates(config Config, now time.Time, rounds int) []time.Time {
+	dates := make([]time.Time, 0, rounds)
+	date := config.RoundStart(now)
+	for range rounds {
+		dates = append(dates, date)
+		date = date.AddDate(0, 0, config.RoundInterval())
 	}
+	return dates
+}
+
+// generateForDates generates a round of pairings starting on each of the dates, recording each in the history.
+// The strategies of the config are tried in turn until one completes within its budget.
+func generateForDates(config Config, hist *history.History, dates []time.Time) ([]Pairings, error) {
+	return generateForDatesContext(context.Background(), config, hist, dates)
+}
 
+func generateForDatesContext(ctx context.Context, config Config, hist *history.History, dates []time.Time) ([]Pairings, error) {
+	if _, err := compileRules(config.Rules); err != nil {
+		return nil, fmt.Errorf("error generating pairings: %w", err)
+	}
+
+	weeklyPairings, err := chooseWithStrategies(ctx, config, determineValidPairings(config), *hist, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	recordRounds(config, hist, weeklyPairings)
 	return weeklyPairings, nil
 }
 
-// determineValidPairings parses the people and their deny lists to determine the valid pairings for each person.
+// recordRounds records the pairings of each round in the history in turn.
+func recordRounds(config Config, hist *history.History, weeklyPairings []Pairings) {
+	for i := range weeklyPairings {
+		recordPairings(config, hist, &weeklyPairings[i])
+	}
+}
+
+// determineValidPairings parses the people, their deny lists and the rules to determine the valid pairings for each person.
+// The valid pairings of recently seen configs are cached, see validPairingsCache, and the caller may change the result.
 func determineValidPairings(config Config) map[ID][]ID {
+	return defaultValidPairingsCache.get(config)
+}
+
+// computeValidPairings checks every pair of people against the hard constraints, which is quadratic in the number of
+// people.
+func computeValidPairings(config Config) map[ID][]ID {
 	pairings := map[ID][]ID{}
 
+	check := newPairCheck(config)
 	for _, person := range config.People {
 		for _, potentialPair := range config.People {
-			if person.ID == potentialPair.ID ||
-				slices.Contains(person.DenyList, potentialPair.ID) || slices.Contains(potentialPair.DenyList, person.ID) {
-				continue
-			}
-
-			if person.Squad != "" && person.Squad == potentialPair.Squad {
+			if check.denial(person, potentialPair) != "" {
 				continue
 			}
 
@@ -172,25 +643,79 @@ func determineValidPairings(config Config) map[ID][]ID {
 	return pairings
 }
 
-// pairPeople based on their valid pairings.
-// Preference is given to unmet people and then by longest time since last meeting.
+// ValidPairings returns the people each person can be paired with under the hard constraints, in config order.
+// People who cannot be paired with anyone are left out. Cadences, absences and other reasons people are not paired in
+// a particular round are not taken into account.
+func (c Config) ValidPairings() map[ID][]ID {
+	return determineValidPairings(c)
+}
+
+// sortedPairs returns a copy of the pairs with the people of each pair sorted, in sorted order.
+func sortedPairs(pairs [][2]ID) [][2]ID {
+	sorted := make([][2]ID, 0, len(pairs))
+	for _, pair := range pairs {
+		sorted = append(sorted, pairKey(pair[0], pair[1]))
+	}
+	slices.SortFunc(sorted, func(a, b [2]ID) int {
+		if order := strings.Compare(string(a[0]), string(b[0])); order != 0 {
+			return order
+		}
+		return strings.Compare(string(a[1]), string(b[1]))
+	})
+	return sorted
+}
+
+// pairPeople based on their valid pairings, starting with any pinned pairs.
+// People are matched in order of their priority, with those owed a skipped round and then members of an active campaign
+// first within each priority. Preference is given to pairings targeted by an active campaign, then the lowest penalty
+// of the soft constraints, then unmet people and then by longest time since last meeting. People still onboarding are
+// only paired within their cohort, and pairs beyond the squad limits are not made, although pins count towards the
+// limits without being skipped.
 func pairPeople(conf Config, idToValidPairings map[ID][]ID, hist history.History, date time.Time) Pairings {
-	pairings := Pairings{}
-	alreadyPaired := getIneligiblePeople(conf, idToValidPairings, date)
+	pairings := Pairings{date: date}
+	idToValidPairings = restrictToCohorts(conf.onboardingCohorts(date), idToValidPairings)
+	people := newOrdinals(conf)
+	ineligible := getIneligiblePeople(conf, idToValidPairings, date)
+	alreadyPaired := newPersonSet(people, ineligible...)
+	scratch := newPairingScratch(people)
+	campaigns := activeCampaigns(conf, date)
+	squadCounts := newSquadCounts(conf)
+	scorer := newRoundScorer(conf, hist, date)
+
+	for _, pin := range pinnedPairings(conf, idToValidPairings, ineligible, date) {
+		pairings.Add(pin[0], pin[1])
+		squadCounts.add(pin[0], pin[1])
+		pairings.penalty += scorer.penalty(pin[0], pin[1])
+		alreadyPaired.add(pin[0])
+		alreadyPaired.add(pin[1])
+	}
+
+	ids := slices.DeleteFunc(conf.IDs(), func(id ID) bool {
+		_, found := idToValidPairings[id]
+		return !found
+	})
+	ids = shufflePeople(conf, ids, date)
+	ids = prioritiseCampaignMembers(scorer.index, campaigns, ids)
+	ids = prioritiseOwed(conf.owed(date), ids)
+	ids = prioritiseByPriority(scorer.index, ids)
 
-	for id, validPairings := range idToValidPairings {
-		if slices.Contains(alreadyPaired, id) {
+	for _, id := range ids {
+		if alreadyPaired.contains(id) {
 			continue
 		}
 
-		orderedPossiblePairings := getOrderedPossiblePairings(id, validPairings, hist)
+		orderedPossiblePairings := getOrderedPossiblePairings(conf, id, idToValidPairings[id], hist, date, scratch)
+		orderedPossiblePairings = prioritiseLowestPenalty(scorer, id, orderedPossiblePairings)
+		orderedPossiblePairings = prioritiseCampaignPairings(scorer.index, campaigns, id, orderedPossiblePairings)
 		for _, pair := range orderedPossiblePairings {
-			if slices.Contains(alreadyPaired, pair) {
+			if alreadyPaired.contains(pair) || !squadCounts.allows(id, pair) {
 				continue
 			}
 			pairings.Add(id, pair)
-			alreadyPaired = append(alreadyPaired, id)
-			alreadyPaired = append(alreadyPaired, pair)
+			squadCounts.add(id, pair)
+			pairings.penalty += scorer.penalty(id, pair)
+			alreadyPaired.add(id)
+			alreadyPaired.add(pair)
 			break
 		}
 	}
@@ -198,26 +723,48 @@ func pairPeople(conf Config, idToValidPairings map[ID][]ID, hist history.History
 	return pairings
 }
 
+// pairingScratch is reused when ordering the possible pairings of each person in a round, so that the sets and slices
+// are allocated once per round rather than once per person.
+type pairingScratch struct {
+	met, valid personSet
+	ordered    []ID
+}
+
+func newPairingScratch(people ordinals) *pairingScratch {
+	return &pairingScratch{met: newPersonSet(people), valid: newPersonSet(people), ordered: make([]ID, 0, len(people))}
+}
+
 // getOrderedPossiblePairings sorts the valid pairings based on the time since last meeting in descending order.
-// Any possible pairings that have not been met will be placed in the front to ensure priority.
-func getOrderedPossiblePairings(id ID, validPairings []ID, hist history.History) []ID {
-	previousMeetingsOldestFirst := history.GetPeopleMetSortedByLastMeeting(hist, history.ID(id))
-	unmetPeople := getPeopleNotMetBefore(validPairings, previousMeetingsOldestFirst)
+// Any possible pairings that have not been met will be placed in the front to ensure priority, in the stranger order of the config.
+// People last met on the same date are shuffled by the tie break of the config.
+// The result is kept in the scratch, so it is only valid until the possible pairings of the next person are ordered.
+func getOrderedPossiblePairings(conf Config, id ID, validPairings []ID, hist history.History, date time.Time, scratch *pairingScratch) []ID {
+	previousMeetingsOldestFirst := breakTies(conf, hist, id, history.GetPeopleMetSortedByLastMeeting(hist, history.ID(id)), date)
+	scratch.met.clear()
+	for _, prevID := range previousMeetingsOldestFirst {
+		scratch.met.add(ID(prevID))
+	}
+	unmetPeople := orderStrangers(conf, hist, id, getPeopleNotMetBefore(scratch.ordered[:0], validPairings, scratch.met), date)
 
+	scratch.valid.clear()
+	for _, validID := range validPairings {
+		scratch.valid.add(validID)
+	}
 	possiblePairingsOrdered := unmetPeople
 	for _, prevID := range previousMeetingsOldestFirst {
-		if slices.Contains(validPairings, ID(prevID)) {
+		if scratch.valid.contains(ID(prevID)) {
 			possiblePairingsOrdered = append(possiblePairingsOrdered, ID(prevID))
 		}
 	}
 
+	scratch.ordered = possiblePairingsOrdered
 	return possiblePairingsOrdered
 }
 
-func getPeopleNotMetBefore(validPairings []ID, previousPairings []history.ID) []ID {
-	var unmetPeople []ID
+// getPeopleNotMetBefore appends the valid pairings who have not been met to unmetPeople.
+func getPeopleNotMetBefore(unmetPeople []ID, validPairings []ID, met personSet) []ID {
 	for _, id := range validPairings {
-		if !slices.Contains(previousPairings, history.ID(id)) {
+		if !met.contains(id) {
 			unmetPeople = append(unmetPeople, id)
 		}
 	}
@@ -225,34 +772,36 @@ func getPeopleNotMetBefore(validPairings []ID, previousPairings []history.ID) []
 	return unmetPeople
 }
 
-// getIneligiblePeople returns the IDs of the people who cannot meet this week.
+// getIneligiblePeople returns the IDs of the people who cannot meet this week, due to their cadence, being paused,
+// being absent or their week being mostly public holidays.
+// Only the people with valid pairings are included, in config order.
 func getIneligiblePeople(conf Config, idToValidPairings map[ID][]ID, date time.Time) []ID {
 	var ineligible []ID
+	quorum := conf.quorumOf(date)
 
-	twoWeekValid := isValidWeekForTwoWeekCadence(date)
-
-	for id := range idToValidPairings {
-		person, err := conf.GetPerson(id)
-		if err != nil {
-			log.Fatalf("Cannot find %s in config", id)
+	for _, person := range conf.People {
+		if _, found := idToValidPairings[person.ID]; !found {
+			continue
 		}
 
-		switch person.Cadence {
-		case CadenceOneWeek, "":
-			continue
-		case CadenceTwoWeeks:
-			if !twoWeekValid {
-				ineligible = append(ineligible, id)
-			}
-		default:
-			log.Fatalf("Unexpected cadence: %s", person.Cadence)
+		if conf.ineligibility(person, date, quorum) != "" {
+			ineligible = append(ineligible, person.ID)
 		}
 	}
 
 	return ineligible
 }
 
-func isValidWeekForTwoWeekCadence(date time.Time) bool {
-	_, week := date.ISOWeek()
-	return (week % 2) == 0
+// isValidWeekForTwoWeekCadence reports whether people on a two week cadence meet in the round containing date.
+// Weekly rounds follow the parity of the ISO week, any other interval counts rounds since the Unix epoch.
+// In both cases the two week cadence means meeting every second round.
+func isValidWeekForTwoWeekCadence(date time.Time, interval int) bool {
+	if interval == defaultInterval {
+		_, week := date.ISOWeek()
+		return (week % 2) == 0
+	}
+
+	year, month, day := date.Date()
+	days := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60)
+	return (days/int64(interval))%2 == 0
 }
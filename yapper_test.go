@@ -2,6 +2,8 @@ package yapper
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -29,8 +31,8 @@ func TestNewConfigFromFileReturnsExpectedConfig(t *testing.T) {
 		{ID: "Luigi", DenyList: []ID{"Waluigi", "Bowser"}, Squad: "bros"},
 		{ID: "Wario", DenyList: []ID{"Mario"}},
 		{ID: "Waluigi", DenyList: []ID{"Luigi"}},
-		{ID: "Toad"},
-		{ID: "Yoshi"},
+		{ID: "Toad", PreferredDays: []Day{DayTuesday, DayWednesday}},
+		{ID: "Yoshi", PreferredDays: []Day{DayWednesday, DayThursday}},
 		{ID: "Peach", DenyList: []ID{"Bowser"}},
 		{ID: "Bowser", Squad: "koopas"},
 		{ID: "Bowser Jr", Squad: "koopas"},
@@ -38,7 +40,7 @@ func TestNewConfigFromFileReturnsExpectedConfig(t *testing.T) {
 		{ID: "Monty Mole", Cadence: CadenceTwoWeeks},
 		{ID: "Koopa Troopa", Squad: "koopas"},
 	}
-	expectedConfig := Config{People: expectedPeople}
+	expectedConfig := Config{Version: ConfigSchemaVersion, People: expectedPeople}
 	config, err := NewConfigFromFile(configPath)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -79,6 +81,37 @@ func TestConfigGetPersonReturnsErrorWhenPersonDoesNotExist(t *testing.T) {
 	}
 }
 
+func TestConfigValidateReturnsErrorForInvalidPreferredDay(t *testing.T) {
+	config := Config{People: []Person{{ID: "Toad", PreferredDays: []Day{"someday"}}}}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to invalid preferred day")
+	}
+}
+
+func TestConfigCommonPreferredDaysReturnsIntersection(t *testing.T) {
+	config := getConfigFromFile(t, validConfigName)
+
+	tests := []struct {
+		id1, id2 ID
+		expected []Day
+	}{
+		{"Toad", "Yoshi", []Day{DayWednesday}},
+		{"Toad", "Mario", []Day{DayTuesday, DayWednesday}},
+		{"Mario", "Wario", nil},
+	}
+
+	for _, test := range tests {
+		days, err := config.CommonPreferredDays(test.id1, test.id2)
+		if err != nil {
+			t.Fatalf("Unexpected error from Config.CommonPreferredDays, %s and %s: %v", test.id1, test.id2, err)
+		}
+
+		if eq := reflect.DeepEqual(days, test.expected); !eq {
+			t.Errorf("For %s and %s expected:\n%v\nGot:\n%v", test.id1, test.id2, test.expected, days)
+		}
+	}
+}
+
 func TestDetermineValidPairingsReturnsCorrectPairings(t *testing.T) {
 	config := getConfigFromFile(t, validConfigName)
 	expected := getValidPairsForConfig()
@@ -242,6 +275,112 @@ func TestPeopleOnTwoWeekCadenceOnlyGetPairedEveryTwoWeeks(t *testing.T) {
 	}
 }
 
+func TestPeopleOnTwoWeekCadenceOnlyGetPairedEverySecondRound(t *testing.T) {
+	date := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	config := getConfigFromFile(t, validConfigName)
+	config.Settings.Interval = 30
+
+	shyGuyRounds := 0
+	hist := history.History{}
+	rounds := 10
+	for range rounds {
+		pairings := pairPeople(config, determineValidPairings(config), hist, date)
+		for id1, id2 := range pairings.All() {
+			checkEligibleToMeetThisWeek(t, config, id1, date)
+			checkEligibleToMeetThisWeek(t, config, id2, date)
+			if id1 == "Shy Guy" || id2 == "Shy Guy" {
+				shyGuyRounds++
+			}
+			hist.AddMeeting(history.ID(id1), history.ID(id2), date)
+		}
+
+		date = date.AddDate(0, 0, config.Settings.Interval)
+	}
+
+	if shyGuyRounds > rounds/2 {
+		t.Errorf("Shy Guy should meet at most every second round, met in %d of %d rounds", shyGuyRounds, rounds)
+	}
+}
+
+func TestConfigValidateReturnsErrorForNegativeInterval(t *testing.T) {
+	config := Config{Settings: Settings{Interval: -7}}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to negative interval")
+	}
+}
+
+func TestConfigRoundStartReturnsMostRecentWeekStart(t *testing.T) {
+	thursdayEvening := time.Date(2025, time.August, 7, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		weekStart Day
+		expected  time.Time
+	}{
+		{"", time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)},
+		{DayMonday, time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)},
+		{DayThursday, time.Date(2025, time.August, 7, 0, 0, 0, 0, time.UTC)},
+		{DayFriday, time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)},
+		{DaySunday, time.Date(2025, time.August, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		config := Config{WeekStart: test.weekStart}
+		if start := config.RoundStart(thursdayEvening); !start.Equal(test.expected) {
+			t.Errorf("For week start %q expected %v, got %v", test.weekStart, test.expected, start)
+		}
+	}
+}
+
+func TestConfigValidateReturnsErrorForInvalidWeekStart(t *testing.T) {
+	config := Config{WeekStart: "someday"}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to invalid week start")
+	}
+}
+
+func TestConfigValidateReturnsErrorForInvalidTimezone(t *testing.T) {
+	config := Config{Timezone: "Mushroom/Kingdom"}
+	if err := config.Validate(); err == nil {
+		t.Errorf("Expected error due to invalid timezone")
+	}
+}
+
+func TestConfigRoundStartUsesTimezoneOfDate(t *testing.T) {
+	config := Config{Timezone: "Pacific/Auckland"}
+	location, err := config.Location()
+	if err != nil {
+		t.Fatalf("Unexpected error from Config.Location: %v", err)
+	}
+
+	// Late on a Sunday in UTC is already Monday in Auckland.
+	sundayUTC := time.Date(2025, time.August, 3, 20, 0, 0, 0, time.UTC)
+	expected := time.Date(2025, time.August, 4, 0, 0, 0, 0, location)
+
+	if start := config.RoundStart(sundayUTC.In(location)); !start.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, start)
+	}
+}
+
+func TestGeneratePairingsNotesWhenPairsLastMet(t *testing.T) {
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi"}}}
+	lastMeeting := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	hist := history.History{}
+	hist.AddMeeting("Mario", "Luigi", lastMeeting)
+
+	weeklyPairings, err := GeneratePairings(config, &hist, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error from GeneratePairings: %v", err)
+	}
+
+	for id1, id2 := range weeklyPairings[0].All() {
+		met, exists := weeklyPairings[0].LastMet(id1, id2)
+		if !exists || !met.Equal(lastMeeting) {
+			t.Errorf("Expected %s and %s to have last met on %v, got %v", id1, id2, lastMeeting, met)
+		}
+	}
+}
+
 func TestPairingsNewFromFileReturnsExpectedPairings(t *testing.T) {
 	path := filepath.Join("testdata", "expectedPairings.json")
 	expected := Pairings{}
@@ -258,6 +397,41 @@ func TestPairingsNewFromFileReturnsExpectedPairings(t *testing.T) {
 	}
 }
 
+func TestPairingsNewFromFileReturnsViolationsOfConfig(t *testing.T) {
+	path := filepath.Join("testdata", "expectedPairings.json")
+	config := Config{People: []Person{{ID: "id1"}, {ID: "id2", DenyList: []ID{"id1"}}}}
+
+	pairings, err := NewPairingsFromFile(path, config)
+	var violationsError ViolationsError
+	if !errors.As(err, &violationsError) {
+		t.Fatalf("Expected a ViolationsError, got %v", err)
+	}
+
+	expected := []Violation{
+		newViolation(ViolationDenyList, "id1", "id2"),
+		newViolation(ViolationPairedTwice, "id2"),
+		newViolation(ViolationUnknownPerson, "id3"),
+	}
+	if !reflect.DeepEqual(violationsError.Violations, expected) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, violationsError.Violations)
+	}
+	if pairings.Len() != 2 {
+		t.Errorf("Expected the pairings to be returned with the violations, got %d pairs", pairings.Len())
+	}
+}
+
+func TestPairingsNewFromFileAcceptsPairingsValidForConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pairings.json")
+	if err := os.WriteFile(path, []byte(`[["id1", "id2"]]`), 0o644); err != nil {
+		t.Fatalf("Unexpected error writing pairings: %v", err)
+	}
+	config := Config{People: []Person{{ID: "id1"}, {ID: "id2"}}}
+
+	if _, err := NewPairingsFromFile(path, config); err != nil {
+		t.Errorf("Unexpected error from NewPairingsFromFile: %v", err)
+	}
+}
+
 func TestPairingsExportWritesExpectedData(t *testing.T) {
 	expectedDataFile := filepath.Join("testdata", "expectedPairings.json")
 	pairings := Pairings{}
@@ -404,7 +578,7 @@ func diffPairings(t *testing.T, actual map[ID][]ID, expected map[ID][]ID) {
 func checkEligibleToMeetThisWeek(t *testing.T, config Config, id ID, date time.Time) {
 	t.Helper()
 
-	twoWeekValid := isValidWeekForTwoWeekCadence(date)
+	twoWeekValid := isValidWeekForTwoWeekCadence(date, config.RoundInterval())
 
 	person, err := config.GetPerson(id)
 	if err != nil {
@@ -442,3 +616,131 @@ func getValidPairsForConfig() map[ID][]ID {
 		"Koopa Troopa": {"Mario", "Luigi", "Wario", "Waluigi", "Yoshi", "Peach", "Shy Guy", "Toad", "Monty Mole"},
 	}
 }
+
+func TestPairPeopleSkipsPausedPeople(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	config := Config{People: []Person{{ID: "Mario"}, {ID: "Luigi", Paused: true}, {ID: "Peach"}}}
+
+	pairings := pairPeople(config, determineValidPairings(config), history.History{}, date)
+
+	for id1, id2 := range pairings.All() {
+		if id1 == "Luigi" || id2 == "Luigi" {
+			t.Errorf("Expected paused Luigi not to be paired, got %v", pairings.data)
+		}
+	}
+
+	if len(pairings.data) != 1 {
+		t.Errorf("Expected Mario and Peach to be paired, got %v", pairings.data)
+	}
+}
+
+func TestConfigExportRoundTrips(t *testing.T) {
+	config := getConfigFromFile(t, validConfigName)
+
+	var buffer bytes.Buffer
+	if err := config.Export(&buffer); err != nil {
+		t.Fatalf("Unexpected error exporting config: %v", err)
+	}
+
+	exported, err := decodeConfig(buffer.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error decoding exported config: %v", err)
+	}
+
+	if !reflect.DeepEqual(config, exported) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", config, exported)
+	}
+}
+
+func TestConfigPeopleAccessors(t *testing.T) {
+	config := Config{People: []Person{
+		{ID: "Mario", Squad: "bros"},
+		{ID: "Bowser", Squad: "koopas"},
+		{ID: "Luigi", Squad: "bros"},
+		{ID: "Toad"},
+	}}
+
+	index := config.Index()
+	if len(index) != 4 || index["Luigi"].Squad != "bros" {
+		t.Errorf("Expected everyone indexed by ID, got %v", index)
+	}
+
+	expectedIDs := []ID{"Mario", "Bowser", "Luigi", "Toad"}
+	if ids := config.IDs(); !reflect.DeepEqual(expectedIDs, ids) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedIDs, ids)
+	}
+
+	expectedSquads := map[string][]ID{"bros": {"Mario", "Luigi"}, "koopas": {"Bowser"}}
+	if squads := config.BySquad(); !reflect.DeepEqual(expectedSquads, squads) {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expectedSquads, squads)
+	}
+}
+
+// BenchmarkGetOrderedPossiblePairings orders the partners of someone in a group of 1000 who has met half of them.
+func BenchmarkGetOrderedPossiblePairings(b *testing.B) {
+	config := Config{}
+	for i := range 1000 {
+		config.People = append(config.People, Person{ID: ID(fmt.Sprintf("person-%d", i))})
+	}
+	idToValidPairings := computeValidPairings(config)
+	scratch := newPairingScratch(newOrdinals(config))
+
+	hist := history.History{}
+	start := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	for i := range 500 {
+		hist.AddMeeting("person-0", history.ID(fmt.Sprintf("person-%d", i+1)), start.AddDate(0, 0, -i))
+	}
+
+	b.ReportAllocs()
+	for range b.N {
+		getOrderedPossiblePairings(config, "person-0", idToValidPairings["person-0"], hist, start, scratch)
+	}
+}
+
+func TestPairingsQueries(t *testing.T) {
+	pairings := NewPairings(time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC), [][2]ID{{"Mario", "Luigi"}, {"Peach", "Toad"}})
+
+	if pairings.Len() != 2 {
+		t.Errorf("Expected 2 pairs, got %d", pairings.Len())
+	}
+	if !pairings.Contains("Luigi", "Mario") || pairings.Contains("Mario", "Peach") {
+		t.Errorf("Expected only Mario and Luigi of the two to be paired in %v", pairings.data)
+	}
+	if partner, found := pairings.PartnerOf("Toad"); !found || partner != "Peach" {
+		t.Errorf("Expected Toad to be paired with Peach, got %q", partner)
+	}
+	if partner, found := pairings.PartnerOf("Bowser"); found {
+		t.Errorf("Expected Bowser not to be paired, got %q", partner)
+	}
+}
+
+func TestPairingsExportSortsPairs(t *testing.T) {
+	pairings := Pairings{}
+	pairings.Add("id3", "id2")
+	pairings.Add("id1", "id4")
+
+	var buffer bytes.Buffer
+	if err := pairings.Export(&buffer); err != nil {
+		t.Fatalf("Unexpected error from Export: %v", err)
+	}
+
+	if expected := `[["id1","id4"],["id2","id3"]]`; buffer.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buffer.String())
+	}
+}
+
+func TestPairingsExportIndentWritesEachPairOnALine(t *testing.T) {
+	pairings := Pairings{}
+	pairings.Add("id3", "id2")
+	pairings.Add("id1", "id4")
+
+	var buffer bytes.Buffer
+	if err := pairings.ExportIndent(&buffer, "  "); err != nil {
+		t.Fatalf("Unexpected error from ExportIndent: %v", err)
+	}
+
+	expected := "[\n  [\n    \"id1\",\n    \"id4\"\n  ],\n  [\n    \"id2\",\n    \"id3\"\n  ]\n]"
+	if buffer.String() != expected {
+		t.Errorf("Expected:\n%v\nGot:\n%v", expected, buffer.String())
+	}
+}